@@ -226,6 +226,15 @@ func (aReq *AgentRequest) ParseField(
 			return "", err
 		}
 		out, err = utils.Sum(utils.StringToInterface(strVal1), utils.StringToInterface(strVal2))
+	case utils.MetaList:
+		var vals []string
+		if vals, err = cfgFld.Value.ParseDataProviderAsSlice(aReq, utils.NestingSep); err != nil {
+			return
+		}
+		if len(vals) == 0 && cfgFld.Mandatory {
+			return nil, utils.NewErrMandatoryIeMissing(cfgFld.Tag)
+		}
+		return vals, nil
 	}
 	if err != nil {
 		return
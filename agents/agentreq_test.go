@@ -122,3 +122,39 @@ func TestAgReqAsNavigableMap(t *testing.T) {
 		t.Errorf("expecting: %+v, received: %+v", eMp, mpOut)
 	}
 }
+
+func TestAgReqParseFieldMetaList(t *testing.T) {
+	data, _ := engine.NewMapStorage()
+	dm := engine.NewDataManager(data)
+	cfg, _ := config.NewDefaultCGRConfig()
+	filterS := engine.NewFilterS(cfg, nil, dm)
+	agReq := newAgentRequest(nil, nil, nil, nil, "cgrates.org", "", filterS)
+	agReq.CGRReply = config.NewNavigableMap(map[string]interface{}{
+		utils.CapAttributes: map[string]interface{}{
+			"ChargingRule": "rule_gold",
+		},
+		utils.CapThresholds: []string{"THD_ACNT_1001", "THD_DATA_CAP"},
+		utils.Error:         "",
+	})
+	tplFld := &config.FCTemplate{Tag: "ChargingRuleName",
+		FieldId: "Charging-Rule-Install.Charging-Rule-Name", Type: utils.MetaList,
+		Value: config.NewRSRParsersMustCompile(
+			"~*cgrep.Attributes.ChargingRule;~*cgrep.Thresholds", true)}
+	eOut := []string{"rule_gold", "THD_ACNT_1001", "THD_DATA_CAP"}
+	if out, err := agReq.ParseField(tplFld); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eOut, out) {
+		t.Errorf("expecting: %+v, received: %+v", eOut, out)
+	}
+	tplFld.Value = config.NewRSRParsersMustCompile("~*cgrep.Attributes.Missing", true)
+	if out, err := agReq.ParseField(tplFld); err != nil {
+		t.Error(err)
+	} else if len(out.([]string)) != 0 {
+		t.Errorf("expecting empty list, received: %+v", out)
+	}
+	tplFld.Mandatory = true
+	if _, err := agReq.ParseField(tplFld); err == nil ||
+		err.Error() != utils.NewErrMandatoryIeMissing("ChargingRuleName").Error() {
+		t.Errorf("received error: %v", err)
+	}
+}
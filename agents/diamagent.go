@@ -21,7 +21,9 @@ package agents
 import (
 	"fmt"
 	"reflect"
-	"strings"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
@@ -38,7 +40,14 @@ func NewDiameterAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
 	if sessionS != nil && reflect.ValueOf(sessionS).IsNil() {
 		sessionS = nil
 	}
-	da := &DiameterAgent{cgrCfg: cgrCfg, filterS: filterS, sessionS: sessionS}
+	da := &DiameterAgent{cgrCfg: cgrCfg, filterS: filterS, sessionS: sessionS,
+		peers:      make(map[string]*diamPeer),
+		gxSessions: make(map[string]*gxSession),
+		conns:      make(map[diam.Conn]struct{}),
+		answers:    make(map[uint32]chan *diam.Message)}
+	if biRPCClnt, isBiRPC := sessionS.(*utils.BiRPCInternalClient); isBiRPC {
+		biRPCClnt.SetClientConn(da) // pass the connection to DA back into SessionS so we can receive the disconnects
+	}
 	dictsPath := cgrCfg.DiameterAgentCfg().DictionariesPath
 	if len(dictsPath) != 0 {
 		if err := loadDictionaries(dictsPath, utils.DiameterAgent); err != nil {
@@ -62,10 +71,33 @@ func NewDiameterAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
 	return da, nil
 }
 
+// diamPeer is the peer connection of one session,
+// together with the request which initiated it
+type diamPeer struct {
+	c diam.Conn
+	m *diam.Message
+}
+
+// gxSession is one policy session, together with the data
+// needed to address later Re-Auth-Requests towards the peer
+type gxSession struct {
+	peer        *diamPeer
+	originHost  string // Origin-Host of the CCR, destination of our RAR
+	originRealm string
+}
+
 type DiameterAgent struct {
-	cgrCfg   *config.CGRConfig
-	filterS  *engine.FilterS
-	sessionS rpcclient.RpcClientConnection // Connection towards CGR-SessionS component
+	cgrCfg     *config.CGRConfig
+	filterS    *engine.FilterS
+	sessionS   rpcclient.RpcClientConnection // Connection towards CGR-SessionS component
+	peers      map[string]*diamPeer          // peer connections indexed on session OriginID
+	pLck       sync.RWMutex
+	gxSessions map[string]*gxSession // Gx sessions indexed on Session-Id
+	gxLck      sync.RWMutex
+	conns      map[diam.Conn]struct{} // peer connections watched for disconnects
+	cLck       sync.Mutex
+	answers    map[uint32]chan *diam.Message // requests originated by us waiting for answer, indexed on HopByHopID
+	aLck       sync.Mutex
 }
 
 // ListenAndServe is called when DiameterAgent is started, usually from within cmd/cgr-engine
@@ -94,6 +126,10 @@ func (da *DiameterAgent) handlers() diam.Handler {
 
 // handleALL is the handler of all messages coming in via Diameter
 func (da *DiameterAgent) handleMessage(c diam.Conn, m *diam.Message) {
+	if m.Header.CommandFlags&diam.RequestFlag != diam.RequestFlag {
+		da.handleAnswer(m)
+		return
+	}
 	dApp, err := m.Dictionary().App(m.Header.ApplicationID)
 	if err != nil {
 		utils.Logger.Err(fmt.Sprintf("<%s> decoding app: %d, err: %s",
@@ -118,6 +154,9 @@ func (da *DiameterAgent) handleMessage(c diam.Conn, m *diam.Message) {
 		utils.MetaAppID:   dApp.ID,
 		utils.MetaCmd:     dCmd.Short + "R",
 	}
+	if m.Header.CommandCode == diam.CreditControl {
+		defer da.remGxSession(m) // Gx sessions are gone after CCR-T even if we fail to process it
+	}
 	rply := config.NewNavigableMap(nil) // share it among different processors
 	var processed bool
 	for _, reqProcessor := range da.cgrCfg.DiameterAgentCfg().RequestProcessors {
@@ -128,7 +167,7 @@ func (da *DiameterAgent) handleMessage(c diam.Conn, m *diam.Message) {
 				reqProcessor.Tenant, da.cgrCfg.GeneralCfg().DefaultTenant,
 				utils.FirstNonEmpty(reqProcessor.Timezone,
 					config.CgrConfig().GeneralCfg().DefaultTimezone),
-				da.filterS))
+				da.filterS), &diamPeer{c: c, m: m})
 		if lclProcessed {
 			processed = lclProcessed
 		}
@@ -152,61 +191,19 @@ func (da *DiameterAgent) handleMessage(c diam.Conn, m *diam.Message) {
 	}
 	a := m.Answer(diam.Success)
 	// write reply into message
-	pathIdx := make(map[string]int) // group items for same path
-	for _, val := range rply.Values() {
-		nmItms, isNMItems := val.([]*config.NMItem)
-		if !isNMItems {
-			utils.Logger.Warning(
-				fmt.Sprintf("<%s> cannot encode reply field: %s, ignoring message %s from %s",
-					utils.DiameterAgent, utils.ToJSON(val), m, c.RemoteAddr()))
-			writeOnConn(c, m.Answer(diam.UnableToComply))
-			return
-		}
-		// find out the first itm which is not an attribute
-		var itm *config.NMItem
-		if len(nmItms) == 1 {
-			itm = nmItms[0]
-		} else { // only for groups
-			for i, cfgItm := range nmItms {
-				itmPath := strings.Join(cfgItm.Path, utils.NestingSep)
-				if i == 0 { // path is common, increase it only once
-					pathIdx[itmPath] += 1
-				}
-				if i == pathIdx[itmPath]-1 { // revert from multiple items to only one per config path
-					itm = cfgItm
-					break
-				}
-			}
-		}
-		if itm == nil {
-			continue // all attributes, not writable to diameter packet
-		}
-		itmStr, err := utils.IfaceAsString(itm.Data)
-		if err != nil {
-			utils.Logger.Warning(
-				fmt.Sprintf("<%s> error: %s processing reply item: %s for message: %s",
-					utils.DiameterAgent, err.Error(), utils.ToJSON(itm), m))
-			writeOnConn(c, m.Answer(diam.UnableToComply))
-			return
-		}
-		var newBranch bool
-		if itm.Config != nil && itm.Config.NewBranch {
-			newBranch = true
-		}
-		if err := messageSetAVPsWithPath(a, itm.Path,
-			itmStr, newBranch, da.cgrCfg.GeneralCfg().DefaultTimezone); err != nil {
-			utils.Logger.Warning(
-				fmt.Sprintf("<%s> error: %s setting reply item: %s for message: %s",
-					utils.DiameterAgent, err.Error(), utils.ToJSON(itm), m))
-			writeOnConn(c, m.Answer(diam.UnableToComply))
-			return
-		}
+	if err = updateDiamMsgFromNavMap(a, rply,
+		da.cgrCfg.GeneralCfg().DefaultTimezone); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s encoding reply for message: %s from %s",
+				utils.DiameterAgent, err.Error(), m, c.RemoteAddr()))
+		writeOnConn(c, m.Answer(diam.UnableToComply))
+		return
 	}
 	writeOnConn(c, a)
 }
 
 func (da *DiameterAgent) processRequest(reqProcessor *config.DARequestProcessor,
-	agReq *AgentRequest) (processed bool, err error) {
+	agReq *AgentRequest, peer *diamPeer) (processed bool, err error) {
 	if pass, err := da.filterS.Pass(agReq.tenant,
 		reqProcessor.Filters, agReq); err != nil || !pass {
 		return pass, err
@@ -260,6 +257,9 @@ func (da *DiameterAgent) processRequest(reqProcessor *config.DARequestProcessor,
 		var initReply sessions.V1InitSessionReply
 		err = da.sessionS.Call(utils.SessionSv1InitiateSession,
 			initArgs, &initReply)
+		if err == nil {
+			da.setPeer(cgrEv.Event, peer) // keep it for the disconnects
+		}
 		if agReq.CGRReply, err = NewCGRReply(&initReply, err); err != nil {
 			return
 		}
@@ -282,6 +282,7 @@ func (da *DiameterAgent) processRequest(reqProcessor *config.DARequestProcessor,
 		var tRply string
 		err = da.sessionS.Call(utils.SessionSv1TerminateSession,
 			terminateArgs, &tRply)
+		da.remPeer(cgrEv.Event)
 		if agReq.CGRReply, err = NewCGRReply(nil, err); err != nil {
 			return
 		}
@@ -320,6 +321,10 @@ func (da *DiameterAgent) processRequest(reqProcessor *config.DARequestProcessor,
 	} else {
 		agReq.Reply.Merge(nM)
 	}
+	if reqProcessor.Flags.HasKey(utils.MetaGx) &&
+		reqType != utils.MetaDryRun && peer != nil {
+		da.trackGxSession(agReq, peer)
+	}
 	if reqType == utils.MetaDryRun {
 		utils.Logger.Info(
 			fmt.Sprintf("<%s> DRY_RUN, Diameter reply: %s",
@@ -327,3 +332,271 @@ func (da *DiameterAgent) processRequest(reqProcessor *config.DARequestProcessor,
 	}
 	return true, nil
 }
+
+// setPeer indexes the peer on the OriginID of the session
+func (da *DiameterAgent) setPeer(ev map[string]interface{}, peer *diamPeer) {
+	originID, _ := utils.IfaceAsString(ev[utils.OriginID])
+	if originID == "" || peer == nil {
+		return
+	}
+	da.pLck.Lock()
+	da.peers[originID] = peer
+	da.pLck.Unlock()
+	da.watchConn(peer.c)
+}
+
+// remPeer removes the peer indexed on the OriginID of the session
+func (da *DiameterAgent) remPeer(ev map[string]interface{}) {
+	originID, _ := utils.IfaceAsString(ev[utils.OriginID])
+	da.pLck.Lock()
+	delete(da.peers, originID)
+	da.pLck.Unlock()
+}
+
+// trackGxSession indexes the Gx session on initial and update requests
+// and removes it on termination
+func (da *DiameterAgent) trackGxSession(agReq *AgentRequest, peer *diamPeer) {
+	sessID, err := agReq.Request.FieldAsString([]string{diamSessionID})
+	if err != nil {
+		return
+	}
+	ccrType, _ := agReq.Request.FieldAsString([]string{diamCCRequestType})
+	da.gxLck.Lock()
+	switch ccrType {
+	case diamCCRInitial, diamCCRUpdate:
+		originHost, _ := agReq.Request.FieldAsString([]string{diamOriginHost})
+		originRealm, _ := agReq.Request.FieldAsString([]string{diamOriginRealm})
+		da.gxSessions[sessID] = &gxSession{peer: peer,
+			originHost: originHost, originRealm: originRealm}
+	case diamCCRTermination:
+		delete(da.gxSessions, sessID)
+	}
+	da.gxLck.Unlock()
+	if peer != nil {
+		da.watchConn(peer.c)
+	}
+}
+
+// remGxSession removes the Gx session terminated by the request,
+// independent of the outcome of processing it
+func (da *DiameterAgent) remGxSession(m *diam.Message) {
+	dP := newDADataProvider(m)
+	if ccrType, _ := dP.FieldAsString([]string{diamCCRequestType}); ccrType != diamCCRTermination {
+		return
+	}
+	sessID, err := dP.FieldAsString([]string{diamSessionID})
+	if err != nil {
+		return
+	}
+	da.gxLck.Lock()
+	delete(da.gxSessions, sessID)
+	da.gxLck.Unlock()
+}
+
+// watchConn removes the peers and Gx sessions indexed on the connection
+// once the peer disconnects
+func (da *DiameterAgent) watchConn(c diam.Conn) {
+	cn, canNotify := c.(diam.CloseNotifier)
+	if !canNotify {
+		return
+	}
+	da.cLck.Lock()
+	if _, has := da.conns[c]; has {
+		da.cLck.Unlock()
+		return
+	}
+	da.conns[c] = struct{}{}
+	da.cLck.Unlock()
+	go func() {
+		<-cn.CloseNotify()
+		da.remConn(c)
+	}()
+}
+
+// remConn removes all the references to a disconnected peer connection
+func (da *DiameterAgent) remConn(c diam.Conn) {
+	da.pLck.Lock()
+	for originID, peer := range da.peers {
+		if peer.c == c {
+			delete(da.peers, originID)
+		}
+	}
+	da.pLck.Unlock()
+	da.gxLck.Lock()
+	for sessID, gxSess := range da.gxSessions {
+		if gxSess.peer != nil && gxSess.peer.c == c {
+			delete(da.gxSessions, sessID)
+		}
+	}
+	da.gxLck.Unlock()
+	da.cLck.Lock()
+	delete(da.conns, c)
+	da.cLck.Unlock()
+}
+
+// handleAnswer passes the answers to the requests originated by us
+// back to the goroutine waiting for them
+func (da *DiameterAgent) handleAnswer(m *diam.Message) {
+	da.aLck.Lock()
+	ansChan, has := da.answers[m.Header.HopByHopID]
+	delete(da.answers, m.Header.HopByHopID)
+	da.aLck.Unlock()
+	if !has {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> ignoring unexpected answer: %s", utils.DiameterAgent, m))
+		return
+	}
+	ansChan <- m
+}
+
+// Call implements rpcclient.RpcClientConnection interface
+func (da *DiameterAgent) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	return utils.RPCCall(da, serviceMethod, args, reply)
+}
+
+// V1DisconnectSession is called by SessionS when a session needs to be terminated
+// sends an Abort-Session-Request or Re-Auth-Request towards the peer of the session
+func (da *DiameterAgent) V1DisconnectSession(args utils.AttrDisconnectSession, reply *string) (err error) {
+	daCfg := da.cgrCfg.DiameterAgentCfg()
+	cmdCode := uint32(diam.AbortSession)
+	if daCfg.DisconnectMethod == utils.MetaRAR {
+		cmdCode = diam.ReAuth
+	}
+	tpl, has := daCfg.Templates[daCfg.DisconnectMethod]
+	if !has {
+		return fmt.Errorf("no template with id: <%s>", daCfg.DisconnectMethod)
+	}
+	originID, _ := utils.IfaceAsString(args.EventStart[utils.OriginID])
+	da.pLck.RLock()
+	peer, has := da.peers[originID]
+	da.pLck.RUnlock()
+	if !has {
+		return fmt.Errorf("no peer connection for session: <%s>", originID)
+	}
+	agReq := newAgentRequest(newDADataProvider(peer.m),
+		map[string]interface{}{
+			utils.OriginHost:       daCfg.OriginHost,
+			utils.OriginRealm:      daCfg.OriginRealm,
+			utils.ProductName:      daCfg.ProductName,
+			utils.MetaAppID:        peer.m.Header.ApplicationID,
+			utils.DISCONNECT_CAUSE: args.Reason,
+		}, nil, nil,
+		da.cgrCfg.GeneralCfg().DefaultTenant,
+		da.cgrCfg.GeneralCfg().DefaultTimezone, da.filterS)
+	agReq.CGRRequest = config.NewNavigableMap(args.EventStart)
+	nM, err := agReq.AsNavigableMap(tpl)
+	if err != nil {
+		return
+	}
+	req := diam.NewRequest(cmdCode, peer.m.Header.ApplicationID, peer.m.Dictionary())
+	if err = updateDiamMsgFromNavMap(req, nM,
+		da.cgrCfg.GeneralCfg().DefaultTimezone); err != nil {
+		return
+	}
+	if err = da.sendRequest(peer.c, req); err != nil {
+		if err != utils.ErrTimedOut {
+			da.remPeer(args.EventStart) // connection is gone
+		}
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+// sendRequest writes the request originated by us on the peer connection
+// and waits for a successful answer
+func (da *DiameterAgent) sendRequest(c diam.Conn, req *diam.Message) (err error) {
+	ansChan := make(chan *diam.Message, 1)
+	da.aLck.Lock()
+	da.answers[req.Header.HopByHopID] = ansChan
+	da.aLck.Unlock()
+	defer func() {
+		da.aLck.Lock()
+		delete(da.answers, req.Header.HopByHopID)
+		da.aLck.Unlock()
+	}()
+	if _, err = req.WriteTo(c); err != nil {
+		return
+	}
+	select {
+	case ans := <-ansChan:
+		var rCode string
+		if rCode, err = diamAnswerResultCode(ans); err != nil {
+			return
+		}
+		if rCode != strconv.Itoa(diam.Success) {
+			return fmt.Errorf("unexpected Result-Code: <%s> from peer: <%s>",
+				rCode, c.RemoteAddr())
+		}
+	case <-time.After(da.cgrCfg.GeneralCfg().ReplyTimeout):
+		return utils.ErrTimedOut
+	}
+	return
+}
+
+// ArgsReAuthorizeGx identifies the Gx session to be re-authorized
+// together with the charging rules changed by the policy
+type ArgsReAuthorizeGx struct {
+	SessionID           string   // Session-Id of the Gx session
+	ChargingRuleInstall []string // Charging-Rule-Name of the rules to be installed
+	ChargingRuleRemove  []string // Charging-Rule-Name of the rules to be removed
+}
+
+// V1ReAuthorizeGx pushes the charging rules changed by the policy towards the peer
+// of a Gx session with a Re-Auth-Request
+func (da *DiameterAgent) V1ReAuthorizeGx(args ArgsReAuthorizeGx, reply *string) (err error) {
+	da.gxLck.RLock()
+	gxSess, has := da.gxSessions[args.SessionID]
+	da.gxLck.RUnlock()
+	if !has {
+		return utils.ErrNotFound
+	}
+	req, err := da.gxReAuthRequest(args, gxSess)
+	if err != nil {
+		return
+	}
+	if err = da.sendRequest(gxSess.peer.c, req); err != nil {
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+// gxReAuthRequest builds the Re-Auth-Request for a Gx session
+// out of the data stored on session initiation
+func (da *DiameterAgent) gxReAuthRequest(args ArgsReAuthorizeGx,
+	gxSess *gxSession) (req *diam.Message, err error) {
+	daCfg := da.cgrCfg.DiameterAgentCfg()
+	tmz := da.cgrCfg.GeneralCfg().DefaultTimezone
+	req = diam.NewRequest(diam.ReAuth, diamGxAppID, gxSess.peer.m.Dictionary())
+	for _, avpPathVal := range [][]string{ // path elements followed by the value
+		{diamSessionID, args.SessionID},
+		{diamOriginHost, daCfg.OriginHost},
+		{diamOriginRealm, daCfg.OriginRealm},
+		{diamDestinationHost, gxSess.originHost},
+		{diamDestinationRealm, gxSess.originRealm},
+		{diamAuthApplicationID, strconv.Itoa(diamGxAppID)},
+		{diamReAuthRequestType, diamReAuthAuthorizeOnly},
+	} {
+		if err = messageSetAVPsWithPath(req, avpPathVal[:len(avpPathVal)-1],
+			avpPathVal[len(avpPathVal)-1], false, tmz); err != nil {
+			return nil, err
+		}
+	}
+	for _, grpRules := range []struct {
+		grpAVP    string
+		ruleNames []string
+	}{
+		{diamChargingRuleInstall, args.ChargingRuleInstall},
+		{diamChargingRuleRemove, args.ChargingRuleRemove},
+	} {
+		for i, ruleName := range grpRules.ruleNames {
+			if err = messageSetAVPsWithPath(req,
+				[]string{grpRules.grpAVP, diamChargingRuleName}, ruleName,
+				i == 0, tmz); err != nil { // all the rules in one group
+				return nil, err
+			}
+		}
+	}
+	return
+}
@@ -36,6 +36,25 @@ import (
 	"github.com/fiorix/go-diameter/diam/dict"
 )
 
+const (
+	diamSessionID           = "Session-Id"
+	diamCCRequestType       = "CC-Request-Type"
+	diamCCRInitial          = "1" // CC-Request-Type values, RFC 4006
+	diamCCRUpdate           = "2"
+	diamCCRTermination      = "3"
+	diamOriginHost          = "Origin-Host"
+	diamOriginRealm         = "Origin-Realm"
+	diamDestinationHost     = "Destination-Host"
+	diamDestinationRealm    = "Destination-Realm"
+	diamAuthApplicationID   = "Auth-Application-Id"
+	diamReAuthRequestType   = "Re-Auth-Request-Type"
+	diamReAuthAuthorizeOnly = "0" // Re-Auth-Request-Type AUTHORIZE_ONLY, RFC 6733
+	diamChargingRuleInstall = "Charging-Rule-Install"
+	diamChargingRuleRemove  = "Charging-Rule-Remove"
+	diamChargingRuleName    = "Charging-Rule-Name"
+	diamGxAppID             = 16777238 // Gx Application-Id, 3GPP TS 29.212
+)
+
 func loadDictionaries(dictsDir, componentId string) error {
 	fi, err := os.Stat(dictsDir)
 	if err != nil {
@@ -192,7 +211,8 @@ func newDiamDataType(typ datatype.TypeID, valStr,
 }
 
 // messageAddAVPsWithPath will dynamically add AVPs into the message
-// 	append:	append to the message, on false overwrite if AVP is single or add to group if AVP is Grouped
+//
+//	append:	append to the message, on false overwrite if AVP is single or add to group if AVP is Grouped
 func messageSetAVPsWithPath(m *diam.Message, pathStr []string,
 	avpValStr string, newBranch bool, tmz string) (err error) {
 	if len(pathStr) == 0 {
@@ -256,6 +276,70 @@ func messageSetAVPsWithPath(m *diam.Message, pathStr []string,
 	return nil
 }
 
+// updateDiamMsgFromNavMap will update the diameter message with items from navigable map
+func updateDiamMsgFromNavMap(m *diam.Message, navMp *config.NavigableMap, tmz string) (err error) {
+	pathIdx := make(map[string]int) // group items for same path
+	for _, val := range navMp.Values() {
+		nmItms, isNMItems := val.([]*config.NMItem)
+		if !isNMItems {
+			return fmt.Errorf("cannot encode reply field: %s", utils.ToJSON(val))
+		}
+		// find out the first itm which is not an attribute
+		var itm *config.NMItem
+		if len(nmItms) == 1 {
+			itm = nmItms[0]
+		} else { // only for groups
+			for i, cfgItm := range nmItms {
+				itmPath := strings.Join(cfgItm.Path, utils.NestingSep)
+				if i == 0 { // path is common, increase it only once
+					pathIdx[itmPath] += 1
+				}
+				if i == pathIdx[itmPath]-1 { // revert from multiple items to only one per config path
+					itm = cfgItm
+					break
+				}
+			}
+		}
+		if itm == nil {
+			continue // all attributes, not writable to diameter packet
+		}
+		var newBranch bool
+		if itm.Config != nil && itm.Config.NewBranch {
+			newBranch = true
+		}
+		if itmVals, isList := itm.Data.([]string); isList { // one AVP for each value
+			for i, itmVal := range itmVals {
+				if i != 0 { // next values are added into the same group or after the first AVP
+					newBranch = len(itm.Path) == 1
+				}
+				if err := messageSetAVPsWithPath(m, itm.Path,
+					itmVal, newBranch, tmz); err != nil {
+					return fmt.Errorf("setting item: %s, err: %s", utils.ToJSON(itm), err.Error())
+				}
+			}
+			continue
+		}
+		itmStr, err := utils.IfaceAsString(itm.Data)
+		if err != nil {
+			return fmt.Errorf("cannot convert item: %s, err: %s", utils.ToJSON(itm), err.Error())
+		}
+		if err := messageSetAVPsWithPath(m, itm.Path,
+			itmStr, newBranch, tmz); err != nil {
+			return fmt.Errorf("setting item: %s, err: %s", utils.ToJSON(itm), err.Error())
+		}
+	}
+	return
+}
+
+// diamAnswerResultCode returns the Result-Code out of an answer message
+func diamAnswerResultCode(m *diam.Message) (rCode string, err error) {
+	rCodeAVP, err := m.FindAVP(avp.ResultCode, dict.UndefinedVendorID)
+	if err != nil {
+		return
+	}
+	return diamAVPAsString(rCodeAVP)
+}
+
 // writeOnConn writes the message on connection, logs failures
 func writeOnConn(c diam.Conn, m *diam.Message) {
 	if _, err := m.WriteTo(c); err != nil {
@@ -21,10 +21,14 @@ package agents
 import (
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
 	"github.com/fiorix/go-diameter/diam"
 	"github.com/fiorix/go-diameter/diam/avp"
 	"github.com/fiorix/go-diameter/diam/datatype"
+	"github.com/fiorix/go-diameter/diam/dict"
 )
 
 func TestDPFieldAsInterface(t *testing.T) {
@@ -204,3 +208,193 @@ func TestMessageSetAVPsWithPath2(t *testing.T) {
 		t.Errorf("Expecting: %+v, received: %+v", eMessage, m)
 	}
 }
+
+func TestUpdateDiamMsgFromNavMap(t *testing.T) {
+	eMessage := diam.NewRequest(diam.AbortSession, 4, nil)
+	eMessage.NewAVP("Session-Id", avp.Mbit, 0,
+		datatype.UTF8String("simuhuawei;1449573472;00001"))
+	eMessage.NewAVP(avp.OriginHost, avp.Mbit, 0,
+		datatype.DiameterIdentity("CGR-DA"))
+	m := diam.NewMessage(diam.AbortSession, diam.RequestFlag, 4,
+		eMessage.Header.HopByHopID, eMessage.Header.EndToEndID, nil)
+	nM := config.NewNavigableMap(nil)
+	nM.Set([]string{"Session-Id"}, []*config.NMItem{
+		{Path: []string{"Session-Id"}, Data: "simuhuawei;1449573472;00001"}}, true)
+	nM.Set([]string{"Origin-Host"}, []*config.NMItem{
+		{Path: []string{"Origin-Host"}, Data: "CGR-DA"}}, true)
+	if err := updateDiamMsgFromNavMap(m, nM, "UTC"); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eMessage.AVP, m.AVP) {
+		t.Errorf("Expecting: %+v, received: %+v", eMessage, m)
+	}
+	nM.Set([]string{"Destination-Host"}, "unsupported", true)
+	if err := updateDiamMsgFromNavMap(m, nM, "UTC"); err == nil {
+		t.Error("expecting error for non NMItems value")
+	}
+}
+
+func TestDiamAnswerResultCode(t *testing.T) {
+	m := diam.NewRequest(diam.AbortSession, 4, nil)
+	if _, err := diamAnswerResultCode(m); err == nil {
+		t.Error("expecting error for missing Result-Code")
+	}
+	a := m.Answer(diam.Success)
+	if rCode, err := diamAnswerResultCode(a); err != nil {
+		t.Error(err)
+	} else if rCode != "2001" {
+		t.Errorf("expecting: 2001, received: %s", rCode)
+	}
+}
+
+func TestDiameterAgentV1DisconnectSessionNoPeer(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	da := &DiameterAgent{cgrCfg: cfg, peers: make(map[string]*diamPeer)}
+	var reply string
+	if err := da.V1DisconnectSession(utils.AttrDisconnectSession{
+		EventStart: map[string]interface{}{
+			utils.OriginID: "simuhuawei;1449573472;00001",
+			utils.Account:  "1001",
+		},
+		Reason: utils.ErrInsufficientCredit.Error()}, &reply); err == nil ||
+		err.Error() != "no peer connection for session: <simuhuawei;1449573472;00001>" {
+		t.Errorf("received error: %v", err)
+	}
+}
+
+func TestDiameterAgentTrackGxSession(t *testing.T) {
+	da := &DiameterAgent{gxSessions: make(map[string]*gxSession)}
+	m := diam.NewRequest(diam.CreditControl, 16777238, nil)
+	m.NewAVP("Session-Id", avp.Mbit, 0,
+		datatype.UTF8String("pcef1;1449573472;00001"))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("pcef1.example.com"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("example.com"))
+	m.NewAVP(avp.CCRequestType, avp.Mbit, 0, datatype.Enumerated(1))
+	peer := &diamPeer{m: m}
+	da.trackGxSession(
+		newAgentRequest(newDADataProvider(m), nil, nil, nil,
+			"cgrates.org", "", nil), peer)
+	eGxSess := &gxSession{peer: peer,
+		originHost: "pcef1.example.com", originRealm: "example.com"}
+	if gxSess, has := da.gxSessions["pcef1;1449573472;00001"]; !has {
+		t.Error("Gx session not tracked")
+	} else if !reflect.DeepEqual(eGxSess, gxSess) {
+		t.Errorf("expecting: %+v, received: %+v", eGxSess, gxSess)
+	}
+	mT := diam.NewRequest(diam.CreditControl, 16777238, nil)
+	mT.NewAVP("Session-Id", avp.Mbit, 0,
+		datatype.UTF8String("pcef1;1449573472;00001"))
+	mT.NewAVP(avp.CCRequestType, avp.Mbit, 0, datatype.Enumerated(3))
+	da.trackGxSession(
+		newAgentRequest(newDADataProvider(mT), nil, nil, nil,
+			"cgrates.org", "", nil), &diamPeer{m: mT})
+	if _, has := da.gxSessions["pcef1;1449573472;00001"]; has {
+		t.Error("Gx session not removed on termination")
+	}
+	da.gxSessions["pcef1;1449573472;00001"] = eGxSess
+	da.remGxSession(mT)
+	if _, has := da.gxSessions["pcef1;1449573472;00001"]; has {
+		t.Error("Gx session not removed on termination")
+	}
+	var reply string
+	if err := da.V1ReAuthorizeGx(ArgsReAuthorizeGx{
+		SessionID: "pcef1;1449573472;00001"}, &reply); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+// closingDiamConn is a diam.Conn which notifies on close
+type closingDiamConn struct {
+	diam.Conn
+	closed chan struct{}
+}
+
+func (c *closingDiamConn) CloseNotify() <-chan struct{} {
+	return c.closed
+}
+
+func TestDiameterAgentRemConnOnClose(t *testing.T) {
+	da := &DiameterAgent{peers: make(map[string]*diamPeer),
+		gxSessions: make(map[string]*gxSession),
+		conns:      make(map[diam.Conn]struct{})}
+	c := &closingDiamConn{closed: make(chan struct{})}
+	da.setPeer(map[string]interface{}{utils.OriginID: "session1"}, &diamPeer{c: c})
+	m := diam.NewRequest(diam.CreditControl, 16777238, nil)
+	m.NewAVP("Session-Id", avp.Mbit, 0,
+		datatype.UTF8String("pcef1;1449573472;00001"))
+	m.NewAVP(avp.CCRequestType, avp.Mbit, 0, datatype.Enumerated(1))
+	da.trackGxSession(
+		newAgentRequest(newDADataProvider(m), nil, nil, nil,
+			"cgrates.org", "", nil), &diamPeer{c: c, m: m})
+	if len(da.conns) != 1 {
+		t.Errorf("expecting one watched connection, received: %+v", da.conns)
+	}
+	close(c.closed)
+	time.Sleep(10 * time.Millisecond)
+	da.pLck.RLock()
+	if len(da.peers) != 0 {
+		t.Errorf("peers not removed: %+v", da.peers)
+	}
+	da.pLck.RUnlock()
+	da.gxLck.RLock()
+	if len(da.gxSessions) != 0 {
+		t.Errorf("Gx sessions not removed: %+v", da.gxSessions)
+	}
+	da.gxLck.RUnlock()
+	da.cLck.Lock()
+	if len(da.conns) != 0 {
+		t.Errorf("connection still watched: %+v", da.conns)
+	}
+	da.cLck.Unlock()
+}
+
+func TestDiameterAgentGxReAuthRequest(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	da := &DiameterAgent{cgrCfg: cfg}
+	m := diam.NewRequest(diam.CreditControl, 16777238, nil)
+	gxSess := &gxSession{peer: &diamPeer{m: m},
+		originHost: "pcef1.example.com", originRealm: "example.com"}
+	req, err := da.gxReAuthRequest(ArgsReAuthorizeGx{
+		SessionID: "pcef1;1449573472;00001"}, gxSess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.CommandCode != diam.ReAuth ||
+		req.Header.ApplicationID != 16777238 {
+		t.Errorf("unexpected header: %+v", req.Header)
+	}
+	dP := newDADataProvider(req)
+	for fld, eVal := range map[string]string{
+		"Session-Id":           "pcef1;1449573472;00001",
+		"Origin-Host":          cfg.DiameterAgentCfg().OriginHost,
+		"Origin-Realm":         cfg.DiameterAgentCfg().OriginRealm,
+		"Destination-Host":     "pcef1.example.com",
+		"Destination-Realm":    "example.com",
+		"Auth-Application-Id":  "16777238",
+		"Re-Auth-Request-Type": "0",
+	} {
+		if val, err := dP.FieldAsString([]string{fld}); err != nil {
+			t.Errorf("field: %s, err: %v", fld, err)
+		} else if val != eVal {
+			t.Errorf("field: %s, expecting: %s, received: %s", fld, eVal, val)
+		}
+	}
+}
+
+func TestUpdateDiamMsgFromNavMapList(t *testing.T) {
+	m := diam.NewRequest(diam.ReAuth, 4, nil)
+	nM := config.NewNavigableMap(nil)
+	nM.Set([]string{"Subscription-Id", "Subscription-Id-Data"}, []*config.NMItem{
+		{Path: []string{"Subscription-Id", "Subscription-Id-Data"},
+			Data: []string{"1001", "1002"}}}, true)
+	if err := updateDiamMsgFromNavMap(m, nM, "UTC"); err != nil {
+		t.Fatal(err)
+	}
+	if avps, err := m.FindAVPsWithPath([]interface{}{"Subscription-Id"},
+		dict.UndefinedVendorID); err != nil {
+		t.Error(err)
+	} else if len(avps) != 1 {
+		t.Errorf("expecting one group, received: %+v", avps)
+	} else if grpAVPs := avps[0].Data.(*diam.GroupedAVP).AVP; len(grpAVPs) != 2 {
+		t.Errorf("expecting 2 AVPs in group, received: %+v", grpAVPs)
+	}
+}
@@ -19,6 +19,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package agents
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
@@ -41,7 +42,8 @@ func newHADataProvider(reqPayload string,
 		return newHTTPUrlDP(req)
 	case utils.MetaXml:
 		return newHTTPXmlDP(req)
-
+	case utils.MetaJSON:
+		return newHTTPJsonDP(req)
 	}
 }
 
@@ -185,6 +187,81 @@ func (hU *httpXmlDP) AsNavigableMap([]*config.FCTemplate) (
 	return nil, utils.ErrNotImplemented
 }
 
+func newHTTPJsonDP(req *http.Request) (dP config.DataProvider, err error) {
+	jsnData := make(map[string]interface{})
+	if err = json.NewDecoder(req.Body).Decode(&jsnData); err != nil {
+		return nil, err
+	}
+	dP = &httpJsonDP{data: jsnData}
+	return
+}
+
+// httpJsonDP implements engine.DataProvider, serving as json data decoder
+// the body is decoded once, fields being searched in the decoded data
+type httpJsonDP struct {
+	data map[string]interface{}
+}
+
+// String is part of engine.DataProvider interface
+func (hJ *httpJsonDP) String() string {
+	return utils.ToJSON(hJ.data)
+}
+
+// FieldAsInterface is part of engine.DataProvider interface
+// list elements are selected with index, ie: Items[0]
+func (hJ *httpJsonDP) FieldAsInterface(fldPath []string) (data interface{}, err error) {
+	if len(fldPath) == 0 {
+		return nil, fmt.Errorf("Empty path")
+	}
+	data = hJ.data
+	for _, spath := range fldPath {
+		idx := -1
+		if sIdx := strings.Index(spath, "["); sIdx != -1 {
+			if spath[len(spath)-1:] != "]" {
+				return nil, fmt.Errorf("filter rule <%s> needs to end in ]", spath[sIdx:])
+			}
+			if idx, err = strconv.Atoi(spath[sIdx+1 : len(spath)-1]); err != nil {
+				return nil, err
+			}
+			spath = spath[:sIdx]
+		}
+		mp, canCast := data.(map[string]interface{})
+		if !canCast {
+			return nil, utils.ErrNotFound
+		}
+		var has bool
+		if data, has = mp[spath]; !has {
+			return nil, utils.ErrNotFound
+		}
+		if idx == -1 {
+			continue
+		}
+		lst, canCast := data.([]interface{})
+		if !canCast || idx < 0 || idx >= len(lst) {
+			return nil, utils.ErrNotFound
+		}
+		data = lst[idx]
+	}
+	return
+}
+
+// FieldAsString is part of engine.DataProvider interface
+func (hJ *httpJsonDP) FieldAsString(fldPath []string) (data string, err error) {
+	var valIface interface{}
+	valIface, err = hJ.FieldAsInterface(fldPath)
+	if err != nil {
+		return
+	}
+	data, err = utils.IfaceAsString(valIface)
+	return
+}
+
+// AsNavigableMap is part of engine.DataProvider interface
+func (hJ *httpJsonDP) AsNavigableMap([]*config.FCTemplate) (
+	nm *config.NavigableMap, err error) {
+	return nil, utils.ErrNotImplemented
+}
+
 // httpAgentReplyEncoder will encode  []*engine.NMElement
 // and write content to http writer
 type httpAgentReplyEncoder interface {
@@ -199,6 +276,8 @@ func newHAReplyEncoder(encType string,
 		return nil, fmt.Errorf("unsupported encoder type <%s>", encType)
 	case utils.MetaXml:
 		return newHAXMLEncoder(w)
+	case utils.MetaJSON:
+		return newHAJSONEncoder(w)
 	}
 }
 
@@ -229,3 +308,22 @@ func (xE *haXMLEncoder) Encode(nM *config.NavigableMap) (err error) {
 	_, err = xE.w.Write(xmlOut)
 	return
 }
+
+func newHAJSONEncoder(w http.ResponseWriter) (jE httpAgentReplyEncoder, err error) {
+	return &haJSONEncoder{w: w}, nil
+}
+
+type haJSONEncoder struct {
+	w http.ResponseWriter
+}
+
+// Encode implements httpAgentReplyEncoder
+func (jE *haJSONEncoder) Encode(nM *config.NavigableMap) (err error) {
+	var jsnOut []byte
+	if jsnOut, err = json.Marshal(nM.AsMapStringInterface()); err != nil {
+		return
+	}
+	jE.w.Header().Set("Content-Type", "application/json")
+	_, err = jE.w.Write(jsnOut)
+	return
+}
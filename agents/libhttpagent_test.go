@@ -22,9 +22,12 @@ import (
 	"bufio"
 	"bytes"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
-	//"github.com/cgrates/cgrates/utils"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
 )
 
 func TestHttpUrlDPFieldAsInterface(t *testing.T) {
@@ -131,3 +134,72 @@ func TestHttpXmlDPFieldAsInterface(t *testing.T) {
 		t.Errorf("expecting: 37, received: <%s>", data)
 	}
 }
+
+func TestHttpJsonDPFieldAsInterface(t *testing.T) {
+	body := `{
+	"Subscriber": {
+		"MSISDN": "+4977000000000",
+		"Balance": 10.5
+	},
+	"Calls": [
+		{"Destination": "+497700000001", "Seconds": 38},
+		{"Destination": "+497700000002", "Seconds": 37}
+	]
+}`
+	req, err := http.NewRequest("POST", "http://localhost:8080/", bytes.NewBuffer([]byte(body)))
+	if err != nil {
+		t.Error(err)
+	}
+	dP, err := newHTTPJsonDP(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := dP.FieldAsString([]string{"Subscriber", "MSISDN"}); err != nil {
+		t.Error(err)
+	} else if data != "+4977000000000" {
+		t.Errorf("expecting: +4977000000000, received: <%s>", data)
+	}
+	if data, err := dP.FieldAsString([]string{"Subscriber", "Balance"}); err != nil {
+		t.Error(err)
+	} else if data != "10.5" {
+		t.Errorf("expecting: 10.5, received: <%s>", data)
+	}
+	if data, err := dP.FieldAsString([]string{"Calls[1]", "Seconds"}); err != nil {
+		t.Error(err)
+	} else if data != "37" {
+		t.Errorf("expecting: 37, received: <%s>", data)
+	}
+	if _, err := dP.FieldAsString([]string{"Calls[2]", "Seconds"}); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if _, err := dP.FieldAsString([]string{"Subscriber", "IMSI"}); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	req, _ = http.NewRequest("POST", "http://localhost:8080/", bytes.NewBuffer([]byte("<xml/>")))
+	if _, err := newHTTPJsonDP(req); err == nil {
+		t.Error("expecting decoding error")
+	}
+}
+
+func TestHAJSONEncoder(t *testing.T) {
+	nM := config.NewNavigableMap(nil)
+	nM.Set([]string{"Result"}, []*config.NMItem{
+		&config.NMItem{Path: []string{"Result"}, Data: "OK"}}, true)
+	nM.Set([]string{"Subscriber", "MaxUsage"}, []*config.NMItem{
+		&config.NMItem{Path: []string{"Subscriber", "MaxUsage"}, Data: 120}}, true)
+	w := httptest.NewRecorder()
+	jE, err := newHAReplyEncoder(utils.MetaJSON, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := jE.Encode(nM); err != nil {
+		t.Error(err)
+	}
+	eOut := `{"Result":"OK","Subscriber":{"MaxUsage":120}}`
+	if rcv := w.Body.String(); rcv != eOut {
+		t.Errorf("expecting: %s, received: %s", eOut, rcv)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("unexpected Content-Type: %s", ct)
+	}
+}
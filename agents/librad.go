@@ -189,3 +189,12 @@ func (pk *radiusDP) AsNavigableMap([]*config.FCTemplate) (
 	nm *config.NavigableMap, err error) {
 	return nil, utils.ErrNotImplemented
 }
+
+// radClientValue returns the value configured for the client, falling back on *default
+func radClientValue(clntVals map[string]string, clntID string) (val string, has bool) {
+	if val, has = clntVals[clntID]; has {
+		return
+	}
+	val, has = clntVals[utils.META_DEFAULT]
+	return
+}
@@ -219,3 +219,90 @@ func TestRadiusDPFieldAsString(t *testing.T) {
 		t.Errorf("Expecting: flopsy, received: <%s>", data)
 	}
 }
+
+func TestRadClientValue(t *testing.T) {
+	clntVals := map[string]string{
+		utils.META_DEFAULT: "127.0.0.1:3799",
+		"192.168.56.203":   "192.168.56.203:1700",
+	}
+	if val, has := radClientValue(clntVals, "192.168.56.203"); !has {
+		t.Error("value not found")
+	} else if val != "192.168.56.203:1700" {
+		t.Errorf("expecting: 192.168.56.203:1700, received: %s", val)
+	}
+	if val, has := radClientValue(clntVals, "192.168.56.204"); !has {
+		t.Error("value not found")
+	} else if val != "127.0.0.1:3799" {
+		t.Errorf("expecting: 127.0.0.1:3799, received: %s", val)
+	}
+	if _, has := radClientValue(map[string]string{}, "192.168.56.204"); has {
+		t.Error("not expecting value")
+	}
+}
+
+func TestRadiusAgentV1DisconnectSessionNoDAAddress(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	ra := &RadiusAgent{cgrCfg: cfg}
+	var reply string
+	if err := ra.V1DisconnectSession(utils.AttrDisconnectSession{
+		EventStart: map[string]interface{}{
+			utils.OriginHost: "192.168.56.203",
+			utils.OriginID:   "dsafdsaf",
+			utils.Account:    "1001",
+		},
+		Reason: utils.ErrInsufficientCredit.Error()}, &reply); err == nil ||
+		err.Error() != "no dynamic authorization address for client: <192.168.56.203>" {
+		t.Errorf("received error: %v", err)
+	}
+}
+
+func TestRadiusAgentV1DisconnectSession(t *testing.T) {
+	daAddr := "127.0.0.1:37990"
+	dicts := map[string]*radigo.Dictionary{utils.META_DEFAULT: dictRad}
+	secrets := map[string]string{utils.META_DEFAULT: "CGRateS.org"}
+	usrNames := make(chan string, 2)
+	daSrv := radigo.NewServer(utils.UDP, daAddr, radigo.NewSecrets(secrets),
+		radigo.NewDictionaries(dicts),
+		map[radigo.PacketCode]func(*radigo.Packet) (*radigo.Packet, error){
+			radDisconnectRequest: func(req *radigo.Packet) (*radigo.Packet, error) {
+				req.SetAVPValues()
+				if avps := req.AttributesWithName("User-Name", ""); len(avps) != 0 {
+					usrNames <- avps[0].GetStringValue()
+				}
+				rpl := req.Reply()
+				rpl.Code = radDisconnectACK
+				return rpl, nil
+			}}, nil)
+	go daSrv.ListenAndServe()
+	time.Sleep(50 * time.Millisecond)
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.RadiusAgentCfg().ClientDaAddresses = map[string]string{utils.META_DEFAULT: daAddr}
+	cfg.RadiusAgentCfg().ClientSecrets = secrets
+	ra := &RadiusAgent{cgrCfg: cfg, dicts: dicts}
+	args := utils.AttrDisconnectSession{
+		EventStart: map[string]interface{}{
+			utils.OriginHost: "127.0.0.1",
+			utils.OriginID:   "dsafdsaf",
+			utils.Account:    "1001",
+		},
+		Reason: utils.ErrInsufficientCredit.Error()}
+	for i := 0; i < 2; i++ {
+		var reply string
+		if err := ra.V1DisconnectSession(args, &reply); err != nil {
+			t.Fatal(err)
+		} else if reply != utils.OK {
+			t.Errorf("received reply: %s", reply)
+		}
+		select {
+		case usrName := <-usrNames:
+			if usrName != "1001" {
+				t.Errorf("expecting User-Name: 1001, received: %s", usrName)
+			}
+		case <-time.After(time.Second):
+			t.Error("no Disconnect-Request received")
+		}
+	}
+	if len(ra.daClnts) != 1 {
+		t.Errorf("expecting one client reused, received: %d", len(ra.daClnts))
+	}
+}
@@ -0,0 +1,288 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+const (
+	promContentType = "text/plain; version=0.0.4; charset=utf-8"
+	promGauge       = "gauge"
+	promCounter     = "counter"
+	promSummary     = "summary"
+)
+
+// NewPrometheusAgent will construct a PrometheusAgent
+// nil connections and metrics disable the matching part of the export
+func NewPrometheusAgent(cfg *config.PrometheusAgentCfg, dfltTenant string,
+	statS, resS, sessionS, cacheS rpcclient.RpcClientConnection,
+	rpcMtrs *utils.RPCMetrics) *PrometheusAgent {
+	return &PrometheusAgent{cfg: cfg, dfltTenant: dfltTenant,
+		statS: statS, resS: resS, sessionS: sessionS, cacheS: cacheS,
+		rpcMtrs: rpcMtrs}
+}
+
+// PrometheusAgent serves the metrics of the engine in Prometheus text exposition format
+type PrometheusAgent struct {
+	cfg        *config.PrometheusAgentCfg
+	dfltTenant string
+	statS      rpcclient.RpcClientConnection
+	resS       rpcclient.RpcClientConnection
+	sessionS   rpcclient.RpcClientConnection
+	cacheS     rpcclient.RpcClientConnection
+	rpcMtrs    *utils.RPCMetrics
+}
+
+// ServeHTTP implements http.Handler interface
+// errors out of one subsystem are logged and do not stop exporting the others
+func (pa *PrometheusAgent) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	pw := new(promWriter)
+	if pa.statS != nil {
+		if err := pa.writeStatMetrics(pw); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s querying %s",
+					utils.PrometheusAgent, err.Error(), utils.StatS))
+		}
+	}
+	if pa.resS != nil {
+		if err := pa.writeResourceMetrics(pw); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s querying %s",
+					utils.PrometheusAgent, err.Error(), utils.ResourceS))
+		}
+	}
+	if pa.sessionS != nil {
+		if err := pa.writeSessionMetrics(pw); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s querying %s",
+					utils.PrometheusAgent, err.Error(), utils.SessionS))
+		}
+	}
+	if pa.cacheS != nil {
+		if err := pa.writeCacheMetrics(pw); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s querying %s",
+					utils.PrometheusAgent, err.Error(), utils.CacheS))
+		}
+	}
+	if pa.rpcMtrs != nil {
+		pa.writeRPCMetrics(pw)
+	}
+	w.Header().Set("Content-Type", promContentType)
+	if _, err := w.Write(pw.Bytes()); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s writing the reply",
+				utils.PrometheusAgent, err.Error()))
+	}
+}
+
+// writeStatMetrics exports the float value of each StatQueue metric
+func (pa *PrometheusAgent) writeStatMetrics(pw *promWriter) (err error) {
+	sqIDs := pa.cfg.StatQueueIDs
+	if len(sqIDs) == 0 {
+		if err = pa.statS.Call(utils.StatSv1GetQueueIDs,
+			pa.dfltTenant, &sqIDs); err != nil {
+			if err.Error() == utils.ErrNotFound.Error() {
+				err = nil
+			}
+			return
+		}
+		sort.Strings(sqIDs)
+	}
+	var smpls []*promSample
+	for _, sqID := range sqIDs {
+		var mtrs map[string]float64
+		if err = pa.statS.Call(utils.StatSv1GetQueueFloatMetrics,
+			&utils.TenantID{Tenant: pa.dfltTenant, ID: sqID}, &mtrs); err != nil {
+			return
+		}
+		mtrIDs := make([]string, 0, len(mtrs))
+		for mtrID := range mtrs {
+			mtrIDs = append(mtrIDs, mtrID)
+		}
+		sort.Strings(mtrIDs)
+		for _, mtrID := range mtrIDs {
+			if mtrs[mtrID] == engine.STATS_NA { // not enough items in the queue
+				continue
+			}
+			smpls = append(smpls, &promSample{
+				labels: []string{"tenant", pa.dfltTenant, "queue_id", sqID, "metric", mtrID},
+				value:  mtrs[mtrID]})
+		}
+	}
+	pw.writeFamily("cgrates_stat_queue_metric",
+		"Value of the StatQueue metric", promGauge, smpls)
+	return
+}
+
+// writeResourceMetrics exports the usage of each configured Resource against its limit
+func (pa *PrometheusAgent) writeResourceMetrics(pw *promWriter) (err error) {
+	var usages, limits []*promSample
+	for _, rsID := range pa.cfg.ResourceIDs {
+		var rs engine.ResourceWithConfig
+		if err = pa.resS.Call(utils.ResourceSv1GetResourceWithConfig,
+			utils.TenantID{Tenant: pa.dfltTenant, ID: rsID}, &rs); err != nil {
+			return
+		}
+		lbls := []string{"tenant", pa.dfltTenant, "resource_id", rsID}
+		usages = append(usages, &promSample{labels: lbls, value: rs.TotalUsage})
+		if rs.Config != nil {
+			limits = append(limits, &promSample{labels: lbls, value: rs.Config.Limit})
+		}
+	}
+	pw.writeFamily("cgrates_resource_usage",
+		"Units allocated out of the Resource", promGauge, usages)
+	pw.writeFamily("cgrates_resource_limit",
+		"Units available in the Resource", promGauge, limits)
+	return
+}
+
+// writeSessionMetrics exports the number of active and passive sessions
+func (pa *PrometheusAgent) writeSessionMetrics(pw *promWriter) (err error) {
+	var actvCnt, psvCnt int
+	if err = pa.sessionS.Call(utils.SessionSv1GetActiveSessionsCount,
+		map[string]string{}, &actvCnt); err != nil {
+		return
+	}
+	if err = pa.sessionS.Call(utils.SessionSv1GetPassiveSessionsCount,
+		map[string]string{}, &psvCnt); err != nil {
+		return
+	}
+	pw.writeFamily("cgrates_sessions_active",
+		"Sessions active in SessionS", promGauge,
+		[]*promSample{{value: float64(actvCnt)}})
+	pw.writeFamily("cgrates_sessions_passive",
+		"Sessions passive in SessionS", promGauge,
+		[]*promSample{{value: float64(psvCnt)}})
+	return
+}
+
+// writeCacheMetrics exports the items, groups, hits and misses of each cache partition
+func (pa *PrometheusAgent) writeCacheMetrics(pw *promWriter) (err error) {
+	var chStats map[string]*engine.CacheStats
+	if err = pa.cacheS.Call(utils.CacheSv1GetCacheHitStats,
+		[]string{}, &chStats); err != nil {
+		return
+	}
+	chIDs := make([]string, 0, len(chStats))
+	for chID := range chStats {
+		chIDs = append(chIDs, chID)
+	}
+	sort.Strings(chIDs)
+	var items, groups, hits, misses []*promSample
+	for _, chID := range chIDs {
+		lbls := []string{"cache", chID}
+		items = append(items, &promSample{labels: lbls, value: float64(chStats[chID].Items)})
+		groups = append(groups, &promSample{labels: lbls, value: float64(chStats[chID].Groups)})
+		hits = append(hits, &promSample{labels: lbls, value: float64(chStats[chID].Hits)})
+		misses = append(misses, &promSample{labels: lbls, value: float64(chStats[chID].Misses)})
+	}
+	pw.writeFamily("cgrates_cache_items",
+		"Items in the cache partition", promGauge, items)
+	pw.writeFamily("cgrates_cache_groups",
+		"Groups in the cache partition", promGauge, groups)
+	pw.writeFamily("cgrates_cache_hits_total",
+		"Reads served out of the cache partition", promCounter, hits)
+	pw.writeFamily("cgrates_cache_misses_total",
+		"Reads not found in the cache partition", promCounter, misses)
+	return
+}
+
+// writeRPCMetrics exports the number of API calls, errors and their duration per method
+func (pa *PrometheusAgent) writeRPCMetrics(pw *promWriter) {
+	mms := pa.rpcMtrs.MethodMetrics()
+	methods := make([]string, 0, len(mms))
+	for method := range mms {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	var errs, durs []*promSample
+	for _, method := range methods {
+		lbls := []string{"method", method}
+		errs = append(errs, &promSample{labels: lbls, value: float64(mms[method].Errors)})
+		durs = append(durs,
+			&promSample{suffix: "_sum", labels: lbls, value: mms[method].Duration.Seconds()},
+			&promSample{suffix: "_count", labels: lbls, value: float64(mms[method].Calls)})
+	}
+	pw.writeFamily("cgrates_rpc_errors_total",
+		"API calls replied with error", promCounter, errs)
+	pw.writeFamily("cgrates_rpc_duration_seconds",
+		"Duration of the API calls", promSummary, durs)
+}
+
+// promSample is one line in a metric family
+type promSample struct {
+	suffix string   // appended to the family name, ie: _sum for summaries
+	labels []string // label names followed by their values
+	value  float64
+}
+
+// promWriter builds the text exposition format
+type promWriter struct {
+	bytes.Buffer
+}
+
+// writeFamily writes the HELP and TYPE of the metric followed by its samples
+// families without samples are not written
+func (pw *promWriter) writeFamily(name, help, typ string, smpls []*promSample) {
+	if len(smpls) == 0 {
+		return
+	}
+	fmt.Fprintf(pw, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	for _, smpl := range smpls {
+		pw.WriteString(name + smpl.suffix)
+		if len(smpl.labels) != 0 {
+			lbls := make([]string, 0, len(smpl.labels)/2)
+			for i := 0; i+1 < len(smpl.labels); i += 2 {
+				lbls = append(lbls,
+					smpl.labels[i]+`="`+promLabelEscaper.Replace(smpl.labels[i+1])+`"`)
+			}
+			pw.WriteString("{" + strings.Join(lbls, ",") + "}")
+		}
+		pw.WriteString(" " + promFloat(smpl.value) + "\n")
+	}
+}
+
+// promLabelEscaper escapes the label values as required by the text format
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// promFloat formats the value as understood by Prometheus
+func promFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
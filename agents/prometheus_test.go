@@ -0,0 +1,128 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/ltcache"
+	"github.com/cgrates/rpcclient"
+)
+
+// promTestConn replies to the calls issued by the PrometheusAgent
+type promTestConn struct{}
+
+func (*promTestConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	switch serviceMethod {
+	case utils.StatSv1GetQueueIDs:
+		*(reply.(*[]string)) = []string{"SQ_2", "SQ_1"}
+	case utils.StatSv1GetQueueFloatMetrics:
+		if args.(*utils.TenantID).ID == "SQ_1" {
+			*(reply.(*map[string]float64)) = map[string]float64{
+				utils.MetaASR: 66.67, utils.MetaACD: 90}
+		} else {
+			*(reply.(*map[string]float64)) = map[string]float64{
+				utils.MetaTCC: engine.STATS_NA}
+		}
+	case utils.ResourceSv1GetResourceWithConfig:
+		*(reply.(*engine.ResourceWithConfig)) = engine.ResourceWithConfig{
+			Resource:   &engine.Resource{Tenant: "cgrates.org", ID: args.(utils.TenantID).ID},
+			TotalUsage: 3,
+			Config:     &engine.ResourceProfile{Limit: 10},
+		}
+	case utils.SessionSv1GetActiveSessionsCount:
+		*(reply.(*int)) = 5
+	case utils.SessionSv1GetPassiveSessionsCount:
+		*(reply.(*int)) = 1
+	case utils.CacheSv1GetCacheHitStats:
+		*(reply.(*map[string]*engine.CacheStats)) = map[string]*engine.CacheStats{
+			utils.CacheFilters: {CacheStats: ltcache.CacheStats{Items: 2, Groups: 1},
+				Hits: 10, Misses: 4},
+		}
+	default:
+		return rpcclient.ErrUnsupporteServiceMethod
+	}
+	return nil
+}
+
+func TestPrometheusAgentServeHTTP(t *testing.T) {
+	conn := new(promTestConn)
+	pa := NewPrometheusAgent(&config.PrometheusAgentCfg{
+		ResourceIDs: []string{"RES_1"}}, "cgrates.org",
+		conn, conn, conn, conn, nil)
+	w := httptest.NewRecorder()
+	pa.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if cType := w.Header().Get("Content-Type"); cType != promContentType {
+		t.Errorf("expecting: %s, received: %s", promContentType, cType)
+	}
+	eOut := `# HELP cgrates_stat_queue_metric Value of the StatQueue metric
+# TYPE cgrates_stat_queue_metric gauge
+cgrates_stat_queue_metric{tenant="cgrates.org",queue_id="SQ_1",metric="*acd"} 90
+cgrates_stat_queue_metric{tenant="cgrates.org",queue_id="SQ_1",metric="*asr"} 66.67
+# HELP cgrates_resource_usage Units allocated out of the Resource
+# TYPE cgrates_resource_usage gauge
+cgrates_resource_usage{tenant="cgrates.org",resource_id="RES_1"} 3
+# HELP cgrates_resource_limit Units available in the Resource
+# TYPE cgrates_resource_limit gauge
+cgrates_resource_limit{tenant="cgrates.org",resource_id="RES_1"} 10
+# HELP cgrates_sessions_active Sessions active in SessionS
+# TYPE cgrates_sessions_active gauge
+cgrates_sessions_active 5
+# HELP cgrates_sessions_passive Sessions passive in SessionS
+# TYPE cgrates_sessions_passive gauge
+cgrates_sessions_passive 1
+# HELP cgrates_cache_items Items in the cache partition
+# TYPE cgrates_cache_items gauge
+cgrates_cache_items{cache="filters"} 2
+# HELP cgrates_cache_groups Groups in the cache partition
+# TYPE cgrates_cache_groups gauge
+cgrates_cache_groups{cache="filters"} 1
+# HELP cgrates_cache_hits_total Reads served out of the cache partition
+# TYPE cgrates_cache_hits_total counter
+cgrates_cache_hits_total{cache="filters"} 10
+# HELP cgrates_cache_misses_total Reads not found in the cache partition
+# TYPE cgrates_cache_misses_total counter
+cgrates_cache_misses_total{cache="filters"} 4
+`
+	if out := w.Body.String(); out != eOut {
+		t.Errorf("expecting:\n%s\nreceived:\n%s", eOut, out)
+	}
+}
+
+func TestPromWriterFamily(t *testing.T) {
+	pw := new(promWriter)
+	pw.writeFamily("cgrates_empty", "Not written", promGauge, nil)
+	pw.writeFamily("cgrates_rpc_duration_seconds", "Duration", promSummary,
+		[]*promSample{
+			{suffix: "_sum", labels: []string{"method", `Sv1."Quoted"\`}, value: 0.5},
+			{suffix: "_count", labels: []string{"method", `Sv1."Quoted"\`}, value: 2},
+		})
+	eOut := `# HELP cgrates_rpc_duration_seconds Duration
+# TYPE cgrates_rpc_duration_seconds summary
+cgrates_rpc_duration_seconds_sum{method="Sv1.\"Quoted\"\\"} 0.5
+cgrates_rpc_duration_seconds_count{method="Sv1.\"Quoted\"\\"} 2
+`
+	if out := pw.String(); out != eOut {
+		t.Errorf("expecting:\n%s\nreceived:\n%s", eOut, out)
+	}
+}
@@ -20,6 +20,8 @@ package agents
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
@@ -36,6 +38,14 @@ const (
 	MetaRadReplyCode = "*radReplyCode"
 )
 
+// Dynamic Authorization packet codes, RFC 5176
+const (
+	radDisconnectRequest radigo.PacketCode = 40
+	radDisconnectACK     radigo.PacketCode = 41
+	radCoARequest        radigo.PacketCode = 43
+	radCoAACK            radigo.PacketCode = 44
+)
+
 func NewRadiusAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
 	sessionS rpcclient.RpcClientConnection) (ra *RadiusAgent, err error) {
 	dts := make(map[string]*radigo.Dictionary, len(cgrCfg.RadiusAgentCfg().ClientDictionaries))
@@ -48,7 +58,10 @@ func NewRadiusAgent(cgrCfg *config.CGRConfig, filterS *engine.FilterS,
 		}
 	}
 	dicts := radigo.NewDictionaries(dts)
-	ra = &RadiusAgent{cgrCfg: cgrCfg, filterS: filterS, sessionS: sessionS}
+	ra = &RadiusAgent{cgrCfg: cgrCfg, filterS: filterS, sessionS: sessionS, dicts: dts}
+	if biRPCClnt, isBiRPC := sessionS.(*utils.BiRPCInternalClient); isBiRPC {
+		biRPCClnt.SetClientConn(ra) // pass the connection to RA back into SessionS so we can receive the disconnects
+	}
 	secrets := radigo.NewSecrets(cgrCfg.RadiusAgentCfg().ClientSecrets)
 	ra.rsAuth = radigo.NewServer(cgrCfg.RadiusAgentCfg().ListenNet,
 		cgrCfg.RadiusAgentCfg().ListenAuth, secrets, dicts,
@@ -67,6 +80,10 @@ type RadiusAgent struct {
 	filterS  *engine.FilterS
 	rsAuth   *radigo.Server
 	rsAcct   *radigo.Server
+	dicts    map[string]*radigo.Dictionary // per client dictionaries, used also when sending requests to the client
+	daReqID  uint32                        // identifier of the last Dynamic Authorization request sent
+	daClnts  map[string]*radigo.Client     // Dynamic Authorization clients, indexed on the address they send to
+	daMux    sync.Mutex                    // protects daClnts
 }
 
 // handleAuth handles RADIUS Authorization request
@@ -293,3 +310,72 @@ func (ra *RadiusAgent) ListenAndServe() (err error) {
 	err = <-errListen
 	return
 }
+
+// Call implements rpcclient.RpcClientConnection interface
+func (ra *RadiusAgent) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	return utils.RPCCall(ra, serviceMethod, args, reply)
+}
+
+// V1DisconnectSession is called by SessionS when a session needs to be terminated
+// sends a Disconnect-Request or CoA-Request (RFC 5176) towards the client,
+// identified by the OriginHost of the session
+func (ra *RadiusAgent) V1DisconnectSession(args utils.AttrDisconnectSession, reply *string) (err error) {
+	raCfg := ra.cgrCfg.RadiusAgentCfg()
+	reqCode, ackCode := radDisconnectRequest, radDisconnectACK
+	if raCfg.DisconnectMethod == utils.MetaCoA {
+		reqCode, ackCode = radCoARequest, radCoAACK
+	}
+	tpl, has := raCfg.Templates[raCfg.DisconnectMethod]
+	if !has {
+		return fmt.Errorf("no template with id: <%s>", raCfg.DisconnectMethod)
+	}
+	clntID, _ := utils.IfaceAsString(args.EventStart[utils.OriginHost])
+	daAddr, has := radClientValue(raCfg.ClientDaAddresses, clntID)
+	if !has {
+		return fmt.Errorf("no dynamic authorization address for client: <%s>", clntID)
+	}
+	secret, _ := radClientValue(raCfg.ClientSecrets, clntID)
+	dict, has := ra.dicts[clntID]
+	if !has {
+		dict = ra.dicts[utils.META_DEFAULT]
+	}
+	clnt, err := ra.daClient(daAddr, secret, dict)
+	if err != nil {
+		return
+	}
+	req := clnt.NewRequest(reqCode, uint8(atomic.AddUint32(&ra.daReqID, 1)))
+	agReq := newAgentRequest(config.NewNavigableMap(args.EventStart),
+		map[string]interface{}{utils.DISCONNECT_CAUSE: args.Reason}, nil, nil,
+		ra.cgrCfg.GeneralCfg().DefaultTenant,
+		ra.cgrCfg.GeneralCfg().DefaultTimezone, ra.filterS)
+	if err = radReplyAppendAttributes(req, agReq, tpl); err != nil {
+		return
+	}
+	rpl, err := clnt.SendRequest(req)
+	if err != nil {
+		return
+	}
+	if rpl.Code != ackCode {
+		return fmt.Errorf("unexpected reply code: <%d> from client: <%s>", rpl.Code, daAddr)
+	}
+	*reply = utils.OK
+	return
+}
+
+// daClient returns the client sending Dynamic Authorization requests to daAddr, connecting it on first use
+func (ra *RadiusAgent) daClient(daAddr, secret string, dict *radigo.Dictionary) (clnt *radigo.Client, err error) {
+	ra.daMux.Lock()
+	defer ra.daMux.Unlock()
+	if clnt, has := ra.daClnts[daAddr]; has {
+		return clnt, nil
+	}
+	if clnt, err = radigo.NewClient(utils.UDP, daAddr, secret, dict,
+		ra.cgrCfg.GeneralCfg().ConnectAttempts, nil); err != nil {
+		return
+	}
+	if ra.daClnts == nil {
+		ra.daClnts = make(map[string]*radigo.Client)
+	}
+	ra.daClnts[daAddr] = clnt
+	return
+}
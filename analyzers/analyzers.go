@@ -20,17 +20,26 @@ package analyzers
 
 import (
 	"fmt"
+	"net/rpc"
+	"time"
 
+	"github.com/cenkalti/rpc2"
+	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
 )
 
 // NewAnalyzerService initializes a AnalyzerService
-func NewAnalyzerService() (*AnalyzerService, error) {
-	return &AnalyzerService{}, nil
+func NewAnalyzerService(cfg *config.CGRConfig) (*AnalyzerService, error) {
+	return &AnalyzerService{
+		cfg:   cfg,
+		calls: newCallStore(cfg.AnalyzerSCfg().Limit, cfg.AnalyzerSCfg().TTL),
+	}, nil
 }
 
 // AnalyzerService is the service handling analyzer
 type AnalyzerService struct {
+	cfg   *config.CGRConfig
+	calls *callStore // API calls captured from the RPC server
 }
 
 // ListenAndServe will initialize the service
@@ -47,3 +56,60 @@ func (aS *AnalyzerService) Shutdown() error {
 	utils.Logger.Info(fmt.Sprintf("<%s> service shutdown complete", utils.AnalyzerS))
 	return nil
 }
+
+// NewServerCodec wraps the rpc.ServerCodec so the API calls passing through it are captured
+func (aS *AnalyzerService) NewServerCodec(sc rpc.ServerCodec, enc, from string) rpc.ServerCodec {
+	return newAnalyzerServerCodec(sc, aS, enc, from)
+}
+
+// NewBiRPCCodec wraps the rpc2.Codec so the API calls received through it are captured
+func (aS *AnalyzerService) NewBiRPCCodec(sc rpc2.Codec, enc, from string) rpc2.Codec {
+	return newAnalyzerBiRPCCodec(sc, aS, enc, from)
+}
+
+// CallInternal does the API call over an *internal connection, capturing it
+func (aS *AnalyzerService) CallInternal(call func(string, interface{}, interface{}) error,
+	serviceMethod string, args, reply interface{}) (err error) {
+	info := &InfoRPC{
+		RequestMethod:   serviceMethod,
+		RequestParams:   args,
+		RequestEncoding: utils.MetaInternal,
+		RequestSource:   utils.MetaInternal,
+		RequestTime:     time.Now(),
+		Tenant:          tenantFromParams(args),
+	}
+	err = call(serviceMethod, args, reply)
+	info.RequestDuration = time.Now().Sub(info.RequestTime)
+	if err != nil {
+		info.ReplyError = err.Error()
+	} else {
+		info.Reply = reply
+	}
+	aS.logCall(info)
+	return
+}
+
+// logCall stores the InfoRPC for later querying
+func (aS *AnalyzerService) logCall(info *InfoRPC) {
+	aS.calls.add(info)
+}
+
+// V1GetCalls returns the captured API calls matching the query arguments
+func (aS *AnalyzerService) V1GetCalls(args *ArgsGetCalls, reply *[]*InfoRPC) (err error) {
+	calls := aS.calls.query(args)
+	if len(calls) == 0 {
+		return utils.ErrNotFound
+	}
+	*reply = calls
+	return
+}
+
+// V1StringQuery returns the captured API calls matching the query string
+func (aS *AnalyzerService) V1StringQuery(args *ArgsStringQuery, reply *[]*InfoRPC) (err error) {
+	var qryArgs *ArgsGetCalls
+	if qryArgs, err = NewArgsGetCallsFromString(args.Query); err != nil {
+		return
+	}
+	qryArgs.Paginator = args.Paginator
+	return aS.V1GetCalls(qryArgs, reply)
+}
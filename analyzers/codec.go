@@ -0,0 +1,190 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package analyzers
+
+import (
+	"net/rpc"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/rpc2"
+)
+
+func newAnalyzerServerCodec(sc rpc.ServerCodec, aS *AnalyzerService,
+	enc, from string) rpc.ServerCodec {
+	return &analyzerServerCodec{
+		sc:   sc,
+		aS:   aS,
+		enc:  enc,
+		from: from,
+		reqs: make(map[uint64]*InfoRPC),
+	}
+}
+
+// analyzerServerCodec sits between the rpc.Server and the real codec,
+// capturing the requests together with their replies
+type analyzerServerCodec struct {
+	sc   rpc.ServerCodec
+	aS   *AnalyzerService
+	enc  string
+	from string
+
+	reqs    map[uint64]*InfoRPC // requests waiting for their reply, indexed on sequence
+	lastSeq uint64              // header and body are read in sequence by the server
+	reqsLk  sync.Mutex
+}
+
+func (c *analyzerServerCodec) ReadRequestHeader(r *rpc.Request) (err error) {
+	if err = c.sc.ReadRequestHeader(r); err != nil {
+		return
+	}
+	c.reqsLk.Lock()
+	c.lastSeq = r.Seq
+	c.reqs[r.Seq] = &InfoRPC{
+		RequestMethod:   r.ServiceMethod,
+		RequestEncoding: c.enc,
+		RequestSource:   c.from,
+		RequestTime:     time.Now(),
+	}
+	c.reqsLk.Unlock()
+	return
+}
+
+func (c *analyzerServerCodec) ReadRequestBody(x interface{}) (err error) {
+	if err = c.sc.ReadRequestBody(x); err != nil {
+		return
+	}
+	c.reqsLk.Lock()
+	if info, has := c.reqs[c.lastSeq]; has {
+		info.RequestParams = x
+		info.Tenant = tenantFromParams(x)
+	}
+	c.reqsLk.Unlock()
+	return
+}
+
+func (c *analyzerServerCodec) WriteResponse(r *rpc.Response, x interface{}) error {
+	c.reqsLk.Lock()
+	info, has := c.reqs[r.Seq]
+	delete(c.reqs, r.Seq)
+	c.reqsLk.Unlock()
+	if has {
+		info.RequestDuration = time.Now().Sub(info.RequestTime)
+		info.ReplyError = r.Error
+		if r.Error == "" {
+			info.Reply = x
+		}
+		c.aS.logCall(info)
+	}
+	return c.sc.WriteResponse(r, x)
+}
+
+func (c *analyzerServerCodec) Close() error {
+	return c.sc.Close()
+}
+
+func newAnalyzerBiRPCCodec(sc rpc2.Codec, aS *AnalyzerService,
+	enc, from string) rpc2.Codec {
+	return &analyzerBiRPCCodec{
+		sc:   sc,
+		aS:   aS,
+		enc:  enc,
+		from: from,
+		reqs: make(map[uint64]*InfoRPC),
+	}
+}
+
+// analyzerBiRPCCodec sits between the rpc2.Server and the real codec,
+// capturing the requests received together with their replies
+// the calls done towards the client over the same connection are not captured
+type analyzerBiRPCCodec struct {
+	sc   rpc2.Codec
+	aS   *AnalyzerService
+	enc  string
+	from string
+
+	reqs    map[uint64]*InfoRPC // requests waiting for their reply, indexed on sequence
+	lastSeq uint64              // header and body are read in sequence by the connection
+	reqsLk  sync.Mutex
+}
+
+func (c *analyzerBiRPCCodec) ReadHeader(req *rpc2.Request, resp *rpc2.Response) (err error) {
+	if err = c.sc.ReadHeader(req, resp); err != nil ||
+		req.Method == "" { // reply to a call done towards the client
+		return
+	}
+	c.reqsLk.Lock()
+	c.lastSeq = req.Seq
+	c.reqs[req.Seq] = &InfoRPC{
+		RequestMethod:   req.Method,
+		RequestEncoding: c.enc,
+		RequestSource:   c.from,
+		RequestTime:     time.Now(),
+	}
+	c.reqsLk.Unlock()
+	return
+}
+
+func (c *analyzerBiRPCCodec) ReadRequestBody(x interface{}) (err error) {
+	if err = c.sc.ReadRequestBody(x); err != nil {
+		return
+	}
+	c.reqsLk.Lock()
+	info, has := c.reqs[c.lastSeq]
+	if has {
+		info.RequestParams = x
+		info.Tenant = tenantFromParams(x)
+	}
+	if c.lastSeq == 0 { // notifications are not replied
+		delete(c.reqs, c.lastSeq)
+	}
+	c.reqsLk.Unlock()
+	if has && c.lastSeq == 0 {
+		c.aS.logCall(info)
+	}
+	return
+}
+
+func (c *analyzerBiRPCCodec) ReadResponseBody(x interface{}) error {
+	return c.sc.ReadResponseBody(x)
+}
+
+func (c *analyzerBiRPCCodec) WriteRequest(r *rpc2.Request, x interface{}) error {
+	return c.sc.WriteRequest(r, x)
+}
+
+func (c *analyzerBiRPCCodec) WriteResponse(r *rpc2.Response, x interface{}) error {
+	c.reqsLk.Lock()
+	info, has := c.reqs[r.Seq]
+	delete(c.reqs, r.Seq)
+	c.reqsLk.Unlock()
+	if has {
+		info.RequestDuration = time.Now().Sub(info.RequestTime)
+		info.ReplyError = r.Error
+		if r.Error == "" {
+			info.Reply = x
+		}
+		c.aS.logCall(info)
+	}
+	return c.sc.WriteResponse(r, x)
+}
+
+func (c *analyzerBiRPCCodec) Close() error {
+	return c.sc.Close()
+}
@@ -0,0 +1,135 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package analyzers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/rpc2"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// testBiRPCCodec reads the headers out of reqs, nil requests being replies to the calls done towards the client
+type testBiRPCCodec struct {
+	reqs []*rpc2.Request
+}
+
+func (c *testBiRPCCodec) ReadHeader(req *rpc2.Request, resp *rpc2.Response) error {
+	if len(c.reqs) == 0 {
+		return errors.New("EOF")
+	}
+	if c.reqs[0] != nil {
+		*req = *c.reqs[0]
+	} else {
+		resp.Seq = 1
+	}
+	c.reqs = c.reqs[1:]
+	return nil
+}
+
+func (c *testBiRPCCodec) ReadRequestBody(x interface{}) error {
+	*(x.(*map[string]interface{})) = map[string]interface{}{utils.Tenant: "cgrates.org"}
+	return nil
+}
+
+func (c *testBiRPCCodec) ReadResponseBody(x interface{}) error            { return nil }
+func (c *testBiRPCCodec) WriteRequest(*rpc2.Request, interface{}) error   { return nil }
+func (c *testBiRPCCodec) WriteResponse(*rpc2.Response, interface{}) error { return nil }
+func (c *testBiRPCCodec) Close() error                                    { return nil }
+
+func TestAnalyzerBiRPCCodec(t *testing.T) {
+	aS := &AnalyzerService{calls: newCallStore(-1, 0)}
+	c := aS.NewBiRPCCodec(&testBiRPCCodec{reqs: []*rpc2.Request{
+		{Seq: 1, Method: "SessionSv1.InitiateSession"},
+		nil,
+		{Method: "SessionSv1.ProcessEvent"}, // notification
+	}}, utils.MetaJSONrpc, "127.0.0.1:5005")
+	var req rpc2.Request
+	var resp rpc2.Response
+	var args map[string]interface{}
+	if err := c.ReadHeader(&req, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRequestBody(&args); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WriteResponse(&rpc2.Response{Seq: 1}, "OK"); err != nil {
+		t.Fatal(err)
+	}
+	req, resp = rpc2.Request{}, rpc2.Response{}
+	if err := c.ReadHeader(&req, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadResponseBody(nil); err != nil {
+		t.Fatal(err)
+	}
+	req, resp = rpc2.Request{}, rpc2.Response{}
+	if err := c.ReadHeader(&req, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRequestBody(&args); err != nil {
+		t.Fatal(err)
+	}
+	calls := aS.calls.query(new(ArgsGetCalls))
+	if len(calls) != 2 {
+		t.Fatalf("expecting 2 calls, received: %s", utils.ToJSON(calls))
+	}
+	if calls[0].RequestMethod != "SessionSv1.ProcessEvent" || calls[0].Reply != nil {
+		t.Errorf("unexpected notification: %s", utils.ToJSON(calls[0]))
+	}
+	if calls[1].RequestMethod != "SessionSv1.InitiateSession" || calls[1].Reply != "OK" ||
+		calls[1].Tenant != "cgrates.org" || calls[1].RequestSource != "127.0.0.1:5005" ||
+		calls[1].RequestEncoding != utils.MetaJSONrpc {
+		t.Errorf("unexpected call: %s", utils.ToJSON(calls[1]))
+	}
+}
+
+func TestAnalyzerCallInternal(t *testing.T) {
+	aS := &AnalyzerService{calls: newCallStore(-1, time.Minute)}
+	call := func(serviceMethod string, args, reply interface{}) error {
+		if serviceMethod != "AttributeSv1.ProcessEvent" {
+			return utils.ErrNotFound
+		}
+		*(reply.(*string)) = utils.OK
+		return nil
+	}
+	var reply string
+	args := map[string]interface{}{utils.Tenant: "cgrates.org"}
+	if err := aS.CallInternal(call, "AttributeSv1.ProcessEvent", args, &reply); err != nil {
+		t.Fatal(err)
+	} else if reply != utils.OK {
+		t.Errorf("unexpected reply: %s", reply)
+	}
+	if err := aS.CallInternal(call, "AttributeSv1.Ping", args, &reply); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	calls := aS.calls.query(new(ArgsGetCalls))
+	if len(calls) != 2 {
+		t.Fatalf("expecting 2 calls, received: %s", utils.ToJSON(calls))
+	}
+	if calls[0].ReplyError != utils.ErrNotFound.Error() || calls[0].Reply != nil {
+		t.Errorf("unexpected call: %s", utils.ToJSON(calls[0]))
+	}
+	if calls[1].RequestMethod != "AttributeSv1.ProcessEvent" || calls[1].Tenant != "cgrates.org" ||
+		calls[1].RequestSource != utils.MetaInternal || calls[1].ReplyError != "" {
+		t.Errorf("unexpected call: %s", utils.ToJSON(calls[1]))
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package analyzers
+
+import (
+	"container/list"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+const (
+	durGreaterThan = ">"
+	durLessThan    = "<"
+)
+
+// InfoRPC is one API call captured by the AnalyzerService
+type InfoRPC struct {
+	RequestMethod   string
+	RequestParams   interface{}
+	RequestEncoding string // *json, *gob or *internal
+	RequestSource   string // remote address of the client, *internal for the *internal connections
+	RequestTime     time.Time
+	RequestDuration time.Duration
+	Tenant          string // populated out of RequestParams if present
+	Reply           interface{}
+	ReplyError      string
+}
+
+// ArgsGetCalls filters the calls returned by AnalyzerSv1.GetCalls
+type ArgsGetCalls struct {
+	RequestMethods []string      // exact method names, or prefixes if ending in *
+	RequestSources []string      // remote addresses of the clients
+	Tenants        []string      // tenants extracted out of the request params
+	ReplyError     *bool         // true for failed calls only, false for successful ones only
+	MinDuration    time.Duration // minimum duration of the request
+	MaxDuration    time.Duration // maximum duration of the request, 0 for no limit
+	utils.Paginator
+}
+
+// ArgsStringQuery is used by AnalyzerSv1.StringQuery
+// Query is a list of space separated Field:Value terms, ie:
+// "RequestMethod:SessionSv1.* Tenant:cgrates.org ReplyError:true RequestDuration:>50ms"
+type ArgsStringQuery struct {
+	Query string
+	utils.Paginator
+}
+
+// NewArgsGetCallsFromString parses the query string into ArgsGetCalls
+func NewArgsGetCallsFromString(qry string) (args *ArgsGetCalls, err error) {
+	args = new(ArgsGetCalls)
+	for _, term := range strings.Fields(qry) {
+		fldVal := strings.SplitN(term, utils.InInFieldSep, 2)
+		if len(fldVal) != 2 || fldVal[1] == "" {
+			return nil, fmt.Errorf("invalid query term: <%s>", term)
+		}
+		switch fldVal[0] {
+		case utils.RequestMethod:
+			args.RequestMethods = append(args.RequestMethods, fldVal[1])
+		case utils.RequestSource:
+			args.RequestSources = append(args.RequestSources, fldVal[1])
+		case utils.Tenant:
+			args.Tenants = append(args.Tenants, fldVal[1])
+		case utils.ReplyError:
+			var withErr bool
+			if withErr, err = strconv.ParseBool(fldVal[1]); err != nil {
+				return nil, err
+			}
+			args.ReplyError = utils.BoolPointer(withErr)
+		case utils.RequestDuration:
+			var dur time.Duration
+			switch {
+			case strings.HasPrefix(fldVal[1], durGreaterThan):
+				if dur, err = utils.ParseDurationWithNanosecs(fldVal[1][1:]); err != nil {
+					return nil, err
+				}
+				args.MinDuration = dur
+			case strings.HasPrefix(fldVal[1], durLessThan):
+				if dur, err = utils.ParseDurationWithNanosecs(fldVal[1][1:]); err != nil {
+					return nil, err
+				}
+				args.MaxDuration = dur
+			default:
+				return nil, fmt.Errorf("invalid duration term: <%s>", term)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported query field: <%s>", fldVal[0])
+		}
+	}
+	return
+}
+
+// matchesMethod checks the method against the exact names or prefixes
+func matchesMethod(method string, fltrs []string) bool {
+	for _, fltr := range fltrs {
+		if strings.HasSuffix(fltr, utils.Meta) {
+			if strings.HasPrefix(method, fltr[:len(fltr)-1]) {
+				return true
+			}
+		} else if method == fltr {
+			return true
+		}
+	}
+	return false
+}
+
+// matches checks if the InfoRPC passes the filters in args
+func (args *ArgsGetCalls) matches(info *InfoRPC) bool {
+	if len(args.RequestMethods) != 0 &&
+		!matchesMethod(info.RequestMethod, args.RequestMethods) {
+		return false
+	}
+	if len(args.RequestSources) != 0 &&
+		!utils.IsSliceMember(args.RequestSources, info.RequestSource) {
+		return false
+	}
+	if len(args.Tenants) != 0 &&
+		!utils.IsSliceMember(args.Tenants, info.Tenant) {
+		return false
+	}
+	if args.ReplyError != nil &&
+		*args.ReplyError != (info.ReplyError != "") {
+		return false
+	}
+	if info.RequestDuration < args.MinDuration {
+		return false
+	}
+	if args.MaxDuration != 0 && info.RequestDuration > args.MaxDuration {
+		return false
+	}
+	return true
+}
+
+// tenantFromParams extracts the Tenant out of the request params, if present
+func tenantFromParams(params interface{}) (tnt string) {
+	v := reflect.ValueOf(params)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		fld := v.FieldByName(utils.Tenant)
+		if fld.IsValid() && fld.Kind() == reflect.String {
+			tnt = fld.String()
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		if val := v.MapIndex(reflect.ValueOf(utils.Tenant)); val.IsValid() {
+			tnt, _ = val.Interface().(string)
+		}
+	}
+	return
+}
+
+func newCallStore(limit int, ttl time.Duration) *callStore {
+	return &callStore{limit: limit, ttl: ttl, calls: list.New()}
+}
+
+// callStore keeps the captured calls in chronological order,
+// bounded by limit and expiring them after ttl
+type callStore struct {
+	limit int
+	ttl   time.Duration
+	calls *list.List
+	sync.RWMutex
+}
+
+// add stores the call, making room by removing the oldest ones
+func (cs *callStore) add(info *InfoRPC) {
+	if cs.limit == 0 {
+		return
+	}
+	cs.Lock()
+	cs.calls.PushBack(info)
+	cs.remExpired()
+	if cs.limit > 0 {
+		for cs.calls.Len() > cs.limit {
+			cs.calls.Remove(cs.calls.Front())
+		}
+	}
+	cs.Unlock()
+}
+
+// remExpired removes the calls older than ttl
+// needs to be called under lock
+func (cs *callStore) remExpired() {
+	if cs.ttl == 0 {
+		return
+	}
+	expTime := time.Now().Add(-cs.ttl)
+	for e := cs.calls.Front(); e != nil; e = cs.calls.Front() {
+		if e.Value.(*InfoRPC).RequestTime.After(expTime) {
+			break
+		}
+		cs.calls.Remove(e)
+	}
+}
+
+// query returns the calls matching args, newest first
+func (cs *callStore) query(args *ArgsGetCalls) (calls []*InfoRPC) {
+	var offset int
+	if args.Paginator.Offset != nil {
+		offset = *args.Paginator.Offset
+	}
+	var expTime time.Time
+	if cs.ttl != 0 {
+		expTime = time.Now().Add(-cs.ttl)
+	}
+	cs.RLock()
+	defer cs.RUnlock()
+	for e := cs.calls.Back(); e != nil; e = e.Prev() {
+		info := e.Value.(*InfoRPC)
+		if !expTime.IsZero() && !info.RequestTime.After(expTime) {
+			break
+		}
+		if !args.matches(info) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		calls = append(calls, info)
+		if args.Paginator.Limit != nil && len(calls) >= *args.Paginator.Limit {
+			break
+		}
+	}
+	return
+}
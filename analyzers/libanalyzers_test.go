@@ -0,0 +1,118 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package analyzers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestNewArgsGetCallsFromString(t *testing.T) {
+	eArgs := &ArgsGetCalls{
+		RequestMethods: []string{"SessionSv1.*", "CDRsV1.ProcessCDR"},
+		Tenants:        []string{"cgrates.org"},
+		ReplyError:     utils.BoolPointer(true),
+		MinDuration:    time.Duration(50 * time.Millisecond),
+		MaxDuration:    time.Duration(time.Second),
+	}
+	if args, err := NewArgsGetCallsFromString("RequestMethod:SessionSv1.* RequestMethod:CDRsV1.ProcessCDR " +
+		"Tenant:cgrates.org ReplyError:true RequestDuration:>50ms RequestDuration:<1s"); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eArgs, args) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eArgs), utils.ToJSON(args))
+	}
+	if _, err := NewArgsGetCallsFromString("Account:1001"); err == nil {
+		t.Error("expecting error for unsupported field")
+	}
+	if _, err := NewArgsGetCallsFromString("RequestDuration:50ms"); err == nil {
+		t.Error("expecting error for missing duration operator")
+	}
+}
+
+func TestTenantFromParams(t *testing.T) {
+	if tnt := tenantFromParams(&utils.CGREvent{Tenant: "cgrates.org"}); tnt != "cgrates.org" {
+		t.Errorf("received: <%s>", tnt)
+	}
+	if tnt := tenantFromParams(&map[string]interface{}{utils.Tenant: "itsyscom.com"}); tnt != "itsyscom.com" {
+		t.Errorf("received: <%s>", tnt)
+	}
+	var ign string
+	if tnt := tenantFromParams(&ign); tnt != "" {
+		t.Errorf("received: <%s>", tnt)
+	}
+	if tnt := tenantFromParams(nil); tnt != "" {
+		t.Errorf("received: <%s>", tnt)
+	}
+}
+
+func TestCallStoreQuery(t *testing.T) {
+	cs := newCallStore(3, 0)
+	now := time.Now()
+	calls := []*InfoRPC{
+		{RequestMethod: "SessionSv1.InitiateSession", Tenant: "cgrates.org",
+			RequestTime: now, RequestDuration: time.Duration(10 * time.Millisecond)},
+		{RequestMethod: "SessionSv1.UpdateSession", Tenant: "cgrates.org",
+			RequestTime: now, RequestDuration: time.Duration(100 * time.Millisecond)},
+		{RequestMethod: "SessionSv1.TerminateSession", Tenant: "itsyscom.com",
+			RequestTime: now, RequestDuration: time.Duration(20 * time.Millisecond),
+			ReplyError: utils.ErrNotFound.Error()},
+		{RequestMethod: "CDRsV1.ProcessCDR", Tenant: "cgrates.org",
+			RequestTime: now, RequestDuration: time.Duration(5 * time.Millisecond)},
+	}
+	for _, info := range calls {
+		cs.add(info)
+	}
+	if rcv := cs.query(new(ArgsGetCalls)); !reflect.DeepEqual([]*InfoRPC{calls[3], calls[2], calls[1]}, rcv) {
+		t.Errorf("received: %s", utils.ToJSON(rcv))
+	}
+	if rcv := cs.query(&ArgsGetCalls{RequestMethods: []string{"SessionSv1.*"},
+		Tenants: []string{"cgrates.org"}}); !reflect.DeepEqual([]*InfoRPC{calls[1]}, rcv) {
+		t.Errorf("received: %s", utils.ToJSON(rcv))
+	}
+	if rcv := cs.query(&ArgsGetCalls{ReplyError: utils.BoolPointer(true)}); !reflect.DeepEqual([]*InfoRPC{calls[2]}, rcv) {
+		t.Errorf("received: %s", utils.ToJSON(rcv))
+	}
+	if rcv := cs.query(&ArgsGetCalls{MinDuration: time.Duration(15 * time.Millisecond),
+		MaxDuration: time.Duration(50 * time.Millisecond)}); !reflect.DeepEqual([]*InfoRPC{calls[2]}, rcv) {
+		t.Errorf("received: %s", utils.ToJSON(rcv))
+	}
+	if rcv := cs.query(&ArgsGetCalls{Paginator: utils.Paginator{Limit: utils.IntPointer(1),
+		Offset: utils.IntPointer(1)}}); !reflect.DeepEqual([]*InfoRPC{calls[2]}, rcv) {
+		t.Errorf("received: %s", utils.ToJSON(rcv))
+	}
+}
+
+func TestCallStoreTTL(t *testing.T) {
+	cs := newCallStore(-1, time.Duration(time.Minute))
+	old := &InfoRPC{RequestMethod: "SessionSv1.InitiateSession",
+		RequestTime: time.Now().Add(-2 * time.Minute)}
+	recent := &InfoRPC{RequestMethod: "SessionSv1.UpdateSession",
+		RequestTime: time.Now()}
+	cs.add(old)
+	cs.add(recent)
+	if cs.calls.Len() != 1 {
+		t.Errorf("expecting 1 call, have: %d", cs.calls.Len())
+	}
+	if rcv := cs.query(new(ArgsGetCalls)); !reflect.DeepEqual([]*InfoRPC{recent}, rcv) {
+		t.Errorf("received: %s", utils.ToJSON(rcv))
+	}
+}
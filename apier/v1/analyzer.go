@@ -44,3 +44,13 @@ func (alSv1 *AnalyzerSv1) Ping(ign string, reply *string) error {
 	*reply = utils.Pong
 	return nil
 }
+
+// GetCalls returns the API calls captured by the AnalyzerS matching the filters
+func (aSv1 *AnalyzerSv1) GetCalls(args *analyzers.ArgsGetCalls, reply *[]*analyzers.InfoRPC) error {
+	return aSv1.aS.V1GetCalls(args, reply)
+}
+
+// StringQuery returns the API calls captured by the AnalyzerS matching the query string
+func (aSv1 *AnalyzerSv1) StringQuery(args *analyzers.ArgsStringQuery, reply *[]*analyzers.InfoRPC) error {
+	return aSv1.aS.V1StringQuery(args, reply)
+}
@@ -34,7 +34,6 @@ import (
 	"github.com/cgrates/cgrates/servmanager"
 	"github.com/cgrates/cgrates/utils"
 	"github.com/cgrates/rpcclient"
-	"github.com/streadway/amqp"
 )
 
 const (
@@ -1047,6 +1046,19 @@ func (self *ApierV1) ReloadCache(attrs utils.AttrReloadCache, reply *string) (er
 	if err = self.DataManager.CacheDataFromDB(utils.ChargerProfilePrefix, dataIDs, true); err != nil {
 		return
 	}
+	// DispatcherProfiles
+	dataIDs = make([]string, 0)
+	if attrs.DispatcherProfileIDs == nil {
+		dataIDs = nil // Reload all
+	} else if len(*attrs.DispatcherProfileIDs) > 0 {
+		dataIDs = make([]string, len(*attrs.DispatcherProfileIDs))
+		for idx, dId := range *attrs.DispatcherProfileIDs {
+			dataIDs[idx] = dId
+		}
+	}
+	if err = self.DataManager.CacheDataFromDB(utils.DispatcherProfilePrefix, dataIDs, true); err != nil {
+		return
+	}
 
 	*reply = utils.OK
 	return nil
@@ -1056,7 +1068,7 @@ func (self *ApierV1) LoadCache(args utils.AttrReloadCache, reply *string) (err e
 	if args.FlushAll {
 		engine.Cache.Clear(nil)
 	}
-	var dstIDs, rvDstIDs, rplIDs, rpfIDs, actIDs, aplIDs, aapIDs, atrgIDs, sgIDs, lcrIDs, dcIDs, alsIDs, rvAlsIDs, rspIDs, resIDs, stqIDs, stqpIDs, thIDs, thpIDs, fltrIDs, splpIDs, alsPrfIDs, cppIDs []string
+	var dstIDs, rvDstIDs, rplIDs, rpfIDs, actIDs, aplIDs, aapIDs, atrgIDs, sgIDs, lcrIDs, dcIDs, alsIDs, rvAlsIDs, rspIDs, resIDs, stqIDs, stqpIDs, thIDs, thpIDs, fltrIDs, splpIDs, alsPrfIDs, cppIDs, dppIDs []string
 	if args.DestinationIDs == nil {
 		dstIDs = nil
 	} else {
@@ -1172,10 +1184,15 @@ func (self *ApierV1) LoadCache(args utils.AttrReloadCache, reply *string) (err e
 	} else {
 		cppIDs = *args.ChargerProfileIDs
 	}
+	if args.DispatcherProfileIDs == nil {
+		dppIDs = nil
+	} else {
+		dppIDs = *args.DispatcherProfileIDs
+	}
 	if err := self.DataManager.LoadDataDBCache(dstIDs, rvDstIDs, rplIDs,
 		rpfIDs, actIDs, aplIDs, aapIDs, atrgIDs, sgIDs, lcrIDs, dcIDs, alsIDs,
 		rvAlsIDs, rspIDs, resIDs, stqIDs, stqpIDs, thIDs, thpIDs,
-		fltrIDs, splpIDs, alsPrfIDs, cppIDs); err != nil {
+		fltrIDs, splpIDs, alsPrfIDs, cppIDs, dppIDs); err != nil {
 		return utils.NewErrServerError(err)
 	}
 	*reply = utils.OK
@@ -1364,6 +1381,16 @@ func (self *ApierV1) FlushCache(args utils.AttrReloadCache, reply *string) (err
 				true, utils.NonTransactional)
 		}
 	}
+	if args.DispatcherProfileIDs == nil {
+		engine.Cache.Clear([]string{utils.CacheDispatcherProfiles, utils.CacheDispatchers})
+	} else if len(*args.DispatcherProfileIDs) != 0 {
+		for _, key := range *args.DispatcherProfileIDs {
+			engine.Cache.Remove(utils.CacheDispatcherProfiles, key,
+				true, utils.NonTransactional)
+			engine.Cache.Remove(utils.CacheDispatchers, key,
+				true, utils.NonTransactional)
+		}
+	}
 
 	*reply = utils.OK
 	return
@@ -1393,6 +1420,7 @@ func (self *ApierV1) GetCacheStats(attrs utils.AttrCacheStats, reply *utils.Cach
 	cs.SupplierProfiles = len(engine.Cache.GetItemIDs(utils.CacheSupplierProfiles, ""))
 	cs.AttributeProfiles = len(engine.Cache.GetItemIDs(utils.CacheAttributeProfiles, ""))
 	cs.ChargerProfiles = len(engine.Cache.GetItemIDs(utils.CacheChargerProfiles, ""))
+	cs.DispatcherProfiles = len(engine.Cache.GetItemIDs(utils.CacheDispatcherProfiles, ""))
 
 	if self.CdrStatsSrv != nil {
 		var queueIds []string
@@ -1840,6 +1868,25 @@ func (v1 *ApierV1) GetCacheKeys(args utils.ArgsCacheKeys, reply *utils.ArgsCache
 		}
 	}
 
+	if args.DispatcherProfileIDs != nil {
+		var ids []string
+		if len(*args.DispatcherProfileIDs) != 0 {
+			for _, id := range *args.DispatcherProfileIDs {
+				if _, hasIt := engine.Cache.Get(utils.CacheDispatcherProfiles, id); hasIt {
+					ids = append(ids, id)
+				}
+			}
+		} else {
+			for _, id := range engine.Cache.GetItemIDs(utils.CacheDispatcherProfiles, "") {
+				ids = append(ids, id)
+			}
+		}
+		ids = args.Paginator.PaginateStringSlice(ids)
+		if len(ids) != 0 {
+			reply.DispatcherProfileIDs = &ids
+		}
+	}
+
 	return
 }
 
@@ -1880,6 +1927,7 @@ func (self *ApierV1) LoadTariffPlanFromFolder(attrs utils.AttrLoadTpFromFolder,
 			path.Join(attrs.FolderPath, utils.SuppliersCsv),
 			path.Join(attrs.FolderPath, utils.AttributesCsv),
 			path.Join(attrs.FolderPath, utils.ChargersCsv),
+			path.Join(attrs.FolderPath, utils.DispatchersCsv),
 		), "", self.Config.GeneralCfg().DefaultTimezone)
 	if err := loader.LoadAll(); err != nil {
 		return utils.NewErrServerError(err)
@@ -2169,48 +2217,10 @@ func (v1 *ApierV1) ReplayFailedPosts(args ArgsReplyFailedPosts, reply *string) (
 		if err != nil {
 			return utils.NewErrServerError(err)
 		}
-		failoverPath := utils.META_NONE
-		if failedReqsOutDir != utils.META_NONE {
-			failoverPath = path.Join(failedReqsOutDir, file.Name())
-		}
-		switch ffn.Transport {
-		case utils.MetaHTTPjsonCDR, utils.MetaHTTPjsonMap, utils.MetaHTTPjson, utils.META_HTTP_POST:
-			_, err = engine.NewHTTPPoster(v1.Config.GeneralCfg().HttpSkipTlsVerify,
-				v1.Config.GeneralCfg().ReplyTimeout).Post(ffn.Address,
-				utils.PosterTransportContentTypes[ffn.Transport], fileContent,
-				v1.Config.GeneralCfg().PosterAttempts, failoverPath)
-		case utils.MetaAMQPjsonCDR, utils.MetaAMQPjsonMap:
-			var amqpPoster *engine.AMQPPoster
-			amqpPoster, err = engine.AMQPPostersCache.GetAMQPPoster(ffn.Address,
-				v1.Config.GeneralCfg().PosterAttempts, failedReqsOutDir)
-			if err == nil { // error will be checked bellow
-				var chn *amqp.Channel
-				chn, err = amqpPoster.Post(
-					nil, utils.PosterTransportContentTypes[ffn.Transport],
-					fileContent, file.Name())
-				if chn != nil {
-					chn.Close()
-				}
-			}
-		default:
-			err = fmt.Errorf("unsupported replication transport: %s", ffn.Transport)
-		}
+		err = engine.ReplayFailedPost(file.Name(), ffn, fileContent, failedReqsOutDir)
 		if err != nil && failedReqsOutDir != utils.META_NONE { // Got error from HTTPPoster could be that content was not written, we need to write it ourselves
-			_, err := guardian.Guardian.Guard(func() (interface{}, error) {
-				if _, err := os.Stat(failoverPath); err == nil || !os.IsNotExist(err) {
-					return 0, err
-				}
-				fileOut, err := os.Create(failoverPath)
-				if err != nil {
-					return 0, err
-				}
-				defer fileOut.Close()
-				if _, err := fileOut.Write(fileContent); err != nil {
-					return 0, err
-				}
-				return 0, nil
-			}, v1.Config.GeneralCfg().LockingTimeout, utils.FileLockPrefix+failoverPath)
-			if err != nil {
+			if err := engine.WriteFailedPost(path.Join(failedReqsOutDir, file.Name()),
+				fileContent); err != nil {
 				return utils.NewErrServerError(err)
 			}
 		}
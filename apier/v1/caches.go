@@ -71,6 +71,12 @@ func (chSv1 *CacheSv1) GetCacheStats(cacheIDs []string,
 	return chSv1.cacheS.V1GetCacheStats(cacheIDs, rply)
 }
 
+// GetCacheHitStats returns CacheStats together with the hits and misses, filtered by cacheIDs
+func (chSv1 *CacheSv1) GetCacheHitStats(cacheIDs []string,
+	rply *map[string]*engine.CacheStats) error {
+	return chSv1.cacheS.V1GetCacheHitStats(cacheIDs, rply)
+}
+
 // PrecacheStatus checks status of active precache processes
 func (chSv1 *CacheSv1) PrecacheStatus(cacheIDs []string, rply *map[string]string) error {
 	return chSv1.cacheS.V1PrecacheStatus(cacheIDs, rply)
@@ -98,3 +104,9 @@ func (chSv1 *CacheSv1) Ping(ign string, reply *string) error {
 	*reply = utils.Pong
 	return nil
 }
+
+// Call implements rpcclient.RpcClientConnection interface for internal RPC
+func (chSv1 *CacheSv1) Call(serviceMethod string,
+	args interface{}, reply interface{}) error {
+	return utils.APIerRPCCall(chSv1, serviceMethod, args, reply)
+}
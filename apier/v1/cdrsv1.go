@@ -84,3 +84,13 @@ func (self *CdrsV1) CountCDRs(args utils.RPCCDRsFilter, reply *int64) error {
 func (self *CdrsV1) GetCDRs(args utils.RPCCDRsFilter, reply *[]*engine.CDR) error {
 	return self.CdrSrv.V1GetCDRs(args, reply)
 }
+
+// GetCDRsAggregate returns the count, usage and cost of the CDRs grouped inside StorDB
+func (self *CdrsV1) GetCDRsAggregate(args utils.RPCCDRsAggregateFilter, reply *[]*engine.CDRsAggregate) error {
+	return self.CdrSrv.V1GetCDRsAggregate(args, reply)
+}
+
+// RerateCDRs rates again the CDRs and returns the cost difference, DryRun leaves the CDRs unchanged
+func (self *CdrsV1) RerateCDRs(args utils.ArgsRerateCDRs, reply *engine.CDRsRerateReport) error {
+	return self.CdrSrv.V1RerateCDRs(args, reply)
+}
@@ -0,0 +1,50 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"github.com/cgrates/cgrates/agents"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func NewDiameterAgentV1(da *agents.DiameterAgent) *DiameterAgentV1 {
+	return &DiameterAgentV1{da: da}
+}
+
+// Exports RPC from DiameterAgent
+type DiameterAgentV1 struct {
+	da *agents.DiameterAgent
+}
+
+// Call implements rpcclient.RpcClientConnection interface for internal RPC
+func (dav1 *DiameterAgentV1) Call(serviceMethod string,
+	args interface{}, reply interface{}) error {
+	return utils.APIerRPCCall(dav1, serviceMethod, args, reply)
+}
+
+// ReAuthorizeGx sends the changed charging rules of a Gx session to the peer via Re-Auth-Request
+func (dav1 *DiameterAgentV1) ReAuthorizeGx(args *agents.ArgsReAuthorizeGx,
+	rply *string) error {
+	return dav1.da.V1ReAuthorizeGx(*args, rply)
+}
+
+func (dav1 *DiameterAgentV1) Ping(ign string, reply *string) error {
+	*reply = utils.Pong
+	return nil
+}
@@ -0,0 +1,45 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// NewFailedPostsV1 initializes FailedPostsV1
+func NewFailedPostsV1(fpr *engine.FailedPostsReplayer) *FailedPostsV1 {
+	return &FailedPostsV1{fpr: fpr}
+}
+
+// Exports RPC from FailedPostsReplayer
+type FailedPostsV1 struct {
+	fpr *engine.FailedPostsReplayer
+}
+
+// Call implements rpcclient.RpcClientConnection interface for internal RPC
+func (fpV1 *FailedPostsV1) Call(serviceMethod string,
+	args interface{}, reply interface{}) error {
+	return utils.APIerRPCCall(fpV1, serviceMethod, args, reply)
+}
+
+// GetStats returns the number of pending and failed posts together with the replay counters
+func (fpV1 *FailedPostsV1) GetStats(ign string, reply *engine.FailedPostsStats) error {
+	return fpV1.fpr.V1GetStats(ign, reply)
+}
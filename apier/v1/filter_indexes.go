@@ -60,6 +60,12 @@ func (self *ApierV1) GetFilterIndexes(arg AttrGetFilterIndexes, reply *[]string)
 		}
 		arg.ItemType = utils.AttributeProfilePrefix
 		key = utils.ConcatenatedKey(arg.Tenant, arg.Context)
+	case utils.MetaDispatchers:
+		if missing := utils.MissingStructFields(&arg, []string{"Context"}); len(missing) != 0 { //Params missing
+			return utils.NewErrMandatoryIeMissing(missing...)
+		}
+		arg.ItemType = utils.DispatcherProfilePrefix
+		key = utils.ConcatenatedKey(arg.Tenant, arg.Context)
 	}
 	if indexes, err = self.DataManager.GetFilterIndexes(
 		utils.PrefixToIndexCache[arg.ItemType], key, "", nil); err != nil {
@@ -57,6 +57,11 @@ func (rsv1 *ResourceSv1) ReleaseResources(args utils.ArgRSv1ResourceUsage, reply
 	return rsv1.rls.V1ReleaseResource(args, reply)
 }
 
+// GetResourceWithConfig returns a Resource together with its configuration
+func (rsv1 *ResourceSv1) GetResourceWithConfig(args utils.TenantID, reply *engine.ResourceWithConfig) error {
+	return rsv1.rls.V1GetResourceWithConfig(args, reply)
+}
+
 // GetResourceProfile returns a resource configuration
 func (apierV1 *ApierV1) GetResourceProfile(arg utils.TenantID, reply *engine.ResourceProfile) error {
 	if missing := utils.MissingStructFields(&arg, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
@@ -93,6 +98,9 @@ func (apierV1 *ApierV1) SetResourceProfile(res *engine.ResourceProfile, reply *s
 	if missing := utils.MissingStructFields(res, []string{"Tenant", "ID"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
+	if err := res.CheckQuotaPeriod(); err != nil {
+		return utils.APIErrorHandler(err)
+	}
 	if err := apierV1.DataManager.SetResourceProfile(res, true); err != nil {
 		return utils.APIErrorHandler(err)
 	}
@@ -45,7 +45,10 @@ func (ssv1 *SessionSv1) Handlers() map[string]interface{} {
 		utils.SessionSv1ProcessCDR:                 ssv1.BiRpcProcessCDR,
 		utils.SessionSv1ProcessEvent:               ssv1.BiRpcProcessEvent,
 		utils.SessionSv1GetActiveSessions:          ssv1.BiRPCV1GetActiveSessions,
+		utils.SessionSv1GetActiveSessionsCount:     ssv1.BiRPCV1GetActiveSessionsCount,
 		utils.SessionSv1GetPassiveSessions:         ssv1.BiRPCV1GetPassiveSessions,
+		utils.SessionSv1GetPassiveSessionsCount:    ssv1.BiRPCV1GetPassiveSessionsCount,
+		utils.SessionSv1ForceDisconnect:            ssv1.BiRPCV1ForceDisconnect,
 		utils.SessionSv1RegisterInternalBiJSONConn: ssv1.BiRPCv1RegisterInternalBiJSONConn,
 	}
 }
@@ -93,10 +96,22 @@ func (ssv1 *SessionSv1) GetActiveSessions(args map[string]string, rply *[]*sessi
 	return ssv1.SMG.BiRPCV1GetActiveSessions(nil, args, rply)
 }
 
+func (ssv1 *SessionSv1) GetActiveSessionsCount(args map[string]string, rply *int) error {
+	return ssv1.SMG.BiRPCV1GetActiveSessionsCount(nil, args, rply)
+}
+
 func (ssv1 *SessionSv1) GetPassiveSessions(args map[string]string, rply *[]*sessions.ActiveSession) error {
 	return ssv1.SMG.BiRPCV1GetPassiveSessions(nil, args, rply)
 }
 
+func (ssv1 *SessionSv1) GetPassiveSessionsCount(args map[string]string, rply *int) error {
+	return ssv1.SMG.BiRPCV1GetPassiveSessionsCount(nil, args, rply)
+}
+
+func (ssv1 *SessionSv1) ForceDisconnect(args utils.AttrForceDisconnect, rply *string) error {
+	return ssv1.SMG.BiRPCV1ForceDisconnect(nil, args, rply)
+}
+
 func (ssv1 *SessionSv1) BiRpcAuthorizeEvent(clnt *rpc2.Client, args *sessions.V1AuthorizeArgs,
 	rply *sessions.V1AuthorizeReply) error {
 	return ssv1.SMG.BiRPCv1AuthorizeEvent(clnt, args, rply)
@@ -141,11 +156,26 @@ func (ssv1 *SessionSv1) BiRPCV1GetActiveSessions(clnt *rpc2.Client, args map[str
 	return ssv1.SMG.BiRPCV1GetActiveSessions(clnt, args, rply)
 }
 
+func (ssv1 *SessionSv1) BiRPCV1GetActiveSessionsCount(clnt *rpc2.Client, args map[string]string,
+	rply *int) error {
+	return ssv1.SMG.BiRPCV1GetActiveSessionsCount(clnt, args, rply)
+}
+
 func (ssv1 *SessionSv1) BiRPCV1GetPassiveSessions(clnt *rpc2.Client, args map[string]string,
 	rply *[]*sessions.ActiveSession) error {
 	return ssv1.SMG.BiRPCV1GetPassiveSessions(clnt, args, rply)
 }
 
+func (ssv1 *SessionSv1) BiRPCV1GetPassiveSessionsCount(clnt *rpc2.Client, args map[string]string,
+	rply *int) error {
+	return ssv1.SMG.BiRPCV1GetPassiveSessionsCount(clnt, args, rply)
+}
+
+func (ssv1 *SessionSv1) BiRPCV1ForceDisconnect(clnt *rpc2.Client, args utils.AttrForceDisconnect,
+	rply *string) error {
+	return ssv1.SMG.BiRPCV1ForceDisconnect(clnt, args, rply)
+}
+
 func (ssv1 *SessionSv1) BiRPCv1RegisterInternalBiJSONConn(clnt *rpc2.Client, args string,
 	rply *string) error {
 	return ssv1.SMG.BiRPCv1RegisterInternalBiJSONConn(clnt, args, rply)
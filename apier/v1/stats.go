@@ -57,6 +57,9 @@ func (apierV1 *ApierV1) SetStatQueueProfile(sqp *engine.StatQueueProfile, reply
 	if missing := utils.MissingStructFields(sqp, []string{"Tenant", "ID"}); len(missing) != 0 {
 		return utils.NewErrMandatoryIeMissing(missing...)
 	}
+	if err := sqp.CheckBucketMetrics(); err != nil {
+		return utils.APIErrorHandler(err)
+	}
 	if err := apierV1.DataManager.SetStatQueueProfile(sqp, true); err != nil {
 		return utils.APIErrorHandler(err)
 	}
@@ -130,6 +133,11 @@ func (stsv1 *StatSv1) GetQueueFloatMetrics(args *utils.TenantID, reply *map[stri
 	return stsv1.sS.V1GetQueueFloatMetrics(args, reply)
 }
 
+// GetQueueHistory returns the metric snapshots stored for a Queue
+func (stsv1 *StatSv1) GetQueueHistory(args *engine.ArgsGetQueueHistory, reply *[]*engine.StatQueueSnapshot) (err error) {
+	return stsv1.sS.V1GetQueueHistory(args, reply)
+}
+
 func (stSv1 *StatSv1) Ping(ign string, reply *string) error {
 	*reply = utils.Pong
 	return nil
@@ -116,6 +116,11 @@ func (apierV1 *ApierV1) RemoveThresholdProfile(args *utils.TenantID, reply *stri
 	return nil
 }
 
+// ResetThreshold clears the hits of a Threshold
+func (tSv1 *ThresholdSv1) ResetThreshold(tntID *utils.TenantID, reply *string) error {
+	return tSv1.tS.V1ResetThreshold(tntID, reply)
+}
+
 func (tSv1 *ThresholdSv1) Ping(ign string, reply *string) error {
 	*reply = utils.Pong
 	return nil
@@ -0,0 +1,71 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"github.com/cgrates/cgrates/utils"
+)
+
+// AttrTimingID identifies a timing stored in DataDB
+type AttrTimingID struct {
+	ID string
+}
+
+// SetTiming stores a timing in DataDB, to be used by *timings filters
+func (self *ApierV1) SetTiming(attrs *utils.TPTiming, reply *string) error {
+	if missing := utils.MissingStructFields(attrs, []string{"ID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if err := self.DataManager.SetTiming(attrs); err != nil {
+		return utils.APIErrorHandler(err)
+	}
+	*reply = utils.OK
+	return nil
+}
+
+// GetTiming returns a timing stored in DataDB
+func (self *ApierV1) GetTiming(arg AttrTimingID, reply *utils.TPTiming) error {
+	if missing := utils.MissingStructFields(&arg, []string{"ID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	tmg, err := self.DataManager.GetTiming(arg.ID, true, utils.NonTransactional)
+	if err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	*reply = *tmg
+	return nil
+}
+
+// RemoveTiming removes a timing from DataDB
+func (self *ApierV1) RemoveTiming(arg AttrTimingID, reply *string) error {
+	if missing := utils.MissingStructFields(&arg, []string{"ID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if err := self.DataManager.RemoveTiming(arg.ID, utils.NonTransactional); err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	*reply = utils.OK
+	return nil
+}
@@ -325,13 +325,19 @@ func startAsteriskAgent(internalSMGChan chan rpcclient.RpcClientConnection, exit
 }
 
 func startDiameterAgent(internalSMGChan chan rpcclient.RpcClientConnection,
-	exitChan chan bool, filterSChan chan *engine.FilterS) {
+	exitChan chan bool, server *utils.Server, filterSChan chan *engine.FilterS) {
 	var err error
 	utils.Logger.Info("Starting CGRateS DiameterAgent service")
 	filterS := <-filterSChan
 	filterSChan <- filterS
-	var smgConn *rpcclient.RpcClientPool
-	if len(cfg.DiameterAgentCfg().SessionSConns) != 0 {
+	var smgConn rpcclient.RpcClientConnection
+	if len(cfg.DiameterAgentCfg().SessionSConns) == 1 &&
+		cfg.DiameterAgentCfg().SessionSConns[0].Address == utils.MetaInternal {
+		smgRpcConn := <-internalSMGChan
+		internalSMGChan <- smgRpcConn
+		// bidirectional so SessionS can request the disconnects
+		smgConn = utils.NewBiRPCInternalClient(smgRpcConn.(*sessions.SMGeneric))
+	} else if len(cfg.DiameterAgentCfg().SessionSConns) != 0 {
 		smgConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST,
 			cfg.TlsCfg().ClientKey,
 			cfg.TlsCfg().ClientCerificate, cfg.TlsCfg().CaCertificate,
@@ -352,6 +358,7 @@ func startDiameterAgent(internalSMGChan chan rpcclient.RpcClientConnection,
 		exitChan <- true
 		return
 	}
+	server.RpcRegister(v1.NewDiameterAgentV1(da))
 	if err = da.ListenAndServe(); err != nil {
 		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> error: %s!", err))
 	}
@@ -364,8 +371,14 @@ func startRadiusAgent(internalSMGChan chan rpcclient.RpcClientConnection, exitCh
 	filterSChan <- filterS
 	utils.Logger.Info("Starting CGRateS RadiusAgent service")
 	var err error
-	var smgConn *rpcclient.RpcClientPool
-	if len(cfg.RadiusAgentCfg().SessionSConns) != 0 {
+	var smgConn rpcclient.RpcClientConnection
+	if len(cfg.RadiusAgentCfg().SessionSConns) == 1 &&
+		cfg.RadiusAgentCfg().SessionSConns[0].Address == utils.MetaInternal {
+		smgRpcConn := <-internalSMGChan
+		internalSMGChan <- smgRpcConn
+		// bidirectional so SessionS can request the disconnects
+		smgConn = utils.NewBiRPCInternalClient(smgRpcConn.(*sessions.SMGeneric))
+	} else if len(cfg.RadiusAgentCfg().SessionSConns) != 0 {
 		smgConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST,
 			cfg.TlsCfg().ClientKey,
 			cfg.TlsCfg().ClientCerificate, cfg.TlsCfg().CaCertificate,
@@ -457,6 +470,61 @@ func startHTTPAgent(internalSMGChan chan rpcclient.RpcClientConnection,
 	}
 }
 
+// startPrometheusAgent registers the /metrics handler and starts counting the API calls
+func startPrometheusAgent(internalStatSChan, internalRsChan, internalSMGChan,
+	internalCacheSChan chan rpcclient.RpcClientConnection,
+	server *utils.Server, exitChan chan bool) {
+	utils.Logger.Info("Starting Prometheus agent")
+	rpcMtrs := utils.NewRPCMetrics()
+	server.SetRPCMetrics(rpcMtrs)
+	var statSConn, resSConn, sSConn, cacheSConn rpcclient.RpcClientConnection
+	var err error
+	for _, conns := range []struct {
+		subsys       string
+		connCfgs     []*config.HaPoolConfig
+		internalChan chan rpcclient.RpcClientConnection
+		conn         *rpcclient.RpcClientConnection
+	}{
+		{utils.StatS, cfg.PrometheusAgentCfg().StatSConns, internalStatSChan, &statSConn},
+		{utils.ResourceS, cfg.PrometheusAgentCfg().ResourceSConns, internalRsChan, &resSConn},
+		{utils.SessionS, cfg.PrometheusAgentCfg().SessionSConns, internalSMGChan, &sSConn},
+		{utils.CacheS, cfg.PrometheusAgentCfg().CacheSConns, internalCacheSChan, &cacheSConn},
+	} {
+		if len(conns.connCfgs) == 0 {
+			continue
+		}
+		if *conns.conn, err = engine.NewRPCPool(rpcclient.POOL_FIRST,
+			cfg.TlsCfg().ClientKey,
+			cfg.TlsCfg().ClientCerificate, cfg.TlsCfg().CaCertificate,
+			cfg.GeneralCfg().ConnectAttempts, cfg.GeneralCfg().Reconnects,
+			cfg.GeneralCfg().ConnectTimeout, cfg.GeneralCfg().ReplyTimeout,
+			conns.connCfgs, conns.internalChan,
+			cfg.GeneralCfg().InternalTtl); err != nil {
+			utils.Logger.Crit(fmt.Sprintf("<%s> could not connect to %s, error: %s",
+				utils.PrometheusAgent, conns.subsys, err.Error()))
+			exitChan <- true
+			return
+		}
+	}
+	server.RegisterHttpHandler(cfg.PrometheusAgentCfg().Path,
+		agents.NewPrometheusAgent(cfg.PrometheusAgentCfg(), cfg.GeneralCfg().DefaultTenant,
+			statSConn, resSConn, sSConn, cacheSConn, rpcMtrs))
+}
+
+// startFailedPostsReplayer fires up the background replay of the failed posts
+func startFailedPostsReplayer(server *utils.Server, exitChan chan bool) {
+	utils.Logger.Info("Starting CGRateS FailedPosts replayer.")
+	fpr := engine.NewFailedPostsReplayer(cfg.FailedPostsCfg(), cfg.GeneralCfg().FailedPostsDir)
+	go func() {
+		if err := fpr.ListenAndServe(exitChan); err != nil {
+			utils.Logger.Crit(fmt.Sprintf("<%s> error: %s", utils.FailedPostsReplayer, err.Error()))
+		}
+		fpr.Shutdown()
+		exitChan <- true
+	}()
+	server.RpcRegister(v1.NewFailedPostsV1(fpr))
+}
+
 func startCDRS(internalCdrSChan chan rpcclient.RpcClientConnection,
 	cdrDb engine.CdrStorage, dm *engine.DataManager,
 	internalRaterChan, internalPubSubSChan, internalAttributeSChan,
@@ -801,7 +869,8 @@ func startResourceService(internalRsChan chan rpcclient.RpcClientConnection, cac
 // startStatService fires up the StatS
 func startStatService(internalStatSChan chan rpcclient.RpcClientConnection, cacheS *engine.CacheS,
 	internalThresholdSChan chan rpcclient.RpcClientConnection, cfg *config.CGRConfig,
-	dm *engine.DataManager, server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS) {
+	dm *engine.DataManager, cdrDb engine.CdrStorage, server *utils.Server,
+	exitChan chan bool, filterSChan chan *engine.FilterS) {
 	var err error
 	var thdSConn *rpcclient.RpcClientPool
 	filterS := <-filterSChan
@@ -824,7 +893,7 @@ func startStatService(internalStatSChan chan rpcclient.RpcClientConnection, cach
 	<-cacheS.GetPrecacheChannel(utils.CacheStatQueues)
 
 	sS, err := engine.NewStatService(dm, cfg.StatSCfg().StoreInterval,
-		thdSConn, filterS, cfg.StatSCfg().StringIndexedFields, cfg.StatSCfg().PrefixIndexedFields)
+		thdSConn, cdrDb, filterS, cfg.StatSCfg().StringIndexedFields, cfg.StatSCfg().PrefixIndexedFields)
 	if err != nil {
 		utils.Logger.Crit(fmt.Sprintf("<StatS> Could not init, error: %s", err.Error()))
 		exitChan <- true
@@ -845,7 +914,8 @@ func startStatService(internalStatSChan chan rpcclient.RpcClientConnection, cach
 }
 
 // startThresholdService fires up the ThresholdS
-func startThresholdService(internalThresholdSChan chan rpcclient.RpcClientConnection,
+func startThresholdService(internalThresholdSChan, internalSMGChan,
+	internalRsChan chan rpcclient.RpcClientConnection,
 	cacheS *engine.CacheS, cfg *config.CGRConfig, dm *engine.DataManager,
 	server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS) {
 	filterS := <-filterSChan
@@ -872,6 +942,39 @@ func startThresholdService(internalThresholdSChan chan rpcclient.RpcClientConnec
 	tSv1 := v1.NewThresholdSv1(tS)
 	server.RpcRegister(tSv1)
 	internalThresholdSChan <- tSv1
+	// connect after publishing ThresholdS since SessionS and ResourceS can wait for it
+	var sSConn, rsConn rpcclient.RpcClientConnection
+	if len(cfg.ThresholdSCfg().SessionSConns) != 0 {
+		if sSConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST,
+			cfg.TlsCfg().ClientKey,
+			cfg.TlsCfg().ClientCerificate, cfg.TlsCfg().CaCertificate,
+			cfg.GeneralCfg().ConnectAttempts, cfg.GeneralCfg().Reconnects,
+			cfg.GeneralCfg().ConnectTimeout, cfg.GeneralCfg().ReplyTimeout,
+			cfg.ThresholdSCfg().SessionSConns, internalSMGChan,
+			cfg.GeneralCfg().InternalTtl); err != nil {
+			utils.Logger.Crit(fmt.Sprintf("<%s> could not connect to %s, error: %s",
+				utils.ThresholdS, utils.SessionS, err.Error()))
+			exitChan <- true
+			return
+		}
+	}
+	if len(cfg.ThresholdSCfg().ResourceSConns) != 0 {
+		if rsConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST,
+			cfg.TlsCfg().ClientKey,
+			cfg.TlsCfg().ClientCerificate, cfg.TlsCfg().CaCertificate,
+			cfg.GeneralCfg().ConnectAttempts, cfg.GeneralCfg().Reconnects,
+			cfg.GeneralCfg().ConnectTimeout, cfg.GeneralCfg().ReplyTimeout,
+			cfg.ThresholdSCfg().ResourceSConns, internalRsChan,
+			cfg.GeneralCfg().InternalTtl); err != nil {
+			utils.Logger.Crit(fmt.Sprintf("<%s> could not connect to %s, error: %s",
+				utils.ThresholdS, utils.ResourceS, err.Error()))
+			exitChan <- true
+			return
+		}
+	}
+	if sSConn != nil || rsConn != nil {
+		tS.SetNotificationConns(sSConn, rsConn)
+	}
 }
 
 // startSupplierService fires up the SupplierS
@@ -964,8 +1067,10 @@ func loaderService(cacheS *engine.CacheS, cfg *config.CGRConfig,
 // startDispatcherService fires up the DispatcherS
 func startDispatcherService(internalDispatcherSChan, internalRaterChan chan rpcclient.RpcClientConnection,
 	cacheS *engine.CacheS, dm *engine.DataManager,
-	server *utils.Server, exitChan chan bool) {
+	server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS) {
 	utils.Logger.Info("Starting CGRateS Dispatcher service.")
+	filterS := <-filterSChan
+	filterSChan <- filterS
 	var err error
 	var ralsConns, resSConns, threshSConns, statSConns, suplSConns, attrSConns, sessionsSConns, chargerSConns *rpcclient.RpcClientPool
 
@@ -1083,8 +1188,23 @@ func startDispatcherService(internalDispatcherSChan, internalRaterChan chan rpcc
 			return
 		}
 	}
-	dspS, err := dispatchers.NewDispatcherService(dm, ralsConns, resSConns,
-		threshSConns, statSConns, suplSConns, attrSConns, sessionsSConns, chargerSConns)
+	hostConns := make(map[string]*rpcclient.RpcClientPool)
+	for hostID, hostCfgs := range cfg.DispatcherSCfg().Hosts {
+		if hostConns[hostID], err = engine.NewRPCPool(rpcclient.POOL_FIRST,
+			cfg.TlsCfg().ClientKey,
+			cfg.TlsCfg().ClientCerificate, cfg.TlsCfg().CaCertificate,
+			cfg.GeneralCfg().ConnectAttempts, cfg.GeneralCfg().Reconnects,
+			cfg.GeneralCfg().ConnectTimeout, cfg.GeneralCfg().ReplyTimeout,
+			hostCfgs, nil, cfg.GeneralCfg().InternalTtl); err != nil {
+			utils.Logger.Crit(fmt.Sprintf("<%s> Could not connect to host with ID: <%s>: %s",
+				utils.DispatcherS, hostID, err.Error()))
+			exitChan <- true
+			return
+		}
+	}
+	dspS, err := dispatchers.NewDispatcherService(dm, cfg, filterS, hostConns,
+		ralsConns, resSConns, threshSConns, statSConns, suplSConns,
+		attrSConns, sessionsSConns, chargerSConns)
 	if err != nil {
 		utils.Logger.Crit(fmt.Sprintf("<%s> Could not init, error: %s", utils.DispatcherS, err.Error()))
 		exitChan <- true
@@ -1098,31 +1218,38 @@ func startDispatcherService(internalDispatcherSChan, internalRaterChan chan rpcc
 		exitChan <- true
 		return
 	}()
-	if !cfg.ThresholdSCfg().Enabled && len(cfg.DispatcherSCfg().ThreshSConns) != 0 {
+	if !cfg.ThresholdSCfg().Enabled && (len(cfg.DispatcherSCfg().ThreshSConns) != 0 ||
+		len(cfg.DispatcherSCfg().Hosts) != 0) {
 		server.RpcRegisterName(utils.ThresholdSv1,
 			v1.NewDispatcherThresholdSv1(dspS))
 	}
-	if !cfg.StatSCfg().Enabled && len(cfg.DispatcherSCfg().StatSConns) != 0 {
+	if !cfg.StatSCfg().Enabled && (len(cfg.DispatcherSCfg().StatSConns) != 0 ||
+		len(cfg.DispatcherSCfg().Hosts) != 0) {
 		server.RpcRegisterName(utils.StatSv1,
 			v1.NewDispatcherStatSv1(dspS))
 	}
-	if !cfg.ResourceSCfg().Enabled && len(cfg.DispatcherSCfg().ResSConns) != 0 {
+	if !cfg.ResourceSCfg().Enabled && (len(cfg.DispatcherSCfg().ResSConns) != 0 ||
+		len(cfg.DispatcherSCfg().Hosts) != 0) {
 		server.RpcRegisterName(utils.ResourceSv1,
 			v1.NewDispatcherResourceSv1(dspS))
 	}
-	if !cfg.SupplierSCfg().Enabled && len(cfg.DispatcherSCfg().SupplSConns) != 0 {
+	if !cfg.SupplierSCfg().Enabled && (len(cfg.DispatcherSCfg().SupplSConns) != 0 ||
+		len(cfg.DispatcherSCfg().Hosts) != 0) {
 		server.RpcRegisterName(utils.SupplierSv1,
 			v1.NewDispatcherSupplierSv1(dspS))
 	}
-	if !cfg.AttributeSCfg().Enabled && len(cfg.DispatcherSCfg().AttrSConns) != 0 {
+	if !cfg.AttributeSCfg().Enabled && (len(cfg.DispatcherSCfg().AttrSConns) != 0 ||
+		len(cfg.DispatcherSCfg().Hosts) != 0) {
 		server.RpcRegisterName(utils.AttributeSv1,
 			v1.NewDispatcherAttributeSv1(dspS))
 	}
-	if !cfg.SessionSCfg().Enabled && len(cfg.DispatcherSCfg().SessionSConns) != 0 {
+	if !cfg.SessionSCfg().Enabled && (len(cfg.DispatcherSCfg().SessionSConns) != 0 ||
+		len(cfg.DispatcherSCfg().Hosts) != 0) {
 		server.RpcRegisterName(utils.SessionSv1,
 			v1.NewDispatcherSessionSv1(dspS))
 	}
-	if !cfg.ChargerSCfg().Enabled && len(cfg.DispatcherSCfg().ChargerSConns) != 0 {
+	if !cfg.ChargerSCfg().Enabled && (len(cfg.DispatcherSCfg().ChargerSConns) != 0 ||
+		len(cfg.DispatcherSCfg().Hosts) != 0) {
 		server.RpcRegisterName(utils.ChargerSv1,
 			v1.NewDispatcherChargerSv1(dspS))
 	}
@@ -1133,12 +1260,14 @@ func startAnalyzerService(internalAnalyzerSChan chan rpcclient.RpcClientConnecti
 	server *utils.Server, exitChan chan bool) {
 	utils.Logger.Info("Starting CGRateS Analyzer service.")
 	var err error
-	aS, err := analyzers.NewAnalyzerService()
+	aS, err := analyzers.NewAnalyzerService(cfg)
 	if err != nil {
 		utils.Logger.Crit(fmt.Sprintf("<%s> Could not init, error: %s", utils.AnalyzerS, err.Error()))
 		exitChan <- true
 		return
 	}
+	server.SetAnalyzer(aS)
+	utils.SetInternalAnalyzer(aS)
 	go func() {
 		if err := aS.ListenAndServe(exitChan); err != nil {
 			utils.Logger.Crit(fmt.Sprintf("<%s> Error: %s listening for packets", utils.AnalyzerS, err.Error()))
@@ -1370,7 +1499,14 @@ func main() {
 			return
 		}
 	}
-	if cfg.RalsCfg().RALsEnabled || cfg.CdrsCfg().CDRSEnabled {
+	var statSnapshots bool // StatS needs StorDB only for the metric snapshots
+	if cfg.StatSCfg().Enabled {
+		if statSnapshots, err = engine.HasStatQueueSnapshots(dm); err != nil {
+			utils.Logger.Crit(fmt.Sprintf("Could not check StatQueue snapshots: %s exiting!", err))
+			return
+		}
+	}
+	if cfg.RalsCfg().RALsEnabled || cfg.CdrsCfg().CDRSEnabled || statSnapshots {
 		storDb, err := engine.ConfigureStorStorage(cfg.StorDbCfg().StorDBType,
 			cfg.StorDbCfg().StorDBHost, cfg.StorDbCfg().StorDBPort,
 			cfg.StorDbCfg().StorDBName, cfg.StorDbCfg().StorDBUser,
@@ -1402,7 +1538,8 @@ func main() {
 
 	// init cache
 	cacheS := engine.NewCacheS(cfg, dm)
-	server.RpcRegister(v1.NewCacheSv1(cacheS)) // before pre-caching so we can check status via API
+	cacheSv1 := v1.NewCacheSv1(cacheS)
+	server.RpcRegister(cacheSv1) // before pre-caching so we can check status via API
 	go func() {
 		if err := cacheS.Precache(); err != nil {
 			errCGR := err.(*utils.CGRError)
@@ -1432,6 +1569,8 @@ func main() {
 	filterSChan := make(chan *engine.FilterS, 1)
 	internalDispatcherSChan := make(chan rpcclient.RpcClientConnection, 1)
 	internalAnalyzerSChan := make(chan rpcclient.RpcClientConnection, 1)
+	internalCacheSChan := make(chan rpcclient.RpcClientConnection, 1)
+	internalCacheSChan <- cacheSv1
 
 	// Start ServiceManager
 	srvManager := servmanager.NewServiceManager(cfg, dm, exitChan, cacheS)
@@ -1495,7 +1634,7 @@ func main() {
 	}
 
 	if cfg.DiameterAgentCfg().Enabled {
-		go startDiameterAgent(internalSMGChan, exitChan, filterSChan)
+		go startDiameterAgent(internalSMGChan, exitChan, server, filterSChan)
 	}
 
 	if cfg.RadiusAgentCfg().Enabled {
@@ -1541,11 +1680,12 @@ func main() {
 
 	if cfg.StatSCfg().Enabled {
 		go startStatService(internalStatSChan, cacheS,
-			internalThresholdSChan, cfg, dm, server, exitChan, filterSChan)
+			internalThresholdSChan, cfg, dm, cdrDb, server, exitChan, filterSChan)
 	}
 
 	if cfg.ThresholdSCfg().Enabled {
-		go startThresholdService(internalThresholdSChan, cacheS,
+		go startThresholdService(internalThresholdSChan, internalSMGChan,
+			internalRsChan, cacheS,
 			cfg, dm, server, exitChan, filterSChan)
 	}
 
@@ -1556,13 +1696,22 @@ func main() {
 	}
 	if cfg.DispatcherSCfg().Enabled {
 		go startDispatcherService(internalDispatcherSChan,
-			internalRaterChan, cacheS, dm, server, exitChan)
+			internalRaterChan, cacheS, dm, server, exitChan, filterSChan)
 	}
 
 	if cfg.AnalyzerSCfg().Enabled {
 		go startAnalyzerService(internalAnalyzerSChan, server, exitChan)
 	}
 
+	if cfg.PrometheusAgentCfg().Enabled {
+		go startPrometheusAgent(internalStatSChan, internalRsChan, internalSMGChan,
+			internalCacheSChan, server, exitChan)
+	}
+
+	if cfg.FailedPostsCfg().Enabled {
+		go startFailedPostsReplayer(server, exitChan)
+	}
+
 	go loaderService(cacheS, cfg, dm, server, exitChan, filterSChan)
 
 	// Serve rpc connections
@@ -18,9 +18,17 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 
 package config
 
-// AttributeSCfg is the configuration of attribute service
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// AnalyzerSCfg is the configuration of analyzer service
 type AnalyzerSCfg struct {
 	Enabled bool
+	Limit   int           // maximum number of API calls kept in memory, -1 for no limit
+	TTL     time.Duration // keep the API calls for this duration, 0 to disable expiry
 }
 
 func (alS *AnalyzerSCfg) loadFromJsonCfg(jsnCfg *AnalyzerSJsonCfg) (err error) {
@@ -30,5 +38,13 @@ func (alS *AnalyzerSCfg) loadFromJsonCfg(jsnCfg *AnalyzerSJsonCfg) (err error) {
 	if jsnCfg.Enabled != nil {
 		alS.Enabled = *jsnCfg.Enabled
 	}
+	if jsnCfg.Limit != nil {
+		alS.Limit = *jsnCfg.Limit
+	}
+	if jsnCfg.Ttl != nil {
+		if alS.TTL, err = utils.ParseDurationWithNanosecs(*jsnCfg.Ttl); err != nil {
+			return
+		}
+	}
 	return nil
 }
@@ -145,6 +145,8 @@ func NewDefaultCGRConfig() (*CGRConfig, error) {
 	cfg.CdreProfiles = make(map[string]*CdreCfg)
 	cfg.CdrcProfiles = make(map[string][]*CdrcCfg)
 	cfg.analyzerSCfg = new(AnalyzerSCfg)
+	cfg.prometheusAgentCfg = new(PrometheusAgentCfg)
+	cfg.failedPostsCfg = new(FailedPostsCfg)
 	cfg.sessionSCfg = new(SessionSCfg)
 	cfg.fsAgentCfg = new(FsAgentCfg)
 	cfg.kamAgentCfg = new(KamAgentCfg)
@@ -281,35 +283,37 @@ type CGRConfig struct {
 
 	ConfigReloads map[string]chan struct{} // Signals to specific entities that a config reload should occur
 
-	generalCfg       *GeneralCfg       // General config
-	dataDbCfg        *DataDbCfg        // Database config
-	storDbCfg        *StorDbCfg        // StroreDb config
-	tlsCfg           *TlsCfg           // TLS config
-	cacheCfg         CacheCfg          // Cache config
-	listenCfg        *ListenCfg        // Listen config
-	httpCfg          *HTTPCfg          // HTTP config
-	filterSCfg       *FilterSCfg       // FilterS config
-	ralsCfg          *RalsCfg          // Rals config
-	schedulerCfg     *SchedulerCfg     // Scheduler config
-	cdrsCfg          *CdrsCfg          // Cdrs config
-	sessionSCfg      *SessionSCfg      // SessionS config
-	fsAgentCfg       *FsAgentCfg       // FreeSWITCHAgent config
-	kamAgentCfg      *KamAgentCfg      // KamailioAgent config
-	asteriskAgentCfg *AsteriskAgentCfg // AsteriskAgent config
-	diameterAgentCfg *DiameterAgentCfg // DiameterAgent config
-	radiusAgentCfg   *RadiusAgentCfg   // RadiusAgent config
-	attributeSCfg    *AttributeSCfg    // AttributeS config
-	chargerSCfg      *ChargerSCfg      // ChargerS config
-	resourceSCfg     *ResourceSConfig  // ResourceS config
-	statsCfg         *StatSCfg         // StatS config
-	thresholdSCfg    *ThresholdSCfg    // ThresholdS config
-	supplierSCfg     *SupplierSCfg     // SupplierS config
-	sureTaxCfg       *SureTaxCfg       // SureTax config
-	dispatcherSCfg   *DispatcherSCfg   // DispatcherS config
-	loaderCgrCfg     *LoaderCgrCfg     // LoaderCgr config
-	migratorCgrCfg   *MigratorCgrCfg   // MigratorCgr config
-	mailerCfg        *MailerCfg        // Mailer config
-	analyzerSCfg     *AnalyzerSCfg     // AnalyzerS config
+	generalCfg         *GeneralCfg         // General config
+	dataDbCfg          *DataDbCfg          // Database config
+	storDbCfg          *StorDbCfg          // StroreDb config
+	tlsCfg             *TlsCfg             // TLS config
+	cacheCfg           CacheCfg            // Cache config
+	listenCfg          *ListenCfg          // Listen config
+	httpCfg            *HTTPCfg            // HTTP config
+	filterSCfg         *FilterSCfg         // FilterS config
+	ralsCfg            *RalsCfg            // Rals config
+	schedulerCfg       *SchedulerCfg       // Scheduler config
+	cdrsCfg            *CdrsCfg            // Cdrs config
+	sessionSCfg        *SessionSCfg        // SessionS config
+	fsAgentCfg         *FsAgentCfg         // FreeSWITCHAgent config
+	kamAgentCfg        *KamAgentCfg        // KamailioAgent config
+	asteriskAgentCfg   *AsteriskAgentCfg   // AsteriskAgent config
+	diameterAgentCfg   *DiameterAgentCfg   // DiameterAgent config
+	radiusAgentCfg     *RadiusAgentCfg     // RadiusAgent config
+	attributeSCfg      *AttributeSCfg      // AttributeS config
+	chargerSCfg        *ChargerSCfg        // ChargerS config
+	resourceSCfg       *ResourceSConfig    // ResourceS config
+	statsCfg           *StatSCfg           // StatS config
+	thresholdSCfg      *ThresholdSCfg      // ThresholdS config
+	supplierSCfg       *SupplierSCfg       // SupplierS config
+	sureTaxCfg         *SureTaxCfg         // SureTax config
+	dispatcherSCfg     *DispatcherSCfg     // DispatcherS config
+	loaderCgrCfg       *LoaderCgrCfg       // LoaderCgr config
+	migratorCgrCfg     *MigratorCgrCfg     // MigratorCgr config
+	mailerCfg          *MailerCfg          // Mailer config
+	analyzerSCfg       *AnalyzerSCfg       // AnalyzerS config
+	prometheusAgentCfg *PrometheusAgentCfg // PrometheusAgent config
+	failedPostsCfg     *FailedPostsCfg     // FailedPosts replay config
 
 	// Deprecated
 	cdrStatsCfg          *CdrStatsCfg   // CdrStats config
@@ -629,6 +633,11 @@ func (self *CGRConfig) checkConfigSanity() error {
 			}
 		}
 	}
+	if self.diameterAgentCfg.Enabled &&
+		!utils.IsSliceMember([]string{utils.MetaDiamASR, utils.MetaRAR}, self.diameterAgentCfg.DisconnectMethod) {
+		return fmt.Errorf("<%s> unsupported disconnect_method: <%s>",
+			utils.DiameterAgent, self.diameterAgentCfg.DisconnectMethod)
+	}
 	if self.radiusAgentCfg.Enabled && !self.sessionSCfg.Enabled {
 		for _, raSMGConn := range self.radiusAgentCfg.SessionSConns {
 			if raSMGConn.Address == utils.MetaInternal {
@@ -636,6 +645,11 @@ func (self *CGRConfig) checkConfigSanity() error {
 			}
 		}
 	}
+	if self.radiusAgentCfg.Enabled &&
+		!utils.IsSliceMember([]string{utils.MetaDMR, utils.MetaCoA}, self.radiusAgentCfg.DisconnectMethod) {
+		return fmt.Errorf("<%s> unsupported disconnect_method: <%s>",
+			utils.RadiusAgent, self.radiusAgentCfg.DisconnectMethod)
+	}
 	if self.sessionSCfg.Enabled {
 		for _, httpAgentCfg := range self.httpAgentCfg {
 			// httpAgent checks
@@ -667,6 +681,21 @@ func (self *CGRConfig) checkConfigSanity() error {
 			}
 		}
 	}
+	// ThresholdS checks
+	if self.thresholdSCfg.Enabled && !self.sessionSCfg.Enabled {
+		for _, connCfg := range self.thresholdSCfg.SessionSConns {
+			if connCfg.Address == utils.MetaInternal {
+				return errors.New("SessionS not enabled but requested by ThresholdS component.")
+			}
+		}
+	}
+	if self.thresholdSCfg.Enabled && !self.resourceSCfg.Enabled {
+		for _, connCfg := range self.thresholdSCfg.ResourceSConns {
+			if connCfg.Address == utils.MetaInternal {
+				return errors.New("ResourceS not enabled but requested by ThresholdS component.")
+			}
+		}
+	}
 	// StatS checks
 	if self.statsCfg.Enabled && !self.thresholdSCfg.Enabled {
 		for _, connCfg := range self.statsCfg.ThresholdSConns {
@@ -707,6 +736,44 @@ func (self *CGRConfig) checkConfigSanity() error {
 			}
 		}
 	}
+	// PrometheusAgent checks
+	if self.prometheusAgentCfg.Enabled {
+		if !self.statsCfg.Enabled {
+			for _, connCfg := range self.prometheusAgentCfg.StatSConns {
+				if connCfg.Address == utils.MetaInternal {
+					return fmt.Errorf("%s not enabled but requested by %s component.",
+						utils.StatS, utils.PrometheusAgent)
+				}
+			}
+		}
+		if !self.resourceSCfg.Enabled {
+			for _, connCfg := range self.prometheusAgentCfg.ResourceSConns {
+				if connCfg.Address == utils.MetaInternal {
+					return fmt.Errorf("%s not enabled but requested by %s component.",
+						utils.ResourceS, utils.PrometheusAgent)
+				}
+			}
+		}
+		if !self.sessionSCfg.Enabled {
+			for _, connCfg := range self.prometheusAgentCfg.SessionSConns {
+				if connCfg.Address == utils.MetaInternal {
+					return fmt.Errorf("%s not enabled but requested by %s component.",
+						utils.SessionS, utils.PrometheusAgent)
+				}
+			}
+		}
+	}
+	// FailedPosts checks
+	if self.failedPostsCfg.Enabled {
+		if self.failedPostsCfg.ReplayInterval <= 0 {
+			return fmt.Errorf("<%s> replay_interval needs to be greater than 0",
+				utils.FailedPostsReplayer)
+		}
+		if self.generalCfg.FailedPostsDir == utils.META_NONE {
+			return fmt.Errorf("<%s> failed_posts_dir cannot be %s",
+				utils.FailedPostsReplayer, utils.META_NONE)
+		}
+	}
 	// DispaterS checks
 	if self.dispatcherSCfg.Enabled {
 		if !utils.IsSliceMember([]string{utils.MetaFirst, utils.MetaRandom, utils.MetaNext,
@@ -948,7 +1015,7 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 	if err != nil {
 		return err
 	}
-	if self.dispatcherSCfg.loadFromJsonCfg(jsnDispatcherCfg); err != nil {
+	if err = self.dispatcherSCfg.loadFromJsonCfg(jsnDispatcherCfg); err != nil {
 		return err
 	}
 
@@ -984,6 +1051,22 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		return err
 	}
 
+	jsnPrometheusAgentCfg, err := jsnCfg.PrometheusAgentJsonCfg()
+	if err != nil {
+		return err
+	}
+	if err := self.prometheusAgentCfg.loadFromJsonCfg(jsnPrometheusAgentCfg); err != nil {
+		return err
+	}
+
+	jsnFailedPostsCfg, err := jsnCfg.FailedPostsJsonCfg()
+	if err != nil {
+		return nil
+	}
+	if err := self.failedPostsCfg.loadFromJsonCfg(jsnFailedPostsCfg); err != nil {
+		return err
+	}
+
 	if jsnCdreCfg != nil {
 		for profileName, jsnCdre1Cfg := range jsnCdreCfg {
 			if _, hasProfile := self.CdreProfiles[profileName]; !hasProfile { // New profile, create before loading from json
@@ -1230,3 +1313,11 @@ func (cfg *CGRConfig) MailerCfg() *MailerCfg {
 func (cfg *CGRConfig) AnalyzerSCfg() *AnalyzerSCfg {
 	return cfg.analyzerSCfg
 }
+
+func (cfg *CGRConfig) PrometheusAgentCfg() *PrometheusAgentCfg {
+	return cfg.prometheusAgentCfg
+}
+
+func (cfg *CGRConfig) FailedPostsCfg() *FailedPostsCfg {
+	return cfg.failedPostsCfg
+}
@@ -142,12 +142,16 @@ const CGRATES_CFG_JSON = `
 	"supplier_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},		// control supplier profile caching
 	"attribute_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},		// control attribute profile caching
 	"charger_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},		// control charger profile caching
+	"dispatcher_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},	// control dispatcher profile caching
 	"resource_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 					// control resource filter indexes caching
 	"stat_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 						// control stat filter indexes caching
 	"threshold_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 				// control threshold filter indexes caching
 	"supplier_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 					// control supplier filter indexes caching
 	"attribute_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 				// control attribute filter indexes caching
 	"charger_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 					// control charger filter indexes caching
+	"dispatcher_filter_indexes" : {"limit": -1, "ttl": "", "static_ttl": false}, 				// control dispatcher filter indexes caching
+	"dispatcher_routes": {"limit": -1, "ttl": "1h", "static_ttl": false},						// hosts chosen by the *sticky dispatching strategy
+	"dispatchers": {"limit": -1, "ttl": "", "static_ttl": false},								// dispatchers built out of dispatcher profiles, keeping the strategy state
 },
 
 
@@ -198,7 +202,7 @@ const CGRATES_CFG_JSON = `
 
 "cdre": {
 	"*default": {
-		"export_format": "*file_csv",					// exported CDRs format <*file_csv|*file_fwv|*http_post|*http_json_cdr|*http_json_map|*amqp_json_cdr|*amqp_json_map>
+		"export_format": "*file_csv",					// exported CDRs format <*file_csv|*file_fwv|*http_post|*http_json_cdr|*http_json_map|*amqp_json_cdr|*amqp_json_map|*kafka_json_cdr|*kafka_json_map>
 		"export_path": "/var/spool/cgrates/cdre",		// path where the exported CDRs will be placed
 		"filters" :[],									// new filters for cdre
 		"tenant": "cgrates.org",						// tenant used in filterS.Pass
@@ -380,6 +384,7 @@ const CGRATES_CFG_JSON = `
 	"origin_realm": "cgrates.org",								// diameter Origin-Realm AVP used in replies
 	"vendor_id": 0,												// diameter Vendor-Id AVP used in replies
 	"product_name": "CGRateS",									// diameter Product-Name AVP used in replies
+	"disconnect_method": "*asr",								// request sent to the peer when SessionS disconnects a session <*asr|*rar>
 	"templates":{
 		"*cca": [
 				{"tag": "SessionId", "field_id": "Session-Id", "type": "*composed", 
@@ -394,6 +399,36 @@ const CGRATES_CFG_JSON = `
 					"value": "~*req.CC-Request-Type", "mandatory": true},
 				{"tag": "CCRequestNumber", "field_id": "CC-Request-Number", "type": "*composed", 
 					"value": "~*req.CC-Request-Number", "mandatory": true},
+		],
+		"*asr": [
+				{"tag": "SessionId", "field_id": "Session-Id", "type": "*composed",
+					"value": "~*req.Session-Id", "mandatory": true},
+				{"tag": "OriginHost", "field_id": "Origin-Host", "type": "*composed",
+					"value": "~*vars.OriginHost", "mandatory": true},
+				{"tag": "OriginRealm", "field_id": "Origin-Realm", "type": "*composed",
+					"value": "~*vars.OriginRealm", "mandatory": true},
+				{"tag": "DestinationHost", "field_id": "Destination-Host", "type": "*composed",
+					"value": "~*req.Origin-Host", "mandatory": true},
+				{"tag": "DestinationRealm", "field_id": "Destination-Realm", "type": "*composed",
+					"value": "~*req.Origin-Realm", "mandatory": true},
+				{"tag": "AuthApplicationId", "field_id": "Auth-Application-Id", "type": "*composed",
+					"value": "~*vars.*appid", "mandatory": true},
+		],
+		"*rar": [
+				{"tag": "SessionId", "field_id": "Session-Id", "type": "*composed",
+					"value": "~*req.Session-Id", "mandatory": true},
+				{"tag": "OriginHost", "field_id": "Origin-Host", "type": "*composed",
+					"value": "~*vars.OriginHost", "mandatory": true},
+				{"tag": "OriginRealm", "field_id": "Origin-Realm", "type": "*composed",
+					"value": "~*vars.OriginRealm", "mandatory": true},
+				{"tag": "DestinationHost", "field_id": "Destination-Host", "type": "*composed",
+					"value": "~*req.Origin-Host", "mandatory": true},
+				{"tag": "DestinationRealm", "field_id": "Destination-Realm", "type": "*composed",
+					"value": "~*req.Origin-Realm", "mandatory": true},
+				{"tag": "AuthApplicationId", "field_id": "Auth-Application-Id", "type": "*composed",
+					"value": "~*vars.*appid", "mandatory": true},
+				{"tag": "ReAuthRequestType", "field_id": "Re-Auth-Request-Type", "type": "*constant",
+					"value": "0", "mandatory": true},
 		]
 	},
 	"request_processors": [],
@@ -414,6 +449,22 @@ const CGRATES_CFG_JSON = `
 	"sessions_conns": [
 		{"address": "*internal"}								// connection towards SessionService
 	],
+	"client_da_addresses": {},									// Dynamic Authorization server of the client, RFC 5176 <*default|$client_ip>: <x.y.z.y:3799>
+	"disconnect_method": "*dmr",								// message sent when SessionS disconnects a session <*dmr|*coa>
+	"templates":{
+		"*dmr": [
+				{"tag": "UserName", "field_id": "User-Name", "type": "*composed",
+					"value": "~*req.Account", "mandatory": true},
+				{"tag": "AcctSessionId", "field_id": "Acct-Session-Id", "type": "*composed",
+					"value": "~*req.OriginID", "mandatory": true},
+		],
+		"*coa": [
+				{"tag": "UserName", "field_id": "User-Name", "type": "*composed",
+					"value": "~*req.Account", "mandatory": true},
+				{"tag": "AcctSessionId", "field_id": "Acct-Session-Id", "type": "*composed",
+					"value": "~*req.OriginID", "mandatory": true},
+		],
+	},
 	"request_processors": [],
 },
 
@@ -477,6 +528,8 @@ const CGRATES_CFG_JSON = `
 	"store_interval": "",					// dump cache regularly to dataDB, 0 - dump at start/shutdown: <""|$dur>
 	//"string_indexed_fields": [],			// query indexes based on these fields for faster processing
 	"prefix_indexed_fields": [],			// query indexes based on these fields for faster processing
+	"sessions_conns": [],					// connections to SessionS for *disconnect_session notifications: <""|*internal|x.y.z.y:1234>
+	"resources_conns": [],					// connections to ResourceS for *release_resource notifications: <""|*internal|x.y.z.y:1234>
 },
 
 
@@ -551,6 +604,8 @@ const CGRATES_CFG_JSON = `
 					{"tag": "Stored", "field_id": "Stored", "type": "*composed", "value": "~8"},
 					{"tag": "Weight", "field_id": "Weight", "type": "*composed", "value": "~9"},
 					{"tag": "ThresholdIDs", "field_id": "ThresholdIDs", "type": "*composed", "value": "~10"},
+					{"tag": "QuotaPeriod", "field_id": "QuotaPeriod", "type": "*composed", "value": "~11"},
+					{"tag": "QuotaTimezone", "field_id": "QuotaTimezone", "type": "*composed", "value": "~12"},
 				],
 			},
 			{
@@ -570,6 +625,8 @@ const CGRATES_CFG_JSON = `
 					{"tag": "Weight", "field_id": "Weight", "type": "*composed", "value": "~10"},
 					{"tag": "MinItems", "field_id": "MinItems", "type": "*composed", "value": "~11"},
 					{"tag": "ThresholdIDs", "field_id": "ThresholdIDs", "type": "*composed", "value": "~12"},
+					{"tag": "BucketInterval", "field_id": "BucketInterval", "type": "*composed", "value": "~13"},
+					{"tag": "SnapshotInterval", "field_id": "SnapshotInterval", "type": "*composed", "value": "~14"},
 				],
 			},
 			{
@@ -587,6 +644,9 @@ const CGRATES_CFG_JSON = `
 					{"tag": "Weight", "field_id": "Weight", "type": "*composed", "value": "~8"},
 					{"tag": "ActionIDs", "field_id": "ActionIDs", "type": "*composed", "value": "~9"},
 					{"tag": "Async", "field_id": "Async", "type": "*composed", "value": "~10"},
+					{"tag": "Notifications", "field_id": "Notifications", "type": "*composed", "value": "~11"},
+					{"tag": "HitsWindow", "field_id": "HitsWindow", "type": "*composed", "value": "~12"},
+					{"tag": "ResetInterval", "field_id": "ResetInterval", "type": "*composed", "value": "~13"},
 				],
 			},
 			{
@@ -624,6 +684,25 @@ const CGRATES_CFG_JSON = `
 					{"tag": "Weight", "field_id": "Weight", "type": "*composed", "value": "~6"},
 				],
 			},
+			{
+				"type": "*dispatchers",						// data source type
+				"file_name": "Dispatchers.csv",			// file name in the tp_in_dir
+				"fields": [
+					{"tag": "Tenant", "field_id": "Tenant", "type": "*composed", "value": "~0", "mandatory": true},
+					{"tag": "ID", "field_id": "ID", "type": "*composed", "value": "~1", "mandatory": true},
+					{"tag": "Subsystems", "field_id": "Subsystems", "type": "*composed", "value": "~2"},
+					{"tag": "FilterIDs", "field_id": "FilterIDs", "type": "*composed", "value": "~3"},
+					{"tag": "ActivationInterval", "field_id": "ActivationInterval", "type": "*composed", "value": "~4"},
+					{"tag": "Strategy", "field_id": "Strategy", "type": "*composed", "value": "~5"},
+					{"tag": "StrategyParameters", "field_id": "StrategyParameters", "type": "*composed", "value": "~6"},
+					{"tag": "HostID", "field_id": "HostID", "type": "*composed", "value": "~7"},
+					{"tag": "HostFilterIDs", "field_id": "HostFilterIDs", "type": "*composed", "value": "~8"},
+					{"tag": "HostWeight", "field_id": "HostWeight", "type": "*composed", "value": "~9"},
+					{"tag": "HostParameters", "field_id": "HostParameters", "type": "*composed", "value": "~10"},
+					{"tag": "HostBlocker", "field_id": "HostBlocker", "type": "*composed", "value": "~11"},
+					{"tag": "Weight", "field_id": "Weight", "type": "*composed", "value": "~12"},
+				],
+			},
 		],
 	},
 ],
@@ -707,12 +786,39 @@ const CGRATES_CFG_JSON = `
 	"attributes_conns": [],					// address where to reach the AttributeS <""|127.0.0.1:2013>
 	"sessions_conns": [],					// connection towards SessionService
 	"chargers_conns": [],					// address where to reach the ChargerS <""|127.0.0.1:2013>
-	"dispatching_strategy":"*first",		// strategy for dispatching <*first|*random|*next|*broadcast>
+	"dispatching_strategy":"*first",		// strategy for dispatching <*first|*random|*next|*broadcast>, DispatcherProfiles also support *sticky|*load
+	//"string_indexed_fields": [],			// query indexes based on these fields for faster processing
+	"prefix_indexed_fields": [],			// query indexes based on these fields for faster processing
+	"hosts": {},							// hosts used by the DispatcherProfiles, indexed on host ID: {"HOST1": [{"address": "127.0.0.1:2012"}]}
+	"load_refresh_interval": "0s",			// interval to query the hosts for their active sessions, used by *load strategy: <""|$dur>
 },
 
 
 "analyzers":{
-	"enabled":false							// starts AnalyzerS service: <true|false>.
+	"enabled":false,						// starts AnalyzerS service: <true|false>.
+	"limit": 10000,							// maximum number of API calls kept for querying, -1 for no limit
+	"ttl": "1h",							// discard the API calls older than this, <""|$dur>
+},
+
+
+"prometheus_agent": {
+	"enabled": false,						// serves the metrics in Prometheus text format: <true|false>
+	"path": "/metrics",						// HTTP path where the metrics are served
+	"stats_conns": [],						// connections to StatS for the StatQueue metrics, empty to disable: <""|*internal|x.y.z.y:1234>
+	"stat_queue_ids": [],					// StatQueue IDs to export, empty for all of the default tenant
+	"resources_conns": [],					// connections to ResourceS for the usages, empty to disable: <""|*internal|x.y.z.y:1234>
+	"resource_ids": [],						// Resource IDs of the default tenant to export
+	"sessions_conns": [],					// connections to SessionS for the sessions counts, empty to disable: <""|*internal|x.y.z.y:1234>
+	"caches_conns": [],						// connections to CacheS for the cache statistics, empty to disable: <""|*internal|x.y.z.y:1234>
+},
+
+
+"failed_posts": {
+	"enabled": false,						// replays periodically the posts out of failed_posts_dir: <true|false>
+	"replay_interval": "1m",				// delay before replaying a failed post, doubled with each new failure
+	"max_backoff": "1h",					// maximum delay between two replays of the same post
+	"max_age": "72h",						// posts failing for longer are moved to dead_letter_dir, 0 to replay forever
+	"dead_letter_dir": "/var/spool/cgrates/failed_posts_dead",	// directory where we move the posts we give up on, <*none> to remove them
 },
 
 
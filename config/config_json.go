@@ -68,6 +68,8 @@ const (
 	ChargerSCfgJson    = "chargers"
 	TlsCfgJson         = "tls"
 	AnalyzerCfgJson    = "analyzers"
+	PrometheusAgentJSN = "prometheus_agent"
+	FailedPostsJSN     = "failed_posts"
 )
 
 // Loads the json config out of io.Reader, eg other sources than file, maybe over http
@@ -524,3 +526,27 @@ func (self CgrJsonCfg) AnalyzerCfgJson() (*AnalyzerSJsonCfg, error) {
 	}
 	return cfg, nil
 }
+
+func (self CgrJsonCfg) PrometheusAgentJsonCfg() (*PrometheusAgentJsonCfg, error) {
+	rawCfg, hasKey := self[PrometheusAgentJSN]
+	if !hasKey {
+		return nil, nil
+	}
+	cfg := new(PrometheusAgentJsonCfg)
+	if err := json.Unmarshal(*rawCfg, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (self CgrJsonCfg) FailedPostsJsonCfg() (*FailedPostsJsonCfg, error) {
+	rawCfg, hasKey := self[FailedPostsJSN]
+	if !hasKey {
+		return nil, nil
+	}
+	cfg := new(FailedPostsJsonCfg)
+	if err := json.Unmarshal(*rawCfg, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -147,6 +147,9 @@ func TestCacheJsonCfg(t *testing.T) {
 		utils.CacheChargerProfiles: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
 			Precache: utils.BoolPointer(false)},
+		utils.CacheDispatcherProfiles: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
+			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
+			Precache: utils.BoolPointer(false)},
 		utils.CacheResourceFilterIndexes: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false)},
 		utils.CacheStatFilterIndexes: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
@@ -159,6 +162,12 @@ func TestCacheJsonCfg(t *testing.T) {
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false)},
 		utils.CacheChargerFilterIndexes: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false)},
+		utils.CacheDispatcherFilterIndexes: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
+			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false)},
+		utils.CacheDispatcherRoutes: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
+			Ttl: utils.StringPointer("1h"), Static_ttl: utils.BoolPointer(false)},
+		utils.CacheDispatchers: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
+			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false)},
 	}
 
 	if gCfg, err := dfCgrJsonCfg.CacheJsonCfg(); err != nil {
@@ -598,10 +607,11 @@ func TestDiameterAgentJsonCfg(t *testing.T) {
 			&HaPoolJsonCfg{
 				Address: utils.StringPointer(utils.MetaInternal),
 			}},
-		Origin_host:  utils.StringPointer("CGR-DA"),
-		Origin_realm: utils.StringPointer("cgrates.org"),
-		Vendor_id:    utils.IntPointer(0),
-		Product_name: utils.StringPointer("CGRateS"),
+		Origin_host:       utils.StringPointer("CGR-DA"),
+		Origin_realm:      utils.StringPointer("cgrates.org"),
+		Vendor_id:         utils.IntPointer(0),
+		Product_name:      utils.StringPointer("CGRateS"),
+		Disconnect_method: utils.StringPointer(utils.MetaDiamASR),
 		Templates: map[string][]*FcTemplateJsonCfg{
 			utils.MetaCCA: {
 				{Tag: utils.StringPointer("SessionId"),
@@ -635,6 +645,75 @@ func TestDiameterAgentJsonCfg(t *testing.T) {
 					Value:     utils.StringPointer("~*req.CC-Request-Number"),
 					Mandatory: utils.BoolPointer(true)},
 			},
+			utils.MetaDiamASR: {
+				{Tag: utils.StringPointer("SessionId"),
+					Field_id:  utils.StringPointer("Session-Id"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*req.Session-Id"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("OriginHost"),
+					Field_id:  utils.StringPointer("Origin-Host"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*vars.OriginHost"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("OriginRealm"),
+					Field_id:  utils.StringPointer("Origin-Realm"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*vars.OriginRealm"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("DestinationHost"),
+					Field_id:  utils.StringPointer("Destination-Host"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*req.Origin-Host"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("DestinationRealm"),
+					Field_id:  utils.StringPointer("Destination-Realm"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*req.Origin-Realm"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("AuthApplicationId"),
+					Field_id:  utils.StringPointer("Auth-Application-Id"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*vars.*appid"),
+					Mandatory: utils.BoolPointer(true)},
+			},
+			utils.MetaRAR: {
+				{Tag: utils.StringPointer("SessionId"),
+					Field_id:  utils.StringPointer("Session-Id"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*req.Session-Id"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("OriginHost"),
+					Field_id:  utils.StringPointer("Origin-Host"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*vars.OriginHost"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("OriginRealm"),
+					Field_id:  utils.StringPointer("Origin-Realm"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*vars.OriginRealm"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("DestinationHost"),
+					Field_id:  utils.StringPointer("Destination-Host"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*req.Origin-Host"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("DestinationRealm"),
+					Field_id:  utils.StringPointer("Destination-Realm"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*req.Origin-Realm"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("AuthApplicationId"),
+					Field_id:  utils.StringPointer("Auth-Application-Id"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*vars.*appid"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("ReAuthRequestType"),
+					Field_id:  utils.StringPointer("Re-Auth-Request-Type"),
+					Type:      utils.StringPointer(utils.META_CONSTANT),
+					Value:     utils.StringPointer("0"),
+					Mandatory: utils.BoolPointer(true)},
+			},
 		},
 		Request_processors: &[]*DARequestProcessorJsnCfg{},
 	}
@@ -661,6 +740,34 @@ func TestRadiusAgentJsonCfg(t *testing.T) {
 			&HaPoolJsonCfg{
 				Address: utils.StringPointer(utils.MetaInternal),
 			}},
+		Client_da_addresses: utils.MapStringStringPointer(map[string]string{}),
+		Disconnect_method:   utils.StringPointer(utils.MetaDMR),
+		Templates: map[string][]*FcTemplateJsonCfg{
+			utils.MetaDMR: {
+				{Tag: utils.StringPointer("UserName"),
+					Field_id:  utils.StringPointer("User-Name"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*req.Account"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("AcctSessionId"),
+					Field_id:  utils.StringPointer("Acct-Session-Id"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*req.OriginID"),
+					Mandatory: utils.BoolPointer(true)},
+			},
+			utils.MetaCoA: {
+				{Tag: utils.StringPointer("UserName"),
+					Field_id:  utils.StringPointer("User-Name"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*req.Account"),
+					Mandatory: utils.BoolPointer(true)},
+				{Tag: utils.StringPointer("AcctSessionId"),
+					Field_id:  utils.StringPointer("Acct-Session-Id"),
+					Type:      utils.StringPointer(utils.META_COMPOSED),
+					Value:     utils.StringPointer("~*req.OriginID"),
+					Mandatory: utils.BoolPointer(true)},
+			},
+		},
 		Request_processors: &[]*RAReqProcessorJsnCfg{},
 	}
 	if cfg, err := dfCgrJsonCfg.RadiusAgentJsonCfg(); err != nil {
@@ -790,6 +897,8 @@ func TestDfThresholdSJsonCfg(t *testing.T) {
 		Store_interval:        utils.StringPointer(""),
 		String_indexed_fields: nil,
 		Prefix_indexed_fields: &[]string{},
+		Sessions_conns:        &[]*HaPoolJsonCfg{},
+		Resources_conns:       &[]*HaPoolJsonCfg{},
 	}
 	if cfg, err := dfCgrJsonCfg.ThresholdSJsonCfg(); err != nil {
 		t.Error(err)
@@ -965,6 +1074,14 @@ func TestDfLoaderJsonCfg(t *testing.T) {
 							Field_id: utils.StringPointer("ThresholdIDs"),
 							Type:     utils.StringPointer(utils.META_COMPOSED),
 							Value:    utils.StringPointer("~10")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("QuotaPeriod"),
+							Field_id: utils.StringPointer("QuotaPeriod"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~11")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("QuotaTimezone"),
+							Field_id: utils.StringPointer("QuotaTimezone"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~12")},
 					},
 				},
 				&LoaderJsonDataType{
@@ -1025,6 +1142,14 @@ func TestDfLoaderJsonCfg(t *testing.T) {
 							Field_id: utils.StringPointer("ThresholdIDs"),
 							Type:     utils.StringPointer(utils.META_COMPOSED),
 							Value:    utils.StringPointer("~12")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("BucketInterval"),
+							Field_id: utils.StringPointer("BucketInterval"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~13")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("SnapshotInterval"),
+							Field_id: utils.StringPointer("SnapshotInterval"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~14")},
 					},
 				},
 				&LoaderJsonDataType{
@@ -1077,6 +1202,18 @@ func TestDfLoaderJsonCfg(t *testing.T) {
 							Field_id: utils.StringPointer("Async"),
 							Type:     utils.StringPointer(utils.META_COMPOSED),
 							Value:    utils.StringPointer("~10")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("Notifications"),
+							Field_id: utils.StringPointer("Notifications"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~11")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("HitsWindow"),
+							Field_id: utils.StringPointer("HitsWindow"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~12")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("ResetInterval"),
+							Field_id: utils.StringPointer("ResetInterval"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~13")},
 					},
 				},
 				&LoaderJsonDataType{
@@ -1187,6 +1324,66 @@ func TestDfLoaderJsonCfg(t *testing.T) {
 							Value:    utils.StringPointer("~6")},
 					},
 				},
+				&LoaderJsonDataType{
+					Type:      utils.StringPointer(utils.MetaDispatchers),
+					File_name: utils.StringPointer(utils.DispatchersCsv),
+					Fields: &[]*FcTemplateJsonCfg{
+						&FcTemplateJsonCfg{Tag: utils.StringPointer(utils.Tenant),
+							Field_id:  utils.StringPointer(utils.Tenant),
+							Type:      utils.StringPointer(utils.META_COMPOSED),
+							Value:     utils.StringPointer("~0"),
+							Mandatory: utils.BoolPointer(true)},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer(utils.ID),
+							Field_id:  utils.StringPointer(utils.ID),
+							Type:      utils.StringPointer(utils.META_COMPOSED),
+							Value:     utils.StringPointer("~1"),
+							Mandatory: utils.BoolPointer(true)},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("Subsystems"),
+							Field_id: utils.StringPointer("Subsystems"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~2")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("FilterIDs"),
+							Field_id: utils.StringPointer("FilterIDs"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~3")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("ActivationInterval"),
+							Field_id: utils.StringPointer("ActivationInterval"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~4")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("Strategy"),
+							Field_id: utils.StringPointer("Strategy"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~5")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("StrategyParameters"),
+							Field_id: utils.StringPointer("StrategyParameters"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~6")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("HostID"),
+							Field_id: utils.StringPointer("HostID"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~7")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("HostFilterIDs"),
+							Field_id: utils.StringPointer("HostFilterIDs"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~8")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("HostWeight"),
+							Field_id: utils.StringPointer("HostWeight"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~9")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("HostParameters"),
+							Field_id: utils.StringPointer("HostParameters"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~10")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("HostBlocker"),
+							Field_id: utils.StringPointer("HostBlocker"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~11")},
+						&FcTemplateJsonCfg{Tag: utils.StringPointer("Weight"),
+							Field_id: utils.StringPointer("Weight"),
+							Type:     utils.StringPointer(utils.META_COMPOSED),
+							Value:    utils.StringPointer("~12")},
+					},
+				},
 			},
 		},
 	}
@@ -1328,16 +1525,19 @@ func TestDfHttpJsonCfg(t *testing.T) {
 
 func TestDfDispatcherSJsonCfg(t *testing.T) {
 	eCfg := &DispatcherSJsonCfg{
-		Enabled:              utils.BoolPointer(false),
-		Rals_conns:           &[]*HaPoolJsonCfg{},
-		Resources_conns:      &[]*HaPoolJsonCfg{},
-		Thresholds_conns:     &[]*HaPoolJsonCfg{},
-		Stats_conns:          &[]*HaPoolJsonCfg{},
-		Suppliers_conns:      &[]*HaPoolJsonCfg{},
-		Attributes_conns:     &[]*HaPoolJsonCfg{},
-		Sessions_conns:       &[]*HaPoolJsonCfg{},
-		Chargers_conns:       &[]*HaPoolJsonCfg{},
-		Dispatching_strategy: utils.StringPointer(utils.MetaFirst),
+		Enabled:               utils.BoolPointer(false),
+		Rals_conns:            &[]*HaPoolJsonCfg{},
+		Resources_conns:       &[]*HaPoolJsonCfg{},
+		Thresholds_conns:      &[]*HaPoolJsonCfg{},
+		Stats_conns:           &[]*HaPoolJsonCfg{},
+		Suppliers_conns:       &[]*HaPoolJsonCfg{},
+		Attributes_conns:      &[]*HaPoolJsonCfg{},
+		Sessions_conns:        &[]*HaPoolJsonCfg{},
+		Chargers_conns:        &[]*HaPoolJsonCfg{},
+		Dispatching_strategy:  utils.StringPointer(utils.MetaFirst),
+		Prefix_indexed_fields: &[]string{},
+		Hosts:                 &map[string]*[]*HaPoolJsonCfg{},
+		Load_refresh_interval: utils.StringPointer("0s"),
 	}
 	if cfg, err := dfCgrJsonCfg.DispatcherSJsonCfg(); err != nil {
 		t.Error(err)
@@ -1413,6 +1613,8 @@ func TestDfTlsCfg(t *testing.T) {
 func TestDfAnalyzerCfg(t *testing.T) {
 	eCfg := &AnalyzerSJsonCfg{
 		Enabled: utils.BoolPointer(false),
+		Limit:   utils.IntPointer(10000),
+		Ttl:     utils.StringPointer("1h"),
 	}
 	if cfg, err := dfCgrJsonCfg.AnalyzerCfgJson(); err != nil {
 		t.Error(err)
@@ -1420,3 +1622,36 @@ func TestDfAnalyzerCfg(t *testing.T) {
 		t.Errorf("Expected: %+v, received: %+v", utils.ToJSON(eCfg), utils.ToJSON(cfg))
 	}
 }
+
+func TestDfPrometheusAgentJsonCfg(t *testing.T) {
+	eCfg := &PrometheusAgentJsonCfg{
+		Enabled:         utils.BoolPointer(false),
+		Path:            utils.StringPointer("/metrics"),
+		Stats_conns:     &[]*HaPoolJsonCfg{},
+		Stat_queue_ids:  &[]string{},
+		Resources_conns: &[]*HaPoolJsonCfg{},
+		Resource_ids:    &[]string{},
+		Sessions_conns:  &[]*HaPoolJsonCfg{},
+		Caches_conns:    &[]*HaPoolJsonCfg{},
+	}
+	if cfg, err := dfCgrJsonCfg.PrometheusAgentJsonCfg(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCfg, cfg) {
+		t.Errorf("Expected: %+v, received: %+v", utils.ToJSON(eCfg), utils.ToJSON(cfg))
+	}
+}
+
+func TestDfFailedPostsJsonCfg(t *testing.T) {
+	eCfg := &FailedPostsJsonCfg{
+		Enabled:         utils.BoolPointer(false),
+		Replay_interval: utils.StringPointer("1m"),
+		Max_backoff:     utils.StringPointer("1h"),
+		Max_age:         utils.StringPointer("72h"),
+		Dead_letter_dir: utils.StringPointer("/var/spool/cgrates/failed_posts_dead"),
+	}
+	if cfg, err := dfCgrJsonCfg.FailedPostsJsonCfg(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCfg, cfg) {
+		t.Errorf("Expected: %+v, received: %+v", utils.ToJSON(eCfg), utils.ToJSON(cfg))
+	}
+}
@@ -730,6 +730,8 @@ func TestCgrCfgJSONDefaultsCacheCFG(t *testing.T) {
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
 		utils.CacheChargerProfiles: &CacheParamCfg{Limit: -1,
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
+		utils.CacheDispatcherProfiles: &CacheParamCfg{Limit: -1,
+			TTL: time.Duration(0), StaticTTL: false, Precache: false},
 		utils.CacheResourceFilterIndexes: &CacheParamCfg{Limit: -1,
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
 		utils.CacheStatFilterIndexes: &CacheParamCfg{Limit: -1,
@@ -742,6 +744,12 @@ func TestCgrCfgJSONDefaultsCacheCFG(t *testing.T) {
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
 		utils.CacheChargerFilterIndexes: &CacheParamCfg{Limit: -1,
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
+		utils.CacheDispatcherFilterIndexes: &CacheParamCfg{Limit: -1,
+			TTL: time.Duration(0), StaticTTL: false, Precache: false},
+		utils.CacheDispatcherRoutes: &CacheParamCfg{Limit: -1,
+			TTL: time.Duration(1 * time.Hour), StaticTTL: false},
+		utils.CacheDispatchers: &CacheParamCfg{Limit: -1,
+			TTL: time.Duration(0), StaticTTL: false},
 	}
 
 	if !reflect.DeepEqual(eCacheCfg, cgrCfg.CacheCfg()) {
@@ -898,6 +906,8 @@ func TestCgrCfgJSONDefaultThresholdSCfg(t *testing.T) {
 		StoreInterval:       0,
 		StringIndexedFields: nil,
 		PrefixIndexedFields: &[]string{},
+		SessionSConns:       []*HaPoolConfig{},
+		ResourceSConns:      []*HaPoolConfig{},
 	}
 	if !reflect.DeepEqual(eThresholdSCfg, cgrCfg.thresholdSCfg) {
 		t.Errorf("received: %+v, expecting: %+v", eThresholdSCfg, cgrCfg.thresholdSCfg)
@@ -932,6 +942,7 @@ func TestCgrCfgJSONDefaultsDiameterAgentCfg(t *testing.T) {
 		OriginRealm:       "cgrates.org",
 		VendorId:          0,
 		ProductName:       "CGRateS",
+		DisconnectMethod:  utils.MetaDiamASR,
 		RequestProcessors: nil,
 	}
 
@@ -959,6 +970,14 @@ func TestCgrCfgJSONDefaultsDiameterAgentCfg(t *testing.T) {
 	if !reflect.DeepEqual(cgrCfg.diameterAgentCfg.ProductName, testDA.ProductName) {
 		t.Errorf("received: %+v, expecting: %+v", cgrCfg.diameterAgentCfg.ProductName, testDA.ProductName)
 	}
+	if cgrCfg.diameterAgentCfg.DisconnectMethod != testDA.DisconnectMethod {
+		t.Errorf("received: %+v, expecting: %+v", cgrCfg.diameterAgentCfg.DisconnectMethod, testDA.DisconnectMethod)
+	}
+	for _, tplID := range []string{utils.MetaCCA, utils.MetaDiamASR, utils.MetaRAR} {
+		if _, has := cgrCfg.diameterAgentCfg.Templates[tplID]; !has {
+			t.Errorf("missing template: %s", tplID)
+		}
+	}
 	if !reflect.DeepEqual(cgrCfg.diameterAgentCfg.RequestProcessors, testDA.RequestProcessors) {
 		t.Errorf("expecting: %+v, received: %+v", testDA.RequestProcessors, cgrCfg.diameterAgentCfg.RequestProcessors)
 	}
@@ -1047,7 +1066,23 @@ func TestRadiusAgentCfg(t *testing.T) {
 		ClientSecrets:      map[string]string{utils.META_DEFAULT: "CGRateS.org"},
 		ClientDictionaries: map[string]string{utils.META_DEFAULT: "/usr/share/cgrates/radius/dict/"},
 		SessionSConns:      []*HaPoolConfig{{Address: utils.MetaInternal}},
-		RequestProcessors:  nil,
+		ClientDaAddresses:  map[string]string{},
+		DisconnectMethod:   utils.MetaDMR,
+		Templates: map[string][]*FCTemplate{
+			utils.MetaDMR: {
+				{Tag: "UserName", FieldId: "User-Name", Type: utils.META_COMPOSED,
+					Value: NewRSRParsersMustCompile("~*req.Account", true), Mandatory: true},
+				{Tag: "AcctSessionId", FieldId: "Acct-Session-Id", Type: utils.META_COMPOSED,
+					Value: NewRSRParsersMustCompile("~*req.OriginID", true), Mandatory: true},
+			},
+			utils.MetaCoA: {
+				{Tag: "UserName", FieldId: "User-Name", Type: utils.META_COMPOSED,
+					Value: NewRSRParsersMustCompile("~*req.Account", true), Mandatory: true},
+				{Tag: "AcctSessionId", FieldId: "Acct-Session-Id", Type: utils.META_COMPOSED,
+					Value: NewRSRParsersMustCompile("~*req.OriginID", true), Mandatory: true},
+			},
+		},
+		RequestProcessors: nil,
 	}
 	if !reflect.DeepEqual(cgrCfg.radiusAgentCfg, testRA) {
 		t.Errorf("expecting: %+v, received: %+v", cgrCfg.radiusAgentCfg, testRA)
@@ -1230,6 +1265,14 @@ func TestCgrLoaderCfgITDefaults(t *testing.T) {
 							FieldId: "ThresholdIDs",
 							Type:    utils.META_COMPOSED,
 							Value:   NewRSRParsersMustCompile("~10", true)},
+						{Tag: "QuotaPeriod",
+							FieldId: "QuotaPeriod",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~11", true)},
+						{Tag: "QuotaTimezone",
+							FieldId: "QuotaTimezone",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~12", true)},
 					},
 				},
 				{
@@ -1290,6 +1333,14 @@ func TestCgrLoaderCfgITDefaults(t *testing.T) {
 							FieldId: "ThresholdIDs",
 							Type:    utils.META_COMPOSED,
 							Value:   NewRSRParsersMustCompile("~12", true)},
+						{Tag: "BucketInterval",
+							FieldId: "BucketInterval",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~13", true)},
+						{Tag: "SnapshotInterval",
+							FieldId: "SnapshotInterval",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~14", true)},
 					},
 				},
 				{
@@ -1342,6 +1393,18 @@ func TestCgrLoaderCfgITDefaults(t *testing.T) {
 							FieldId: "Async",
 							Type:    utils.META_COMPOSED,
 							Value:   NewRSRParsersMustCompile("~10", true)},
+						{Tag: "Notifications",
+							FieldId: "Notifications",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~11", true)},
+						{Tag: "HitsWindow",
+							FieldId: "HitsWindow",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~12", true)},
+						{Tag: "ResetInterval",
+							FieldId: "ResetInterval",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~13", true)},
 					},
 				},
 				{
@@ -1452,6 +1515,66 @@ func TestCgrLoaderCfgITDefaults(t *testing.T) {
 							Value:   NewRSRParsersMustCompile("~6", true)},
 					},
 				},
+				{
+					Type:     utils.MetaDispatchers,
+					Filename: utils.DispatchersCsv,
+					Fields: []*FCTemplate{
+						{Tag: "Tenant",
+							FieldId:   "Tenant",
+							Type:      utils.META_COMPOSED,
+							Value:     NewRSRParsersMustCompile("~0", true),
+							Mandatory: true},
+						{Tag: "ID",
+							FieldId:   "ID",
+							Type:      utils.META_COMPOSED,
+							Value:     NewRSRParsersMustCompile("~1", true),
+							Mandatory: true},
+						{Tag: "Subsystems",
+							FieldId: "Subsystems",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~2", true)},
+						{Tag: "FilterIDs",
+							FieldId: "FilterIDs",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~3", true)},
+						{Tag: "ActivationInterval",
+							FieldId: "ActivationInterval",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~4", true)},
+						{Tag: "Strategy",
+							FieldId: "Strategy",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~5", true)},
+						{Tag: "StrategyParameters",
+							FieldId: "StrategyParameters",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~6", true)},
+						{Tag: "HostID",
+							FieldId: "HostID",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~7", true)},
+						{Tag: "HostFilterIDs",
+							FieldId: "HostFilterIDs",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~8", true)},
+						{Tag: "HostWeight",
+							FieldId: "HostWeight",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~9", true)},
+						{Tag: "HostParameters",
+							FieldId: "HostParameters",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~10", true)},
+						{Tag: "HostBlocker",
+							FieldId: "HostBlocker",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~11", true)},
+						{Tag: "Weight",
+							FieldId: "Weight",
+							Type:    utils.META_COMPOSED,
+							Value:   NewRSRParsersMustCompile("~12", true)},
+					},
+				},
 			},
 		},
 	}
@@ -1473,6 +1596,9 @@ func TestCgrCfgJSONDefaultDispatcherSCfg(t *testing.T) {
 		SessionSConns:       []*HaPoolConfig{},
 		ChargerSConns:       []*HaPoolConfig{},
 		DispatchingStrategy: utils.MetaFirst,
+		PrefixIndexedFields: &[]string{},
+		Hosts:               map[string][]*HaPoolConfig{},
+		LoadRefreshInterval: 0,
 	}
 	if !reflect.DeepEqual(cgrCfg.dispatcherSCfg, eDspSCfg) {
 		t.Errorf("received: %+v, expecting: %+v", cgrCfg.dispatcherSCfg, eDspSCfg)
@@ -1676,8 +1802,41 @@ func TestCfgTlsCfg(t *testing.T) {
 func TestCgrCfgJSONDefaultAnalyzerSCfg(t *testing.T) {
 	aSCfg := &AnalyzerSCfg{
 		Enabled: false,
+		Limit:   10000,
+		TTL:     time.Duration(time.Hour),
 	}
 	if !reflect.DeepEqual(cgrCfg.analyzerSCfg, aSCfg) {
 		t.Errorf("received: %+v, expecting: %+v", cgrCfg.analyzerSCfg, aSCfg)
 	}
 }
+
+func TestCgrCfgJSONDefaultPrometheusAgentCfg(t *testing.T) {
+	paCfg := &PrometheusAgentCfg{
+		Enabled:        false,
+		Path:           "/metrics",
+		StatSConns:     []*HaPoolConfig{},
+		StatQueueIDs:   []string{},
+		ResourceSConns: []*HaPoolConfig{},
+		ResourceIDs:    []string{},
+		SessionSConns:  []*HaPoolConfig{},
+		CacheSConns:    []*HaPoolConfig{},
+	}
+	if !reflect.DeepEqual(cgrCfg.PrometheusAgentCfg(), paCfg) {
+		t.Errorf("received: %+v, expecting: %+v",
+			utils.ToJSON(cgrCfg.PrometheusAgentCfg()), utils.ToJSON(paCfg))
+	}
+}
+
+func TestCgrCfgJSONDefaultFailedPostsCfg(t *testing.T) {
+	fpCfg := &FailedPostsCfg{
+		Enabled:        false,
+		ReplayInterval: time.Minute,
+		MaxBackoff:     time.Hour,
+		MaxAge:         72 * time.Hour,
+		DeadLetterDir:  "/var/spool/cgrates/failed_posts_dead",
+	}
+	if !reflect.DeepEqual(cgrCfg.FailedPostsCfg(), fpCfg) {
+		t.Errorf("received: %+v, expecting: %+v",
+			utils.ToJSON(cgrCfg.FailedPostsCfg()), utils.ToJSON(fpCfg))
+	}
+}
@@ -31,6 +31,7 @@ type DiameterAgentCfg struct {
 	OriginRealm       string
 	VendorId          int
 	ProductName       string
+	DisconnectMethod  string // <*asr|*rar> request sent to the peer to disconnect a session
 	Templates         map[string][]*FCTemplate
 	RequestProcessors []*DARequestProcessor
 }
@@ -67,6 +68,9 @@ func (da *DiameterAgentCfg) loadFromJsonCfg(jsnCfg *DiameterAgentJsonCfg) (err e
 	if jsnCfg.Product_name != nil {
 		da.ProductName = *jsnCfg.Product_name
 	}
+	if jsnCfg.Disconnect_method != nil {
+		da.DisconnectMethod = *jsnCfg.Disconnect_method
+	}
 	if jsnCfg.Templates != nil {
 		if da.Templates == nil {
 			da.Templates = make(map[string][]*FCTemplate)
@@ -18,6 +18,12 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 
 package config
 
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
 // DispatcherSCfg is the configuration of dispatcher service
 type DispatcherSCfg struct {
 	Enabled             bool
@@ -30,6 +36,10 @@ type DispatcherSCfg struct {
 	SessionSConns       []*HaPoolConfig
 	ChargerSConns       []*HaPoolConfig
 	DispatchingStrategy string
+	StringIndexedFields *[]string
+	PrefixIndexedFields *[]string
+	Hosts               map[string][]*HaPoolConfig // connections to the hosts referenced in DispatcherProfiles
+	LoadRefreshInterval time.Duration              // interval to query the hosts for their active sessions, 0 to disable
 }
 
 func (dps *DispatcherSCfg) loadFromJsonCfg(jsnCfg *DispatcherSJsonCfg) (err error) {
@@ -98,5 +108,39 @@ func (dps *DispatcherSCfg) loadFromJsonCfg(jsnCfg *DispatcherSJsonCfg) (err erro
 	if jsnCfg.Dispatching_strategy != nil {
 		dps.DispatchingStrategy = *jsnCfg.Dispatching_strategy
 	}
+	if jsnCfg.String_indexed_fields != nil {
+		sif := make([]string, len(*jsnCfg.String_indexed_fields))
+		for i, fID := range *jsnCfg.String_indexed_fields {
+			sif[i] = fID
+		}
+		dps.StringIndexedFields = &sif
+	}
+	if jsnCfg.Prefix_indexed_fields != nil {
+		pif := make([]string, len(*jsnCfg.Prefix_indexed_fields))
+		for i, fID := range *jsnCfg.Prefix_indexed_fields {
+			pif[i] = fID
+		}
+		dps.PrefixIndexedFields = &pif
+	}
+	if jsnCfg.Hosts != nil {
+		if dps.Hosts == nil {
+			dps.Hosts = make(map[string][]*HaPoolConfig)
+		}
+		for hostID, jsnHaCfgs := range *jsnCfg.Hosts {
+			if jsnHaCfgs == nil {
+				continue
+			}
+			dps.Hosts[hostID] = make([]*HaPoolConfig, len(*jsnHaCfgs))
+			for idx, jsnHaCfg := range *jsnHaCfgs {
+				dps.Hosts[hostID][idx] = NewDfltHaPoolConfig()
+				dps.Hosts[hostID][idx].loadFromJsonCfg(jsnHaCfg)
+			}
+		}
+	}
+	if jsnCfg.Load_refresh_interval != nil {
+		if dps.LoadRefreshInterval, err = utils.ParseDurationWithNanosecs(*jsnCfg.Load_refresh_interval); err != nil {
+			return
+		}
+	}
 	return nil
 }
@@ -0,0 +1,62 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// FailedPostsCfg is the configuration of the background replay of failed posts
+type FailedPostsCfg struct {
+	Enabled        bool
+	ReplayInterval time.Duration // first delay between replays of a post
+	MaxBackoff     time.Duration // the delay between replays doubles up to this
+	MaxAge         time.Duration // posts older than this are moved to DeadLetterDir, 0 to replay forever
+	DeadLetterDir  string        // where the posts we give up on are moved, *none to remove them
+}
+
+func (fp *FailedPostsCfg) loadFromJsonCfg(jsnCfg *FailedPostsJsonCfg) (err error) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Enabled != nil {
+		fp.Enabled = *jsnCfg.Enabled
+	}
+	if jsnCfg.Replay_interval != nil {
+		if fp.ReplayInterval, err = utils.ParseDurationWithNanosecs(*jsnCfg.Replay_interval); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Max_backoff != nil {
+		if fp.MaxBackoff, err = utils.ParseDurationWithNanosecs(*jsnCfg.Max_backoff); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Max_age != nil {
+		if fp.MaxAge, err = utils.ParseDurationWithNanosecs(*jsnCfg.Max_age); err != nil {
+			return
+		}
+	}
+	if jsnCfg.Dead_letter_dir != nil {
+		fp.DeadLetterDir = *jsnCfg.Dead_letter_dir
+	}
+	return
+}
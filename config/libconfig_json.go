@@ -342,6 +342,7 @@ type DiameterAgentJsonCfg struct {
 	Origin_realm       *string
 	Vendor_id          *int
 	Product_name       *string
+	Disconnect_method  *string
 	Templates          map[string][]*FcTemplateJsonCfg
 	Request_processors *[]*DARequestProcessorJsnCfg
 }
@@ -367,8 +368,11 @@ type RadiusAgentJsonCfg struct {
 	Client_secrets      *map[string]string
 	Client_dictionaries *map[string]string
 	Sessions_conns      *[]*HaPoolJsonCfg
+	Client_da_addresses *map[string]string
+	Disconnect_method   *string
 	Tenant              *string
 	Timezone            *string
+	Templates           map[string][]*FcTemplateJsonCfg
 	Request_processors  *[]*RAReqProcessorJsnCfg
 }
 
@@ -467,6 +471,8 @@ type ThresholdSJsonCfg struct {
 	Store_interval        *string
 	String_indexed_fields *[]string
 	Prefix_indexed_fields *[]string
+	Sessions_conns        *[]*HaPoolJsonCfg
+	Resources_conns       *[]*HaPoolJsonCfg
 }
 
 // Supplier service config section
@@ -540,16 +546,20 @@ type SureTaxJsonCfg struct {
 
 // Dispatcher service config section
 type DispatcherSJsonCfg struct {
-	Enabled              *bool
-	Rals_conns           *[]*HaPoolJsonCfg
-	Resources_conns      *[]*HaPoolJsonCfg
-	Thresholds_conns     *[]*HaPoolJsonCfg
-	Stats_conns          *[]*HaPoolJsonCfg
-	Suppliers_conns      *[]*HaPoolJsonCfg
-	Attributes_conns     *[]*HaPoolJsonCfg
-	Sessions_conns       *[]*HaPoolJsonCfg
-	Chargers_conns       *[]*HaPoolJsonCfg
-	Dispatching_strategy *string
+	Enabled               *bool
+	Rals_conns            *[]*HaPoolJsonCfg
+	Resources_conns       *[]*HaPoolJsonCfg
+	Thresholds_conns      *[]*HaPoolJsonCfg
+	Stats_conns           *[]*HaPoolJsonCfg
+	Suppliers_conns       *[]*HaPoolJsonCfg
+	Attributes_conns      *[]*HaPoolJsonCfg
+	Sessions_conns        *[]*HaPoolJsonCfg
+	Chargers_conns        *[]*HaPoolJsonCfg
+	Dispatching_strategy  *string
+	String_indexed_fields *[]string
+	Prefix_indexed_fields *[]string
+	Hosts                 *map[string]*[]*HaPoolJsonCfg
+	Load_refresh_interval *string
 }
 
 type LoaderCfgJson struct {
@@ -603,4 +613,27 @@ type FcTemplateJsonCfg struct {
 // Analyzer service json config section
 type AnalyzerSJsonCfg struct {
 	Enabled *bool
+	Limit   *int
+	Ttl     *string
+}
+
+// PrometheusAgent json config section
+type PrometheusAgentJsonCfg struct {
+	Enabled         *bool
+	Path            *string
+	Stats_conns     *[]*HaPoolJsonCfg
+	Stat_queue_ids  *[]string
+	Resources_conns *[]*HaPoolJsonCfg
+	Resource_ids    *[]string
+	Sessions_conns  *[]*HaPoolJsonCfg
+	Caches_conns    *[]*HaPoolJsonCfg
+}
+
+// FailedPosts json config section
+type FailedPostsJsonCfg struct {
+	Enabled         *bool
+	Replay_interval *string
+	Max_backoff     *string
+	Max_age         *string
+	Dead_letter_dir *string
 }
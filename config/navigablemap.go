@@ -221,6 +221,45 @@ func (nM *NavigableMap) AsCGREvent(tnt string, pathSep string) (cgrEv *utils.CGR
 	return
 }
 
+// AsMapStringInterface returns the data as layered map[string]interface{}, ie: to be marshaled as JSON
+// treats particular case when the value of map is []*NMItem, multiple items becoming a list of values
+// items configured as XML attributes are not considered
+func (nM *NavigableMap) AsMapStringInterface() map[string]interface{} {
+	return layeredMapData(nM.data)
+}
+
+// layeredMapData will recursively go through map replacing the []*NMItem and *NavigableMap values
+func layeredMapData(data map[string]interface{}) (mp map[string]interface{}) {
+	mp = make(map[string]interface{}, len(data))
+	for k, v := range data {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			mp[k] = layeredMapData(val)
+		case *NavigableMap:
+			mp[k] = layeredMapData(val.data)
+		case []*NMItem:
+			var itmsData []interface{}
+			for _, nmItm := range val {
+				if nmItm.Config != nil &&
+					nmItm.Config.AttributeID != "" {
+					continue
+				}
+				itmsData = append(itmsData, nmItm.Data)
+			}
+			switch len(itmsData) {
+			case 0:
+			case 1:
+				mp[k] = itmsData[0]
+			default:
+				mp[k] = itmsData
+			}
+		default:
+			mp[k] = v
+		}
+	}
+	return
+}
+
 // XMLElement is specially crafted to be automatically marshalled by encoding/xml
 type XMLElement struct {
 	XMLName    xml.Name
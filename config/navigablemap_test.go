@@ -766,3 +766,39 @@ func TestNavMapAsCGREvent(t *testing.T) {
 		t.Errorf("expecting: %+v, \nreceived: %+v", utils.ToJSON(eEv), utils.ToJSON(cgrEv.Event))
 	}
 }
+
+func TestNavMapAsMapStringInterface(t *testing.T) {
+	nM := NewNavigableMap(map[string]interface{}{
+		"FirstLevel": map[string]interface{}{
+			"Field1": []*NMItem{
+				&NMItem{Path: []string{"FirstLevel", "Field1"},
+					Data: "Value1"},
+				&NMItem{Path: []string{"FirstLevel", "Field1"},
+					Data: "attrVal1",
+					Config: &FCTemplate{Tag: "AttributeTest",
+						AttributeID: "attribute1"}}},
+			"Field2": []*NMItem{
+				&NMItem{Path: []string{"FirstLevel", "Field2"},
+					Data: "Value2"},
+				&NMItem{Path: []string{"FirstLevel", "Field2"},
+					Data: 3}},
+		},
+		"Field3": NewNavigableMap(map[string]interface{}{
+			"Field4": "Value4",
+		}),
+		"Field5": 10,
+	})
+	eMp := map[string]interface{}{
+		"FirstLevel": map[string]interface{}{
+			"Field1": "Value1",
+			"Field2": []interface{}{"Value2", 3},
+		},
+		"Field3": map[string]interface{}{
+			"Field4": "Value4",
+		},
+		"Field5": 10,
+	}
+	if mp := nM.AsMapStringInterface(); !reflect.DeepEqual(eMp, mp) {
+		t.Errorf("expecting: %+v, \nreceived: %+v", utils.ToJSON(eMp), utils.ToJSON(mp))
+	}
+}
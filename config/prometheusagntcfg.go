@@ -0,0 +1,84 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+// PrometheusAgentCfg is the configuration of the /metrics exporter
+type PrometheusAgentCfg struct {
+	Enabled        bool
+	Path           string // HTTP path where the metrics are served
+	StatSConns     []*HaPoolConfig
+	StatQueueIDs   []string // StatQueues exported, empty for all of the default tenant
+	ResourceSConns []*HaPoolConfig
+	ResourceIDs    []string // Resources exported out of the default tenant
+	SessionSConns  []*HaPoolConfig
+	CacheSConns    []*HaPoolConfig
+}
+
+func (pa *PrometheusAgentCfg) loadFromJsonCfg(jsnCfg *PrometheusAgentJsonCfg) (err error) {
+	if jsnCfg == nil {
+		return
+	}
+	if jsnCfg.Enabled != nil {
+		pa.Enabled = *jsnCfg.Enabled
+	}
+	if jsnCfg.Path != nil {
+		pa.Path = *jsnCfg.Path
+	}
+	if jsnCfg.Stats_conns != nil {
+		pa.StatSConns = make([]*HaPoolConfig, len(*jsnCfg.Stats_conns))
+		for idx, jsnHaCfg := range *jsnCfg.Stats_conns {
+			pa.StatSConns[idx] = NewDfltHaPoolConfig()
+			pa.StatSConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
+	if jsnCfg.Stat_queue_ids != nil {
+		pa.StatQueueIDs = make([]string, len(*jsnCfg.Stat_queue_ids))
+		for i, sqID := range *jsnCfg.Stat_queue_ids {
+			pa.StatQueueIDs[i] = sqID
+		}
+	}
+	if jsnCfg.Resources_conns != nil {
+		pa.ResourceSConns = make([]*HaPoolConfig, len(*jsnCfg.Resources_conns))
+		for idx, jsnHaCfg := range *jsnCfg.Resources_conns {
+			pa.ResourceSConns[idx] = NewDfltHaPoolConfig()
+			pa.ResourceSConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
+	if jsnCfg.Resource_ids != nil {
+		pa.ResourceIDs = make([]string, len(*jsnCfg.Resource_ids))
+		for i, rsID := range *jsnCfg.Resource_ids {
+			pa.ResourceIDs[i] = rsID
+		}
+	}
+	if jsnCfg.Sessions_conns != nil {
+		pa.SessionSConns = make([]*HaPoolConfig, len(*jsnCfg.Sessions_conns))
+		for idx, jsnHaCfg := range *jsnCfg.Sessions_conns {
+			pa.SessionSConns[idx] = NewDfltHaPoolConfig()
+			pa.SessionSConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
+	if jsnCfg.Caches_conns != nil {
+		pa.CacheSConns = make([]*HaPoolConfig, len(*jsnCfg.Caches_conns))
+		for idx, jsnHaCfg := range *jsnCfg.Caches_conns {
+			pa.CacheSConns[idx] = NewDfltHaPoolConfig()
+			pa.CacheSConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
+	return
+}
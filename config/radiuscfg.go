@@ -30,6 +30,9 @@ type RadiusAgentCfg struct {
 	ClientSecrets      map[string]string
 	ClientDictionaries map[string]string
 	SessionSConns      []*HaPoolConfig
+	ClientDaAddresses  map[string]string // Dynamic Authorization servers (RFC 5176) of the clients
+	DisconnectMethod   string            // <*dmr|*coa> message sent to disconnect a session
+	Templates          map[string][]*FCTemplate
 	RequestProcessors  []*RARequestProcessor
 }
 
@@ -72,6 +75,27 @@ func (self *RadiusAgentCfg) loadFromJsonCfg(jsnCfg *RadiusAgentJsonCfg) (err err
 			self.SessionSConns[idx].loadFromJsonCfg(jsnHaCfg)
 		}
 	}
+	if jsnCfg.Client_da_addresses != nil {
+		if self.ClientDaAddresses == nil {
+			self.ClientDaAddresses = make(map[string]string)
+		}
+		for k, v := range *jsnCfg.Client_da_addresses {
+			self.ClientDaAddresses[k] = v
+		}
+	}
+	if jsnCfg.Disconnect_method != nil {
+		self.DisconnectMethod = *jsnCfg.Disconnect_method
+	}
+	if jsnCfg.Templates != nil {
+		if self.Templates == nil {
+			self.Templates = make(map[string][]*FCTemplate)
+		}
+		for k, jsnTpls := range jsnCfg.Templates {
+			if self.Templates[k], err = FCTemplatesFromFCTemplatesJsonCfg(jsnTpls); err != nil {
+				return
+			}
+		}
+	}
 	if jsnCfg.Request_processors != nil {
 		for _, reqProcJsn := range *jsnCfg.Request_processors {
 			rp := new(RARequestProcessor)
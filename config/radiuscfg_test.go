@@ -52,6 +52,10 @@ func TestRadiusAgentCfgloadFromJsonCfg(t *testing.T) {
 	"sessions_conns": [
 		{"address": "*internal"}								// connection towards SessionService
 	],
+	"client_da_addresses": {
+		"*default": "127.0.0.1:3799",
+	},
+	"disconnect_method": "*coa",
 	"request_processors": [],
 },
 }`
@@ -62,6 +66,8 @@ func TestRadiusAgentCfgloadFromJsonCfg(t *testing.T) {
 		ClientSecrets:      map[string]string{"*default": "CGRateS.org"},
 		ClientDictionaries: map[string]string{"*default": "/usr/share/cgrates/radius/dict/"},
 		SessionSConns:      []*HaPoolConfig{{Address: "*internal"}},
+		ClientDaAddresses:  map[string]string{"*default": "127.0.0.1:3799"},
+		DisconnectMethod:   utils.MetaCoA,
 	}
 	if jsnCfg, err := NewCgrJsonCfgFromReader(strings.NewReader(cfgJSONStr)); err != nil {
 		t.Error(err)
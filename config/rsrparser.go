@@ -113,6 +113,23 @@ func (prsrs RSRParsers) ParseDataProviderWithInterfaces(dP DataProvider, separat
 	return
 }
 
+// ParseDataProviderAsSlice will parse each of the parsers into separate items
+// keeping list values as separate items and ignoring the missing fields
+func (prsrs RSRParsers) ParseDataProviderAsSlice(dP DataProvider, separator string) (out []string, err error) {
+	for _, prsr := range prsrs {
+		var outPrsr []string
+		if outPrsr, err = prsr.ParseDataProviderAsSlice(dP, separator); err != nil {
+			if err != utils.ErrNotFound {
+				return nil, err
+			}
+			err = nil
+			continue
+		}
+		out = append(out, outPrsr...)
+	}
+	return
+}
+
 func NewRSRParser(parserRules string, allFiltersMatch bool) (rsrParser *RSRParser, err error) {
 	if len(parserRules) == 0 {
 		return
@@ -280,6 +297,41 @@ func (prsr *RSRParser) ParseDataProvider(dP DataProvider, separator string) (out
 	return prsr.ParseValue(outStr)
 }
 
+// ParseDataProviderAsSlice parses the field out of DataProvider, one item for each element of a list
+// items not passing the filters are ignored
+func (prsr *RSRParser) ParseDataProviderAsSlice(dP DataProvider, separator string) (out []string, err error) {
+	var outIface interface{}
+	if prsr.attrValue == "" {
+		if outIface, err = dP.FieldAsInterface(
+			strings.Split(prsr.attrName, separator)); err != nil {
+			return
+		}
+	}
+	var vals []interface{}
+	switch outVal := outIface.(type) {
+	case []string:
+		for _, val := range outVal {
+			vals = append(vals, val)
+		}
+	case []interface{}:
+		vals = outVal
+	default:
+		vals = []interface{}{outIface}
+	}
+	for _, val := range vals {
+		var outVal string
+		if outVal, err = prsr.ParseValue(val); err != nil {
+			if err != utils.ErrFilterNotPassingNoCaps {
+				return nil, err
+			}
+			err = nil
+			continue
+		}
+		out = append(out, outVal)
+	}
+	return
+}
+
 func (prsr *RSRParser) ParseDataProviderWithInterfaces(dP DataProvider, separator string) (out string, err error) {
 	var outIface interface{}
 	if prsr.attrValue == "" {
@@ -135,3 +135,17 @@ func TestRSRParsersParseEvent2(t *testing.T) {
 		t.Errorf("expecting: %s, received: %s", eOut, out)
 	}
 }
+
+func TestRSRParsersParseDataProviderAsSlice(t *testing.T) {
+	prsrs := NewRSRParsersMustCompile("~Rules;~Thresholds(!THD_ACNT_1001);~Missing;static_rule", true)
+	nM := NewNavigableMap(map[string]interface{}{
+		"Rules":      "rule1",
+		"Thresholds": []string{"THD_ACNT_1001", "THD_GOLD"},
+	})
+	eOut := []string{"rule1", "THD_GOLD", "static_rule"}
+	if out, err := prsrs.ParseDataProviderAsSlice(nM, utils.NestingSep); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eOut, out) {
+		t.Errorf("expecting: %+v, received: %+v", eOut, out)
+	}
+}
@@ -29,6 +29,8 @@ type ThresholdSCfg struct {
 	StoreInterval       time.Duration // Dump regularly from cache into dataDB
 	StringIndexedFields *[]string
 	PrefixIndexedFields *[]string
+	SessionSConns       []*HaPoolConfig // used by *disconnect_session notifications
+	ResourceSConns      []*HaPoolConfig // used by *release_resource notifications
 }
 
 func (t *ThresholdSCfg) loadFromJsonCfg(jsnCfg *ThresholdSJsonCfg) (err error) {
@@ -57,5 +59,19 @@ func (t *ThresholdSCfg) loadFromJsonCfg(jsnCfg *ThresholdSJsonCfg) (err error) {
 		}
 		t.PrefixIndexedFields = &pif
 	}
+	if jsnCfg.Sessions_conns != nil {
+		t.SessionSConns = make([]*HaPoolConfig, len(*jsnCfg.Sessions_conns))
+		for idx, jsnHaCfg := range *jsnCfg.Sessions_conns {
+			t.SessionSConns[idx] = NewDfltHaPoolConfig()
+			t.SessionSConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
+	if jsnCfg.Resources_conns != nil {
+		t.ResourceSConns = make([]*HaPoolConfig, len(*jsnCfg.Resources_conns))
+		for idx, jsnHaCfg := range *jsnCfg.Resources_conns {
+			t.ResourceSConns[idx] = NewDfltHaPoolConfig()
+			t.ResourceSConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
 	return nil
 }
@@ -22,6 +22,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/cgrates/cgrates/utils"
 )
 
 func TestThresholdSCfgloadFromJsonCfg(t *testing.T) {
@@ -42,11 +44,13 @@ func TestThresholdSCfgloadFromJsonCfg(t *testing.T) {
 	"store_interval": "2h",					// dump cache regularly to dataDB, 0 - dump at start/shutdown: <""|$dur>
 	//"string_indexed_fields": [],			// query indexes based on these fields for faster processing
 	"prefix_indexed_fields": ["index1", "index2"],			// query indexes based on these fields for faster processing
+	"sessions_conns": [{"address": "*internal"}],
 	},		
 }`
 	expected = ThresholdSCfg{
 		StoreInterval:       time.Duration(time.Hour * 2),
 		PrefixIndexedFields: &[]string{"index1", "index2"},
+		SessionSConns:       []*HaPoolConfig{{Address: utils.MetaInternal}},
 	}
 	if jsnCfg, err := NewCgrJsonCfgFromReader(strings.NewReader(cfgJSONStr)); err != nil {
 		t.Error(err)
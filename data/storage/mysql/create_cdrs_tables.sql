@@ -50,3 +50,15 @@ CREATE TABLE sessions_costs (
   KEY run_origin_idx (run_id, origin_id),
   KEY deleted_at_idx (deleted_at)
 );
+
+DROP TABLE IF EXISTS stat_queue_snapshots;
+CREATE TABLE stat_queue_snapshots (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  tenant varchar(64) NOT NULL,
+  queue_id varchar(64) NOT NULL,
+  metrics text NOT NULL,
+  snapshot_time datetime NOT NULL,
+  created_at TIMESTAMP NULL,
+  PRIMARY KEY (`id`),
+  KEY queue_time_idx (tenant, queue_id, snapshot_time)
+);
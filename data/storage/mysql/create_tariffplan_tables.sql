@@ -410,6 +410,8 @@ CREATE TABLE tp_resources (
   `stored` BOOLEAN NOT NULL,
   `weight` decimal(8,2) NOT NULL,
   `threshold_ids` varchar(64) NOT NULL,
+  `quota_period` varchar(16) NOT NULL,
+  `quota_timezone` varchar(64) NOT NULL,
   `created_at` TIMESTAMP,
   PRIMARY KEY (`pk`),
   KEY `tpid` (`tpid`),
@@ -437,6 +439,8 @@ CREATE TABLE tp_stats (
   `weight` decimal(8,2) NOT NULL,
   `min_items` int(11) NOT NULL,
   `threshold_ids` varchar(64) NOT NULL,
+  `bucket_interval` varchar(32) NOT NULL,
+  `snapshot_interval` varchar(32) NOT NULL,
   `created_at` TIMESTAMP,
   PRIMARY KEY (`pk`),
   KEY `tpid` (`tpid`),
@@ -462,6 +466,9 @@ CREATE TABLE tp_thresholds (
   `weight` decimal(8,2) NOT NULL,
   `action_ids` varchar(64) NOT NULL,
   `async` BOOLEAN NOT NULL,
+  `notifications` varchar(256) NOT NULL,
+  `hits_window` varchar(16) NOT NULL,
+  `reset_interval` varchar(16) NOT NULL,
   `created_at` TIMESTAMP,
   PRIMARY KEY (`pk`),
   KEY `tpid` (`tpid`),
@@ -568,6 +575,34 @@ CREATE TABLE tp_chargers (
     `id`,`filter_ids`,`run_id`,`attribute_ids`)
 );
 
+--
+-- Table structure for table `tp_dispatchers`
+--
+
+DROP TABLE IF EXISTS tp_dispatchers;
+CREATE TABLE tp_dispatchers (
+  `pk` int(11) NOT NULL AUTO_INCREMENT,
+  `tpid` varchar(64) NOT NULL,
+  `tenant` varchar(64) NOT NULL,
+  `id` varchar(64) NOT NULL,
+  `subsystems` varchar(64) NOT NULL,
+  `filter_ids` varchar(64) NOT NULL,
+  `activation_interval` varchar(64) NOT NULL,
+  `strategy` varchar(64) NOT NULL,
+  `strategy_parameters` varchar(64) NOT NULL,
+  `host_id` varchar(64) NOT NULL,
+  `host_filter_ids` varchar(64) NOT NULL,
+  `host_weight` decimal(8,2) NOT NULL,
+  `host_parameters` varchar(64) NOT NULL,
+  `host_blocker` BOOLEAN NOT NULL,
+  `weight` decimal(8,2) NOT NULL,
+  `created_at` TIMESTAMP,
+  PRIMARY KEY (`pk`),
+  KEY `tpid` (`tpid`),
+  UNIQUE KEY `unique_tp_dispatchers` (`tpid`,`tenant`,
+    `id`,`filter_ids`,`strategy`,`host_id`,`host_filter_ids`)
+);
+
 --
 -- Table structure for table `versions`
 --
@@ -57,3 +57,15 @@ DROP INDEX IF EXISTS run_origin_sessionscost_idx;
 CREATE INDEX run_origin_sessionscost_idx ON sessions_costs (run_id, origin_id);
 DROP INDEX IF EXISTS deleted_at_sessionscost_idx;
 CREATE INDEX deleted_at_sessionscost_idx ON sessions_costs (deleted_at);
+
+DROP TABLE IF EXISTS stat_queue_snapshots;
+CREATE TABLE stat_queue_snapshots (
+  id SERIAL PRIMARY KEY,
+  tenant VARCHAR(64) NOT NULL,
+  queue_id VARCHAR(64) NOT NULL,
+  metrics jsonb NOT NULL,
+  snapshot_time TIMESTAMP WITH TIME ZONE NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE
+);
+DROP INDEX IF EXISTS queue_time_statqueuesnapshot_idx;
+CREATE INDEX queue_time_statqueuesnapshot_idx ON stat_queue_snapshots (tenant, queue_id, snapshot_time);
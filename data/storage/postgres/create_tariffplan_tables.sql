@@ -406,6 +406,8 @@ CREATE TABLE tp_resources (
   "stored" BOOLEAN NOT NULL,
   "weight" NUMERIC(8,2) NOT NULL,
   "threshold_ids" varchar(64) NOT NULL,
+  "quota_period" varchar(16) NOT NULL,
+  "quota_timezone" varchar(64) NOT NULL,
   "created_at" TIMESTAMP WITH TIME ZONE
 );
 CREATE INDEX tp_resources_idx ON tp_resources (tpid);
@@ -433,6 +435,8 @@ CREATE TABLE tp_stats (
   "weight" decimal(8,2) NOT NULL,
   "min_items" INTEGER NOT NULL,
   "threshold_ids" varchar(64) NOT NULL,
+  "bucket_interval" varchar(32) NOT NULL,
+  "snapshot_interval" varchar(32) NOT NULL,
   "created_at" TIMESTAMP WITH TIME ZONE
 );
 CREATE INDEX tp_stats_idx ON tp_stats (tpid);
@@ -457,6 +461,9 @@ CREATE TABLE tp_thresholds (
   "weight" decimal(8,2) NOT NULL,
   "action_ids" varchar(64) NOT NULL,
   "async" BOOLEAN NOT NULL,
+  "notifications" varchar(256) NOT NULL,
+  "hits_window" varchar(16) NOT NULL,
+  "reset_interval" varchar(16) NOT NULL,
   "created_at" TIMESTAMP WITH TIME ZONE
 );
 CREATE INDEX tp_thresholds_idx ON tp_thresholds (tpid);
@@ -558,6 +565,33 @@ CREATE INDEX tp_suppliers_unique ON tp_suppliers  ("tpid",  "tenant", "id",
   CREATE INDEX tp_chargers_unique ON tp_chargers  ("tpid",  "tenant", "id",
     "filter_ids","run_id","attribute_ids");
 
+--
+-- Table structure for table `tp_dispatchers`
+--
+
+DROP TABLE IF EXISTS tp_dispatchers;
+CREATE TABLE tp_dispatchers (
+  "pk" SERIAL PRIMARY KEY,
+  "tpid" varchar(64) NOT NULL,
+  "tenant" varchar(64) NOT NULL,
+  "id" varchar(64) NOT NULL,
+  "subsystems" varchar(64) NOT NULL,
+  "filter_ids" varchar(64) NOT NULL,
+  "activation_interval" varchar(64) NOT NULL,
+  "strategy" varchar(64) NOT NULL,
+  "strategy_parameters" varchar(64) NOT NULL,
+  "host_id" varchar(64) NOT NULL,
+  "host_filter_ids" varchar(64) NOT NULL,
+  "host_weight" decimal(8,2) NOT NULL,
+  "host_parameters" varchar(64) NOT NULL,
+  "host_blocker" BOOLEAN NOT NULL,
+  "weight" decimal(8,2) NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX tp_dispatchers_ids ON tp_dispatchers (tpid);
+CREATE INDEX tp_dispatchers_unique ON tp_dispatchers  ("tpid", "tenant", "id",
+  "filter_ids","strategy","host_id","host_filter_ids");
+
 --
 -- Table structure for table `versions`
 --
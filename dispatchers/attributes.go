@@ -24,34 +24,26 @@ import (
 )
 
 func (dS *DispatcherService) AttributeSv1Ping(ign string, reply *string) error {
-	if dS.attrS == nil {
-		return utils.NewErrNotConnected(utils.AttributeS)
-	}
-	return dS.attrS.Call(utils.AttributeSv1Ping, ign, reply)
+	return dS.Dispatch(&utils.CGREvent{Tenant: dS.cfg.GeneralCfg().DefaultTenant}, utils.MetaAttributes, dS.attrS,
+		utils.AttributeSv1Ping, ign, reply)
 }
 
 func (dS *DispatcherService) AttributeSv1GetAttributeForEvent(args *ArgsAttrProcessEventWithApiKey,
 	reply *engine.AttributeProfile) (err error) {
-	if dS.attrS == nil {
-		return utils.NewErrNotConnected(utils.AttributeS)
-	}
 	if err = dS.authorize(utils.AttributeSv1GetAttributeForEvent, args.AttrArgsProcessEvent.CGREvent.Tenant,
 		args.APIKey, args.AttrArgsProcessEvent.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.attrS.Call(utils.AttributeSv1GetAttributeForEvent, args.AttrArgsProcessEvent, reply)
-
+	return dS.Dispatch(&args.AttrArgsProcessEvent.CGREvent, utils.MetaAttributes, dS.attrS,
+		utils.AttributeSv1GetAttributeForEvent, args.AttrArgsProcessEvent, reply)
 }
 
 func (dS *DispatcherService) AttributeSv1ProcessEvent(args *ArgsAttrProcessEventWithApiKey,
 	reply *engine.AttrSProcessEventReply) (err error) {
-	if dS.attrS == nil {
-		return utils.NewErrNotConnected(utils.AttributeS)
-	}
 	if err = dS.authorize(utils.AttributeSv1ProcessEvent, args.AttrArgsProcessEvent.CGREvent.Tenant,
 		args.APIKey, args.AttrArgsProcessEvent.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.attrS.Call(utils.AttributeSv1ProcessEvent, args.AttrArgsProcessEvent, reply)
-
+	return dS.Dispatch(&args.AttrArgsProcessEvent.CGREvent, utils.MetaAttributes, dS.attrS,
+		utils.AttributeSv1ProcessEvent, args.AttrArgsProcessEvent, reply)
 }
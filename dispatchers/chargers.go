@@ -24,32 +24,26 @@ import (
 )
 
 func (dS *DispatcherService) ChargerSv1Ping(ign string, reply *string) error {
-	if dS.chargerS == nil {
-		return utils.NewErrNotConnected(utils.ChargerS)
-	}
-	return dS.chargerS.Call(utils.ChargerSv1Ping, ign, reply)
+	return dS.Dispatch(&utils.CGREvent{Tenant: dS.cfg.GeneralCfg().DefaultTenant}, utils.MetaChargers, dS.chargerS,
+		utils.ChargerSv1Ping, ign, reply)
 }
 
 func (dS *DispatcherService) ChargerSv1GetChargersForEvent(args *CGREvWithApiKey,
 	reply *engine.ChargerProfiles) (err error) {
-	if dS.chargerS == nil {
-		return utils.NewErrNotConnected(utils.ChargerS)
-	}
 	if err = dS.authorize(utils.ChargerSv1GetChargersForEvent, args.CGREvent.Tenant,
 		args.APIKey, args.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.chargerS.Call(utils.ChargerSv1GetChargersForEvent, args.CGREvent, reply)
+	return dS.Dispatch(&args.CGREvent, utils.MetaChargers, dS.chargerS,
+		utils.ChargerSv1GetChargersForEvent, args.CGREvent, reply)
 }
 
 func (dS *DispatcherService) ChargerSv1ProcessEvent(args *CGREvWithApiKey,
 	reply *[]*engine.AttrSProcessEventReply) (err error) {
-	if dS.chargerS == nil {
-		return utils.NewErrNotConnected(utils.ChargerS)
-	}
 	if err = dS.authorize(utils.ChargerSv1ProcessEvent, args.CGREvent.Tenant,
 		args.APIKey, args.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.chargerS.Call(utils.ChargerSv1ProcessEvent, args.CGREvent, reply)
+	return dS.Dispatch(&args.CGREvent, utils.MetaChargers, dS.chargerS,
+		utils.ChargerSv1ProcessEvent, args.CGREvent, reply)
 }
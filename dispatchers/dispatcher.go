@@ -21,16 +21,20 @@ package dispatchers
 import (
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
+	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/utils"
 	"github.com/cgrates/rpcclient"
 )
 
 // NewDispatcherService initializes a DispatcherService
-func NewDispatcherService(dm *engine.DataManager, rals, resS, thdS,
-	statS, splS, attrS, sessionS, chargerS rpcclient.RpcClientConnection) (*DispatcherService, error) {
+func NewDispatcherService(dm *engine.DataManager, cfg *config.CGRConfig,
+	filterS *engine.FilterS, conns map[string]*rpcclient.RpcClientPool,
+	rals, resS, thdS, statS, splS, attrS, sessionS,
+	chargerS rpcclient.RpcClientConnection) (*DispatcherService, error) {
 	if rals != nil && reflect.ValueOf(rals).IsNil() {
 		rals = nil
 	}
@@ -56,32 +60,56 @@ func NewDispatcherService(dm *engine.DataManager, rals, resS, thdS,
 		chargerS = nil
 	}
 	return &DispatcherService{dm: dm,
-		rals:     rals,
-		resS:     resS,
-		thdS:     thdS,
-		statS:    statS,
-		splS:     splS,
-		attrS:    attrS,
-		sessionS: sessionS,
-		chargerS: chargerS}, nil
+		cfg:       cfg,
+		filterS:   filterS,
+		conns:     conns,
+		hLoads:    newHostLoads(),
+		stopLoads: make(chan struct{}),
+		rals:      rals,
+		resS:      resS,
+		thdS:      thdS,
+		statS:     statS,
+		splS:      splS,
+		attrS:     attrS,
+		sessionS:  sessionS,
+		chargerS:  chargerS}, nil
 }
 
 // DispatcherService  is the service handling dispatcher
 type DispatcherService struct {
-	dm       *engine.DataManager
-	rals     rpcclient.RpcClientConnection // RALs connections
-	resS     rpcclient.RpcClientConnection // ResourceS connections
-	thdS     rpcclient.RpcClientConnection // ThresholdS connections
-	statS    rpcclient.RpcClientConnection // StatS connections
-	splS     rpcclient.RpcClientConnection // SupplierS connections
-	attrS    rpcclient.RpcClientConnection // AttributeS connections
-	sessionS rpcclient.RpcClientConnection // SessionS server connections
-	chargerS rpcclient.RpcClientConnection // ChargerS server connections
+	dm        *engine.DataManager
+	cfg       *config.CGRConfig
+	filterS   *engine.FilterS
+	conns     map[string]*rpcclient.RpcClientPool // connections to the hosts referenced by DispatcherProfiles
+	dspsMux   sync.Mutex                          // one Dispatcher per profile is cached, keeping the strategy state
+	hLoads    *hostLoads                          // load of the hosts, used by *load strategy
+	stopLoads chan struct{}                       // stops refreshing the hosts load
+	stopOnce  sync.Once                           // Shutdown can be called more than once
+	rals      rpcclient.RpcClientConnection       // RALs connections
+	resS      rpcclient.RpcClientConnection       // ResourceS connections
+	thdS      rpcclient.RpcClientConnection       // ThresholdS connections
+	statS     rpcclient.RpcClientConnection       // StatS connections
+	splS      rpcclient.RpcClientConnection       // SupplierS connections
+	attrS     rpcclient.RpcClientConnection       // AttributeS connections
+	sessionS  rpcclient.RpcClientConnection       // SessionS server connections
+	chargerS  rpcclient.RpcClientConnection       // ChargerS server connections
+}
+
+// subsystemServices is used to report the missing connections for a subsystem
+var subsystemServices = map[string]string{
+	utils.MetaAttributes: utils.AttributeS,
+	utils.MetaChargers:   utils.ChargerS,
+	utils.MetaResources:  utils.ResourceS,
+	utils.MetaSessionS:   utils.SessionS,
+	utils.MetaStats:      utils.StatS,
+	utils.MetaSuppliers:  utils.SupplierS,
+	utils.MetaThresholds: utils.ThresholdS,
 }
 
 // ListenAndServe will initialize the service
 func (dS *DispatcherService) ListenAndServe(exitChan chan bool) error {
 	utils.Logger.Info("Starting Dispatcher service")
+	go dS.refreshHostLoads()
 	e := <-exitChan
 	exitChan <- e // put back for the others listening for shutdown request
 	return nil
@@ -90,10 +118,47 @@ func (dS *DispatcherService) ListenAndServe(exitChan chan bool) error {
 // Shutdown is called to shutdown the service
 func (dS *DispatcherService) Shutdown() error {
 	utils.Logger.Info(fmt.Sprintf("<%s> service shutdown initialized", utils.DispatcherS))
+	dS.stopOnce.Do(func() { close(dS.stopLoads) })
 	utils.Logger.Info(fmt.Sprintf("<%s> service shutdown complete", utils.DispatcherS))
 	return nil
 }
 
+// refreshHostLoads periodically queries the hosts for their active sessions
+func (dS *DispatcherService) refreshHostLoads() {
+	interval := dS.cfg.DispatcherSCfg().LoadRefreshInterval
+	if interval <= 0 {
+		return
+	}
+	tkr := time.NewTicker(interval)
+	defer tkr.Stop()
+	for {
+		dS.queryHostLoads()
+		select {
+		case <-dS.stopLoads:
+			return
+		case <-tkr.C:
+		}
+	}
+}
+
+// queryHostLoads updates the load of the hosts used by *load profiles with their active sessions
+func (dS *DispatcherService) queryHostLoads() {
+	for _, hostID := range dS.hLoads.hostIDs() {
+		conn, has := dS.conns[hostID]
+		if !has {
+			continue
+		}
+		var count int
+		if err := conn.Call(utils.SessionSv1GetActiveSessionsCount,
+			map[string]string{}, &count); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<%s> error: <%s> querying active sessions of host with ID: <%s>",
+				utils.DispatcherS, err.Error(), hostID))
+			continue
+		}
+		dS.hLoads.setActiveSessions(hostID, count)
+	}
+}
+
 func (dS *DispatcherService) authorizeEvent(ev *utils.CGREvent,
 	reply *engine.AttrSProcessEventReply) (err error) {
 	if dS.attrS == nil {
@@ -134,3 +199,130 @@ func (dS *DispatcherService) authorize(method, tenant, apiKey string, evTime *ti
 	}
 	return
 }
+
+// dispatcherProfileForEvent returns the matching DispatcherProfile with the highest weight
+func (dS *DispatcherService) dispatcherProfileForEvent(ev *utils.CGREvent,
+	subsys string) (matchedPrfl *engine.DispatcherProfile, err error) {
+	anyIdxKey := utils.ConcatenatedKey(ev.Tenant, utils.META_ANY)
+	idxKey := anyIdxKey
+	if subsys != "" {
+		idxKey = utils.ConcatenatedKey(ev.Tenant, subsys)
+	}
+	prflIDs, err := engine.MatchingItemIDsForEvent(ev.Event,
+		dS.cfg.DispatcherSCfg().StringIndexedFields, dS.cfg.DispatcherSCfg().PrefixIndexedFields,
+		dS.dm, utils.CacheDispatcherFilterIndexes, idxKey, dS.cfg.FilterSCfg().IndexedSelects)
+	if err != nil {
+		if err != utils.ErrNotFound || idxKey == anyIdxKey {
+			return nil, err
+		}
+		if prflIDs, err = engine.MatchingItemIDsForEvent(ev.Event,
+			dS.cfg.DispatcherSCfg().StringIndexedFields, dS.cfg.DispatcherSCfg().PrefixIndexedFields,
+			dS.dm, utils.CacheDispatcherFilterIndexes, anyIdxKey,
+			dS.cfg.FilterSCfg().IndexedSelects); err != nil {
+			return nil, err
+		}
+	}
+	for prflID := range prflIDs {
+		prfl, err := dS.dm.GetDispatcherProfile(ev.Tenant, prflID, true, true, utils.NonTransactional)
+		if err != nil {
+			if err == utils.ErrNotFound {
+				continue
+			}
+			return nil, err
+		}
+		if prfl.ActivationInterval != nil && ev.Time != nil &&
+			!prfl.ActivationInterval.IsActiveAtTime(*ev.Time) { // not active
+			continue
+		}
+		if pass, err := dS.filterS.Pass(ev.Tenant, prfl.FilterIDs,
+			config.NewNavigableMap(ev.Event)); err != nil {
+			return nil, err
+		} else if !pass {
+			continue
+		}
+		if matchedPrfl == nil || prfl.Weight > matchedPrfl.Weight {
+			matchedPrfl = prfl
+		}
+	}
+	if matchedPrfl == nil {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
+// cachedDispatcher is the Dispatcher cached for a profile together with the strategy it was built for
+type cachedDispatcher struct {
+	strategy string
+	d        Dispatcher
+}
+
+// dispatcherForProfile returns the Dispatcher of the profile, creating it on first use
+// the Dispatcher is cached on profile ID so it is cleared together with the profile
+func (dS *DispatcherService) dispatcherForProfile(pfl *engine.DispatcherProfile) (d Dispatcher, err error) {
+	tntID := pfl.TenantID()
+	dS.dspsMux.Lock()
+	defer dS.dspsMux.Unlock()
+	if x, has := engine.Cache.Get(utils.CacheDispatchers, tntID); has {
+		if cd := x.(*cachedDispatcher); cd.strategy == pfl.Strategy {
+			cd.d.SetProfile(pfl) // profile could be updated in the meantime
+			return cd.d, nil
+		}
+	}
+	if d, err = newDispatcher(pfl, dS.hLoads); err != nil {
+		return
+	}
+	engine.Cache.Set(utils.CacheDispatchers, tntID,
+		&cachedDispatcher{strategy: pfl.Strategy, d: d}, nil,
+		true, utils.NonTransactional)
+	return
+}
+
+// hostConnsForEvent returns the connections of the profile hosts passing their filters
+func (dS *DispatcherService) hostConnsForEvent(pfl *engine.DispatcherProfile,
+	ev *utils.CGREvent) (conns map[string]*rpcclient.RpcClientPool, err error) {
+	conns = make(map[string]*rpcclient.RpcClientPool)
+	for _, host := range pfl.Hosts {
+		conn, has := dS.conns[host.ID]
+		if !has {
+			utils.Logger.Warning(fmt.Sprintf("<%s> no connection for host with ID: <%s> in profile: <%s>",
+				utils.DispatcherS, host.ID, pfl.TenantID()))
+			continue
+		}
+		if len(host.FilterIDs) != 0 {
+			if pass, err := dS.filterS.Pass(ev.Tenant, host.FilterIDs,
+				config.NewNavigableMap(ev.Event)); err != nil {
+				return nil, err
+			} else if !pass {
+				continue
+			}
+		}
+		conns[host.ID] = conn
+	}
+	return
+}
+
+// Dispatch routes the API call over the hosts of the DispatcherProfile matching the event,
+// falling back on the subsystem connections from config when no profile matches
+func (dS *DispatcherService) Dispatch(ev *utils.CGREvent, subsys string,
+	fallbackConn rpcclient.RpcClientConnection,
+	serviceMethod string, args interface{}, reply interface{}) (err error) {
+	var pfl *engine.DispatcherProfile
+	if pfl, err = dS.dispatcherProfileForEvent(ev, subsys); err != nil {
+		if err != utils.ErrNotFound {
+			return utils.NewErrServerError(err)
+		}
+		if fallbackConn == nil {
+			return utils.NewErrNotConnected(subsystemServices[subsys])
+		}
+		return fallbackConn.Call(serviceMethod, args, reply)
+	}
+	var d Dispatcher
+	if d, err = dS.dispatcherForProfile(pfl); err != nil {
+		return utils.NewErrServerError(err)
+	}
+	var conns map[string]*rpcclient.RpcClientPool
+	if conns, err = dS.hostConnsForEvent(pfl, ev); err != nil {
+		return utils.NewErrServerError(err)
+	}
+	return d.Dispatch(conns, ev, serviceMethod, args, reply)
+}
@@ -0,0 +1,397 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package dispatchers
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/rpc"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+}
+
+// Dispatcher is responsible for routing requests to the hosts of a DispatcherProfile
+type Dispatcher interface {
+	// SetProfile is used to update the configuration information within dispatcher
+	// to make sure we take decisions based on latest config
+	SetProfile(pfl *engine.DispatcherProfile)
+	// HostIDs returns the ordered list of host IDs
+	HostIDs() (hostIDs []string)
+	// Dispatch is used to send the method over the connections given,
+	// hosts without connection are skipped
+	Dispatch(conns map[string]*rpcclient.RpcClientPool, ev *utils.CGREvent,
+		serviceMethod string, args interface{}, reply interface{}) (err error)
+}
+
+// newDispatcher constructs instances of Dispatcher based on the profile strategy
+func newDispatcher(pfl *engine.DispatcherProfile, hLoads *hostLoads) (d Dispatcher, err error) {
+	switch pfl.Strategy {
+	case utils.MetaFirst:
+		d = new(WeightDispatcher)
+	case utils.MetaRandom:
+		d = new(RandomDispatcher)
+	case utils.MetaNext:
+		d = new(RoundRobinDispatcher)
+	case utils.MetaBroadcast:
+		d = new(BroadcastDispatcher)
+	case utils.MetaSticky:
+		d = new(StickyDispatcher)
+	case utils.MetaLoad:
+		d = &LoadDispatcher{hLoads: hLoads}
+	default:
+		return nil, fmt.Errorf("unsupported dispatch strategy: <%s>", pfl.Strategy)
+	}
+	d.SetProfile(pfl)
+	return
+}
+
+// WeightDispatcher selects the next connection based on weight
+type WeightDispatcher struct {
+	sync.RWMutex
+	pfl     *engine.DispatcherProfile
+	hostIDs []string
+}
+
+func (wd *WeightDispatcher) SetProfile(pfl *engine.DispatcherProfile) {
+	hosts := make(engine.DispatcherHosts, len(pfl.Hosts))
+	copy(hosts, pfl.Hosts) // profile can be shared via cache, do not sort it in place
+	hosts.Sort()
+	wd.Lock()
+	wd.pfl = pfl
+	wd.hostIDs = hosts.HostIDs()
+	wd.Unlock()
+}
+
+func (wd *WeightDispatcher) HostIDs() (hostIDs []string) {
+	wd.RLock()
+	hostIDs = make([]string, len(wd.hostIDs))
+	copy(hostIDs, wd.hostIDs)
+	wd.RUnlock()
+	return
+}
+
+func (wd *WeightDispatcher) Dispatch(conns map[string]*rpcclient.RpcClientPool, ev *utils.CGREvent,
+	serviceMethod string, args interface{}, reply interface{}) (err error) {
+	_, err = callHosts(wd.HostIDs(), wd.blockers(), conns, serviceMethod, args, reply)
+	return
+}
+
+// blockers returns the IDs of the hosts marked as Blocker
+func (wd *WeightDispatcher) blockers() (blkrs utils.StringMap) {
+	wd.RLock()
+	blkrs = blockerHostIDs(wd.pfl)
+	wd.RUnlock()
+	return
+}
+
+// RandomDispatcher selects the next connection randomly
+type RandomDispatcher struct {
+	WeightDispatcher
+}
+
+func (rd *RandomDispatcher) Dispatch(conns map[string]*rpcclient.RpcClientPool, ev *utils.CGREvent,
+	serviceMethod string, args interface{}, reply interface{}) (err error) {
+	hostIDs := rd.HostIDs()
+	for i := len(hostIDs) - 1; i > 0; i-- {
+		j := rand.Intn(i + 1)
+		hostIDs[i], hostIDs[j] = hostIDs[j], hostIDs[i]
+	}
+	_, err = callHosts(hostIDs, rd.blockers(), conns, serviceMethod, args, reply)
+	return
+}
+
+// RoundRobinDispatcher selects the next connection in round-robin fashion
+type RoundRobinDispatcher struct {
+	WeightDispatcher
+	hostIdx int // used for the next connection
+	idxLk   sync.Mutex
+}
+
+func (rrd *RoundRobinDispatcher) Dispatch(conns map[string]*rpcclient.RpcClientPool, ev *utils.CGREvent,
+	serviceMethod string, args interface{}, reply interface{}) (err error) {
+	hostIDs := rrd.HostIDs()
+	if len(hostIDs) == 0 {
+		return utils.ErrHostNotFound
+	}
+	rrd.idxLk.Lock()
+	if rrd.hostIdx >= len(hostIDs) {
+		rrd.hostIdx = 0
+	}
+	startIdx := rrd.hostIdx
+	rrd.hostIdx++
+	rrd.idxLk.Unlock()
+	_, err = callHosts(append(hostIDs[startIdx:], hostIDs[:startIdx]...),
+		rrd.blockers(), conns, serviceMethod, args, reply)
+	return
+}
+
+// BroadcastDispatcher will send the request to all the hosts
+// the reply is the one of the first host answering without error
+type BroadcastDispatcher struct {
+	WeightDispatcher
+}
+
+func (bd *BroadcastDispatcher) Dispatch(conns map[string]*rpcclient.RpcClientPool, ev *utils.CGREvent,
+	serviceMethod string, args interface{}, reply interface{}) (err error) {
+	hostIDs := bd.HostIDs()
+	err = utils.ErrHostNotFound
+	var hasReply bool
+	for _, hostID := range hostIDs {
+		conn, has := conns[hostID]
+		if !has {
+			continue
+		}
+		rpl := reply
+		if hasReply { // do not overwrite the reply already received
+			rpl = reflect.New(reflect.TypeOf(reply).Elem()).Interface()
+		}
+		if errCall := conn.Call(serviceMethod, args, rpl); errCall != nil {
+			if !hasReply {
+				err = errCall
+			}
+			utils.Logger.Warning(fmt.Sprintf("<%s> error: <%s> broadcasting %s to host with ID: <%s>",
+				utils.DispatcherS, errCall.Error(), serviceMethod, hostID))
+			continue
+		}
+		hasReply = true
+		err = nil
+	}
+	return
+}
+
+// StickyDispatcher sends the requests having the same value in the key field
+// to the same host, failing over by weight if the sticky one is unreachable
+type StickyDispatcher struct {
+	WeightDispatcher
+}
+
+// routeID returns the cache key of the route, empty if the event is missing the key field
+func (sd *StickyDispatcher) routeID(ev *utils.CGREvent) string {
+	keyField := utils.OriginID
+	sd.RLock()
+	tntID := sd.pfl.TenantID()
+	if fld, has := sd.pfl.StrategyParams[utils.MetaKeyField]; has {
+		if fldStr, err := utils.IfaceAsString(fld); err == nil && fldStr != "" {
+			keyField = fldStr
+		}
+	}
+	sd.RUnlock()
+	if ev == nil {
+		return ""
+	}
+	keyVal, err := ev.FieldAsString(keyField)
+	if err != nil || keyVal == "" {
+		return ""
+	}
+	return utils.ConcatenatedKey(tntID, keyVal)
+}
+
+func (sd *StickyDispatcher) Dispatch(conns map[string]*rpcclient.RpcClientPool, ev *utils.CGREvent,
+	serviceMethod string, args interface{}, reply interface{}) (err error) {
+	hostIDs := sd.HostIDs()
+	routeID := sd.routeID(ev)
+	if routeID == "" { // nothing to stick on
+		_, err = callHosts(hostIDs, sd.blockers(), conns, serviceMethod, args, reply)
+		return
+	}
+	if x, has := engine.Cache.Get(utils.CacheDispatcherRoutes, routeID); has {
+		hostIDs = hostIDsWithFirst(hostIDs, x.(string))
+	}
+	var hostID string
+	if hostID, err = callHosts(hostIDs, sd.blockers(), conns,
+		serviceMethod, args, reply); hostID != "" {
+		engine.Cache.Set(utils.CacheDispatcherRoutes, routeID, hostID,
+			nil, true, utils.NonTransactional)
+	}
+	return
+}
+
+// LoadDispatcher sends the request to the host with the lowest load,
+// the load being the active sessions reported by the host plus
+// the requests in progress from this dispatcher
+type LoadDispatcher struct {
+	WeightDispatcher
+	hLoads *hostLoads
+}
+
+// SetProfile registers the hosts of the profile to be queried for their load
+func (ld *LoadDispatcher) SetProfile(pfl *engine.DispatcherProfile) {
+	ld.WeightDispatcher.SetProfile(pfl)
+	ld.hLoads.addHosts(ld.HostIDs())
+}
+
+func (ld *LoadDispatcher) Dispatch(conns map[string]*rpcclient.RpcClientPool, ev *utils.CGREvent,
+	serviceMethod string, args interface{}, reply interface{}) (err error) {
+	hostIDs := ld.hLoads.sortByLoad(ld.HostIDs())
+	blkrs := ld.blockers()
+	err = utils.ErrHostNotFound
+	for _, hostID := range hostIDs {
+		if conn, has := conns[hostID]; has {
+			ld.hLoads.incOutstanding(hostID, 1)
+			err = conn.Call(serviceMethod, args, reply)
+			ld.hLoads.incOutstanding(hostID, -1)
+			if !isNetworkError(err) {
+				return
+			}
+		}
+		if blkrs.HasKey(hostID) { // no failover past a blocker host
+			break
+		}
+	}
+	return
+}
+
+func newHostLoads() *hostLoads {
+	return &hostLoads{
+		hosts:          make(utils.StringMap),
+		activeSessions: make(map[string]int),
+		outstanding:    make(map[string]int),
+	}
+}
+
+// hostLoads keeps the load of the hosts, shared by all the LoadDispatchers
+type hostLoads struct {
+	sync.RWMutex
+	hosts          utils.StringMap // hosts referenced by *load profiles, queried on refresh
+	activeSessions map[string]int  // as reported by the hosts on last refresh
+	outstanding    map[string]int  // requests sent to the host and not yet answered
+}
+
+// load returns the current load of the host
+// needs to be called under lock
+func (hl *hostLoads) load(hostID string) int {
+	return hl.activeSessions[hostID] + hl.outstanding[hostID]
+}
+
+// addHosts registers the hosts to be queried for their active sessions
+func (hl *hostLoads) addHosts(hostIDs []string) {
+	hl.Lock()
+	for _, hostID := range hostIDs {
+		hl.hosts[hostID] = true
+	}
+	hl.Unlock()
+}
+
+// hostIDs returns the hosts to be queried for their active sessions
+func (hl *hostLoads) hostIDs() (hostIDs []string) {
+	hl.RLock()
+	hostIDs = hl.hosts.Slice()
+	hl.RUnlock()
+	return
+}
+
+func (hl *hostLoads) setActiveSessions(hostID string, count int) {
+	hl.Lock()
+	hl.activeSessions[hostID] = count
+	hl.Unlock()
+}
+
+func (hl *hostLoads) incOutstanding(hostID string, delta int) {
+	hl.Lock()
+	hl.outstanding[hostID] += delta
+	hl.Unlock()
+}
+
+// sortByLoad orders the hosts with the lowest load first, keeping the weight order on equal load
+func (hl *hostLoads) sortByLoad(hostIDs []string) []string {
+	hl.RLock()
+	loads := make(map[string]int, len(hostIDs))
+	for _, hostID := range hostIDs {
+		loads[hostID] = hl.load(hostID)
+	}
+	hl.RUnlock()
+	sort.SliceStable(hostIDs, func(i, j int) bool {
+		return loads[hostIDs[i]] < loads[hostIDs[j]]
+	})
+	return hostIDs
+}
+
+// hostIDsWithFirst moves the firstID in front, keeping the order of the others
+func hostIDsWithFirst(hostIDs []string, firstID string) (sorted []string) {
+	sorted = make([]string, 0, len(hostIDs))
+	for _, hostID := range hostIDs {
+		if hostID == firstID {
+			sorted = append([]string{hostID}, sorted...)
+			continue
+		}
+		sorted = append(sorted, hostID)
+	}
+	return
+}
+
+// callHosts sends the request to the hosts in order,
+// failing over to the next one only on network errors
+// returns the ID of the host which answered the request
+func callHosts(hostIDs []string, blockers utils.StringMap,
+	conns map[string]*rpcclient.RpcClientPool,
+	serviceMethod string, args interface{}, reply interface{}) (hostID string, err error) {
+	err = utils.ErrHostNotFound
+	for _, hID := range hostIDs {
+		if conn, has := conns[hID]; has {
+			if err = conn.Call(serviceMethod, args, reply); !isNetworkError(err) {
+				return hID, err
+			}
+		}
+		if blockers.HasKey(hID) { // no failover past a blocker host
+			break
+		}
+	}
+	return
+}
+
+// blockerHostIDs returns the IDs of the hosts within profile having Blocker set
+func blockerHostIDs(pfl *engine.DispatcherProfile) (blkrs utils.StringMap) {
+	blkrs = make(utils.StringMap)
+	if pfl == nil {
+		return
+	}
+	for _, host := range pfl.Hosts {
+		if host.Blocker {
+			blkrs[host.ID] = true
+		}
+	}
+	return
+}
+
+// isNetworkError will decide if the error received from a host allows failover
+func isNetworkError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if _, isNetErr := err.(net.Error); isNetErr {
+		return true
+	}
+	return err == rpc.ErrShutdown ||
+		err == io.EOF ||
+		err == io.ErrUnexpectedEOF ||
+		err.Error() == rpc.ErrShutdown.Error() ||
+		err.Error() == utils.ErrHostNotFound.Error()
+}
@@ -0,0 +1,381 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package dispatchers
+
+import (
+	"io"
+	"net/rpc"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+// testConn replies with its ID, or with the configured error
+type testConn struct {
+	id  string
+	err error
+}
+
+func (tc *testConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	if tc.err != nil {
+		return tc.err
+	}
+	*(reply.(*string)) = tc.id
+	return nil
+}
+
+func newTestPool(id string, err error) *rpcclient.RpcClientPool {
+	pool := rpcclient.NewRpcClientPool(rpcclient.POOL_FIRST, 0)
+	pool.AddClient(&testConn{id: id, err: err})
+	return pool
+}
+
+func testDispatcherProfile(strategy string) *engine.DispatcherProfile {
+	return &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_1",
+		Strategy: strategy,
+		Hosts: engine.DispatcherHosts{
+			&engine.DispatcherHost{ID: "HOST2", Weight: 10},
+			&engine.DispatcherHost{ID: "HOST1", Weight: 20},
+			&engine.DispatcherHost{ID: "HOST3", Weight: 5},
+		},
+	}
+}
+
+func TestNewDispatcher(t *testing.T) {
+	for strategy, eType := range map[string]Dispatcher{
+		utils.MetaFirst:     new(WeightDispatcher),
+		utils.MetaRandom:    new(RandomDispatcher),
+		utils.MetaNext:      new(RoundRobinDispatcher),
+		utils.MetaBroadcast: new(BroadcastDispatcher),
+		utils.MetaSticky:    new(StickyDispatcher),
+		utils.MetaLoad:      new(LoadDispatcher),
+	} {
+		if d, err := newDispatcher(testDispatcherProfile(strategy), newHostLoads()); err != nil {
+			t.Error(err)
+		} else if reflect.TypeOf(d) != reflect.TypeOf(eType) {
+			t.Errorf("strategy: %s, expecting: %T, received: %T", strategy, eType, d)
+		}
+	}
+	if _, err := newDispatcher(testDispatcherProfile("*unsupported"), nil); err == nil {
+		t.Error("expecting error for unsupported strategy")
+	}
+}
+
+func TestWeightDispatcherFailover(t *testing.T) {
+	pfl := testDispatcherProfile(utils.MetaFirst)
+	d, err := newDispatcher(pfl, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eIDs := []string{"HOST1", "HOST2", "HOST3"}; !reflect.DeepEqual(eIDs, d.HostIDs()) {
+		t.Errorf("expecting: %+v, received: %+v", eIDs, d.HostIDs())
+	}
+	if pfl.Hosts[0].ID != "HOST2" {
+		t.Error("profile hosts were sorted in place")
+	}
+	conns := map[string]*rpcclient.RpcClientPool{
+		"HOST1": newTestPool("HOST1", io.EOF),
+		"HOST2": newTestPool("HOST2", nil),
+		"HOST3": newTestPool("HOST3", nil),
+	}
+	var reply string
+	if err := d.Dispatch(conns, nil, utils.ChargerSv1Ping, "", &reply); err != nil {
+		t.Error(err)
+	} else if reply != "HOST2" {
+		t.Errorf("expecting failover to HOST2, received: %s", reply)
+	}
+	conns["HOST2"] = newTestPool("HOST2", utils.ErrNotFound) // application error, no failover
+	if err := d.Dispatch(conns, nil, utils.ChargerSv1Ping, "", &reply); err == nil ||
+		err.Error() != utils.ErrNotFound.Error() {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if err := d.Dispatch(map[string]*rpcclient.RpcClientPool{}, nil,
+		utils.ChargerSv1Ping, "", &reply); err != utils.ErrHostNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrHostNotFound, err)
+	}
+}
+
+func TestWeightDispatcherBlocker(t *testing.T) {
+	pfl := testDispatcherProfile(utils.MetaFirst)
+	pfl.Hosts[1].Blocker = true // HOST1
+	d, err := newDispatcher(pfl, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conns := map[string]*rpcclient.RpcClientPool{
+		"HOST1": newTestPool("HOST1", rpc.ErrShutdown),
+		"HOST2": newTestPool("HOST2", nil),
+	}
+	var reply string
+	if err := d.Dispatch(conns, nil, utils.ChargerSv1Ping, "", &reply); err == nil {
+		t.Error("expecting no failover past the blocker host")
+	}
+}
+
+func TestRoundRobinDispatcher(t *testing.T) {
+	d, err := newDispatcher(testDispatcherProfile(utils.MetaNext), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conns := map[string]*rpcclient.RpcClientPool{
+		"HOST1": newTestPool("HOST1", nil),
+		"HOST2": newTestPool("HOST2", nil),
+		"HOST3": newTestPool("HOST3", nil),
+	}
+	for _, eReply := range []string{"HOST1", "HOST2", "HOST3", "HOST1"} {
+		var reply string
+		if err := d.Dispatch(conns, nil, utils.ChargerSv1Ping, "", &reply); err != nil {
+			t.Error(err)
+		} else if reply != eReply {
+			t.Errorf("expecting: %s, received: %s", eReply, reply)
+		}
+	}
+}
+
+func TestBroadcastDispatcher(t *testing.T) {
+	d, err := newDispatcher(testDispatcherProfile(utils.MetaBroadcast), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conns := map[string]*rpcclient.RpcClientPool{
+		"HOST1": newTestPool("HOST1", utils.ErrNotFound),
+		"HOST2": newTestPool("HOST2", nil),
+		"HOST3": newTestPool("HOST3", nil),
+	}
+	var reply string
+	if err := d.Dispatch(conns, nil, utils.ChargerSv1Ping, "", &reply); err != nil {
+		t.Error(err)
+	} else if reply != "HOST2" {
+		t.Errorf("expecting: HOST2, received: %s", reply)
+	}
+}
+
+func TestStickyDispatcher(t *testing.T) {
+	pfl := testDispatcherProfile(utils.MetaSticky)
+	pfl.StrategyParams = map[string]interface{}{utils.MetaKeyField: utils.CGRID}
+	d, err := newDispatcher(pfl, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := &utils.CGREvent{
+		Tenant: "cgrates.org",
+		ID:     "StickyEvent",
+		Event: map[string]interface{}{
+			utils.CGRID: "sticky_cgrid",
+		},
+	}
+	conns := map[string]*rpcclient.RpcClientPool{
+		"HOST1": newTestPool("HOST1", io.EOF),
+		"HOST2": newTestPool("HOST2", nil),
+		"HOST3": newTestPool("HOST3", nil),
+	}
+	var reply string
+	if err := d.Dispatch(conns, ev, utils.SessionSv1Ping, "", &reply); err != nil {
+		t.Error(err)
+	} else if reply != "HOST2" {
+		t.Errorf("expecting: HOST2, received: %s", reply)
+	}
+	conns["HOST1"] = newTestPool("HOST1", nil) // back online, should stay on HOST2
+	if err := d.Dispatch(conns, ev, utils.SessionSv1Ping, "", &reply); err != nil {
+		t.Error(err)
+	} else if reply != "HOST2" {
+		t.Errorf("expecting sticky HOST2, received: %s", reply)
+	}
+	conns["HOST2"] = newTestPool("HOST2", rpc.ErrShutdown) // sticky host down
+	if err := d.Dispatch(conns, ev, utils.SessionSv1Ping, "", &reply); err != nil {
+		t.Error(err)
+	} else if reply != "HOST1" {
+		t.Errorf("expecting failover to HOST1, received: %s", reply)
+	}
+	if x, has := engine.Cache.Get(utils.CacheDispatcherRoutes,
+		utils.ConcatenatedKey(pfl.TenantID(), "sticky_cgrid")); !has {
+		t.Error("route not cached")
+	} else if x.(string) != "HOST1" {
+		t.Errorf("expecting route to HOST1, received: %s", x)
+	}
+	// event without key field is dispatched by weight
+	if err := d.Dispatch(conns, &utils.CGREvent{Tenant: "cgrates.org"},
+		utils.SessionSv1Ping, "", &reply); err != nil {
+		t.Error(err)
+	} else if reply != "HOST1" {
+		t.Errorf("expecting: HOST1, received: %s", reply)
+	}
+}
+
+func TestLoadDispatcher(t *testing.T) {
+	hLoads := newHostLoads()
+	d, err := newDispatcher(testDispatcherProfile(utils.MetaLoad), hLoads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conns := map[string]*rpcclient.RpcClientPool{
+		"HOST1": newTestPool("HOST1", nil),
+		"HOST2": newTestPool("HOST2", nil),
+		"HOST3": newTestPool("HOST3", nil),
+	}
+	var reply string
+	if err := d.Dispatch(conns, nil, utils.SessionSv1Ping, "", &reply); err != nil {
+		t.Error(err)
+	} else if reply != "HOST1" { // same load, weight decides
+		t.Errorf("expecting: HOST1, received: %s", reply)
+	}
+	hLoads.setActiveSessions("HOST1", 10)
+	hLoads.setActiveSessions("HOST2", 5)
+	hLoads.incOutstanding("HOST3", 7)
+	if err := d.Dispatch(conns, nil, utils.SessionSv1Ping, "", &reply); err != nil {
+		t.Error(err)
+	} else if reply != "HOST2" {
+		t.Errorf("expecting: HOST2, received: %s", reply)
+	}
+	conns["HOST2"] = newTestPool("HOST2", io.EOF)
+	if err := d.Dispatch(conns, nil, utils.SessionSv1Ping, "", &reply); err != nil {
+		t.Error(err)
+	} else if reply != "HOST3" {
+		t.Errorf("expecting failover to HOST3, received: %s", reply)
+	}
+	if hLoads.outstanding["HOST2"] != 0 || hLoads.outstanding["HOST3"] != 7 {
+		t.Errorf("unexpected outstanding requests: %+v", hLoads.outstanding)
+	}
+}
+
+func TestIsNetworkError(t *testing.T) {
+	for _, err := range []error{io.EOF, rpc.ErrShutdown, utils.ErrHostNotFound} {
+		if !isNetworkError(err) {
+			t.Errorf("expecting network error for: %v", err)
+		}
+	}
+	for _, err := range []error{nil, utils.ErrNotFound} {
+		if isNetworkError(err) {
+			t.Errorf("not expecting network error for: %v", err)
+		}
+	}
+}
+
+func TestDispatcherForProfile(t *testing.T) {
+	dS := &DispatcherService{hLoads: newHostLoads()}
+	pfl := testDispatcherProfile(utils.MetaNext)
+	d, err := dS.dispatcherForProfile(pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rcv, err := dS.dispatcherForProfile(pfl); err != nil {
+		t.Error(err)
+	} else if rcv != d {
+		t.Error("expecting the cached dispatcher")
+	}
+	pfl = testDispatcherProfile(utils.MetaFirst) // strategy changed
+	if rcv, err := dS.dispatcherForProfile(pfl); err != nil {
+		t.Error(err)
+	} else if _, canCast := rcv.(*WeightDispatcher); !canCast {
+		t.Errorf("expecting: *WeightDispatcher, received: %T", rcv)
+	}
+	if ids := engine.Cache.GetItemIDs(utils.CacheDispatchers, ""); len(ids) != 1 {
+		t.Errorf("expecting one cached dispatcher, received: %+v", ids)
+	}
+	engine.Cache.Clear([]string{utils.CacheDispatchers})
+	if _, has := engine.Cache.Get(utils.CacheDispatchers, pfl.TenantID()); has {
+		t.Error("dispatcher not cleared")
+	}
+}
+
+func TestRefreshHostLoadsStop(t *testing.T) {
+	cfg, err := config.NewDefaultCGRConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.DispatcherSCfg().LoadRefreshInterval = time.Hour
+	dS := &DispatcherService{cfg: cfg, hLoads: newHostLoads(),
+		stopLoads: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		dS.refreshHostLoads()
+		close(done)
+	}()
+	close(dS.stopLoads)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("refreshing the host loads not stopped")
+	}
+}
+
+// countConn replies with the configured active sessions, counting the calls
+type countConn struct {
+	count int
+	calls int
+}
+
+func (cc *countConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	cc.calls++
+	*(reply.(*int)) = cc.count
+	return nil
+}
+
+func TestQueryHostLoads(t *testing.T) {
+	dS := &DispatcherService{hLoads: newHostLoads(),
+		conns: make(map[string]*rpcclient.RpcClientPool)}
+	cConns := make(map[string]*countConn)
+	for i, hostID := range []string{"HOST1", "HOST2", "HOST3", "HOST4"} {
+		cConns[hostID] = &countConn{count: i + 1}
+		dS.conns[hostID] = rpcclient.NewRpcClientPool(rpcclient.POOL_FIRST, 0)
+		dS.conns[hostID].AddClient(cConns[hostID])
+	}
+	dS.queryHostLoads()
+	for hostID, cConn := range cConns {
+		if cConn.calls != 0 {
+			t.Errorf("host: %s queried without *load profile", hostID)
+		}
+	}
+	if _, err := newDispatcher(testDispatcherProfile(utils.MetaLoad), dS.hLoads); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newDispatcher(testDispatcherProfile(utils.MetaFirst), dS.hLoads); err != nil {
+		t.Fatal(err)
+	}
+	dS.queryHostLoads()
+	if cConns["HOST4"].calls != 0 {
+		t.Error("host: HOST4 queried without *load profile")
+	}
+	for i, hostID := range []string{"HOST1", "HOST2", "HOST3"} {
+		if cConns[hostID].calls != 1 {
+			t.Errorf("host: %s, expecting 1 query, received: %d", hostID, cConns[hostID].calls)
+		}
+		if load := dS.hLoads.activeSessions[hostID]; load != i+1 {
+			t.Errorf("host: %s, expecting load: %d, received: %d", hostID, i+1, load)
+		}
+	}
+}
+
+func TestDispatcherServiceShutdownTwice(t *testing.T) {
+	dS := &DispatcherService{stopLoads: make(chan struct{})}
+	dS.Shutdown()
+	dS.Shutdown()
+	select {
+	case <-dS.stopLoads:
+	default:
+		t.Error("refreshing the host loads not stopped")
+	}
+}
@@ -24,21 +24,16 @@ import (
 )
 
 func (dS *DispatcherService) ResourceSv1Ping(ign string, rpl *string) (err error) {
-	if dS.resS == nil {
-		return utils.NewErrNotConnected(utils.ResourceS)
-	}
-	return dS.resS.Call(utils.ResourceSv1Ping, ign, rpl)
+	return dS.Dispatch(&utils.CGREvent{Tenant: dS.cfg.GeneralCfg().DefaultTenant}, utils.MetaResources, dS.resS,
+		utils.ResourceSv1Ping, ign, rpl)
 }
 
 func (dS *DispatcherService) ResourceSv1GetResourcesForEvent(args *ArgsV1ResUsageWithApiKey,
 	reply *engine.Resources) (err error) {
-	if dS.resS == nil {
-		return utils.NewErrNotConnected(utils.ResourceS)
-	}
 	if err = dS.authorize(utils.ResourceSv1GetResourcesForEvent, args.ArgRSv1ResourceUsage.CGREvent.Tenant,
 		args.APIKey, args.ArgRSv1ResourceUsage.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.resS.Call(utils.ResourceSv1GetResourcesForEvent, args.ArgRSv1ResourceUsage, reply)
-
+	return dS.Dispatch(&args.ArgRSv1ResourceUsage.CGREvent, utils.MetaResources, dS.resS,
+		utils.ResourceSv1GetResourcesForEvent, args.ArgRSv1ResourceUsage, reply)
 }
@@ -24,80 +24,66 @@ import (
 )
 
 func (dS *DispatcherService) SessionSv1Ping(ign string, rpl *string) (err error) {
-	if dS.sessionS == nil {
-		return utils.NewErrNotConnected(utils.SessionS)
-	}
-	return dS.sessionS.Call(utils.SessionSv1Ping, ign, rpl)
+	return dS.Dispatch(&utils.CGREvent{Tenant: dS.cfg.GeneralCfg().DefaultTenant}, utils.MetaSessionS, dS.sessionS,
+		utils.SessionSv1Ping, ign, rpl)
 }
 
 func (dS *DispatcherService) SessionSv1AuthorizeEventWithDigest(args *AuthorizeArgsWithApiKey,
 	reply *sessions.V1AuthorizeReplyWithDigest) (err error) {
-	if dS.sessionS == nil {
-		return utils.NewErrNotConnected(utils.SessionS)
-	}
 	if err = dS.authorize(utils.SessionSv1AuthorizeEventWithDigest, args.V1AuthorizeArgs.CGREvent.Tenant,
 		args.APIKey, args.V1AuthorizeArgs.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.sessionS.Call(utils.SessionSv1AuthorizeEventWithDigest, args.V1AuthorizeArgs, reply)
+	return dS.Dispatch(&args.V1AuthorizeArgs.CGREvent, utils.MetaSessionS, dS.sessionS,
+		utils.SessionSv1AuthorizeEventWithDigest, args.V1AuthorizeArgs, reply)
 }
 
 func (dS *DispatcherService) SessionSv1InitiateSessionWithDigest(args *InitArgsWithApiKey,
 	reply *sessions.V1InitSessionReply) (err error) {
-	if dS.sessionS == nil {
-		return utils.NewErrNotConnected(utils.SessionS)
-	}
 	if err = dS.authorize(utils.SessionSv1InitiateSessionWithDigest, args.V1InitSessionArgs.CGREvent.Tenant,
 		args.APIKey, args.V1InitSessionArgs.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.sessionS.Call(utils.SessionSv1InitiateSessionWithDigest, args.V1InitSessionArgs, reply)
+	return dS.Dispatch(&args.V1InitSessionArgs.CGREvent, utils.MetaSessionS, dS.sessionS,
+		utils.SessionSv1InitiateSessionWithDigest, args.V1InitSessionArgs, reply)
 }
 
 func (dS *DispatcherService) SessionSv1ProcessCDR(args *CGREvWithApiKey,
 	reply *string) (err error) {
-	if dS.sessionS == nil {
-		return utils.NewErrNotConnected(utils.SessionS)
-	}
 	if err = dS.authorize(utils.SessionSv1ProcessCDR, args.CGREvent.Tenant,
 		args.APIKey, args.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.sessionS.Call(utils.SessionSv1ProcessCDR, args.CGREvent, reply)
+	return dS.Dispatch(&args.CGREvent, utils.MetaSessionS, dS.sessionS,
+		utils.SessionSv1ProcessCDR, args.CGREvent, reply)
 }
 
 func (dS *DispatcherService) SessionSv1ProcessEvent(args *ProcessEventWithApiKey,
 	reply *sessions.V1ProcessEventReply) (err error) {
-	if dS.sessionS == nil {
-		return utils.NewErrNotConnected(utils.SessionS)
-	}
 	if err = dS.authorize(utils.SessionSv1ProcessEvent, args.V1ProcessEventArgs.CGREvent.Tenant,
 		args.APIKey, args.V1ProcessEventArgs.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.sessionS.Call(utils.SessionSv1ProcessEvent, args.V1ProcessEventArgs, reply)
+	return dS.Dispatch(&args.V1ProcessEventArgs.CGREvent, utils.MetaSessionS, dS.sessionS,
+		utils.SessionSv1ProcessEvent, args.V1ProcessEventArgs, reply)
 }
 
 func (dS *DispatcherService) SessionSv1TerminateSession(args *TerminateSessionWithApiKey,
 	reply *string) (err error) {
-	if dS.sessionS == nil {
-		return utils.NewErrNotConnected(utils.SessionS)
-	}
 	if err = dS.authorize(utils.SessionSv1TerminateSession, args.V1TerminateSessionArgs.CGREvent.Tenant,
 		args.APIKey, args.V1TerminateSessionArgs.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.sessionS.Call(utils.SessionSv1TerminateSession, args.V1TerminateSessionArgs, reply)
+	return dS.Dispatch(&args.V1TerminateSessionArgs.CGREvent, utils.MetaSessionS, dS.sessionS,
+		utils.SessionSv1TerminateSession, args.V1TerminateSessionArgs, reply)
 }
 
 func (dS *DispatcherService) SessionSv1UpdateSession(args *UpdateSessionWithApiKey,
 	reply *sessions.V1UpdateSessionReply) (err error) {
-	if dS.sessionS == nil {
-		return utils.NewErrNotConnected(utils.SessionS)
-	}
 	if err = dS.authorize(utils.SessionSv1UpdateSession, args.V1UpdateSessionArgs.CGREvent.Tenant,
 		args.APIKey, args.V1UpdateSessionArgs.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.sessionS.Call(utils.SessionSv1UpdateSession, args.V1UpdateSessionArgs, reply)
+	return dS.Dispatch(&args.V1UpdateSessionArgs.CGREvent, utils.MetaSessionS, dS.sessionS,
+		utils.SessionSv1UpdateSession, args.V1UpdateSessionArgs, reply)
 }
@@ -25,45 +25,37 @@ import (
 )
 
 func (dS *DispatcherService) StatSv1Ping(ign string, reply *string) error {
-	if dS.statS == nil {
-		return utils.NewErrNotConnected(utils.StatS)
-	}
-	return dS.statS.Call(utils.StatSv1Ping, ign, reply)
+	return dS.Dispatch(&utils.CGREvent{Tenant: dS.cfg.GeneralCfg().DefaultTenant}, utils.MetaStats, dS.statS,
+		utils.StatSv1Ping, ign, reply)
 }
 
 func (dS *DispatcherService) StatSv1GetStatQueuesForEvent(args *ArgsStatProcessEventWithApiKey,
 	reply *[]string) (err error) {
-	if dS.statS == nil {
-		return utils.NewErrNotConnected(utils.StatS)
-	}
 	if err = dS.authorize(utils.StatSv1GetStatQueuesForEvent, args.CGREvent.Tenant,
 		args.APIKey, args.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.statS.Call(utils.StatSv1GetStatQueuesForEvent, args, reply)
+	return dS.Dispatch(&args.CGREvent, utils.MetaStats, dS.statS,
+		utils.StatSv1GetStatQueuesForEvent, args, reply)
 }
 
 func (dS *DispatcherService) StatSv1GetQueueStringMetrics(args *TntIDWithApiKey,
 	reply *map[string]string) (err error) {
-	if dS.statS == nil {
-		return utils.NewErrNotConnected(utils.StatS)
-	}
 	nowTime := time.Now()
 	if err = dS.authorize(utils.StatSv1GetQueueStringMetrics, args.TenantID.Tenant,
 		args.APIKey, &nowTime); err != nil {
 		return
 	}
-	return dS.statS.Call(utils.StatSv1GetQueueStringMetrics, args.TenantID, reply)
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantID.Tenant, ID: args.TenantID.ID}, utils.MetaStats, dS.statS,
+		utils.StatSv1GetQueueStringMetrics, args.TenantID, reply)
 }
 
 func (dS *DispatcherService) StatSv1ProcessEvent(args *ArgsStatProcessEventWithApiKey,
 	reply *[]string) (err error) {
-	if dS.statS == nil {
-		return utils.NewErrNotConnected(utils.StatS)
-	}
 	if err = dS.authorize(utils.StatSv1ProcessEvent, args.CGREvent.Tenant,
 		args.APIKey, args.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.statS.Call(utils.StatSv1ProcessEvent, args, reply)
+	return dS.Dispatch(&args.CGREvent, utils.MetaStats, dS.statS,
+		utils.StatSv1ProcessEvent, args, reply)
 }
@@ -24,21 +24,16 @@ import (
 )
 
 func (dS *DispatcherService) SupplierSv1Ping(ign string, reply *string) error {
-	if dS.splS == nil {
-		return utils.NewErrNotConnected(utils.SupplierS)
-	}
-	return dS.splS.Call(utils.SupplierSv1Ping, ign, reply)
+	return dS.Dispatch(&utils.CGREvent{Tenant: dS.cfg.GeneralCfg().DefaultTenant}, utils.MetaSuppliers, dS.splS,
+		utils.SupplierSv1Ping, ign, reply)
 }
 
 func (dS *DispatcherService) SupplierSv1GetSuppliers(args *ArgsGetSuppliersWithApiKey,
 	reply *engine.SortedSuppliers) (err error) {
-	if dS.splS == nil {
-		return utils.NewErrNotConnected(utils.SupplierS)
-	}
 	if err = dS.authorize(utils.SupplierSv1GetSuppliers, args.ArgsGetSuppliers.CGREvent.Tenant,
 		args.APIKey, args.ArgsGetSuppliers.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.splS.Call(utils.SupplierSv1GetSuppliers, args.ArgsGetSuppliers, reply)
-
+	return dS.Dispatch(&args.ArgsGetSuppliers.CGREvent, utils.MetaSuppliers, dS.splS,
+		utils.SupplierSv1GetSuppliers, args.ArgsGetSuppliers, reply)
 }
@@ -24,32 +24,26 @@ import (
 )
 
 func (dS *DispatcherService) ThresholdSv1Ping(ign string, reply *string) error {
-	if dS.thdS == nil {
-		return utils.NewErrNotConnected(utils.ThresholdS)
-	}
-	return dS.thdS.Call(utils.ThresholdSv1Ping, ign, reply)
+	return dS.Dispatch(&utils.CGREvent{Tenant: dS.cfg.GeneralCfg().DefaultTenant}, utils.MetaThresholds, dS.thdS,
+		utils.ThresholdSv1Ping, ign, reply)
 }
 
 func (dS *DispatcherService) ThresholdSv1GetThresholdsForEvent(args *ArgsProcessEventWithApiKey,
 	t *engine.Thresholds) (err error) {
-	if dS.thdS == nil {
-		return utils.NewErrNotConnected(utils.ThresholdS)
-	}
 	if err = dS.authorize(utils.ThresholdSv1GetThresholdsForEvent, args.ArgsProcessEvent.CGREvent.Tenant,
 		args.APIKey, args.ArgsProcessEvent.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.thdS.Call(utils.ThresholdSv1GetThresholdsForEvent, args.ArgsProcessEvent, t)
+	return dS.Dispatch(&args.ArgsProcessEvent.CGREvent, utils.MetaThresholds, dS.thdS,
+		utils.ThresholdSv1GetThresholdsForEvent, args.ArgsProcessEvent, t)
 }
 
 func (dS *DispatcherService) ThresholdSv1ProcessEvent(args *ArgsProcessEventWithApiKey,
 	tIDs *[]string) (err error) {
-	if dS.thdS == nil {
-		return utils.NewErrNotConnected(utils.ThresholdS)
-	}
 	if err = dS.authorize(utils.ThresholdSv1ProcessEvent, args.ArgsProcessEvent.CGREvent.Tenant,
 		args.APIKey, args.ArgsProcessEvent.CGREvent.Time); err != nil {
 		return
 	}
-	return dS.thdS.Call(utils.ThresholdSv1ProcessEvent, args.ArgsProcessEvent, tIDs)
+	return dS.Dispatch(&args.ArgsProcessEvent.CGREvent, utils.MetaThresholds, dS.thdS,
+		utils.ThresholdSv1ProcessEvent, args.ArgsProcessEvent, tIDs)
 }
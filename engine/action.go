@@ -83,6 +83,7 @@ const (
 	SetExpiry                 = "*set_expiry"
 	MetaPublishAccount        = "*publish_account"
 	MetaPublishBalance        = "*publish_balance"
+	MetaPostKafka             = "*post_kafka"
 )
 
 func (a *Action) Clone() *Action {
@@ -123,6 +124,7 @@ func getActionFunc(typ string) (actionTypeFunc, bool) {
 		SetExpiry:                 setExpiryAction,
 		MetaPublishAccount:        publishAccount,
 		MetaPublishBalance:        publishBalance,
+		MetaPostKafka:             postKafka,
 	}
 	f, exists := actionFuncMap[typ]
 	return f, exists
@@ -416,6 +418,45 @@ func callUrlAsync(ub *Account, sq *CDRStatsQueueTriggered, a *Action, acs Action
 	return nil
 }
 
+// postKafka publishes the account or the stats queue into the Kafka topic out of ExtraParameters
+// the topic needs to be part of ExtraParameters, ie: localhost:9092?topic=cgrates_accounts
+func postKafka(ub *Account, sq *CDRStatsQueueTriggered, a *Action, acs Actions) error {
+	var o interface{}
+	if ub != nil {
+		o = ub
+	}
+	if sq != nil {
+		o = sq
+	}
+	jsn, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	if topic, err := KafkaTopic(a.ExtraParameters); err != nil {
+		return err
+	} else if topic == "" { // the default one is reserved to the CDRs
+		return utils.NewErrMandatoryIeMissing("topic")
+	}
+	cfg := config.CgrConfig()
+	ffn := &utils.FallbackFileName{
+		Module:     fmt.Sprintf("%s>%s", utils.ActionsPoster, a.ActionType),
+		Transport:  utils.MetaKafkajsonMap,
+		Address:    a.ExtraParameters,
+		RequestID:  utils.GenUUID(),
+		FileSuffix: utils.JSNSuffix,
+	}
+	kafkaPoster, err := KafkaPostersCache.GetKafkaPoster(a.ExtraParameters,
+		cfg.GeneralCfg().PosterAttempts, cfg.GeneralCfg().FailedPostsDir)
+	if err != nil {
+		return err
+	}
+	fallbackFileName := utils.META_NONE
+	if cfg.GeneralCfg().FailedPostsDir != utils.META_NONE {
+		fallbackFileName = ffn.AsString()
+	}
+	return kafkaPoster.Post(jsn, fallbackFileName)
+}
+
 // Mails the balance hitting the threshold towards predefined list of addresses
 func mailAsync(ub *Account, sq *CDRStatsQueueTriggered, a *Action, acs Actions) error {
 	cgrCfg := config.CgrConfig()
@@ -2599,3 +2599,11 @@ func BenchmarkUUID(b *testing.B) {
 		b.StartTimer()
 	}
 }
+
+func TestPostKafkaMissingTopic(t *testing.T) {
+	a := &Action{ActionType: MetaPostKafka, ExtraParameters: "localhost:9092"}
+	if err := postKafka(&Account{ID: "cgrates.org:1001"}, nil, a, nil); err == nil ||
+		err.Error() != utils.NewErrMandatoryIeMissing("topic").Error() {
+		t.Errorf("expecting: %v, received: %v", utils.NewErrMandatoryIeMissing("topic"), err)
+	}
+}
@@ -20,6 +20,7 @@ package engine
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/cgrates/cgrates/config"
@@ -27,7 +28,7 @@ import (
 	"github.com/cgrates/ltcache"
 )
 
-var Cache *ltcache.TransCache
+var Cache *TransCache
 
 func init() {
 	InitCache(nil)
@@ -59,6 +60,7 @@ var precachedPartitions = []string{
 	utils.CacheSupplierProfiles,
 	utils.CacheAttributeProfiles,
 	utils.CacheChargerProfiles,
+	utils.CacheDispatcherProfiles,
 }
 
 // InitCache will instantiate the cache with specific or default configuraiton
@@ -66,7 +68,69 @@ func InitCache(cfg config.CacheCfg) {
 	if cfg == nil {
 		cfg = config.CgrConfig().CacheCfg()
 	}
-	Cache = ltcache.NewTransCache(cfg.AsTransCacheConfig())
+	Cache = NewTransCache(cfg)
+}
+
+// NewTransCache instantiates the TransCache with counters for each of the configured partitions
+func NewTransCache(cfg config.CacheCfg) (tc *TransCache) {
+	tc = &TransCache{TransCache: ltcache.NewTransCache(cfg.AsTransCacheConfig()),
+		counters: map[string]*cacheCounters{utils.MetaDefault: new(cacheCounters)}}
+	for cacheID := range cfg {
+		tc.counters[cacheID] = new(cacheCounters)
+	}
+	return
+}
+
+// cacheCounters holds the reads out of one cache partition, updated atomically
+type cacheCounters struct {
+	hits   int64
+	misses int64
+}
+
+// TransCache is the ltcache.TransCache counting the hits and misses of each partition
+type TransCache struct {
+	*ltcache.TransCache
+	counters map[string]*cacheCounters // not modified after init so no locking needed
+}
+
+// Get is the ltcache.TransCache.Get, counting the hit or miss
+func (tc *TransCache) Get(chID, itmID string) (itm interface{}, has bool) {
+	itm, has = tc.TransCache.Get(chID, itmID)
+	cntrs, known := tc.counters[chID]
+	if !known { // served out of the default partition
+		cntrs = tc.counters[utils.MetaDefault]
+	}
+	if has {
+		atomic.AddInt64(&cntrs.hits, 1)
+	} else {
+		atomic.AddInt64(&cntrs.misses, 1)
+	}
+	return
+}
+
+// GetCacheHitStats returns the ltcache.CacheStats together with the hits and misses
+func (tc *TransCache) GetCacheHitStats(chIDs []string) (cs map[string]*CacheStats) {
+	ltStats := tc.TransCache.GetCacheStats(chIDs)
+	cs = make(map[string]*CacheStats, len(ltStats))
+	for chID, ltStat := range ltStats {
+		chStats := new(CacheStats)
+		if ltStat != nil {
+			chStats.CacheStats = *ltStat
+		}
+		if cntrs, has := tc.counters[chID]; has {
+			chStats.Hits = atomic.LoadInt64(&cntrs.hits)
+			chStats.Misses = atomic.LoadInt64(&cntrs.misses)
+		}
+		cs[chID] = chStats
+	}
+	return
+}
+
+// CacheStats extends the ltcache.CacheStats with the number of reads out of the partition
+type CacheStats struct {
+	ltcache.CacheStats
+	Hits   int64
+	Misses int64
 }
 
 // NewCacheS initializes the Cache service
@@ -170,6 +234,13 @@ func (chS *CacheS) V1GetCacheStats(cacheIDs []string,
 	return
 }
 
+// V1GetCacheHitStats returns the cache stats together with the hits and misses of each partition
+func (chS *CacheS) V1GetCacheHitStats(cacheIDs []string,
+	rply *map[string]*CacheStats) (err error) {
+	*rply = Cache.GetCacheHitStats(cacheIDs)
+	return
+}
+
 func (chS *CacheS) V1PrecacheStatus(cacheIDs []string, rply *map[string]string) (err error) {
 	if len(cacheIDs) == 0 {
 		for _, cacheID := range precachedPartitions {
@@ -0,0 +1,49 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestTransCacheCounters(t *testing.T) {
+	tc := NewTransCache(config.CacheCfg{
+		utils.CacheFilters: &config.CacheParamCfg{Limit: -1}})
+	tc.Set(utils.CacheFilters, "cgrates.org:FLTR_1", "FLTR_1", nil, true, "")
+	if _, has := tc.Get(utils.CacheFilters, "cgrates.org:FLTR_1"); !has {
+		t.Error("item not cached")
+	}
+	if _, has := tc.Get(utils.CacheFilters, "cgrates.org:FLTR_2"); has {
+		t.Error("item should not be cached")
+	}
+	tc.Get(utils.CacheFilters, "cgrates.org:FLTR_2")
+	tc.Get("not_configured", "item") // counted on *default
+	chStats := tc.GetCacheHitStats([]string{utils.CacheFilters})
+	if cs, has := chStats[utils.CacheFilters]; !has {
+		t.Errorf("no stats for %s: %s", utils.CacheFilters, utils.ToJSON(chStats))
+	} else if cs.Items != 1 || cs.Hits != 1 || cs.Misses != 2 {
+		t.Errorf("unexpected stats: %s", utils.ToJSON(cs))
+	}
+	if cntrs := tc.counters[utils.MetaDefault]; cntrs.misses != 1 {
+		t.Errorf("unexpected default counters: %+v", cntrs)
+	}
+}
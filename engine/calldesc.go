@@ -948,7 +948,9 @@ func (cd *CallDescriptor) refundIncrements() (acnt *Account, err error) {
 				return
 			}
 			balance.AddValue(increment.Cost)
-			account.countUnits(-increment.Cost, utils.MONETARY, cc, balance)
+			if increment.Cost > 0 { // negative costs are adjustments debiting the balance, not counted as usage
+				account.countUnits(-increment.Cost, utils.MONETARY, cc, balance)
+			}
 		}
 	}
 	acnt = accountsCache[utils.ConcatenatedKey(cd.Tenant, cd.Account)]
@@ -1738,6 +1738,39 @@ func TestCDRefundIncrementsZeroValue(t *testing.T) {
 	}
 }
 
+func TestCDRefundIncrementsNegativeCost(t *testing.T) {
+	ub := &Account{
+		ID: "test:refneg",
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: Balances{
+				&Balance{Uuid: "moneya", Value: 100},
+			},
+		},
+		UnitCounters: UnitCounters{
+			utils.MONETARY: []*UnitCounter{
+				&UnitCounter{CounterType: utils.COUNTER_BALANCE,
+					Counters: CounterFilters{&CounterFilter{Filter: &BalanceFilter{}}}},
+			},
+		},
+	}
+	dm.DataDB().SetAccount(ub)
+	increments := Increments{
+		&Increment{Cost: 2, BalanceInfo: &DebitInfo{
+			Monetary: &MonetaryInfo{UUID: "moneya"}, AccountID: ub.ID}},
+		&Increment{Cost: -3, BalanceInfo: &DebitInfo{
+			Monetary: &MonetaryInfo{UUID: "moneya"}, AccountID: ub.ID}},
+	}
+	cd := &CallDescriptor{TOR: utils.VOICE, Increments: increments}
+	cd.RefundIncrements()
+	ub, _ = dm.DataDB().GetAccount(ub.ID)
+	if ub.BalanceMap[utils.MONETARY][0].GetValue() != 99 {
+		t.Error("Error refunding money: ", utils.ToIJSON(ub.BalanceMap))
+	}
+	if cntr := ub.UnitCounters[utils.MONETARY][0].Counters[0].Value; cntr != -2 { // negative cost not counted
+		t.Errorf("Expecting counter: -2, received: %v", cntr)
+	}
+}
+
 func TestCDDebitBalanceSubjectWithFallback(t *testing.T) {
 	acnt := &Account{
 		ID: "TCDDBSWF:account1",
@@ -241,13 +241,13 @@ func (cdre *CDRExporter) composeTrailer() (err error) {
 func (cdre *CDRExporter) postCdr(cdr *CDR) (err error) {
 	var body interface{}
 	switch cdre.exportFormat {
-	case utils.MetaHTTPjsonCDR, utils.MetaAMQPjsonCDR:
+	case utils.MetaHTTPjsonCDR, utils.MetaAMQPjsonCDR, utils.MetaKafkajsonCDR:
 		jsn, err := json.Marshal(cdr)
 		if err != nil {
 			return err
 		}
 		body = jsn
-	case utils.MetaHTTPjsonMap, utils.MetaAMQPjsonMap:
+	case utils.MetaHTTPjsonMap, utils.MetaAMQPjsonMap, utils.MetaKafkajsonMap:
 		expMp, err := cdr.AsExportMap(cdre.exportTemplate.ContentFields, cdre.httpSkipTlsCheck, nil, cdre.roundingDecimals, cdre.filterS)
 		if err != nil {
 			return err
@@ -294,6 +294,11 @@ func (cdre *CDRExporter) postCdr(cdr *CDR) (err error) {
 				chn.Close()
 			}
 		}
+	case utils.MetaKafkajsonCDR, utils.MetaKafkajsonMap:
+		var kafkaPoster *KafkaPoster
+		if kafkaPoster, err = KafkaPostersCache.GetKafkaPoster(cdre.exportPath, cdre.attempts, cdre.fallbackPath); err == nil {
+			err = kafkaPoster.Post(body.([]byte), fallbackFileName)
+		}
 	}
 	return
 }
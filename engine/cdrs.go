@@ -459,11 +459,27 @@ func (self *CdrServer) rateCDR(cdr *CDR) ([]*CDR, error) {
 func (self *CdrServer) getCostFromRater(cdr *CDR) (*CallCost, error) {
 	cc := new(CallCost)
 	var err error
+	cd := newCallDescriptorFromCDR(cdr)
+	if utils.IsSliceMember([]string{utils.META_PSEUDOPREPAID, utils.META_POSTPAID, utils.META_PREPAID,
+		utils.PSEUDOPREPAID, utils.POSTPAID, utils.PREPAID}, cdr.RequestType) { // Prepaid - Cost can be recalculated in case of missing records from SM
+		err = self.rals.Call("Responder.Debit", cd, cc)
+	} else {
+		err = self.rals.Call("Responder.GetCost", cd, cc)
+	}
+	if err != nil {
+		return cc, err
+	}
+	cdr.CostSource = utils.MetaCDRs
+	return cc, nil
+}
+
+// newCallDescriptorFromCDR builds the CallDescriptor used to rate the CDR
+func newCallDescriptorFromCDR(cdr *CDR) *CallDescriptor {
 	timeStart := cdr.AnswerTime
 	if timeStart.IsZero() { // Fix for FreeSWITCH unanswered calls
 		timeStart = cdr.SetupTime
 	}
-	cd := &CallDescriptor{
+	return &CallDescriptor{
 		TOR:             cdr.ToR,
 		Direction:       utils.OUT,
 		Tenant:          cdr.Tenant,
@@ -476,17 +492,6 @@ func (self *CdrServer) getCostFromRater(cdr *CDR) (*CallCost, error) {
 		DurationIndex:   cdr.Usage,
 		PerformRounding: true,
 	}
-	if utils.IsSliceMember([]string{utils.META_PSEUDOPREPAID, utils.META_POSTPAID, utils.META_PREPAID,
-		utils.PSEUDOPREPAID, utils.POSTPAID, utils.PREPAID}, cdr.RequestType) { // Prepaid - Cost can be recalculated in case of missing records from SM
-		err = self.rals.Call("Responder.Debit", cd, cc)
-	} else {
-		err = self.rals.Call("Responder.GetCost", cd, cc)
-	}
-	if err != nil {
-		return cc, err
-	}
-	cdr.CostSource = utils.MetaCDRs
-	return cc, nil
 }
 
 func (self *CdrServer) replicateCDRs(cdrs []*CDR) (err error) {
@@ -858,3 +863,28 @@ func (self *CdrServer) V1CountCDRs(args utils.RPCCDRsFilter, cnt *int64) error {
 	}
 	return nil
 }
+
+// V1GetCDRsAggregate returns the count, usage and cost of the CDRs for each combination of the GroupBy values
+func (self *CdrServer) V1GetCDRsAggregate(args utils.RPCCDRsAggregateFilter,
+	aggrs *[]*CDRsAggregate) error {
+	groupBy, err := args.AsCDRsGroupBy()
+	if err != nil {
+		return utils.NewErrServerError(err)
+	}
+	cdrsFltr, err := args.AsCDRsFilter(self.Timezone())
+	if err != nil {
+		if err.Error() != utils.NotFoundCaps {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	if qryAggrs, err := self.cdrDb.GetCDRsAggregate(cdrsFltr, groupBy); err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	} else {
+		*aggrs = qryAggrs
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+const (
+	cdrsAggrHourLayout = "2006-01-02T15"
+	cdrsAggrDayLayout  = "2006-01-02"
+)
+
+// CDRsAggregate summarizes the CDRs sharing the same values of the GroupBy fields
+type CDRsAggregate struct {
+	Group      map[string]string // values of the group, indexed on the GroupBy rule
+	Count      int64
+	TotalUsage time.Duration
+	TotalCost  float64 // unrated CDRs, with negative cost, are not summed
+}
+
+// cdrGroupValue returns the value of the cdr considered when grouping on gb
+func cdrGroupValue(cdr *CDR, gb *utils.CDRsGroupBy) string {
+	switch gb.Field {
+	case utils.Tenant:
+		return cdr.Tenant
+	case utils.Category:
+		return cdr.Category
+	case utils.Account:
+		return cdr.Account
+	case utils.Subject:
+		return cdr.Subject
+	case utils.RunID:
+		return cdr.RunID
+	case utils.ToR:
+		return cdr.ToR
+	case utils.RequestType:
+		return cdr.RequestType
+	case utils.Destination:
+		if gb.PrefixLength != 0 && len(cdr.Destination) > gb.PrefixLength {
+			return cdr.Destination[:gb.PrefixLength]
+		}
+		return cdr.Destination
+	case utils.AnswerTime:
+		if cdr.AnswerTime.IsZero() {
+			return ""
+		}
+		if gb.Interval == utils.MetaHourly {
+			return cdr.AnswerTime.UTC().Format(cdrsAggrHourLayout)
+		}
+		return cdr.AnswerTime.UTC().Format(cdrsAggrDayLayout)
+	}
+	return ""
+}
+
+// aggregateCDRs groups and summarizes the CDRs in memory
+func aggregateCDRs(cdrs []*CDR, groupBy []*utils.CDRsGroupBy) (aggrs []*CDRsAggregate) {
+	aggrIdx := make(map[string]*CDRsAggregate)
+	for _, cdr := range cdrs {
+		grpVals := make([]string, len(groupBy))
+		for i, gb := range groupBy {
+			grpVals[i] = cdrGroupValue(cdr, gb)
+		}
+		grpKey := fmt.Sprintf("%q", grpVals)
+		aggr, has := aggrIdx[grpKey]
+		if !has {
+			aggr = &CDRsAggregate{Group: make(map[string]string)}
+			for i, gb := range groupBy {
+				aggr.Group[gb.Rule] = grpVals[i]
+			}
+			aggrIdx[grpKey] = aggr
+			aggrs = append(aggrs, aggr)
+		}
+		aggr.Count += 1
+		aggr.TotalUsage += cdr.Usage
+		if cdr.Cost > 0 {
+			aggr.TotalCost += cdr.Cost
+		}
+	}
+	sortCDRsAggregates(aggrs, groupBy)
+	return
+}
+
+// sortCDRsAggregates orders the aggregates on their group values, in the order of groupBy
+func sortCDRsAggregates(aggrs []*CDRsAggregate, groupBy []*utils.CDRsGroupBy) {
+	sort.SliceStable(aggrs, func(i, j int) bool {
+		for _, gb := range groupBy {
+			if aggrs[i].Group[gb.Rule] != aggrs[j].Group[gb.Rule] {
+				return aggrs[i].Group[gb.Rule] < aggrs[j].Group[gb.Rule]
+			}
+		}
+		return false
+	})
+}
+
+// paginateCDRsAggregates returns the aggregates within the limit and offset of pgnt
+func paginateCDRsAggregates(aggrs []*CDRsAggregate, pgnt utils.Paginator) []*CDRsAggregate {
+	if pgnt.Offset != nil && *pgnt.Offset > 0 {
+		if *pgnt.Offset >= len(aggrs) {
+			return nil
+		}
+		aggrs = aggrs[*pgnt.Offset:]
+	}
+	if pgnt.Limit != nil && *pgnt.Limit > 0 && *pgnt.Limit < len(aggrs) {
+		aggrs = aggrs[:*pgnt.Limit]
+	}
+	return aggrs
+}
@@ -0,0 +1,202 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestAggregateCDRs(t *testing.T) {
+	cdrs := []*CDR{
+		{Account: "1002", Destination: "4986517174963", Usage: time.Minute, Cost: 0.5,
+			AnswerTime: time.Date(2018, 10, 4, 15, 10, 0, 0, time.UTC)},
+		{Account: "1001", Destination: "4986517174964", Usage: 2 * time.Minute, Cost: 1,
+			AnswerTime: time.Date(2018, 10, 4, 16, 10, 0, 0, time.UTC)},
+		{Account: "1001", Destination: "4915117174963", Usage: 30 * time.Second, Cost: -1,
+			AnswerTime: time.Date(2018, 10, 4, 23, 59, 0, 0, time.UTC)},
+		{Account: "1001", Destination: "4986", Usage: 10 * time.Second, Cost: 0.2,
+			AnswerTime: time.Date(2018, 10, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	groupBy := []*utils.CDRsGroupBy{
+		{Rule: utils.Account, Field: utils.Account},
+		{Rule: "Destination:4", Field: utils.Destination, PrefixLength: 4},
+		{Rule: "AnswerTime:*daily", Field: utils.AnswerTime, Interval: utils.MetaDaily},
+	}
+	eAggrs := []*CDRsAggregate{
+		{Group: map[string]string{utils.Account: "1001", "Destination:4": "4915", "AnswerTime:*daily": "2018-10-04"},
+			Count: 1, TotalUsage: 30 * time.Second},
+		{Group: map[string]string{utils.Account: "1001", "Destination:4": "4986", "AnswerTime:*daily": "2018-10-04"},
+			Count: 1, TotalUsage: 2 * time.Minute, TotalCost: 1},
+		{Group: map[string]string{utils.Account: "1001", "Destination:4": "4986", "AnswerTime:*daily": "2018-10-05"},
+			Count: 1, TotalUsage: 10 * time.Second, TotalCost: 0.2},
+		{Group: map[string]string{utils.Account: "1002", "Destination:4": "4986", "AnswerTime:*daily": "2018-10-04"},
+			Count: 1, TotalUsage: time.Minute, TotalCost: 0.5},
+	}
+	if aggrs := aggregateCDRs(cdrs, groupBy); !reflect.DeepEqual(eAggrs, aggrs) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eAggrs), utils.ToJSON(aggrs))
+	}
+	eAggrs = []*CDRsAggregate{
+		{Group: map[string]string{"Destination:4": "4915"},
+			Count: 1, TotalUsage: 30 * time.Second},
+		{Group: map[string]string{"Destination:4": "4986"},
+			Count: 3, TotalUsage: 3*time.Minute + 10*time.Second, TotalCost: 1.7},
+	}
+	if aggrs := aggregateCDRs(cdrs, groupBy[1:2]); !reflect.DeepEqual(eAggrs, aggrs) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eAggrs), utils.ToJSON(aggrs))
+	}
+	eAggrs = []*CDRsAggregate{
+		{Group: map[string]string{}, Count: 4,
+			TotalUsage: 3*time.Minute + 40*time.Second, TotalCost: 1.7},
+	}
+	if aggrs := aggregateCDRs(cdrs, nil); !reflect.DeepEqual(eAggrs, aggrs) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eAggrs), utils.ToJSON(aggrs))
+	}
+}
+
+func TestPaginateCDRsAggregates(t *testing.T) {
+	aggrs := []*CDRsAggregate{{Count: 1}, {Count: 2}, {Count: 3}}
+	if rcv := paginateCDRsAggregates(aggrs, utils.Paginator{}); !reflect.DeepEqual(aggrs, rcv) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(aggrs), utils.ToJSON(rcv))
+	}
+	if rcv := paginateCDRsAggregates(aggrs, utils.Paginator{
+		Limit: utils.IntPointer(1), Offset: utils.IntPointer(1)}); !reflect.DeepEqual(aggrs[1:2], rcv) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(aggrs[1:2]), utils.ToJSON(rcv))
+	}
+	if rcv := paginateCDRsAggregates(aggrs, utils.Paginator{Offset: utils.IntPointer(3)}); len(rcv) != 0 {
+		t.Errorf("Expecting no aggregates, received: %s", utils.ToJSON(rcv))
+	}
+}
+
+func TestMapStorageGetCDRsAggregate(t *testing.T) {
+	mpStor, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mpStor.GetCDRsAggregate(new(utils.CDRsFilter), nil); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	cet := time.FixedZone("CET", 3600)
+	for _, cdr := range []*CDR{
+		{CGRID: "cdr1", RunID: utils.MetaDefault, Tenant: "cgrates.org", Account: "1001",
+			Usage: time.Minute, Cost: 0.5, AnswerTime: time.Date(2018, 10, 5, 0, 30, 0, 0, cet)},
+		{CGRID: "cdr2", RunID: utils.MetaDefault, Tenant: "cgrates.org", Account: "1001",
+			Usage: 2 * time.Minute, Cost: 1, AnswerTime: time.Date(2018, 10, 4, 16, 10, 0, 0, time.UTC)},
+		{CGRID: "cdr3", RunID: utils.MetaDefault, Tenant: "cgrates.org", Account: "1002",
+			Usage: 30 * time.Second, Cost: -1, AnswerTime: time.Date(2018, 10, 5, 10, 0, 0, 0, time.UTC)},
+		{CGRID: "cdr4", RunID: utils.MetaDefault, Tenant: "itsyscom.com", Account: "1001",
+			Usage: time.Second, Cost: 0.1, AnswerTime: time.Date(2018, 10, 5, 10, 0, 0, 0, time.UTC)},
+	} {
+		if err := mpStor.SetCDR(cdr, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := mpStor.SetCDR(&CDR{CGRID: "cdr1", RunID: utils.MetaDefault}, false); err != utils.ErrExists {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrExists, err)
+	}
+	fltr := &utils.CDRsFilter{Tenants: []string{"cgrates.org"}}
+	groupBy := []*utils.CDRsGroupBy{
+		{Rule: "AnswerTime:*daily", Field: utils.AnswerTime, Interval: utils.MetaDaily},
+	}
+	eAggrs := []*CDRsAggregate{
+		{Group: map[string]string{"AnswerTime:*daily": "2018-10-04"},
+			Count: 2, TotalUsage: 3 * time.Minute, TotalCost: 1.5},
+		{Group: map[string]string{"AnswerTime:*daily": "2018-10-05"},
+			Count: 1, TotalUsage: 30 * time.Second},
+	}
+	if aggrs, err := mpStor.GetCDRsAggregate(fltr, groupBy); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eAggrs, aggrs) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eAggrs), utils.ToJSON(aggrs))
+	}
+	fltr.Paginator = utils.Paginator{Offset: utils.IntPointer(1)}
+	if aggrs, err := mpStor.GetCDRsAggregate(fltr, groupBy); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eAggrs[1:], aggrs) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eAggrs[1:]), utils.ToJSON(aggrs))
+	}
+}
+
+func TestMapStorageGetCDRs(t *testing.T) {
+	mpStor, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cdr := range []*CDR{
+		{CGRID: "cdr1", RunID: utils.MetaDefault, Destination: "4986517174963",
+			Usage: time.Minute, Cost: 0.5, ExtraFields: map[string]string{"Service": "voice"}},
+		{CGRID: "cdr2", RunID: utils.MetaDefault, Destination: "4915117174963",
+			Usage: 2 * time.Minute, Cost: 1},
+		{CGRID: "cdr3", RunID: "*raw", Destination: "4986517174964",
+			Usage: 30 * time.Second, Cost: -1},
+	} {
+		if err := mpStor.SetCDR(cdr, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cgrIDs := func(cdrs []*CDR) (ids []string) {
+		for _, cdr := range cdrs {
+			ids = append(ids, cdr.CGRID)
+		}
+		return
+	}
+	if cdrs, _, err := mpStor.GetCDRs(&utils.CDRsFilter{}, false); err != nil {
+		t.Error(err)
+	} else if ids := cgrIDs(cdrs); !reflect.DeepEqual([]string{"cdr1", "cdr2", "cdr3"}, ids) {
+		t.Errorf("Received: %+v", ids)
+	}
+	if cdrs, _, err := mpStor.GetCDRs(&utils.CDRsFilter{OrderBy: "Usage;desc",
+		Paginator: utils.Paginator{Limit: utils.IntPointer(2)}}, false); err != nil {
+		t.Error(err)
+	} else if ids := cgrIDs(cdrs); !reflect.DeepEqual([]string{"cdr2", "cdr1"}, ids) {
+		t.Errorf("Received: %+v", ids)
+	}
+	if cdrs, _, err := mpStor.GetCDRs(&utils.CDRsFilter{DestinationPrefixes: []string{"4986"},
+		NotRunIDs: []string{"*raw"}, MinUsage: "1m"}, false); err != nil {
+		t.Error(err)
+	} else if ids := cgrIDs(cdrs); !reflect.DeepEqual([]string{"cdr1"}, ids) {
+		t.Errorf("Received: %+v", ids)
+	}
+	if cdrs, _, err := mpStor.GetCDRs(&utils.CDRsFilter{
+		ExtraFields: map[string]string{"Service": utils.MetaExists}}, false); err != nil {
+		t.Error(err)
+	} else if ids := cgrIDs(cdrs); !reflect.DeepEqual([]string{"cdr1"}, ids) {
+		t.Errorf("Received: %+v", ids)
+	}
+	if _, cnt, err := mpStor.GetCDRs(&utils.CDRsFilter{MinCost: utils.Float64Pointer(0),
+		MaxCost: utils.Float64Pointer(-1), Count: true}, false); err != nil {
+		t.Error(err)
+	} else if cnt != 2 {
+		t.Errorf("Expecting 2 CDRs, received: %d", cnt)
+	}
+	if _, _, err := mpStor.GetCDRs(&utils.CDRsFilter{OrderBy: "Destination"}, false); err == nil {
+		t.Error("Expecting error on invalid OrderBy")
+	}
+	if _, cnt, err := mpStor.GetCDRs(&utils.CDRsFilter{RunIDs: []string{"*raw"}}, true); err != nil {
+		t.Error(err)
+	} else if cnt != 1 {
+		t.Errorf("Expecting 1 removed CDR, received: %d", cnt)
+	}
+	if _, _, err := mpStor.GetCDRs(&utils.CDRsFilter{RunIDs: []string{"*raw"}}, false); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
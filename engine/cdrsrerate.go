@@ -0,0 +1,394 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// CDRRerateDiff is the cost difference of one CDR after rerating
+type CDRRerateDiff struct {
+	CGRID   string
+	RunID   string
+	Tenant  string
+	Account string
+	OldCost float64
+	NewCost float64
+	Delta   float64 // NewCost - OldCost, unrated CDRs (negative cost) are considered with cost 0
+	Error   string  // the CDR could not be rerated and is left unchanged
+
+	Adjustments []*BalanceAdjustment // balances credited/debited with the Delta
+}
+
+// BalanceAdjustment is the value added to a monetary balance when rerating, negative for debits
+type BalanceAdjustment struct {
+	AccountID   string
+	BalanceUUID string
+	BalanceID   string
+	Value       float64
+}
+
+// CDRsRerateReport is the reply of V1RerateCDRs
+type CDRsRerateReport struct {
+	DryRun        bool
+	CDRs          []*CDRRerateDiff
+	AccountsDelta map[string]float64 // total delta indexed on tenant:account
+	TotalDelta    float64
+}
+
+// addDiff adds the diff to the report, errored diffs are not summed
+func (rpl *CDRsRerateReport) addDiff(diff *CDRRerateDiff, roundDec int) {
+	rpl.CDRs = append(rpl.CDRs, diff)
+	if diff.Error != "" {
+		return
+	}
+	acntID := utils.ConcatenatedKey(diff.Tenant, diff.Account)
+	rpl.AccountsDelta[acntID] = roundDelta(rpl.AccountsDelta[acntID]+diff.Delta, roundDec)
+	rpl.TotalDelta = roundDelta(rpl.TotalDelta+diff.Delta, roundDec)
+}
+
+// roundDelta rounds the absolute value so negative deltas are not pushed away from 0
+func roundDelta(delta float64, roundDec int) float64 {
+	if delta < 0 {
+		return -utils.Round(-delta, roundDec, utils.ROUNDING_MIDDLE)
+	}
+	return utils.Round(delta, roundDec, utils.ROUNDING_MIDDLE)
+}
+
+// ecMonetaryCharges returns the monetary balances charged in the EventCost, with the value charged on each
+func ecMonetaryCharges(ec *EventCost) (chrgs []*BalanceAdjustment) {
+	blncIDs := make(map[string]string) // IDs of the balances out of AccountSummary, indexed on UUID
+	nonMonetary := make(utils.StringMap)
+	if ec.AccountSummary != nil {
+		for _, blncSmry := range ec.AccountSummary.BalanceSummaries {
+			if blncSmry.Type != utils.MONETARY {
+				nonMonetary[blncSmry.UUID] = true
+				continue
+			}
+			blncIDs[blncSmry.UUID] = blncSmry.ID
+		}
+	}
+	chrgIdx := make(map[string]*BalanceAdjustment)
+	for _, cIl := range ec.Charges {
+		for _, incr := range cIl.Increments {
+			if incr.Cost == 0 {
+				continue
+			}
+			bc, has := ec.Accounting[incr.AccountingID]
+			if !has {
+				continue
+			}
+			if bc.ExtraChargeID != "" && bc.ExtraChargeID != utils.META_NONE { // units paid with monetary
+				if bc, has = ec.Accounting[bc.ExtraChargeID]; !has {
+					continue
+				}
+			}
+			if bc.BalanceUUID == "" || nonMonetary[bc.BalanceUUID] {
+				continue
+			}
+			chrgKey := utils.ConcatenatedKey(bc.AccountID, bc.BalanceUUID)
+			chrg, has := chrgIdx[chrgKey]
+			if !has {
+				chrg = &BalanceAdjustment{AccountID: bc.AccountID,
+					BalanceUUID: bc.BalanceUUID, BalanceID: blncIDs[bc.BalanceUUID]}
+				chrgIdx[chrgKey] = chrg
+				chrgs = append(chrgs, chrg)
+			}
+			chrg.Value += incr.TotalCost() * float64(cIl.CompressFactor)
+		}
+	}
+	return
+}
+
+// balanceAdjustments splits the delta over the monetary balances charged in the EventCost,
+// in proportion of the value charged on each, positive values are credited back
+func balanceAdjustments(ec *EventCost, delta float64, roundDec int) (adjs []*BalanceAdjustment) {
+	adjs = ecMonetaryCharges(ec)
+	var totalChrgd float64
+	for _, adj := range adjs {
+		totalChrgd += adj.Value
+	}
+	if totalChrgd <= 0 {
+		return nil
+	}
+	remain := -delta
+	for i, adj := range adjs {
+		if i == len(adjs)-1 { // last balance takes the rounding leftovers
+			adj.Value = roundDelta(remain, roundDec)
+			break
+		}
+		adj.Value = roundDelta(-delta*adj.Value/totalChrgd, roundDec)
+		remain -= adj.Value
+	}
+	return
+}
+
+// rerateRunID returns the RunID of the rerate history with sequence seq
+func rerateRunID(runID string, seq int) string {
+	return utils.ConcatenatedKey(runID, utils.MetaRerate, strconv.Itoa(seq))
+}
+
+// rerateAdjustedRunID returns the RunID of the SMCost marking the balances adjusted by the rerate with sequence seq
+func rerateAdjustedRunID(runID string, seq int) string {
+	return utils.ConcatenatedKey(rerateRunID(runID, seq), utils.MetaAdjusted)
+}
+
+// rerateCostSource marks the CDR as stored by the rerate with sequence seq
+func rerateCostSource(seq int) string {
+	return utils.ConcatenatedKey(utils.MetaRerate, strconv.Itoa(seq))
+}
+
+// rerateSequence returns the sequence of the next rerate, out of the rerate history of the CDR
+// pending is true when the last rerate stored its history but not the CDR, so it should be finished with the same sequence
+// adjusted is the SMCost keeping the CostDetails of the pending rerate which already adjusted the balances
+func (cdrS *CdrServer) rerateSequence(cdr *CDR) (seq int, pending bool, adjusted *SMCost, err error) {
+	smCosts, err := cdrS.cdrDb.GetSMCosts(cdr.CGRID, "", cdr.OriginHost, "")
+	if err != nil {
+		if err.Error() != utils.NotFoundCaps {
+			return
+		}
+		err = nil
+	}
+	prfx := utils.ConcatenatedKey(cdr.RunID, utils.MetaRerate) + utils.CONCATENATED_KEY_SEP
+	var lastSMCost *SMCost
+	for _, smCost := range smCosts {
+		if !strings.HasPrefix(smCost.RunID, prfx) {
+			continue
+		}
+		if smCostSeq, errSeq := strconv.Atoi(smCost.RunID[len(prfx):]); errSeq == nil && smCostSeq > seq {
+			seq = smCostSeq
+			lastSMCost = smCost
+		}
+	}
+	if lastSMCost != nil && cdr.CostSource != rerateCostSource(seq) && // CDR not changed since the history was stored
+		cdr.CostSource == lastSMCost.CostSource && cdr.Cost == lastSMCost.CostDetails.GetCost() {
+		adjRunID := rerateAdjustedRunID(cdr.RunID, seq)
+		for _, smCost := range smCosts {
+			if smCost.RunID == adjRunID {
+				adjusted = smCost
+				break
+			}
+		}
+		return seq, true, adjusted, nil
+	}
+	return seq + 1, false, nil, nil
+}
+
+// rerateHistory returns the SMCost keeping the cost of the CDR before the rerate with sequence seq
+func rerateHistory(cdr *CDR, seq int) *SMCost {
+	ec := cdr.CostDetails.Clone()
+	if ec == nil {
+		ec = &EventCost{CGRID: cdr.CGRID, RunID: cdr.RunID, StartTime: cdr.AnswerTime,
+			Usage: utils.DurationPointer(cdr.Usage)}
+	}
+	ec.Cost = utils.Float64Pointer(cdr.Cost)
+	return &SMCost{CGRID: cdr.CGRID, RunID: rerateRunID(cdr.RunID, seq),
+		OriginHost: cdr.OriginHost, OriginID: cdr.OriginID,
+		CostSource: cdr.CostSource, Usage: cdr.Usage, CostDetails: ec}
+}
+
+// rerateEventCost returns the CostDetails of the rerated CDR, keeping the balances initially charged
+// and adding the adjustments of the rerate with sequence seq as charges without usage
+func rerateEventCost(ec *EventCost, adjs []*BalanceAdjustment, seq int, cost float64) (rrEC *EventCost) {
+	rrEC = ec.Clone()
+	if rrEC.Accounting == nil {
+		rrEC.Accounting = make(Accounting)
+	}
+	for i, adj := range adjs {
+		acntID := rerateAccountingID(seq, i)
+		rrEC.Accounting[acntID] = &BalanceCharge{AccountID: adj.AccountID,
+			BalanceUUID: adj.BalanceUUID, Units: -adj.Value}
+		rrEC.Charges = append(rrEC.Charges, &ChargingInterval{CompressFactor: 1,
+			Increments: []*ChargingIncrement{
+				{Cost: -adj.Value, AccountingID: acntID, CompressFactor: 1}}})
+	}
+	rrEC.ResetCounters()
+	rrEC.Cost = utils.Float64Pointer(cost)
+	return
+}
+
+// rerateAccountingID returns the AccountingID of the adjustment with index i of the rerate with sequence seq
+func rerateAccountingID(seq, i int) string {
+	return utils.ConcatenatedKey(rerateCostSource(seq), strconv.Itoa(i))
+}
+
+// rerateAdjustments returns the adjustments of the rerate with sequence seq out of the CostDetails built by rerateEventCost
+func rerateAdjustments(ec *EventCost, seq int) (adjs []*BalanceAdjustment) {
+	blncIDs := make(map[string]string)
+	if ec.AccountSummary != nil {
+		for _, blncSmry := range ec.AccountSummary.BalanceSummaries {
+			blncIDs[blncSmry.UUID] = blncSmry.ID
+		}
+	}
+	for i := 0; ; i++ {
+		bc, has := ec.Accounting[rerateAccountingID(seq, i)]
+		if !has {
+			return
+		}
+		adjs = append(adjs, &BalanceAdjustment{AccountID: bc.AccountID,
+			BalanceUUID: bc.BalanceUUID, BalanceID: blncIDs[bc.BalanceUUID], Value: -bc.Units})
+	}
+}
+
+// adjustBalances applies the adjustments of the rerate with sequence seq via RALs, logging each of them
+func (cdrS *CdrServer) adjustBalances(cdr *CDR, adjs []*BalanceAdjustment, seq int) (err error) {
+	incrs := make(Increments, len(adjs))
+	for i, adj := range adjs {
+		incrs[i] = &Increment{Cost: adj.Value, CompressFactor: 1,
+			BalanceInfo: &DebitInfo{AccountID: adj.AccountID,
+				Monetary: &MonetaryInfo{UUID: adj.BalanceUUID, ID: adj.BalanceID}}}
+	}
+	cd := &CallDescriptor{
+		CgrID:       cdr.CGRID,
+		RunID:       rerateRunID(cdr.RunID, seq),
+		Direction:   utils.OUT,
+		Category:    cdr.Category,
+		Tenant:      cdr.Tenant,
+		Subject:     cdr.Subject,
+		Account:     cdr.Account,
+		Destination: cdr.Destination,
+		TOR:         cdr.ToR,
+		Increments:  incrs,
+	}
+	var acnt Account
+	if err = cdrS.rals.Call("Responder.RefundIncrements", cd, &acnt); err != nil {
+		return
+	}
+	for _, adj := range adjs {
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> rerating CDR with CGRID: %s, RunID: %s, adjusted balance with UUID: %s, ID: %s of account: %s with value: %v",
+				utils.CDRs, cdr.CGRID, cdr.RunID, adj.BalanceUUID, adj.BalanceID, adj.AccountID, adj.Value))
+	}
+	return
+}
+
+// rerateCDRWithDiff calculates the CDR cost out of the current tariffs, balances are not debited
+// with adjustBlncs, the delta of the CDRs debiting balances is split over the monetary balances initially charged
+// unless dryRun, the previous cost is kept as SMCost with the rerate RunID before adjusting the balances,
+// the adjusted balances are marked with the new CostDetails as SMCost, so a retry does not adjust them again,
+// then the CDR is stored with the new cost, marked with the rerate sequence in CostSource
+func (cdrS *CdrServer) rerateCDRWithDiff(cdr *CDR, dryRun, adjustBlncs bool) (diff *CDRRerateDiff) {
+	diff = &CDRRerateDiff{CGRID: cdr.CGRID, RunID: cdr.RunID,
+		Tenant: cdr.Tenant, Account: cdr.Account, OldCost: cdr.Cost}
+	cc := new(CallCost)
+	if err := cdrS.rals.Call("Responder.GetCost",
+		newCallDescriptorFromCDR(cdr), cc); err != nil {
+		diff.Error = err.Error()
+		return
+	}
+	diff.NewCost = cc.Cost
+	diff.Delta = roundDelta(diff.NewCost-math.Max(diff.OldCost, 0),
+		cdrS.cgrCfg.GeneralCfg().RoundingDecimals)
+	if adjustBlncs && diff.Delta != 0 && cdr.CostDetails != nil &&
+		utils.IsSliceMember([]string{utils.META_PREPAID, utils.META_PSEUDOPREPAID, utils.META_POSTPAID,
+			utils.PREPAID, utils.PSEUDOPREPAID, utils.POSTPAID}, cdr.RequestType) {
+		if diff.Adjustments = balanceAdjustments(cdr.CostDetails, diff.Delta,
+			cdrS.cgrCfg.GeneralCfg().RoundingDecimals); len(diff.Adjustments) == 0 {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> rerating CDR with CGRID: %s, RunID: %s, no monetary balance charged to adjust with: %v",
+					utils.CDRs, cdr.CGRID, cdr.RunID, diff.Delta))
+		}
+	}
+	if dryRun {
+		return
+	}
+	seq, pending, adjusted, err := cdrS.rerateSequence(cdr)
+	if err == nil && !pending {
+		err = cdrS.cdrDb.SetSMCost(rerateHistory(cdr, seq))
+	}
+	if err != nil {
+		utils.Logger.Err(
+			fmt.Sprintf("<%s> error: %s storing rerate history of CDR with CGRID: %s, RunID: %s",
+				utils.CDRs, err.Error(), cdr.CGRID, cdr.RunID))
+		diff.Error = err.Error()
+		return
+	}
+	if adjusted != nil { // balances already adjusted, finish the rerate with the cost they were adjusted for
+		diff.NewCost = adjusted.CostDetails.GetCost()
+		diff.Delta = roundDelta(diff.NewCost-math.Max(diff.OldCost, 0),
+			cdrS.cgrCfg.GeneralCfg().RoundingDecimals)
+		diff.Adjustments = rerateAdjustments(adjusted.CostDetails, seq)
+		cdr.CostDetails = adjusted.CostDetails
+	} else if len(diff.Adjustments) != 0 {
+		if err := cdrS.adjustBalances(cdr, diff.Adjustments, seq); err != nil {
+			diff.Error = err.Error()
+			return
+		}
+		rrEC := rerateEventCost(cdr.CostDetails, diff.Adjustments, seq, cc.Cost)
+		if err := cdrS.cdrDb.SetSMCost(&SMCost{CGRID: cdr.CGRID, RunID: rerateAdjustedRunID(cdr.RunID, seq),
+			OriginHost: cdr.OriginHost, OriginID: cdr.OriginID, CostSource: rerateCostSource(seq),
+			Usage: cdr.Usage, CostDetails: rrEC}); err != nil {
+			utils.Logger.Err(
+				fmt.Sprintf("<%s> error: %s storing rerate adjustments of CDR with CGRID: %s, RunID: %s, balances adjusted: %d",
+					utils.CDRs, err.Error(), cdr.CGRID, cdr.RunID, len(diff.Adjustments)))
+			diff.Error = err.Error()
+			return
+		}
+		cdr.CostDetails = rrEC
+	} else {
+		cdr.CostDetails = NewEventCostFromCallCost(cc, cdr.CGRID, cdr.RunID)
+	}
+	cdr.Cost = diff.NewCost
+	cdr.CostSource = rerateCostSource(seq)
+	cdr.ExtraInfo = ""
+	if err := cdrS.cdrDb.SetCDR(cdr, true); err != nil {
+		utils.Logger.Err(
+			fmt.Sprintf("<%s> error: %s storing rerated CDR with CGRID: %s, RunID: %s, balances adjusted: %d",
+				utils.CDRs, err.Error(), cdr.CGRID, cdr.RunID, len(diff.Adjustments)))
+		diff.Error = err.Error()
+	}
+	return
+}
+
+// V1RerateCDRs rates again the CDRs out of the current tariffs and reports the cost difference
+// per CDR and per account, *raw and *none CDRs are not rerated
+func (cdrS *CdrServer) V1RerateCDRs(args utils.ArgsRerateCDRs, rpl *CDRsRerateReport) error {
+	if cdrS.rals == nil {
+		return utils.NewErrNotConnected(utils.RALService)
+	}
+	cdrsFltr, err := args.AsCDRsFilter(cdrS.Timezone())
+	if err != nil {
+		if err.Error() != utils.NotFoundCaps {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	cdrsFltr.NotRunIDs = append(cdrsFltr.NotRunIDs, utils.MetaRaw)
+	cdrsFltr.NotRequestTypes = append(cdrsFltr.NotRequestTypes, utils.META_NONE)
+	cdrs, _, err := cdrS.cdrDb.GetCDRs(cdrsFltr, false)
+	if err != nil {
+		if err.Error() != utils.NotFoundCaps {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	report := &CDRsRerateReport{DryRun: args.DryRun,
+		AccountsDelta: make(map[string]float64)}
+	for _, cdr := range cdrs {
+		report.addDiff(cdrS.rerateCDRWithDiff(cdr, args.DryRun, args.AdjustBalances),
+			cdrS.cgrCfg.GeneralCfg().RoundingDecimals)
+	}
+	*rpl = *report
+	return nil
+}
@@ -0,0 +1,363 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// failSetCDRStorage fails the first fails SetCDR calls
+type failSetCDRStorage struct {
+	*MapStorage
+	fails int
+}
+
+func (ms *failSetCDRStorage) SetCDR(cdr *CDR, allowUpdate bool) error {
+	if ms.fails > 0 {
+		ms.fails--
+		return utils.ErrServerError
+	}
+	return ms.MapStorage.SetCDR(cdr, allowUpdate)
+}
+
+// rerateConn replies to Responder.GetCost with the cost configured for the Account
+// and records the Responder.RefundIncrements calls, failing them with refundErr
+type rerateConn struct {
+	costs     map[string]float64
+	refunds   []*CallDescriptor
+	refundErr error
+}
+
+func (c *rerateConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	cd := args.(*CallDescriptor)
+	if serviceMethod == "Responder.RefundIncrements" {
+		c.refunds = append(c.refunds, cd)
+		return c.refundErr
+	}
+	cost, has := c.costs[cd.Account]
+	if !has {
+		return utils.ErrNotFound
+	}
+	*(reply.(*CallCost)) = CallCost{Destination: cd.Destination, Cost: cost}
+	return nil
+}
+
+func TestCDRsRerateCDRWithDiff(t *testing.T) {
+	cdrS := &CdrServer{cgrCfg: config.CgrConfig(),
+		rals: &rerateConn{costs: map[string]float64{"1001": 0.7, "1002": 0.2}}}
+	cdrs := []*CDR{
+		{CGRID: "CGRID1", RunID: utils.META_DEFAULT, Tenant: "cgrates.org", Account: "1001",
+			Destination: "1002", Usage: time.Minute, Cost: 0.5,
+			AnswerTime: time.Date(2018, 10, 4, 15, 10, 0, 0, time.UTC)},
+		{CGRID: "CGRID2", RunID: utils.META_DEFAULT, Tenant: "cgrates.org", Account: "1001",
+			Destination: "1003", Usage: time.Minute, Cost: -1,
+			AnswerTime: time.Date(2018, 10, 4, 15, 11, 0, 0, time.UTC)},
+		{CGRID: "CGRID3", RunID: utils.META_DEFAULT, Tenant: "cgrates.org", Account: "1002",
+			Destination: "1001", Usage: time.Minute, Cost: 0.3,
+			AnswerTime: time.Date(2018, 10, 4, 15, 12, 0, 0, time.UTC)},
+		{CGRID: "CGRID4", RunID: utils.META_DEFAULT, Tenant: "cgrates.org", Account: "1003",
+			Destination: "1001", Usage: time.Minute, Cost: 0.3,
+			AnswerTime: time.Date(2018, 10, 4, 15, 13, 0, 0, time.UTC)},
+	}
+	report := &CDRsRerateReport{DryRun: true, AccountsDelta: make(map[string]float64)}
+	for _, cdr := range cdrs {
+		report.addDiff(cdrS.rerateCDRWithDiff(cdr, true, false), 4)
+	}
+	eReport := &CDRsRerateReport{
+		DryRun: true,
+		CDRs: []*CDRRerateDiff{
+			{CGRID: "CGRID1", RunID: utils.META_DEFAULT, Tenant: "cgrates.org", Account: "1001",
+				OldCost: 0.5, NewCost: 0.7, Delta: 0.2},
+			{CGRID: "CGRID2", RunID: utils.META_DEFAULT, Tenant: "cgrates.org", Account: "1001",
+				OldCost: -1, NewCost: 0.7, Delta: 0.7},
+			{CGRID: "CGRID3", RunID: utils.META_DEFAULT, Tenant: "cgrates.org", Account: "1002",
+				OldCost: 0.3, NewCost: 0.2, Delta: -0.1},
+			{CGRID: "CGRID4", RunID: utils.META_DEFAULT, Tenant: "cgrates.org", Account: "1003",
+				OldCost: 0.3, Error: utils.ErrNotFound.Error()},
+		},
+		AccountsDelta: map[string]float64{
+			"cgrates.org:1001": 0.9,
+			"cgrates.org:1002": -0.1,
+		},
+		TotalDelta: 0.8,
+	}
+	if !reflect.DeepEqual(eReport, report) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eReport), utils.ToJSON(report))
+	}
+	for _, cdr := range cdrs { // dry run should not touch the CDRs
+		if cdr.CostDetails != nil || len(cdr.ExtraFields) != 0 {
+			t.Errorf("CDR modified on dry run: %s", utils.ToJSON(cdr))
+		}
+	}
+	if cdrs[0].Cost != 0.5 {
+		t.Errorf("Expecting cost: 0.5, received: %v", cdrs[0].Cost)
+	}
+}
+
+func TestCDRsRoundDelta(t *testing.T) {
+	for delta, eRounded := range map[float64]float64{
+		0.19999999999999996:  0.2,
+		-0.09999999999999998: -0.1,
+		-0.10004:             -0.1,
+		-0.10005:             -0.1001,
+	} {
+		if rounded := roundDelta(delta, 4); rounded != eRounded {
+			t.Errorf("delta: %v, expecting: %v, received: %v", delta, eRounded, rounded)
+		}
+	}
+}
+
+func testRerateEventCost() *EventCost {
+	return &EventCost{
+		CGRID: "CGRID1",
+		RunID: utils.META_DEFAULT,
+		Charges: []*ChargingInterval{
+			{RatingID: "RATING1", CompressFactor: 1,
+				Increments: []*ChargingIncrement{
+					{Usage: time.Second, AccountingID: "ACNT_VOICE", CompressFactor: 30},
+					{Usage: time.Second, Cost: 0.01, AccountingID: "ACNT_MONETARY", CompressFactor: 30},
+				}},
+			{RatingID: "RATING1", CompressFactor: 2,
+				Increments: []*ChargingIncrement{
+					{Usage: time.Minute, Cost: 0.3, AccountingID: "ACNT_VOICE_WITH_COST", CompressFactor: 1},
+				}},
+		},
+		Accounting: Accounting{
+			"ACNT_VOICE": &BalanceCharge{AccountID: "cgrates.org:1001",
+				BalanceUUID: "UUID_VOICE", Units: 1},
+			"ACNT_MONETARY": &BalanceCharge{AccountID: "cgrates.org:1001",
+				BalanceUUID: "UUID_MONETARY", Units: 0.01},
+			"ACNT_VOICE_WITH_COST": &BalanceCharge{AccountID: "cgrates.org:1001",
+				BalanceUUID: "UUID_VOICE", Units: 60, ExtraChargeID: "ACNT_SHARED"},
+			"ACNT_SHARED": &BalanceCharge{AccountID: "cgrates.org:SHARED",
+				BalanceUUID: "UUID_SHARED", Units: 0.3},
+		},
+		AccountSummary: &AccountSummary{Tenant: "cgrates.org", ID: "1001",
+			BalanceSummaries: []*BalanceSummary{
+				{UUID: "UUID_VOICE", ID: "VOICE", Type: utils.VOICE},
+				{UUID: "UUID_MONETARY", ID: "MONETARY", Type: utils.MONETARY},
+			}},
+	}
+}
+
+func TestCDRsBalanceAdjustments(t *testing.T) {
+	eAdjs := []*BalanceAdjustment{
+		{AccountID: "cgrates.org:1001", BalanceUUID: "UUID_MONETARY", BalanceID: "MONETARY", Value: 0.1},
+		{AccountID: "cgrates.org:SHARED", BalanceUUID: "UUID_SHARED", Value: 0.2},
+	}
+	if adjs := balanceAdjustments(testRerateEventCost(), -0.3, 4); !reflect.DeepEqual(eAdjs, adjs) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eAdjs), utils.ToJSON(adjs))
+	}
+	eAdjs = []*BalanceAdjustment{
+		{AccountID: "cgrates.org:1001", BalanceUUID: "UUID_MONETARY", BalanceID: "MONETARY", Value: -0.03},
+		{AccountID: "cgrates.org:SHARED", BalanceUUID: "UUID_SHARED", Value: -0.06},
+	}
+	if adjs := balanceAdjustments(testRerateEventCost(), 0.09, 4); !reflect.DeepEqual(eAdjs, adjs) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eAdjs), utils.ToJSON(adjs))
+	}
+	ec := testRerateEventCost() // only free units charged
+	ec.Charges = ec.Charges[:1]
+	ec.Charges[0].Increments = ec.Charges[0].Increments[:1]
+	if adjs := balanceAdjustments(ec, -0.3, 4); adjs != nil {
+		t.Errorf("Expecting no adjustments, received: %s", utils.ToJSON(adjs))
+	}
+}
+
+func TestCDRsRerateCDRWithDiffAdjustBalances(t *testing.T) {
+	cdrS := &CdrServer{cgrCfg: config.CgrConfig(),
+		rals: &rerateConn{costs: map[string]float64{"1001": 0.6}}}
+	cdr := &CDR{CGRID: "CGRID1", RunID: utils.META_DEFAULT, RequestType: utils.META_PREPAID,
+		Tenant: "cgrates.org", Account: "1001", Destination: "1002", Usage: 150 * time.Second,
+		AnswerTime: time.Date(2018, 10, 4, 15, 10, 0, 0, time.UTC),
+		Cost:       0.9, CostDetails: testRerateEventCost()}
+	eDiff := &CDRRerateDiff{CGRID: "CGRID1", RunID: utils.META_DEFAULT,
+		Tenant: "cgrates.org", Account: "1001", OldCost: 0.9, NewCost: 0.6, Delta: -0.3,
+		Adjustments: []*BalanceAdjustment{
+			{AccountID: "cgrates.org:1001", BalanceUUID: "UUID_MONETARY", BalanceID: "MONETARY", Value: 0.1},
+			{AccountID: "cgrates.org:SHARED", BalanceUUID: "UUID_SHARED", Value: 0.2},
+		}}
+	if diff := cdrS.rerateCDRWithDiff(cdr, true, true); !reflect.DeepEqual(eDiff, diff) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eDiff), utils.ToJSON(diff))
+	}
+	cdr.RequestType = utils.META_RATED // no balances debited
+	eDiff.Adjustments = nil
+	if diff := cdrS.rerateCDRWithDiff(cdr, true, true); !reflect.DeepEqual(eDiff, diff) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eDiff), utils.ToJSON(diff))
+	}
+}
+
+func TestCDRsRerateCDRWithDiffHistory(t *testing.T) {
+	cdrDb, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rals := &rerateConn{costs: map[string]float64{"1001": 0.7}}
+	cdrS := &CdrServer{cgrCfg: config.CgrConfig(), cdrDb: cdrDb, rals: rals}
+	cdr := &CDR{CGRID: "CGRID1", RunID: utils.META_DEFAULT, OriginHost: "127.0.0.1",
+		Tenant: "cgrates.org", Account: "1001", Destination: "1002", Usage: time.Minute,
+		AnswerTime: time.Date(2018, 10, 4, 15, 10, 0, 0, time.UTC), Cost: 0.5,
+		CostSource: utils.MetaSessionS, ExtraFields: map[string]string{"Service": "voice"}}
+	if err := cdrDb.SetCDR(cdr, false); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cdrS.rerateCDRWithDiff(cdr, false, false); diff.Error != "" {
+		t.Fatal(diff.Error)
+	}
+	rals.costs["1001"] = 0.8
+	if diff := cdrS.rerateCDRWithDiff(cdr, false, false); diff.Error != "" {
+		t.Fatal(diff.Error)
+	}
+	if cdrs, _, err := cdrDb.GetCDRs(&utils.CDRsFilter{CGRIDs: []string{"CGRID1"}}, false); err != nil {
+		t.Error(err)
+	} else if len(cdrs) != 1 || cdrs[0].Cost != 0.8 {
+		t.Errorf("Unexpected CDRs: %s", utils.ToJSON(cdrs))
+	} else if eExtra := map[string]string{"Service": "voice"}; !reflect.DeepEqual(eExtra, cdrs[0].ExtraFields) {
+		t.Errorf("Expecting: %+v, received: %+v", eExtra, cdrs[0].ExtraFields)
+	}
+	for seq, ePrev := range map[int]*SMCost{
+		1: {CostSource: utils.MetaSessionS, CostDetails: &EventCost{Cost: utils.Float64Pointer(0.5)}},
+		2: {CostSource: rerateCostSource(1), CostDetails: &EventCost{Cost: utils.Float64Pointer(0.7)}},
+	} {
+		runID := rerateRunID(utils.META_DEFAULT, seq)
+		if smCosts, err := cdrDb.GetSMCosts("CGRID1", runID, "127.0.0.1", ""); err != nil {
+			t.Error(err)
+		} else if len(smCosts) != 1 || smCosts[0].CostSource != ePrev.CostSource ||
+			smCosts[0].CostDetails.GetCost() != ePrev.CostDetails.GetCost() {
+			t.Errorf("Unexpected history for %s: %s", runID, utils.ToJSON(smCosts))
+		}
+	}
+}
+
+func TestCDRsRerateCDRWithDiffRetryAdjustment(t *testing.T) {
+	cdrDb, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rals := &rerateConn{costs: map[string]float64{"1001": 0.6}, refundErr: utils.ErrServerError}
+	cdrS := &CdrServer{cgrCfg: config.CgrConfig(), cdrDb: cdrDb, rals: rals}
+	cdr := &CDR{CGRID: "CGRID1", RunID: utils.META_DEFAULT, RequestType: utils.META_PREPAID,
+		Tenant: "cgrates.org", Account: "1001", Destination: "1002", Usage: 150 * time.Second,
+		AnswerTime: time.Date(2018, 10, 4, 15, 10, 0, 0, time.UTC),
+		Cost:       0.9, CostSource: utils.MetaSessionS, CostDetails: testRerateEventCost()}
+	if err := cdrDb.SetCDR(cdr, false); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cdrS.rerateCDRWithDiff(cdr, false, true); diff.Error != utils.ErrServerError.Error() {
+		t.Errorf("Expecting error: %v, received: %s", utils.ErrServerError, diff.Error)
+	}
+	rals.refundErr = nil
+	if diff := cdrS.rerateCDRWithDiff(cdr, false, true); diff.Error != "" {
+		t.Fatal(diff.Error)
+	}
+	if len(rals.refunds) != 2 {
+		t.Fatalf("Expecting 2 refunds, received: %s", utils.ToJSON(rals.refunds))
+	}
+	eRunID := rerateRunID(utils.META_DEFAULT, 1)
+	for _, cd := range rals.refunds {
+		if cd.CgrID != "CGRID1" || cd.RunID != eRunID {
+			t.Errorf("Expecting CgrID: CGRID1, RunID: %s, received: %s, %s", eRunID, cd.CgrID, cd.RunID)
+		}
+	}
+	if smCosts, err := cdrDb.GetSMCosts("CGRID1", "", "", ""); err != nil {
+		t.Error(err)
+	} else if len(smCosts) != 2 {
+		t.Errorf("Unexpected history: %s", utils.ToJSON(smCosts))
+	}
+	cdrs, _, err := cdrDb.GetCDRs(&utils.CDRsFilter{CGRIDs: []string{"CGRID1"}}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cdrs[0].Cost != 0.6 || cdrs[0].CostSource != rerateCostSource(1) {
+		t.Errorf("Unexpected CDR: %s", utils.ToJSON(cdrs[0]))
+	}
+	ec := cdrs[0].CostDetails
+	for acntID, bc := range testRerateEventCost().Accounting {
+		if !reflect.DeepEqual(bc, ec.Accounting[acntID]) {
+			t.Errorf("Expecting accounting %s: %s, received: %s", acntID, utils.ToJSON(bc), utils.ToJSON(ec.Accounting[acntID]))
+		}
+	}
+	ec.ResetCounters()
+	if cost := ec.GetCost(); cost != 0.6 {
+		t.Errorf("Expecting cost: 0.6, received: %v", cost)
+	}
+	eChrgs := []*BalanceAdjustment{
+		{AccountID: "cgrates.org:1001", BalanceUUID: "UUID_MONETARY", BalanceID: "MONETARY", Value: 0.2},
+		{AccountID: "cgrates.org:SHARED", BalanceUUID: "UUID_SHARED", Value: 0.4},
+	}
+	chrgs := ecMonetaryCharges(ec)
+	for _, chrg := range chrgs {
+		chrg.Value = roundDelta(chrg.Value, 4)
+	}
+	if !reflect.DeepEqual(eChrgs, chrgs) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eChrgs), utils.ToJSON(chrgs))
+	}
+}
+
+func TestCDRsRerateCDRWithDiffRetrySetCDR(t *testing.T) {
+	mapDb, err := NewMapStorage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cdrDb := &failSetCDRStorage{MapStorage: mapDb}
+	rals := &rerateConn{costs: map[string]float64{"1001": 0.6}}
+	cdrS := &CdrServer{cgrCfg: config.CgrConfig(), cdrDb: cdrDb, rals: rals}
+	cdr := &CDR{CGRID: "CGRID1", RunID: utils.META_DEFAULT, RequestType: utils.META_PREPAID,
+		Tenant: "cgrates.org", Account: "1001", Destination: "1002", Usage: 150 * time.Second,
+		AnswerTime: time.Date(2018, 10, 4, 15, 10, 0, 0, time.UTC),
+		Cost:       0.9, CostSource: utils.MetaSessionS, CostDetails: testRerateEventCost()}
+	if err := cdrDb.SetCDR(cdr, false); err != nil {
+		t.Fatal(err)
+	}
+	cdrDb.fails = 1
+	if diff := cdrS.rerateCDRWithDiff(cdr.Clone(), false, true); diff.Error != utils.ErrServerError.Error() {
+		t.Errorf("Expecting error: %v, received: %s", utils.ErrServerError, diff.Error)
+	}
+	rals.costs["1001"] = 0.5 // the retry keeps the cost the balances were adjusted for
+	diff := cdrS.rerateCDRWithDiff(cdr.Clone(), false, true)
+	if diff.Error != "" {
+		t.Fatal(diff.Error)
+	}
+	if len(rals.refunds) != 1 {
+		t.Errorf("Expecting 1 refund, received: %s", utils.ToJSON(rals.refunds))
+	}
+	eAdjs := []*BalanceAdjustment{
+		{AccountID: "cgrates.org:1001", BalanceUUID: "UUID_MONETARY", BalanceID: "MONETARY", Value: 0.1},
+		{AccountID: "cgrates.org:SHARED", BalanceUUID: "UUID_SHARED", Value: 0.2},
+	}
+	if diff.NewCost != 0.6 || diff.Delta != -0.3 || !reflect.DeepEqual(eAdjs, diff.Adjustments) {
+		t.Errorf("Unexpected diff: %s", utils.ToJSON(diff))
+	}
+	cdrs, _, err := cdrDb.GetCDRs(&utils.CDRsFilter{CGRIDs: []string{"CGRID1"}}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cdrs) != 1 || cdrs[0].Cost != 0.6 || cdrs[0].CostSource != rerateCostSource(1) {
+		t.Fatalf("Unexpected CDRs: %s", utils.ToJSON(cdrs))
+	}
+	if seq, pending, adjusted, err := cdrS.rerateSequence(cdrs[0]); err != nil {
+		t.Error(err)
+	} else if seq != 2 || pending || adjusted != nil {
+		t.Errorf("Unexpected sequence: %d, pending: %v, adjusted: %s", seq, pending, utils.ToJSON(adjusted))
+	}
+}
@@ -63,7 +63,7 @@ func (cS *ChargerService) Shutdown() (err error) {
 
 // matchingChargingProfilesForEvent returns ordered list of matching chargers which are active by the time of the function call
 func (cS *ChargerService) matchingChargerProfilesForEvent(cgrEv *utils.CGREvent) (cPs ChargerProfiles, err error) {
-	cpIDs, err := matchingItemIDsForEvent(cgrEv.Event,
+	cpIDs, err := MatchingItemIDsForEvent(cgrEv.Event,
 		cS.cfg.ChargerSCfg().StringIndexedFields, cS.cfg.ChargerSCfg().PrefixIndexedFields,
 		cS.dm, utils.CacheChargerFilterIndexes, cgrEv.Tenant, cS.cfg.FilterSCfg().IndexedSelects)
 	if err != nil {
@@ -0,0 +1,328 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/guardian"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/streadway/amqp"
+)
+
+// ReplayFailedPost re-sends the content of a failed post out of fileName
+// on errors the posters will write the content back into failoverDir, unless *none
+func ReplayFailedPost(fileName string, ffn *utils.FallbackFileName,
+	content []byte, failoverDir string) (err error) {
+	cfg := config.CgrConfig()
+	failoverPath := utils.META_NONE
+	if failoverDir != utils.META_NONE {
+		failoverPath = path.Join(failoverDir, fileName)
+	}
+	switch ffn.Transport {
+	case utils.MetaHTTPjsonCDR, utils.MetaHTTPjsonMap, utils.MetaHTTPjson, utils.META_HTTP_POST:
+		_, err = NewHTTPPoster(cfg.GeneralCfg().HttpSkipTlsVerify,
+			cfg.GeneralCfg().ReplyTimeout).Post(ffn.Address,
+			utils.PosterTransportContentTypes[ffn.Transport], content,
+			cfg.GeneralCfg().PosterAttempts, failoverPath)
+	case utils.MetaAMQPjsonCDR, utils.MetaAMQPjsonMap:
+		var amqpPoster *AMQPPoster
+		amqpPoster, err = AMQPPostersCache.GetAMQPPoster(ffn.Address,
+			cfg.GeneralCfg().PosterAttempts, failoverDir)
+		if err == nil { // error will be checked bellow
+			var chn *amqp.Channel
+			chn, err = amqpPoster.Post(
+				nil, utils.PosterTransportContentTypes[ffn.Transport],
+				content, fileName)
+			if chn != nil {
+				chn.Close()
+			}
+		}
+	case utils.MetaKafkajsonCDR, utils.MetaKafkajsonMap:
+		var kafkaPoster *KafkaPoster
+		if kafkaPoster, err = KafkaPostersCache.GetKafkaPoster(ffn.Address,
+			cfg.GeneralCfg().PosterAttempts, failoverDir); err == nil {
+			fallbackFileName := utils.META_NONE
+			if failoverDir != utils.META_NONE {
+				fallbackFileName = fileName
+			}
+			err = kafkaPoster.Post(content, fallbackFileName)
+		}
+	default:
+		err = fmt.Errorf("unsupported replication transport: %s", ffn.Transport)
+	}
+	return
+}
+
+// WriteFailedPost writes the content of a failed post if the posters did not do it already
+func WriteFailedPost(filePath string, content []byte) (err error) {
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		if _, err := os.Stat(filePath); err == nil || !os.IsNotExist(err) {
+			return 0, err
+		}
+		fileOut, err := os.Create(filePath)
+		if err != nil {
+			return 0, err
+		}
+		defer fileOut.Close()
+		if _, err := fileOut.Write(content); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.FileLockPrefix+filePath)
+	return
+}
+
+// writeBackFailedPost writes the post back after a failed replay
+// the ModTime of the file is kept as the time of the first failure so MaxAge survives restarts
+func writeBackFailedPost(filePath string, content []byte, firstSeen time.Time) (err error) {
+	if err = WriteFailedPost(filePath, content); err != nil {
+		return
+	}
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		return 0, os.Chtimes(filePath, time.Now(), firstSeen)
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.FileLockPrefix+filePath)
+	return
+}
+
+// readFailedPost reads and removes the file of a failed post
+func readFailedPost(filePath string) (content []byte, err error) {
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		if content, err = ioutil.ReadFile(filePath); err != nil {
+			return 0, err
+		}
+		if err := os.Remove(filePath); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.FileLockPrefix+filePath)
+	return
+}
+
+// NewFailedPostsReplayer constructs a FailedPostsReplayer for the posts in failedPostsDir
+func NewFailedPostsReplayer(cfg *config.FailedPostsCfg, failedPostsDir string) *FailedPostsReplayer {
+	return &FailedPostsReplayer{cfg: cfg,
+		failedPostsDir: failedPostsDir,
+		posts:          make(map[string]*failedPost),
+		stopReplay:     make(chan struct{})}
+}
+
+// FailedPostsReplayer re-sends periodically the posts found in the failed posts directory
+// each post failing again is delayed with exponential backoff until it reaches MaxAge and is moved to DeadLetterDir
+type FailedPostsReplayer struct {
+	cfg            *config.FailedPostsCfg
+	failedPostsDir string
+	posts          map[string]*failedPost // replay state of the posts, indexed on file name
+	replayed       int64                  // posts sent out successfully
+	replayErrors   int64                  // replays failed
+	mux            sync.RWMutex           // protects posts and the counters
+	stopReplay     chan struct{}
+}
+
+// failedPost is the replay state of one file
+// the time of the first failure is the ModTime of the file, preserved on each write back
+type failedPost struct {
+	attempts   int
+	nextReplay time.Time
+}
+
+// ListenAndServe will start the replay loop
+func (fpr *FailedPostsReplayer) ListenAndServe(exitChan chan bool) error {
+	go fpr.runReplay()
+	e := <-exitChan
+	exitChan <- e // put back for the others listening for shutdown request
+	return nil
+}
+
+// Shutdown is called to shutdown the service
+func (fpr *FailedPostsReplayer) Shutdown() error {
+	utils.Logger.Info(fmt.Sprintf("<%s> shutdown initialized", utils.FailedPostsReplayer))
+	close(fpr.stopReplay)
+	utils.Logger.Info(fmt.Sprintf("<%s> shutdown complete", utils.FailedPostsReplayer))
+	return nil
+}
+
+// runReplay replays the posts on each ReplayInterval
+func (fpr *FailedPostsReplayer) runReplay() {
+	for {
+		select {
+		case <-fpr.stopReplay:
+			return
+		case <-time.After(fpr.cfg.ReplayInterval):
+		}
+		fpr.replayPosts(time.Now())
+	}
+}
+
+// replayPosts represents one pass over the failed posts directory
+func (fpr *FailedPostsReplayer) replayPosts(now time.Time) {
+	filesInDir, err := ioutil.ReadDir(fpr.failedPostsDir)
+	if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s reading directory: %s",
+				utils.FailedPostsReplayer, err.Error(), fpr.failedPostsDir))
+		return
+	}
+	inDir := make(map[string]bool)
+	for _, file := range filesInDir {
+		if file.IsDir() {
+			continue
+		}
+		fileName := file.Name()
+		inDir[fileName] = true
+		fpr.mux.Lock()
+		fp, has := fpr.posts[fileName]
+		if !has {
+			fp = new(failedPost)
+			fpr.posts[fileName] = fp
+		}
+		fpr.mux.Unlock()
+		if now.Before(fp.nextReplay) {
+			continue
+		}
+		ffn, err := utils.NewFallbackFileNameFronString(fileName)
+		if err != nil ||
+			(fpr.cfg.MaxAge > 0 && now.Sub(file.ModTime()) > fpr.cfg.MaxAge) {
+			fpr.deadLetter(fileName)
+			continue
+		}
+		if err = fpr.replayPost(fileName, ffn, file.ModTime()); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s replaying post: %s",
+					utils.FailedPostsReplayer, err.Error(), fileName))
+		}
+		fpr.mux.Lock()
+		if err == nil {
+			fpr.replayed += 1
+			delete(fpr.posts, fileName)
+		} else {
+			fpr.replayErrors += 1
+			fp.attempts += 1
+			fp.nextReplay = now.Add(fpr.backoff(fp.attempts))
+		}
+		fpr.mux.Unlock()
+	}
+	fpr.mux.Lock()
+	for fileName := range fpr.posts {
+		if !inDir[fileName] { // replayed by others, ie: ApierV1.ReplayFailedPosts
+			delete(fpr.posts, fileName)
+		}
+	}
+	fpr.mux.Unlock()
+}
+
+// replayPost sends out the post in fileName, writing it back into the directory on errors
+func (fpr *FailedPostsReplayer) replayPost(fileName string, ffn *utils.FallbackFileName,
+	firstSeen time.Time) (err error) {
+	filePath := path.Join(fpr.failedPostsDir, fileName)
+	content, err := readFailedPost(filePath)
+	if err != nil {
+		return
+	}
+	if err = ReplayFailedPost(fileName, ffn, content, fpr.failedPostsDir); err != nil {
+		if errWrite := writeBackFailedPost(filePath, content, firstSeen); errWrite != nil {
+			utils.Logger.Err(
+				fmt.Sprintf("<%s> error: %s writing back post: %s, content: %s",
+					utils.FailedPostsReplayer, errWrite.Error(), fileName, string(content)))
+		}
+	}
+	return
+}
+
+// backoff returns the delay before the next replay, doubling the ReplayInterval for each attempt
+func (fpr *FailedPostsReplayer) backoff(attempts int) (delay time.Duration) {
+	delay = fpr.cfg.ReplayInterval
+	for i := 1; i < attempts; i++ {
+		if fpr.cfg.MaxBackoff > 0 && delay >= fpr.cfg.MaxBackoff {
+			break
+		}
+		delay *= 2
+	}
+	if fpr.cfg.MaxBackoff > 0 && delay > fpr.cfg.MaxBackoff {
+		delay = fpr.cfg.MaxBackoff
+	}
+	return
+}
+
+// deadLetter moves the post into DeadLetterDir, or removes it for *none
+func (fpr *FailedPostsReplayer) deadLetter(fileName string) {
+	filePath := path.Join(fpr.failedPostsDir, fileName)
+	_, err := guardian.Guardian.Guard(func() (interface{}, error) {
+		if fpr.cfg.DeadLetterDir == utils.META_NONE {
+			return 0, os.Remove(filePath)
+		}
+		return 0, os.Rename(filePath, path.Join(fpr.cfg.DeadLetterDir, fileName))
+	}, config.CgrConfig().GeneralCfg().LockingTimeout, utils.FileLockPrefix+filePath)
+	if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<%s> error: %s moving post: %s to dead letter directory",
+				utils.FailedPostsReplayer, err.Error(), fileName))
+		return
+	}
+	fpr.mux.Lock()
+	delete(fpr.posts, fileName)
+	fpr.mux.Unlock()
+}
+
+// FailedPostsStats are the counters of the FailedPostsReplayer
+type FailedPostsStats struct {
+	Pending      int   // posts waiting to be replayed
+	Failed       int   // posts given up on, found in the dead letter directory
+	Replayed     int64 // posts sent out successfully since start
+	ReplayErrors int64 // replays failed since start
+}
+
+// V1GetStats returns the counters of the failed posts
+func (fpr *FailedPostsReplayer) V1GetStats(ign string, reply *FailedPostsStats) (err error) {
+	var stats FailedPostsStats
+	if stats.Pending, err = countFiles(fpr.failedPostsDir); err != nil {
+		return
+	}
+	if fpr.cfg.DeadLetterDir != utils.META_NONE {
+		if stats.Failed, err = countFiles(fpr.cfg.DeadLetterDir); err != nil {
+			return
+		}
+	}
+	fpr.mux.RLock()
+	stats.Replayed = fpr.replayed
+	stats.ReplayErrors = fpr.replayErrors
+	fpr.mux.RUnlock()
+	*reply = stats
+	return
+}
+
+// countFiles returns the number of files in dirPath, ignoring the subdirectories
+func countFiles(dirPath string) (cnt int, err error) {
+	filesInDir, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return
+	}
+	for _, file := range filesInDir {
+		if !file.IsDir() {
+			cnt++
+		}
+	}
+	return
+}
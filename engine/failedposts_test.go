@@ -0,0 +1,127 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestFailedPostsBackoff(t *testing.T) {
+	fpr := NewFailedPostsReplayer(&config.FailedPostsCfg{
+		ReplayInterval: time.Minute, MaxBackoff: 5 * time.Minute}, "")
+	for attempts, eDelay := range map[int]time.Duration{
+		1:  time.Minute,
+		2:  2 * time.Minute,
+		3:  4 * time.Minute,
+		4:  5 * time.Minute,
+		10: 5 * time.Minute,
+	} {
+		if delay := fpr.backoff(attempts); delay != eDelay {
+			t.Errorf("attempts: %d, expecting: %v, received: %v", attempts, eDelay, delay)
+		}
+	}
+}
+
+func TestFailedPostsReplayPosts(t *testing.T) {
+	failedDir, err := ioutil.TempDir("", "failed_posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(failedDir)
+	deadDir, err := ioutil.TempDir("", "failed_posts_dead")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(deadDir)
+	now := time.Now()
+	pendingFile := (&utils.FallbackFileName{Module: utils.CDRPoster,
+		Transport: utils.MetaHTTPjsonCDR, Address: "http://127.0.0.1:12080/cdrs",
+		RequestID: "pending"}).AsString()
+	expiredFile := (&utils.FallbackFileName{Module: utils.CDRPoster,
+		Transport: utils.MetaHTTPjsonCDR, Address: "http://127.0.0.1:12080/cdrs",
+		RequestID: "expired"}).AsString()
+	for _, fileName := range []string{pendingFile, expiredFile, "unsupported.txt"} {
+		if err := ioutil.WriteFile(path.Join(failedDir, fileName),
+			[]byte(`{"CGRID":"1"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chtimes(path.Join(failedDir, expiredFile),
+		now.Add(-2*time.Hour), now.Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	fpr := NewFailedPostsReplayer(&config.FailedPostsCfg{
+		ReplayInterval: time.Minute, MaxBackoff: time.Hour,
+		MaxAge: time.Hour, DeadLetterDir: deadDir}, failedDir)
+	fpr.posts[pendingFile] = &failedPost{attempts: 1,
+		nextReplay: now.Add(time.Minute)} // not due for replay
+	fpr.posts["replayed_by_others.json"] = new(failedPost)
+	fpr.replayPosts(now)
+	if len(fpr.posts) != 1 || fpr.posts[pendingFile] == nil {
+		t.Errorf("unexpected posts: %+v", fpr.posts)
+	}
+	for _, fileName := range []string{expiredFile, "unsupported.txt"} {
+		if _, err := os.Stat(path.Join(deadDir, fileName)); err != nil {
+			t.Errorf("file: %s not moved to dead letter directory, error: %v", fileName, err)
+		}
+	}
+	var stats FailedPostsStats
+	if err := fpr.V1GetStats("", &stats); err != nil {
+		t.Error(err)
+	} else if eStats := (FailedPostsStats{Pending: 1, Failed: 2}); stats != eStats {
+		t.Errorf("expecting: %+v, received: %+v", eStats, stats)
+	}
+}
+
+func TestFailedPostsWriteBackKeepsFirstSeen(t *testing.T) {
+	failedDir, err := ioutil.TempDir("", "failed_posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(failedDir)
+	filePath := path.Join(failedDir, "post.json")
+	firstSeen := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	if err := writeBackFailedPost(filePath, []byte(`{"CGRID":"1"}`), firstSeen); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := readFailedPost(filePath); err != nil {
+		t.Error(err)
+	} else if string(content) != `{"CGRID":"1"}` {
+		t.Errorf("unexpected content: %s", content)
+	}
+	// written back by the posters before us
+	if err := ioutil.WriteFile(filePath, []byte(`{"CGRID":"2"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeBackFailedPost(filePath, []byte(`{"CGRID":"1"}`), firstSeen); err != nil {
+		t.Fatal(err)
+	}
+	if fInfo, err := os.Stat(filePath); err != nil {
+		t.Error(err)
+	} else if !fInfo.ModTime().Equal(firstSeen) {
+		t.Errorf("expecting: %v, received: %v", firstSeen, fInfo.ModTime())
+	}
+}
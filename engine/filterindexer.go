@@ -97,6 +97,9 @@ func (rfi *FilterIndexer) cacheRemItemType() { // ToDo: tune here by removing pe
 
 	case utils.ChargerProfilePrefix:
 		Cache.Clear([]string{utils.CacheChargerFilterIndexes})
+
+	case utils.DispatcherProfilePrefix:
+		Cache.Clear([]string{utils.CacheDispatcherFilterIndexes})
 	}
 }
 
@@ -201,6 +204,17 @@ func (rfi *FilterIndexer) RemoveItemFromIndex(tenant, itemID string, oldFilters
 				filterIDs[i] = fltrID
 			}
 		}
+	case utils.DispatcherProfilePrefix:
+		dpp, err := rfi.dm.GetDispatcherProfile(tenant, itemID, true, false, utils.NonTransactional)
+		if err != nil && err != utils.ErrNotFound {
+			return err
+		}
+		if dpp != nil {
+			filterIDs = make([]string, len(dpp.FilterIDs))
+			for i, fltrID := range dpp.FilterIDs {
+				filterIDs[i] = fltrID
+			}
+		}
 	default:
 	}
 	if len(filterIDs) == 0 {
@@ -209,6 +223,11 @@ func (rfi *FilterIndexer) RemoveItemFromIndex(tenant, itemID string, oldFilters
 	for _, oldFltr := range oldFilters {
 		filterIDs = append(filterIDs, oldFltr)
 	}
+	// item can be indexed on *none if none of its filters is indexable
+	if err = rfi.loadFldNameFldValIndex(utils.META_NONE,
+		utils.META_ANY, utils.META_ANY); err != nil && err != utils.ErrNotFound {
+		return err
+	}
 	for _, fltrID := range filterIDs {
 		var fltr *Filter
 		if fltrID == utils.META_NONE {
@@ -270,6 +289,7 @@ func createAndIndex(itemPrefix, tenant, context, itemID string, filterIDs []stri
 	if len(fltrIDs) == 0 {
 		fltrIDs = []string{utils.META_NONE}
 	}
+	var indexed bool // at least one rule was indexed
 	for _, fltrID := range fltrIDs {
 		var fltr *Filter
 		if fltrID == utils.META_NONE {
@@ -298,6 +318,7 @@ func createAndIndex(itemPrefix, tenant, context, itemID string, filterIDs []stri
 			if utils.IsSliceMember([]string{MetaString, MetaPrefix, utils.META_NONE}, flt.Type) {
 				fldType, fldName = flt.Type, flt.FieldName
 				fldVals = flt.Values
+				indexed = true
 			}
 			for _, fldVal := range fldVals {
 				if err = indexer.loadFldNameFldValIndex(fldType,
@@ -308,5 +329,22 @@ func createAndIndex(itemPrefix, tenant, context, itemID string, filterIDs []stri
 		}
 		indexer.IndexTPFilter(FilterToTPFilter(fltr), itemID)
 	}
+	if !indexed { // ie: only negated or *exists rules, index on *none so FilterS can check it
+		if err = indexer.loadFldNameFldValIndex(utils.META_NONE,
+			utils.META_ANY, utils.META_ANY); err != nil && err != utils.ErrNotFound {
+			return err
+		}
+		indexer.IndexTPFilter(&utils.TPFilterProfile{
+			Tenant: tenant,
+			ID:     itemID,
+			Filters: []*utils.TPFilter{
+				{
+					Type:      utils.META_NONE,
+					FieldName: utils.META_ANY,
+					Values:    []string{utils.META_ANY},
+				},
+			},
+		}, itemID)
+	}
 	return indexer.StoreIndexes(true, utils.NonTransactional)
 }
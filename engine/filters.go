@@ -42,8 +42,36 @@ const (
 	MetaLessOrEqual    = "*lte"
 	MetaGreaterThan    = "*gt"
 	MetaGreaterOrEqual = "*gte"
+	MetaSuffix         = "*suffix"
+	MetaExists         = "*exists"
+	MetaEmpty          = "*empty"
+	MetaNot            = "*not" // prefix negating any of the filter types, ie: *notstring
 )
 
+var (
+	// filterTypes are the supported types, each one can be negated with MetaNot prefix
+	filterTypes = []string{MetaString, MetaPrefix, MetaSuffix,
+		MetaTimings, MetaRSR, MetaStatS, MetaDestinations, MetaExists, MetaEmpty,
+		MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual}
+	// needsFieldName are the types requiring FieldName
+	needsFieldName = []string{MetaString, MetaPrefix, MetaSuffix,
+		MetaTimings, MetaDestinations, MetaExists, MetaEmpty,
+		MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual}
+	// needsValues are the types requiring Values
+	needsValues = []string{MetaString, MetaPrefix, MetaSuffix,
+		MetaTimings, MetaRSR, MetaDestinations, MetaLessThan,
+		MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual}
+)
+
+// baseFilterType returns the filter type without the MetaNot prefix
+// and if the type was negated
+func baseFilterType(fltrType string) (baseType string, negative bool) {
+	if !strings.HasPrefix(fltrType, MetaNot) {
+		return fltrType, false
+	}
+	return utils.Meta + fltrType[len(MetaNot):], true
+}
+
 func NewFilterS(cfg *config.CGRConfig,
 	statSChan chan rpcclient.RpcClientConnection, dm *DataManager) *FilterS {
 	return &FilterS{statSChan: statSChan, dm: dm, cfg: cfg}
@@ -104,9 +132,18 @@ func (fS *FilterS) Pass(tenant string, filterIDs []string,
 }
 
 // NewFilterFromInline parses an inline rule into a compiled Filter
+// the values can be missing for the types not using them, ie: *exists:Account
 func NewFilterFromInline(tenant, inlnRule string) (f *Filter, err error) {
 	ruleSplt := strings.Split(inlnRule, utils.InInFieldSep)
-	if len(ruleSplt) != 3 {
+	var vals []string
+	switch len(ruleSplt) {
+	case 3:
+		vals = strings.Split(ruleSplt[2], utils.INFIELD_SEP)
+	case 2:
+		if baseType, _ := baseFilterType(ruleSplt[0]); utils.IsSliceMember(needsValues, baseType) {
+			return nil, fmt.Errorf("inline parse error for string: <%s>", inlnRule)
+		}
+	default:
 		return nil, fmt.Errorf("inline parse error for string: <%s>", inlnRule)
 	}
 	f = &Filter{
@@ -116,7 +153,7 @@ func NewFilterFromInline(tenant, inlnRule string) (f *Filter, err error) {
 			{
 				Type:      ruleSplt[0],
 				FieldName: ruleSplt[1],
-				Values:    strings.Split(ruleSplt[2], utils.INFIELD_SEP)}},
+				Values:    vals}},
 	}
 	if err = f.Compile(); err != nil {
 		return nil, err
@@ -146,19 +183,14 @@ func (f *Filter) Compile() (err error) {
 }
 
 func NewFilterRule(rfType, fieldName string, vals []string) (*FilterRule, error) {
-	if !utils.IsSliceMember([]string{MetaString, MetaPrefix,
-		MetaTimings, MetaRSR, MetaStatS, MetaDestinations,
-		MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual}, rfType) {
+	baseType, _ := baseFilterType(rfType)
+	if !utils.IsSliceMember(filterTypes, baseType) {
 		return nil, fmt.Errorf("Unsupported filter Type: %s", rfType)
 	}
-	if fieldName == "" && utils.IsSliceMember([]string{MetaString,
-		MetaPrefix, MetaTimings, MetaDestinations, MetaLessThan,
-		MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual}, rfType) {
+	if fieldName == "" && utils.IsSliceMember(needsFieldName, baseType) {
 		return nil, fmt.Errorf("FieldName is mandatory for Type: %s", rfType)
 	}
-	if len(vals) == 0 && utils.IsSliceMember([]string{MetaString, MetaPrefix,
-		MetaTimings, MetaRSR, MetaDestinations, MetaDestinations, MetaLessThan,
-		MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual}, rfType) {
+	if len(vals) == 0 && utils.IsSliceMember(needsValues, baseType) {
 		return nil, fmt.Errorf("Values is mandatory for Type: %s", rfType)
 	}
 	rf := &FilterRule{Type: rfType, FieldName: fieldName, Values: vals}
@@ -177,7 +209,7 @@ type RFStatSThreshold struct {
 // FilterRule filters requests coming into various places
 // Pass rule: default negative, one mathing rule should pass the filter
 type FilterRule struct {
-	Type            string              // Filter type (*string, *timing, *rsr_filters, *stats, *lt, *lte, *gt, *gte)
+	Type            string              // Filter type (*string, *suffix, *exists, *empty, *timing, *rsr_filters, *stats, *lt, *lte, *gt, *gte), negated with *not prefix
 	FieldName       string              // Name of the field providing us the Values to check (used in case of some )
 	Values          []string            // Filter definition
 	rsrFields       config.RSRParsers   // Cache here the RSRFilter Values
@@ -186,11 +218,12 @@ type FilterRule struct {
 
 // Separate method to compile RSR fields
 func (rf *FilterRule) CompileValues() (err error) {
-	if rf.Type == MetaRSR {
+	baseType, _ := baseFilterType(rf.Type)
+	if baseType == MetaRSR {
 		if rf.rsrFields, err = config.NewRSRParsersFromSlice(rf.Values, true); err != nil {
 			return
 		}
-	} else if rf.Type == MetaStatS {
+	} else if baseType == MetaStatS {
 		rf.statSThresholds = make([]*RFStatSThreshold, len(rf.Values))
 		for i, val := range rf.Values {
 			valSplt := strings.Split(val, utils.InInFieldSep)
@@ -216,25 +249,36 @@ func (rf *FilterRule) CompileValues() (err error) {
 }
 
 // Pass is the method which should be used from outside.
-func (fltr *FilterRule) Pass(dP config.DataProvider, rpcClnt rpcclient.RpcClientConnection) (bool, error) {
-	switch fltr.Type {
+func (fltr *FilterRule) Pass(dP config.DataProvider, rpcClnt rpcclient.RpcClientConnection) (result bool, err error) {
+	baseType, negative := baseFilterType(fltr.Type)
+	switch baseType {
 	case MetaString:
-		return fltr.passString(dP)
+		result, err = fltr.passString(dP)
 	case MetaPrefix:
-		return fltr.passStringPrefix(dP)
+		result, err = fltr.passStringPrefix(dP)
+	case MetaSuffix:
+		result, err = fltr.passStringSuffix(dP)
 	case MetaTimings:
-		return fltr.passTimings(dP)
+		result, err = fltr.passTimings(dP)
 	case MetaDestinations:
-		return fltr.passDestinations(dP)
+		result, err = fltr.passDestinations(dP)
 	case MetaRSR:
-		return fltr.passRSR(dP)
+		result, err = fltr.passRSR(dP)
 	case MetaStatS:
-		return fltr.passStatS(dP, rpcClnt)
+		result, err = fltr.passStatS(dP, rpcClnt)
 	case MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual:
-		return fltr.passGreaterThan(dP)
+		result, err = fltr.passGreaterThan(dP)
+	case MetaExists:
+		result, err = fltr.passExists(dP)
+	case MetaEmpty:
+		result, err = fltr.passEmpty(dP)
 	default:
-		return false, utils.ErrNotImplemented
+		err = utils.ErrNotImplemented
+	}
+	if err != nil {
+		return false, err
 	}
+	return result != negative, nil
 }
 
 func (fltr *FilterRule) passString(dP config.DataProvider) (bool, error) {
@@ -269,9 +313,103 @@ func (fltr *FilterRule) passStringPrefix(dP config.DataProvider) (bool, error) {
 	return false, nil
 }
 
-// ToDo when Timings will be available in DataDb
+func (fltr *FilterRule) passStringSuffix(dP config.DataProvider) (bool, error) {
+	strVal, err := dP.FieldAsString(strings.Split(fltr.FieldName, utils.NestingSep))
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, sfx := range fltr.Values {
+		if strings.HasSuffix(strVal, sfx) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (fltr *FilterRule) passExists(dP config.DataProvider) (bool, error) {
+	if _, err := dP.FieldAsInterface(strings.Split(fltr.FieldName, utils.NestingSep)); err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// passEmpty checks if the field is present with an empty value (nil, "", empty slice or map)
+func (fltr *FilterRule) passEmpty(dP config.DataProvider) (bool, error) {
+	val, err := dP.FieldAsInterface(strings.Split(fltr.FieldName, utils.NestingSep))
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	if val == nil {
+		return true, nil
+	}
+	rVal := reflect.ValueOf(val)
+	if rVal.Kind() == reflect.Ptr {
+		if rVal.IsNil() {
+			return true, nil
+		}
+		rVal = rVal.Elem()
+	}
+	switch rVal.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return rVal.Len() == 0, nil
+	default:
+		return false, nil
+	}
+}
+
+// passTimings checks the time in FieldName against the timings stored in DataDB, with IDs as Values
 func (fltr *FilterRule) passTimings(dP config.DataProvider) (bool, error) {
-	return false, utils.ErrNotImplemented
+	tmIf, err := dP.FieldAsInterface(strings.Split(fltr.FieldName, utils.NestingSep))
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	tm, err := utils.IfaceAsTime(tmIf, config.CgrConfig().GeneralCfg().DefaultTimezone)
+	if err != nil {
+		return false, err
+	}
+	for _, tmgID := range fltr.Values {
+		tpTmg, err := dm.GetTiming(tmgID, false, utils.NonTransactional)
+		if err != nil {
+			if err == utils.ErrNotFound {
+				continue
+			}
+			return false, err
+		}
+		if riTimingFromTPTiming(tpTmg).IsActiveAt(tm) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// riTimingFromTPTiming converts the stored timing so it can be checked against a time
+func riTimingFromTPTiming(tpTmg *utils.TPTiming) (rit *RITiming) {
+	rit = &RITiming{
+		Years:     tpTmg.Years,
+		Months:    tpTmg.Months,
+		MonthDays: tpTmg.MonthDays,
+		WeekDays:  tpTmg.WeekDays,
+	}
+	// *any or missing times are covering the full day
+	if len(strings.Split(tpTmg.StartTime, utils.InInFieldSep)) == 3 {
+		rit.StartTime = tpTmg.StartTime
+	}
+	if len(strings.Split(tpTmg.EndTime, utils.InInFieldSep)) == 3 {
+		rit.EndTime = tpTmg.EndTime
+	}
+	return
 }
 
 func (fltr *FilterRule) passDestinations(dP config.DataProvider) (bool, error) {
@@ -333,6 +471,7 @@ func (fltr *FilterRule) passStatS(dP config.DataProvider,
 }
 
 func (fltr *FilterRule) passGreaterThan(dP config.DataProvider) (bool, error) {
+	fltrType, _ := baseFilterType(fltr.Type)
 	fldIf, err := dP.FieldAsInterface(strings.Split(fltr.FieldName, utils.NestingSep))
 	if err != nil {
 		if err == utils.ErrNotFound {
@@ -345,15 +484,15 @@ func (fltr *FilterRule) passGreaterThan(dP config.DataProvider) (bool, error) {
 	}
 	for _, val := range fltr.Values {
 		orEqual := false
-		if fltr.Type == MetaGreaterOrEqual ||
-			fltr.Type == MetaLessThan {
+		if fltrType == MetaGreaterOrEqual ||
+			fltrType == MetaLessThan {
 			orEqual = true
 		}
 		if gte, err := utils.GreaterThan(fldIf, utils.StringToInterface(val), orEqual); err != nil {
 			return false, err
-		} else if utils.IsSliceMember([]string{MetaGreaterThan, MetaGreaterOrEqual}, fltr.Type) && gte {
+		} else if utils.IsSliceMember([]string{MetaGreaterThan, MetaGreaterOrEqual}, fltrType) && gte {
 			return true, nil
-		} else if !gte && utils.IsSliceMember([]string{MetaLessThan, MetaLessOrEqual}, fltr.Type) && !gte {
+		} else if !gte && utils.IsSliceMember([]string{MetaLessThan, MetaLessOrEqual}, fltrType) && !gte {
 			return true, nil
 		}
 	}
@@ -467,3 +467,119 @@ func TestPassFiltersForEventWithEmptyFilter(t *testing.T) {
 		t.Errorf("Expecting: %+v, received: %+v", true, pass)
 	}
 }
+
+func TestFilterPassSuffixExistsEmpty(t *testing.T) {
+	ev := config.NewNavigableMap(nil)
+	ev.Set([]string{utils.Account}, "1007@cgrates.org", true)
+	ev.Set([]string{utils.Subject}, "", true)
+	ev.Set([]string{"Attrs"}, []string{}, true)
+	ev.Set([]string{utils.Weight}, 20, true)
+	for _, tc := range []struct {
+		fltrType  string
+		fieldName string
+		vals      []string
+		ePass     bool
+	}{
+		{MetaSuffix, utils.Account, []string{"@itsyscom.com", "@cgrates.org"}, true},
+		{MetaSuffix, utils.Account, []string{"@itsyscom.com"}, false},
+		{MetaSuffix, utils.Destination, []string{"@cgrates.org"}, false},
+		{MetaExists, utils.Account, nil, true},
+		{MetaExists, utils.Destination, nil, false},
+		{MetaEmpty, utils.Subject, nil, true},
+		{MetaEmpty, "Attrs", nil, true},
+		{MetaEmpty, utils.Account, nil, false},
+		{MetaEmpty, utils.Destination, nil, false},
+		{"*notsuffix", utils.Account, []string{"@itsyscom.com"}, true},
+		{"*notstring", utils.Account, []string{"1007@cgrates.org"}, false},
+		{"*notprefix", utils.Destination, []string{"+49"}, true},
+		{"*notexists", utils.Destination, nil, true},
+		{"*notexists", utils.Account, nil, false},
+		{"*notempty", utils.Account, nil, true},
+		{"*notgte", utils.Weight, []string{"30"}, true},
+	} {
+		rf, err := NewFilterRule(tc.fltrType, tc.fieldName, tc.vals)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pass, err := rf.Pass(ev, nil); err != nil {
+			t.Error(err)
+		} else if pass != tc.ePass {
+			t.Errorf("%s:%s:%v expecting: %v, received: %v",
+				tc.fltrType, tc.fieldName, tc.vals, tc.ePass, pass)
+		}
+	}
+}
+
+func TestFilterNewFilterRuleNegated(t *testing.T) {
+	if _, err := NewFilterRule("*notunsupported", utils.Account, []string{"1001"}); err == nil {
+		t.Error("expecting error for unsupported type")
+	}
+	if _, err := NewFilterRule("*notstring", utils.Account, nil); err == nil {
+		t.Error("expecting error for missing values")
+	}
+	if _, err := NewFilterRule("*notexists", "", nil); err == nil {
+		t.Error("expecting error for missing field name")
+	}
+	if _, err := NewFilterFromInline("cgrates.org", "*string:Account"); err == nil {
+		t.Error("expecting error for missing values")
+	}
+	if f, err := NewFilterFromInline("cgrates.org", "*notexists:Account"); err != nil {
+		t.Error(err)
+	} else if f.Rules[0].Type != "*notexists" || f.Rules[0].FieldName != utils.Account {
+		t.Errorf("unexpected rule: %+v", f.Rules[0])
+	}
+}
+
+func TestFilterPassTimings(t *testing.T) {
+	Cache.Set(utils.CacheTimings, "WEEKEND", &utils.TPTiming{
+		ID:        "WEEKEND",
+		WeekDays:  utils.WeekDays{time.Saturday, time.Sunday},
+		StartTime: utils.ANY,
+	}, nil, true, "")
+	Cache.Set(utils.CacheTimings, "BUSINESS_HOURS", &utils.TPTiming{
+		ID:        "BUSINESS_HOURS",
+		WeekDays:  utils.WeekDays{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		StartTime: "08:00:00",
+		EndTime:   "18:00:00",
+	}, nil, true, "")
+	rf, err := NewFilterRule(MetaTimings, utils.AnswerTime, []string{"WEEKEND"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := config.NewNavigableMap(nil)
+	ev.Set([]string{utils.AnswerTime}, time.Date(2018, time.October, 6, 14, 0, 0, 0, time.UTC), true) // Saturday
+	if passes, err := rf.passTimings(ev); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("not passing")
+	}
+	ev.Set([]string{utils.AnswerTime}, "2018-10-08T14:00:00Z", true) // Monday, as string
+	if passes, err := rf.passTimings(ev); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("should not pass")
+	}
+	if rf, err = NewFilterRule(MetaTimings, utils.AnswerTime,
+		[]string{"NOT_STORED", "BUSINESS_HOURS"}); err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev, nil); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("not passing")
+	}
+	ev.Set([]string{utils.AnswerTime}, "2018-10-08T19:00:00Z", true)
+	if passes, err := rf.Pass(ev, nil); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("should not pass outside business hours")
+	}
+	if rf, err = NewFilterRule("*nottimings", utils.AnswerTime, []string{"BUSINESS_HOURS"}); err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev, nil); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("not passing")
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"sort"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// DispatcherHost is one of the engine pools a DispatcherProfile routes to
+type DispatcherHost struct {
+	ID        string // matches one of the hosts defined in dispatchers config
+	FilterIDs []string
+	Weight    float64                // used for sorting in strategies
+	Params    map[string]interface{} // additional parameters stored for a host
+	Blocker   bool                   // no connection after this one
+}
+
+// DispatcherHosts is a sortable list of DispatcherHost
+type DispatcherHosts []*DispatcherHost
+
+// Sort is part of sort interface, sort based on Weight
+func (dHs DispatcherHosts) Sort() {
+	sort.Slice(dHs, func(i, j int) bool { return dHs[i].Weight > dHs[j].Weight })
+}
+
+// HostIDs returns the IDs of the hosts, in their current order
+func (dHs DispatcherHosts) HostIDs() (hostIDs []string) {
+	hostIDs = make([]string, len(dHs))
+	for i, dH := range dHs {
+		hostIDs[i] = dH.ID
+	}
+	return
+}
+
+// DispatcherProfile is the config for one Dispatcher
+type DispatcherProfile struct {
+	Tenant             string
+	ID                 string
+	Subsystems         []string // subsystems the profile applies to, *any for all
+	FilterIDs          []string
+	ActivationInterval *utils.ActivationInterval // activation interval
+	Strategy           string                    // one of *first/*random/*next/*broadcast
+	StrategyParams     map[string]interface{}    // ie for distribution, set here the pool weights
+	Weight             float64                   // used for profile sorting on match
+	Hosts              DispatcherHosts           // dispatch to these connections
+}
+
+func (dP *DispatcherProfile) TenantID() string {
+	return utils.ConcatenatedKey(dP.Tenant, dP.ID)
+}
+
+// DispatcherProfiles is a sortable list of Dispatcher profiles
+type DispatcherProfiles []*DispatcherProfile
+
+// Sort is part of sort interface, sort based on Weight
+func (dps DispatcherProfiles) Sort() {
+	sort.Slice(dps, func(i, j int) bool { return dps[i].Weight > dps[j].Weight })
+}
@@ -45,7 +45,7 @@ func NewRPCPool(dispatchStrategy string, key_path, cert_path, ca_path string, co
 				return nil, errors.New("TTL triggered")
 			}
 			rpcClient, err = rpcclient.NewRpcClient("", "", rpcConnCfg.Tls, key_path, cert_path, ca_path, connAttempts,
-				reconnects, connectTimeout, replyTimeout, rpcclient.INTERNAL_RPC, internalConn, false)
+				reconnects, connectTimeout, replyTimeout, rpcclient.INTERNAL_RPC, utils.NewInternalConn(internalConn), false)
 		} else if utils.IsSliceMember([]string{utils.MetaJSONrpc, utils.MetaGOBrpc, ""}, rpcConnCfg.Transport) {
 			codec := utils.GOB
 			if rpcConnCfg.Transport != "" {
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"github.com/cgrates/cgrates/utils"
 	"sort"
+	"strconv"
 	"time"
 )
 
@@ -39,12 +40,32 @@ type StatQueueProfile struct {
 	Stored             bool
 	Weight             float64
 	MinItems           int
+	BucketInterval     time.Duration // aggregate events into time buckets over the TTL window, 0 to track them individually
+	SnapshotInterval   time.Duration // store the metric values into StorDB at this interval, 0 to disable
 }
 
 func (sqp *StatQueueProfile) TenantID() string {
 	return utils.ConcatenatedKey(sqp.Tenant, sqp.ID)
 }
 
+// CheckBucketMetrics makes sure all the metrics can be aggregated into time buckets
+// when the profile is configured with BucketInterval
+func (sqp *StatQueueProfile) CheckBucketMetrics() (err error) {
+	if sqp.BucketInterval <= 0 {
+		return
+	}
+	for _, mtrc := range sqp.Metrics {
+		var metric StatMetric
+		if metric, err = NewStatMetric(mtrc.MetricID, sqp.MinItems, mtrc.Parameters); err != nil {
+			return
+		}
+		if _, canBucket := metric.(StatBucketMetric); !canBucket {
+			return fmt.Errorf("metric: <%s> cannot be aggregated into time buckets", mtrc.MetricID)
+		}
+	}
+	return
+}
+
 // NewStoredStatQueue initiates a StoredStatQueue out of StatQueue
 func NewStoredStatQueue(sq *StatQueue, ms Marshaler) (sSQ *StoredStatQueue, err error) {
 	sSQ = &StoredStatQueue{
@@ -127,6 +148,7 @@ type StatQueue struct {
 	sqPrfl    *StatQueueProfile
 	dirty     *bool          // needs save
 	ttl       *time.Duration // timeToLeave, picked on each init
+	lastSnap  time.Time      // time of the last metrics snapshot
 }
 
 // SqID will compose the unique identifier for the StatQueue out of Tenant and ID
@@ -145,7 +167,8 @@ func (sq *StatQueue) ProcessEvent(ev *utils.CGREvent) (err error) {
 // remStatEvent removes an event from metrics
 func (sq *StatQueue) remEventWithID(evID string) {
 	for metricID, metric := range sq.SQMetrics {
-		if err := metric.RemEvent(evID); err != nil {
+		if err := metric.RemEvent(evID); err != nil &&
+			err != utils.ErrNotFound { // events without the metric field are never counted
 			utils.Logger.Warning(fmt.Sprintf("<StatQueue> metricID: %s, remove eventID: %s, error: %s", metricID, evID, err.Error()))
 		}
 	}
@@ -171,11 +194,17 @@ func (sq *StatQueue) remExpired() {
 }
 
 // remOnQueueLength removes elements based on QueueLength setting
+// for bucketed queues the QueueLength limits the number of buckets
 func (sq *StatQueue) remOnQueueLength() {
 	if sq.sqPrfl.QueueLength <= 0 { // infinite length
 		return
 	}
 	if len(sq.SQItems) == sq.sqPrfl.QueueLength { // reached limit, rem first element
+		if sq.sqPrfl.BucketInterval > 0 {
+			if bktID, _ := sq.bucketID(time.Now()); sq.SQItems[len(sq.SQItems)-1].EventID == bktID {
+				return // event goes into the last bucket
+			}
+		}
 		itm := sq.SQItems[0]
 		sq.remEventWithID(itm.EventID)
 		sq.SQItems = sq.SQItems[1:]
@@ -184,6 +213,10 @@ func (sq *StatQueue) remOnQueueLength() {
 
 // addStatEvent computes metrics for an event
 func (sq *StatQueue) addStatEvent(ev *utils.CGREvent) {
+	if sq.sqPrfl != nil && sq.sqPrfl.BucketInterval > 0 {
+		sq.addStatEventToBucket(ev)
+		return
+	}
 	var expTime *time.Time
 	if sq.ttl != nil {
 		expTime = utils.TimePointer(time.Now().Add(*sq.ttl))
@@ -202,6 +235,77 @@ func (sq *StatQueue) addStatEvent(ev *utils.CGREvent) {
 	}
 }
 
+// bucketID returns the ID and the start time of the bucket containing t
+func (sq *StatQueue) bucketID(t time.Time) (bktID string, bktStart time.Time) {
+	bktStart = t.Truncate(sq.sqPrfl.BucketInterval)
+	return utils.ConcatenatedKey(utils.MetaBucket,
+		strconv.FormatInt(bktStart.UnixNano(), 10)), bktStart
+}
+
+// addStatEventToBucket aggregates the event into the current time bucket
+// the buckets are tracked in SQItems so they expire as the events would
+func (sq *StatQueue) addStatEventToBucket(ev *utils.CGREvent) {
+	bktID, bktStart := sq.bucketID(time.Now())
+	if len(sq.SQItems) == 0 || sq.SQItems[len(sq.SQItems)-1].EventID != bktID {
+		var expTime *time.Time
+		if sq.ttl != nil {
+			expTime = utils.TimePointer(bktStart.Add(sq.sqPrfl.BucketInterval + *sq.ttl))
+		}
+		sq.SQItems = append(sq.SQItems,
+			struct {
+				EventID    string
+				ExpiryTime *time.Time
+			}{bktID, expTime})
+	}
+	for metricID, metric := range sq.SQMetrics {
+		bktMetric, canBucket := metric.(StatBucketMetric)
+		if !canBucket {
+			utils.Logger.Warning(fmt.Sprintf("<StatQueue> metricID: %s, cannot aggregate eventID: %s into buckets",
+				metricID, ev.ID))
+			continue
+		}
+		if err := bktMetric.AddEventToBucket(bktID, ev); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<StatQueue> metricID: %s, add eventID: %s to bucket: %s, error: %s",
+				metricID, ev.ID, bktID, err.Error()))
+		}
+	}
+}
+
+// newSnapshot returns the snapshot of the metrics if SnapshotInterval passed since the last one
+func (sq *StatQueue) newSnapshot(t time.Time) (sqSnap *StatQueueSnapshot) {
+	if sq.sqPrfl == nil || sq.sqPrfl.SnapshotInterval <= 0 ||
+		(!sq.lastSnap.IsZero() && t.Sub(sq.lastSnap) < sq.sqPrfl.SnapshotInterval) {
+		return
+	}
+	sq.lastSnap = t
+	sqSnap = &StatQueueSnapshot{
+		Tenant:  sq.Tenant,
+		ID:      sq.ID,
+		Time:    t,
+		Metrics: make(map[string]float64, len(sq.SQMetrics)),
+	}
+	for metricID, metric := range sq.SQMetrics {
+		sqSnap.Metrics[metricID] = metric.GetFloat64Value()
+	}
+	return
+}
+
+// StatQueueSnapshot holds the values of the StatQueue metrics at one point in time
+type StatQueueSnapshot struct {
+	Tenant  string
+	ID      string // QueueID
+	Time    time.Time
+	Metrics map[string]float64
+}
+
+// ArgsGetQueueHistory filters the snapshots returned by StatSv1.GetQueueHistory
+type ArgsGetQueueHistory struct {
+	Tenant string
+	ID     string     // QueueID
+	From   *time.Time // inclusive
+	Until  *time.Time // exclusive
+}
+
 // StatQueues is a sortable list of StatQueue
 type StatQueues []*StatQueue
 
@@ -205,3 +205,129 @@ func TestStatAddStatEvent(t *testing.T) {
 		t.Errorf("ASR: %v", asrMetric)
 	}
 }
+
+func TestStatBucketedQueue(t *testing.T) {
+	sq = &StatQueue{
+		Tenant: "cgrates.org",
+		ID:     "BUCKETED",
+		sqPrfl: &StatQueueProfile{
+			BucketInterval: time.Hour,
+			QueueLength:    2,
+		},
+		ttl: utils.DurationPointer(time.Hour),
+		SQMetrics: map[string]StatMetric{
+			utils.MetaASR: &StatASR{Events: make(map[string]bool)},
+			utils.MetaACD: &StatACD{Events: make(map[string]time.Duration)},
+		},
+	}
+	for evID, ev := range map[string]map[string]interface{}{
+		"TestStatBucketedQueue_1": {utils.AnswerTime: time.Now(), utils.Usage: time.Duration(time.Minute)},
+		"TestStatBucketedQueue_2": {utils.AnswerTime: time.Now(), utils.Usage: time.Duration(2 * time.Minute)},
+		"TestStatBucketedQueue_3": {utils.AnswerTime: time.Time{}},
+	} {
+		if err := sq.ProcessEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: evID, Event: ev}); err != nil {
+			t.Error(err)
+		}
+	}
+	if len(sq.SQItems) != 1 {
+		t.Fatalf("expecting one bucket, received: %+v", sq.SQItems)
+	}
+	bktID, bktStart := sq.bucketID(time.Now())
+	if sq.SQItems[0].EventID != bktID {
+		t.Errorf("expecting bucket: %s, received: %s", bktID, sq.SQItems[0].EventID)
+	} else if eExp := bktStart.Add(2 * time.Hour); !sq.SQItems[0].ExpiryTime.Equal(eExp) {
+		t.Errorf("expecting expiry: %v, received: %v", eExp, sq.SQItems[0].ExpiryTime)
+	}
+	asrMetric := sq.SQMetrics[utils.MetaASR].(*StatASR)
+	acdMetric := sq.SQMetrics[utils.MetaACD].(*StatACD)
+	if len(asrMetric.Events) != 0 || len(acdMetric.Events) != 0 {
+		t.Errorf("events tracked individually: %+v, %+v", asrMetric.Events, acdMetric.Events)
+	}
+	if asr := asrMetric.GetFloat64Value(); asr != 66.66667 {
+		t.Errorf("received ASR: %v", asr)
+	}
+	if acd := acdMetric.GetFloat64Value(); acd != 60 {
+		t.Errorf("received ACD: %v", acd)
+	}
+	// older bucket in front, queue full but the event goes into the last bucket
+	sq.SQItems = append([]struct {
+		EventID    string
+		ExpiryTime *time.Time
+	}{{"*bucket:0", utils.TimePointer(time.Now().Add(time.Minute))}}, sq.SQItems...)
+	sq.remOnQueueLength()
+	if len(sq.SQItems) != 2 {
+		t.Fatalf("wrong items: %+v", sq.SQItems)
+	}
+	sq.SQItems = sq.SQItems[1:]
+	sq.SQItems[0].ExpiryTime = utils.TimePointer(time.Now())
+	sq.remExpired()
+	if len(sq.SQItems) != 0 {
+		t.Errorf("wrong items: %+v", sq.SQItems)
+	}
+	if asr := asrMetric.GetFloat64Value(); asr != STATS_NA {
+		t.Errorf("received ASR: %v", asr)
+	} else if len(asrMetric.Buckets) != 0 || asrMetric.Count != 0 {
+		t.Errorf("unexpected ASR: %+v", asrMetric)
+	}
+}
+
+func TestStatQueueProfileCheckBucketMetrics(t *testing.T) {
+	sqp := &StatQueueProfile{Tenant: "cgrates.org", ID: "SQ_BUCKETS",
+		Metrics: []*utils.MetricWithParams{
+			{MetricID: utils.MetaASR},
+			{MetricID: "*highest:Cost", Parameters: "Cost"},
+			{MetricID: "*p95:PDD", Parameters: "PDD"},
+		},
+	}
+	if err := sqp.CheckBucketMetrics(); err != nil { // events tracked individually
+		t.Error(err)
+	}
+	sqp.BucketInterval = time.Minute
+	if err := sqp.CheckBucketMetrics(); err == nil ||
+		err.Error() != "metric: <*p95:PDD> cannot be aggregated into time buckets" {
+		t.Errorf("received error: %v", err)
+	}
+	sqp.Metrics = sqp.Metrics[:2]
+	if err := sqp.CheckBucketMetrics(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStatQueueNewSnapshot(t *testing.T) {
+	sq = &StatQueue{
+		Tenant: "cgrates.org",
+		ID:     "SNAPSHOT",
+		sqPrfl: &StatQueueProfile{},
+		SQMetrics: map[string]StatMetric{
+			utils.MetaASR: &StatASR{
+				Answered: 1,
+				Count:    2,
+				Events: map[string]bool{
+					"cgrates.org:TestStatQueueNewSnapshot_1": true,
+					"cgrates.org:TestStatQueueNewSnapshot_2": false,
+				},
+			},
+		},
+	}
+	now := time.Now()
+	if sqSnap := sq.newSnapshot(now); sqSnap != nil {
+		t.Errorf("snapshot without SnapshotInterval: %+v", sqSnap)
+	}
+	sq.sqPrfl.SnapshotInterval = time.Minute
+	eSnap := &StatQueueSnapshot{
+		Tenant:  "cgrates.org",
+		ID:      "SNAPSHOT",
+		Time:    now,
+		Metrics: map[string]float64{utils.MetaASR: 50},
+	}
+	if sqSnap := sq.newSnapshot(now); !reflect.DeepEqual(eSnap, sqSnap) {
+		t.Errorf("expecting: %+v, received: %+v", eSnap, sqSnap)
+	}
+	if sqSnap := sq.newSnapshot(now.Add(30 * time.Second)); sqSnap != nil {
+		t.Errorf("snapshot before SnapshotInterval: %+v", sqSnap)
+	}
+	eSnap.Time = now.Add(time.Minute)
+	if sqSnap := sq.newSnapshot(now.Add(time.Minute)); !reflect.DeepEqual(eSnap, sqSnap) {
+		t.Errorf("expecting: %+v, received: %+v", eSnap, sqSnap)
+	}
+}
@@ -20,6 +20,7 @@ package engine
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 
@@ -34,11 +35,18 @@ type SortedSupplier struct {
 	globalStats        map[string]float64
 }
 
+// ExcludedSupplier is a supplier left out by the sorting strategy
+type ExcludedSupplier struct {
+	SupplierID string
+	Reason     string // why the supplier was excluded, ie: limit breached
+}
+
 // SuppliersReply is returned as part of GetSuppliers call
 type SortedSuppliers struct {
-	ProfileID       string            // Profile matched
-	Sorting         string            // Sorting algorithm
-	SortedSuppliers []*SortedSupplier // list of supplier IDs and SortingData data
+	ProfileID         string              // Profile matched
+	Sorting           string              // Sorting algorithm
+	SortedSuppliers   []*SortedSupplier   // list of supplier IDs and SortingData data
+	ExcludedSuppliers []*ExcludedSupplier // populated by the strategies filtering out suppliers
 }
 
 // SupplierIDs returns list of suppliers
@@ -115,6 +123,29 @@ func (sSpls *SortedSuppliers) SortQOS(params []string) {
 	})
 }
 
+// SortLoadDistribution is part of sort interface,
+// sort ascendent based on the usage per Ratio after allocating a new unit with fallback on Weight
+func (sSpls *SortedSuppliers) SortLoadDistribution() {
+	sort.Slice(sSpls.SortedSuppliers, func(i, j int) bool {
+		ldI := sSpls.SortedSuppliers[i].loadPerRatio()
+		ldJ := sSpls.SortedSuppliers[j].loadPerRatio()
+		if ldI == ldJ {
+			return sSpls.SortedSuppliers[i].SortingData[utils.Weight].(float64) > sSpls.SortedSuppliers[j].SortingData[utils.Weight].(float64)
+		}
+		return ldI < ldJ
+	})
+}
+
+// loadPerRatio returns the usage of the supplier per Ratio unit, considering also the unit about to be allocated
+// suppliers without Ratio are always the most loaded
+func (sSpl *SortedSupplier) loadPerRatio() float64 {
+	ratio := sSpl.SortingData[utils.Ratio].(float64)
+	if ratio <= 0 {
+		return math.Inf(1)
+	}
+	return (sSpl.SortingData[utils.ResourceUsage].(float64) + 1) / ratio
+}
+
 // Digest returns list of supplierIDs + parameters for easier outside access
 // format suppl1:suppl1params,suppl2:suppl2params
 func (sSpls *SortedSuppliers) Digest() string {
@@ -138,6 +169,8 @@ func NewSupplierSortDispatcher(lcrS *SupplierService) (ssd SupplierSortDispatche
 	ssd[utils.MetaLeastCost] = NewLeastCostSorter(lcrS)
 	ssd[utils.MetaHighestCost] = NewHighestCostSorter(lcrS)
 	ssd[utils.MetaQOS] = NewQOSSupplierSorter(lcrS)
+	ssd[utils.MetaLoadDistribution] = NewLoadDistributionSorter(lcrS)
+	ssd[utils.MetaQOSThreshold] = NewQOSThresholdSorter(lcrS)
 	return
 }
 
@@ -846,3 +846,102 @@ func TestLibSuppliersSortQOS8(t *testing.T) {
 			utils.ToJSON(eOrderedSpls), utils.ToJSON(sSpls))
 	}
 }
+
+func TestLibSuppliersSortLoadDistribution(t *testing.T) {
+	sSpls := &SortedSuppliers{
+		SortedSuppliers: []*SortedSupplier{
+			&SortedSupplier{
+				SupplierID: "supplier1",
+				SortingData: map[string]interface{}{
+					utils.ResourceUsage: 5.0,
+					utils.Ratio:         6.0,
+					utils.Weight:        10.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier2",
+				SortingData: map[string]interface{}{
+					utils.ResourceUsage: 2.0,
+					utils.Ratio:         3.0,
+					utils.Weight:        20.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier3",
+				SortingData: map[string]interface{}{
+					utils.ResourceUsage: 1.0,
+					utils.Ratio:         1.0,
+					utils.Weight:        30.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier4",
+				SortingData: map[string]interface{}{
+					utils.ResourceUsage: 0.0,
+					utils.Ratio:         0.0,
+					utils.Weight:        40.0,
+				},
+			},
+		},
+	}
+	sSpls.SortLoadDistribution()
+	eIDs := []string{"supplier2", "supplier1", "supplier3", "supplier4"} // 1 and weight, 1, 2, never
+	if rIDs := sSpls.SupplierIDs(); !reflect.DeepEqual(eIDs, rIDs) {
+		t.Errorf("expecting: %+v, received: %+v", eIDs, rIDs)
+	}
+}
+
+func TestLibSuppliersSupplierRatios(t *testing.T) {
+	eRatios := map[string]float64{
+		utils.MetaDefault: 2,
+		"supplier1":       60,
+		"supplier2":       30.5,
+	}
+	if ratios, err := supplierRatios([]string{"supplier1:60",
+		"supplier2:30.5", "*default:2"}); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eRatios, ratios) {
+		t.Errorf("expecting: %+v, received: %+v", eRatios, ratios)
+	}
+	if _, err := supplierRatios([]string{"supplier1"}); err == nil {
+		t.Error("expecting error for missing ratio")
+	}
+	if _, err := supplierRatios([]string{"supplier1:sixty"}); err == nil {
+		t.Error("expecting error for invalid ratio")
+	}
+}
+
+func TestLibSuppliersQOSLimits(t *testing.T) {
+	lmts, err := newQOSLimits([]string{"*asr:*gte:40", "*pdd:*lte:5s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eLmts := []*qosLimit{
+		&qosLimit{metricID: utils.MetaASR, operator: MetaGreaterOrEqual, value: 40},
+		&qosLimit{metricID: utils.MetaPDD, operator: MetaLessOrEqual, value: 5},
+	}
+	if !reflect.DeepEqual(eLmts, lmts) {
+		t.Errorf("expecting: %+v, received: %+v", eLmts, lmts)
+	}
+	sortingData := map[string]interface{}{
+		utils.Weight: 10.0,
+		utils.Cost:   0.1,
+		utils.ConcatenatedKey(utils.MetaASR, "STATS_SPL1"): 35.0,
+		utils.ConcatenatedKey(utils.MetaPDD, "STATS_SPL1"): -1.0,
+	}
+	if reason := lmts[0].breach(sortingData); reason != "*asr:STATS_SPL1: 35 not *gte 40" {
+		t.Errorf("unexpected reason: %q", reason)
+	}
+	if reason := lmts[1].breach(sortingData); reason != "" { // not enough data
+		t.Errorf("unexpected reason: %q", reason)
+	}
+	sortingData[utils.ConcatenatedKey(utils.MetaPDD, "STATS_SPL1")] = 3.0
+	if reason := lmts[1].breach(sortingData); reason != "" {
+		t.Errorf("unexpected reason: %q", reason)
+	}
+	for _, params := range [][]string{{"*asr:40"}, {"*asr:*eq:40"}, {"*asr:*gte:forty"}} {
+		if _, err := newQOSLimits(params); err == nil {
+			t.Errorf("expecting error for: %+v", params)
+		}
+	}
+}
@@ -147,6 +147,7 @@ func LoadTariffPlanFromFolder(tpPath, timezone string, dm *DataManager, disable_
 		path.Join(tpPath, utils.SuppliersCsv),
 		path.Join(tpPath, utils.AttributesCsv),
 		path.Join(tpPath, utils.ChargersCsv),
+		path.Join(tpPath, utils.DispatchersCsv),
 	), "", timezone)
 	if err := loader.LoadAll(); err != nil {
 		return utils.NewErrServerError(err)
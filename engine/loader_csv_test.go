@@ -20,6 +20,7 @@ package engine
 import (
 	"log"
 	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -265,21 +266,21 @@ cgrates.org,mas,true,another,value,10
 *out,cgrates.org,call,remo,remo,*any,*rating,Account,remo,minu,10
 `
 	resProfiles = `
-#Tenant[0],Id[1],FilterIDs[2],ActivationInterval[3],TTL[4],Limit[5],AllocationMessage[6],Blocker[7],Stored[8],Weight[9],Thresholds[10]
-cgrates.org,ResGroup21,FLTR_1,2014-07-29T15:00:00Z,1s,2,call,true,true,10,
-cgrates.org,ResGroup22,FLTR_ACNT_dan,2014-07-29T15:00:00Z,3600s,2,premium_call,true,true,10,
+#Tenant[0],Id[1],FilterIDs[2],ActivationInterval[3],TTL[4],Limit[5],AllocationMessage[6],Blocker[7],Stored[8],Weight[9],Thresholds[10],QuotaPeriod[11],QuotaTimezone[12]
+cgrates.org,ResGroup21,FLTR_1,2014-07-29T15:00:00Z,1s,2,call,true,true,10,,,
+cgrates.org,ResGroup22,FLTR_ACNT_dan,2014-07-29T15:00:00Z,3600s,2,premium_call,true,true,10,,*monthly,UTC
 `
 	stats = `
-#Tenant[0],Id[1],FilterIDs[2],ActivationInterval[3],QueueLength[4],TTL[5],Metrics[6],Blocker[7],Stored[8],Weight[9],MinItems[10],Thresholds[11]
-cgrates.org,TestStats,FLTR_1,2014-07-29T15:00:00Z,100,1s,*sum;*average,Value,true,true,20,2,Th1;Th2
-cgrates.org,TestStats,,,,,*sum,Usage,true,true,20,2,
-cgrates.org,TestStats2,FLTR_1,2014-07-29T15:00:00Z,100,1s,*sum;*average,Value;Usage,true,true,20,2,Th
-cgrates.org,TestStats2,,,,,*sum;*average,Cost,true,true,20,2,
+#Tenant[0],Id[1],FilterIDs[2],ActivationInterval[3],QueueLength[4],TTL[5],Metrics[6],MetricParams[7],Blocker[8],Stored[9],Weight[10],MinItems[11],Thresholds[12],BucketInterval[13],SnapshotInterval[14]
+cgrates.org,TestStats,FLTR_1,2014-07-29T15:00:00Z,100,1s,*sum;*average,Value,true,true,20,2,Th1;Th2,,5m
+cgrates.org,TestStats,,,,,*sum,Usage,true,true,20,2,,,
+cgrates.org,TestStats2,FLTR_1,2014-07-29T15:00:00Z,100,1s,*sum;*average,Value;Usage,true,true,20,2,Th,1m,
+cgrates.org,TestStats2,,,,,*sum;*average,Cost,true,true,20,2,,,
 `
 
 	thresholds = `
-#Tenant[0],Id[1],FilterIDs[2],ActivationInterval[3],MaxHits[4],MinHits[5],MinSleep[6],Blocker[7],Weight[8],ActionIDs[9],Async[10]
-cgrates.org,Threshold1,FLTR_1;FLTR_ACNT_dan,2014-07-29T15:00:00Z,12,10,1s,true,10,THRESH1,true
+#Tenant[0],Id[1],FilterIDs[2],ActivationInterval[3],MaxHits[4],MinHits[5],MinSleep[6],Blocker[7],Weight[8],ActionIDs[9],Async[10],Notifications[11],HitsWindow[12],ResetInterval[13]
+cgrates.org,Threshold1,FLTR_1;FLTR_ACNT_dan,2014-07-29T15:00:00Z,12,10,1s,true,10,THRESH1,true,*log;*http_json:http://localhost:2080/thresholds,1m,24h
 `
 
 	filters = `
@@ -306,6 +307,11 @@ cgrates.org,ALS1,con2;con3,,,Field2,Initial2,Sub2,false,,
 	chargerProfiles = `
 #Tenant,ID,FilterIDs,ActivationInterval,RunID,AttributeIDs,Weight
 cgrates.org,Charger1,*string:Account:1001,2014-07-29T15:00:00Z,*rated,ATTR_1001_SIMPLEAUTH,20
+`
+	dispatcherProfiles = `
+#Tenant,ID,Subsystems,FilterIDs,ActivationInterval,Strategy,StrategyParameters,HostID,HostFilterIDs,HostWeight,HostParameters,HostBlocker,Weight
+cgrates.org,D1,*any,*string:Account:1001,2014-07-29T15:00:00Z,*first,,ALL,,20,,false,20
+cgrates.org,D1,,,,,,ALL2,,10,,false,
 `
 )
 
@@ -314,7 +320,7 @@ var csvr *TpReader
 func init() {
 	csvr = NewTpReader(dm.dataDB, NewStringCSVStorage(',', destinations, timings, rates, destinationRates, ratingPlans, ratingProfiles,
 		sharedGroups, lcrs, actions, actionPlans, actionTriggers, accountActions, derivedCharges,
-		cdrStats, users, aliases, resProfiles, stats, thresholds, filters, sppProfiles, attributeProfiles, chargerProfiles), testTPID, "")
+		cdrStats, users, aliases, resProfiles, stats, thresholds, filters, sppProfiles, attributeProfiles, chargerProfiles, dispatcherProfiles), testTPID, "")
 
 	if err := csvr.LoadDestinations(); err != nil {
 		log.Print("error in LoadDestinations:", err)
@@ -385,6 +391,9 @@ func init() {
 	if err := csvr.LoadChargerProfiles(); err != nil {
 		log.Print("error in LoadChargerProfiles:", err)
 	}
+	if err := csvr.LoadDispatcherProfiles(); err != nil {
+		log.Print("error in LoadDispatcherProfiles:", err)
+	}
 	csvr.WriteToDatabase(false, false, false)
 	Cache.Clear(nil)
 	//dm.LoadDataDBCache(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
@@ -1467,6 +1476,8 @@ func TestLoadResourceProfiles(t *testing.T) {
 			Stored:            true,
 			Weight:            10,
 			Limit:             "2",
+			QuotaPeriod:       utils.MetaMonthly,
+			QuotaTimezone:     "UTC",
 		},
 	}
 	resKey := utils.TenantID{Tenant: "cgrates.org", ID: "ResGroup21"}
@@ -1503,11 +1514,12 @@ func TestLoadStatQueueProfiles(t *testing.T) {
 					Parameters: "Usage",
 				},
 			},
-			ThresholdIDs: []string{"Th1", "Th2"},
-			Blocker:      true,
-			Stored:       true,
-			Weight:       20,
-			MinItems:     2,
+			ThresholdIDs:     []string{"Th1", "Th2"},
+			Blocker:          true,
+			Stored:           true,
+			Weight:           20,
+			MinItems:         2,
+			SnapshotInterval: "5m",
 		},
 		utils.TenantID{Tenant: "cgrates.org", ID: "TestStats2"}: &utils.TPStats{
 			Tenant:    "cgrates.org",
@@ -1545,11 +1557,12 @@ func TestLoadStatQueueProfiles(t *testing.T) {
 					Parameters: "Cost",
 				},
 			},
-			ThresholdIDs: []string{"Th"},
-			Blocker:      true,
-			Stored:       true,
-			Weight:       20,
-			MinItems:     2,
+			ThresholdIDs:   []string{"Th"},
+			Blocker:        true,
+			Stored:         true,
+			Weight:         20,
+			MinItems:       2,
+			BucketInterval: "1m",
 		},
 	}
 	stKeys := []utils.TenantID{
@@ -1578,6 +1591,12 @@ func TestLoadStatQueueProfiles(t *testing.T) {
 			t.Errorf("Expecting: %s, received: %s",
 				utils.ToJSON(eStats[stKey].Metrics),
 				utils.ToJSON(csvr.sqProfiles[stKey].Metrics))
+		} else if eStats[stKey].BucketInterval != csvr.sqProfiles[stKey].BucketInterval {
+			t.Errorf("Expecting: %s, received: %s",
+				eStats[stKey].BucketInterval, csvr.sqProfiles[stKey].BucketInterval)
+		} else if eStats[stKey].SnapshotInterval != csvr.sqProfiles[stKey].SnapshotInterval {
+			t.Errorf("Expecting: %s, received: %s",
+				eStats[stKey].SnapshotInterval, csvr.sqProfiles[stKey].SnapshotInterval)
 		}
 	}
 }
@@ -1599,6 +1618,10 @@ func TestLoadThresholdProfiles(t *testing.T) {
 			Weight:    10,
 			ActionIDs: []string{"THRESH1"},
 			Async:     true,
+			Notifications: []string{"*log",
+				"*http_json:http://localhost:2080/thresholds"},
+			HitsWindow:    "1m",
+			ResetInterval: "24h",
 		},
 	}
 	eThresholdReverse := map[utils.TenantID]*utils.TPThreshold{
@@ -1617,6 +1640,10 @@ func TestLoadThresholdProfiles(t *testing.T) {
 			Weight:    10,
 			ActionIDs: []string{"THRESH1"},
 			Async:     true,
+			Notifications: []string{"*log",
+				"*http_json:http://localhost:2080/thresholds"},
+			HitsWindow:    "1m",
+			ResetInterval: "24h",
 		},
 	}
 	thkey := utils.TenantID{Tenant: "cgrates.org", ID: "Threshold1"}
@@ -1818,6 +1845,42 @@ func TestLoadChargerProfiles(t *testing.T) {
 	}
 }
 
+func TestLoadDispatcherProfiles(t *testing.T) {
+	eDispatcherProfiles := &utils.TPDispatcherProfile{
+		TPid:       testTPID,
+		Tenant:     "cgrates.org",
+		ID:         "D1",
+		Subsystems: []string{"*any"},
+		FilterIDs:  []string{"*string:Account:1001"},
+		ActivationInterval: &utils.TPActivationInterval{
+			ActivationTime: "2014-07-29T15:00:00Z",
+		},
+		Strategy: "*first",
+		Weight:   20,
+		Hosts: []*utils.TPDispatcherHost{
+			&utils.TPDispatcherHost{
+				ID:     "ALL",
+				Weight: 20,
+			},
+			&utils.TPDispatcherHost{
+				ID:     "ALL2",
+				Weight: 10,
+			},
+		},
+	}
+	dppKey := utils.TenantID{Tenant: "cgrates.org", ID: "D1"}
+	if len(csvr.dispatcherProfiles) != 1 {
+		t.Errorf("Failed to load dispatcherProfiles: %s", utils.ToIJSON(csvr.dispatcherProfiles))
+	} else if rcv := csvr.dispatcherProfiles[dppKey]; len(rcv.Hosts) != len(eDispatcherProfiles.Hosts) {
+		t.Errorf("Expecting: %+v, received: %+v", utils.ToJSON(eDispatcherProfiles), utils.ToJSON(rcv))
+	} else {
+		sort.Slice(rcv.Hosts, func(i, j int) bool { return rcv.Hosts[i].ID < rcv.Hosts[j].ID })
+		if !reflect.DeepEqual(eDispatcherProfiles, rcv) {
+			t.Errorf("Expecting: %+v, received: %+v", utils.ToJSON(eDispatcherProfiles), utils.ToJSON(rcv))
+		}
+	}
+}
+
 func TestLoadResource(t *testing.T) {
 	eResources := []*utils.TenantID{
 		&utils.TenantID{
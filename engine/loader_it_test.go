@@ -119,6 +119,7 @@ func TestLoaderITRemoveLoad(t *testing.T) {
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.SuppliersCsv),
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.AttributesCsv),
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.ChargersCsv),
+		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.DispatchersCsv),
 	), "", "")
 
 	if err = loader.LoadDestinations(); err != nil {
@@ -224,6 +225,7 @@ func TestLoaderITLoadFromCSV(t *testing.T) {
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.SuppliersCsv),
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.AttributesCsv),
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.ChargersCsv),
+		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.DispatchersCsv),
 	), "", "")
 
 	if err = loader.LoadDestinations(); err != nil {
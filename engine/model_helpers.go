@@ -1847,6 +1847,12 @@ func (tps TpResources) AsTPResources() (result []*utils.TPResource) {
 		if tp.AllocationMessage != "" {
 			rl.AllocationMessage = tp.AllocationMessage
 		}
+		if tp.QuotaPeriod != "" {
+			rl.QuotaPeriod = tp.QuotaPeriod
+		}
+		if tp.QuotaTimezone != "" {
+			rl.QuotaTimezone = tp.QuotaTimezone
+		}
 		rl.Blocker = tp.Blocker
 		rl.Stored = tp.Stored
 		if len(tp.ActivationInterval) != 0 {
@@ -1915,6 +1921,8 @@ func APItoModelResource(rl *utils.TPResource) (mdls TpResources) {
 				mdl.Weight = rl.Weight
 				mdl.Limit = rl.Limit
 				mdl.AllocationMessage = rl.AllocationMessage
+				mdl.QuotaPeriod = rl.QuotaPeriod
+				mdl.QuotaTimezone = rl.QuotaTimezone
 				if rl.ActivationInterval != nil {
 					if rl.ActivationInterval.ActivationTime != "" {
 						mdl.ActivationInterval = rl.ActivationInterval.ActivationTime
@@ -1945,9 +1953,14 @@ func APItoResource(tpRL *utils.TPResource, timezone string) (rp *ResourceProfile
 		Blocker:           tpRL.Blocker,
 		Stored:            tpRL.Stored,
 		AllocationMessage: tpRL.AllocationMessage,
+		QuotaPeriod:       tpRL.QuotaPeriod,
+		QuotaTimezone:     tpRL.QuotaTimezone,
 		ThresholdIDs:      make([]string, len(tpRL.ThresholdIDs)),
 		FilterIDs:         make([]string, len(tpRL.FilterIDs)),
 	}
+	if err = rp.CheckQuotaPeriod(); err != nil {
+		return nil, err
+	}
 	if tpRL.UsageTTL != "" {
 		if rp.UsageTTL, err = utils.ParseDurationWithNanosecs(tpRL.UsageTTL); err != nil {
 			return nil, err
@@ -2007,6 +2020,12 @@ func (tps TpStatsS) AsTPStats() (result []*utils.TPStats) {
 		if tp.TTL != "" {
 			st.TTL = tp.TTL
 		}
+		if tp.BucketInterval != "" {
+			st.BucketInterval = tp.BucketInterval
+		}
+		if tp.SnapshotInterval != "" {
+			st.SnapshotInterval = tp.SnapshotInterval
+		}
 		if tp.Metrics != "" {
 			if _, has := metricmap[(&utils.TenantID{Tenant: tp.Tenant, ID: tp.ID}).TenantID()]; !has {
 				metricmap[(&utils.TenantID{Tenant: tp.Tenant, ID: tp.ID}).TenantID()] = make(map[string]*utils.MetricWithParams)
@@ -2100,6 +2119,8 @@ func APItoModelStats(st *utils.TPStats) (mdls TpStatsS) {
 				mdl.Weight = st.Weight
 				mdl.QueueLength = st.QueueLength
 				mdl.MinItems = st.MinItems
+				mdl.BucketInterval = st.BucketInterval
+				mdl.SnapshotInterval = st.SnapshotInterval
 				for i, val := range st.Metrics {
 					if i != 0 {
 						mdl.Metrics += utils.INFIELD_SEP
@@ -2158,6 +2179,19 @@ func APItoStats(tpST *utils.TPStats, timezone string) (st *StatQueueProfile, err
 			return nil, err
 		}
 	}
+	if tpST.BucketInterval != "" {
+		if st.BucketInterval, err = utils.ParseDurationWithNanosecs(tpST.BucketInterval); err != nil {
+			return nil, err
+		}
+		if err = st.CheckBucketMetrics(); err != nil {
+			return nil, err
+		}
+	}
+	if tpST.SnapshotInterval != "" {
+		if st.SnapshotInterval, err = utils.ParseDurationWithNanosecs(tpST.SnapshotInterval); err != nil {
+			return nil, err
+		}
+	}
 	for i, trh := range tpST.ThresholdIDs {
 		st.ThresholdIDs[i] = trh
 	}
@@ -2192,6 +2226,19 @@ func (tps TpThresholdS) AsTPThreshold() (result []*utils.TPThreshold) {
 				Async:    tp.Async,
 			}
 		}
+		if tp.HitsWindow != "" {
+			th.HitsWindow = tp.HitsWindow
+		}
+		if tp.ResetInterval != "" {
+			th.ResetInterval = tp.ResetInterval
+		}
+		if tp.Notifications != "" {
+			for _, ntf := range strings.Split(tp.Notifications, utils.INFIELD_SEP) {
+				if !utils.IsSliceMember(th.Notifications, ntf) {
+					th.Notifications = append(th.Notifications, ntf)
+				}
+			}
+		}
 		if tp.ActionIDs != "" {
 			if _, has := actionMap[(&utils.TenantID{Tenant: tp.Tenant, ID: tp.ID}).TenantID()]; !has {
 				actionMap[(&utils.TenantID{Tenant: tp.Tenant, ID: tp.ID}).TenantID()] = make(utils.StringMap)
@@ -2263,6 +2310,9 @@ func APItoModelTPThreshold(th *utils.TPThreshold) (mdls TpThresholdS) {
 				mdl.MinHits = th.MinHits
 				mdl.MinSleep = th.MinSleep
 				mdl.Async = th.Async
+				mdl.Notifications = strings.Join(th.Notifications, utils.INFIELD_SEP)
+				mdl.HitsWindow = th.HitsWindow
+				mdl.ResetInterval = th.ResetInterval
 				if th.ActivationInterval != nil {
 					if th.ActivationInterval.ActivationTime != "" {
 						mdl.ActivationInterval = th.ActivationInterval.ActivationTime
@@ -2302,6 +2352,9 @@ func APItoModelTPThreshold(th *utils.TPThreshold) (mdls TpThresholdS) {
 					mdl.MinHits = th.MinHits
 					mdl.MinSleep = th.MinSleep
 					mdl.Async = th.Async
+					mdl.Notifications = strings.Join(th.Notifications, utils.INFIELD_SEP)
+					mdl.HitsWindow = th.HitsWindow
+					mdl.ResetInterval = th.ResetInterval
 					if th.ActivationInterval != nil {
 						if th.ActivationInterval.ActivationTime != "" {
 							mdl.ActivationInterval = th.ActivationInterval.ActivationTime
@@ -2336,6 +2389,16 @@ func APItoThresholdProfile(tpTH *utils.TPThreshold, timezone string) (th *Thresh
 			return nil, err
 		}
 	}
+	if tpTH.HitsWindow != "" {
+		if th.HitsWindow, err = utils.ParseDurationWithNanosecs(tpTH.HitsWindow); err != nil {
+			return nil, err
+		}
+	}
+	if tpTH.ResetInterval != "" {
+		if th.ResetInterval, err = utils.ParseDurationWithNanosecs(tpTH.ResetInterval); err != nil {
+			return nil, err
+		}
+	}
 	for i, ati := range tpTH.ActionIDs {
 		th.ActionIDs[i] = ati
 
@@ -2343,6 +2406,13 @@ func APItoThresholdProfile(tpTH *utils.TPThreshold, timezone string) (th *Thresh
 	for i, fli := range tpTH.FilterIDs {
 		th.FilterIDs[i] = fli
 	}
+	for _, ntfStr := range tpTH.Notifications {
+		var ntf *ThresholdNotification
+		if ntf, err = NewThresholdNotification(ntfStr); err != nil {
+			return nil, err
+		}
+		th.Notifications = append(th.Notifications, ntf)
+	}
 	if tpTH.ActivationInterval != nil {
 		if th.ActivationInterval, err = tpTH.ActivationInterval.AsActivationInterval(timezone); err != nil {
 			return nil, err
@@ -3005,3 +3075,197 @@ func APItoChargerProfile(tpCPP *utils.TPChargerProfile, timezone string) (cpp *C
 	}
 	return cpp, nil
 }
+
+type TPDispatchers []*TPDispatcher
+
+func (tps TPDispatchers) AsTPDispatchers() (result []*utils.TPDispatcherProfile) {
+	mst := make(map[string]*utils.TPDispatcherProfile)
+	filterMap := make(map[string]utils.StringMap)
+	subsystemMap := make(map[string]utils.StringMap)
+	strategyParamMap := make(map[string]utils.StringMap)
+	hostsMap := make(map[string]map[string]*utils.TPDispatcherHost)
+	for _, tp := range tps {
+		tntID := (&utils.TenantID{Tenant: tp.Tenant, ID: tp.ID}).TenantID()
+		tpDPP, found := mst[tntID]
+		if !found {
+			tpDPP = &utils.TPDispatcherProfile{
+				TPid:   tp.Tpid,
+				Tenant: tp.Tenant,
+				ID:     tp.ID,
+			}
+		}
+		if tp.Weight != 0 {
+			tpDPP.Weight = tp.Weight
+		}
+		if tp.Strategy != "" {
+			tpDPP.Strategy = tp.Strategy
+		}
+		if len(tp.ActivationInterval) != 0 {
+			tpDPP.ActivationInterval = new(utils.TPActivationInterval)
+			aiSplt := strings.Split(tp.ActivationInterval, utils.INFIELD_SEP)
+			if len(aiSplt) == 2 {
+				tpDPP.ActivationInterval.ActivationTime = aiSplt[0]
+				tpDPP.ActivationInterval.ExpiryTime = aiSplt[1]
+			} else if len(aiSplt) == 1 {
+				tpDPP.ActivationInterval.ActivationTime = aiSplt[0]
+			}
+		}
+		if tp.FilterIDs != "" {
+			if _, has := filterMap[tntID]; !has {
+				filterMap[tntID] = make(utils.StringMap)
+			}
+			for _, filter := range strings.Split(tp.FilterIDs, utils.INFIELD_SEP) {
+				filterMap[tntID][filter] = true
+			}
+		}
+		if tp.Subsystems != "" {
+			if _, has := subsystemMap[tntID]; !has {
+				subsystemMap[tntID] = make(utils.StringMap)
+			}
+			for _, subsystem := range strings.Split(tp.Subsystems, utils.INFIELD_SEP) {
+				subsystemMap[tntID][subsystem] = true
+			}
+		}
+		if tp.StrategyParameters != "" {
+			if _, has := strategyParamMap[tntID]; !has {
+				strategyParamMap[tntID] = make(utils.StringMap)
+			}
+			for _, param := range strings.Split(tp.StrategyParameters, utils.INFIELD_SEP) {
+				strategyParamMap[tntID][param] = true
+			}
+		}
+		if tp.HostID != "" {
+			if _, has := hostsMap[tntID]; !has {
+				hostsMap[tntID] = make(map[string]*utils.TPDispatcherHost)
+			}
+			host, found := hostsMap[tntID][tp.HostID]
+			if !found {
+				host = &utils.TPDispatcherHost{
+					ID:      tp.HostID,
+					Weight:  tp.HostWeight,
+					Blocker: tp.HostBlocker,
+				}
+			}
+			if tp.HostFilterIDs != "" {
+				host.FilterIDs = append(host.FilterIDs,
+					strings.Split(tp.HostFilterIDs, utils.INFIELD_SEP)...)
+			}
+			if tp.HostParameters != "" {
+				host.Params = append(host.Params,
+					strings.Split(tp.HostParameters, utils.INFIELD_SEP)...)
+			}
+			hostsMap[tntID][tp.HostID] = host
+		}
+		mst[tntID] = tpDPP
+	}
+	result = make([]*utils.TPDispatcherProfile, len(mst))
+	i := 0
+	for tntID, tp := range mst {
+		result[i] = tp
+		for filterID := range filterMap[tntID] {
+			result[i].FilterIDs = append(result[i].FilterIDs, filterID)
+		}
+		for subsystem := range subsystemMap[tntID] {
+			result[i].Subsystems = append(result[i].Subsystems, subsystem)
+		}
+		for param := range strategyParamMap[tntID] {
+			result[i].StrategyParams = append(result[i].StrategyParams, param)
+		}
+		for _, host := range hostsMap[tntID] {
+			result[i].Hosts = append(result[i].Hosts, host)
+		}
+		i++
+	}
+	return
+}
+
+func APItoModelTPDispatcher(tpDPP *utils.TPDispatcherProfile) (mdls TPDispatchers) {
+	if tpDPP == nil {
+		return
+	}
+	mdl := &TPDispatcher{
+		Tpid:               tpDPP.TPid,
+		Tenant:             tpDPP.Tenant,
+		ID:                 tpDPP.ID,
+		Subsystems:         strings.Join(tpDPP.Subsystems, utils.INFIELD_SEP),
+		FilterIDs:          strings.Join(tpDPP.FilterIDs, utils.INFIELD_SEP),
+		Strategy:           tpDPP.Strategy,
+		StrategyParameters: strings.Join(tpDPP.StrategyParams, utils.INFIELD_SEP),
+		Weight:             tpDPP.Weight,
+	}
+	if tpDPP.ActivationInterval != nil {
+		if tpDPP.ActivationInterval.ActivationTime != "" {
+			mdl.ActivationInterval = tpDPP.ActivationInterval.ActivationTime
+		}
+		if tpDPP.ActivationInterval.ExpiryTime != "" {
+			mdl.ActivationInterval += utils.INFIELD_SEP + tpDPP.ActivationInterval.ExpiryTime
+		}
+	}
+	if len(tpDPP.Hosts) == 0 {
+		return TPDispatchers{mdl}
+	}
+	for i, host := range tpDPP.Hosts {
+		if i != 0 {
+			mdl = &TPDispatcher{
+				Tpid:   tpDPP.TPid,
+				Tenant: tpDPP.Tenant,
+				ID:     tpDPP.ID,
+			}
+		}
+		mdl.HostID = host.ID
+		mdl.HostFilterIDs = strings.Join(host.FilterIDs, utils.INFIELD_SEP)
+		mdl.HostWeight = host.Weight
+		mdl.HostParameters = strings.Join(host.Params, utils.INFIELD_SEP)
+		mdl.HostBlocker = host.Blocker
+		mdls = append(mdls, mdl)
+	}
+	return
+}
+
+// dispatcherParamsAsMap converts the key:value parameters into a map
+func dispatcherParamsAsMap(params []string) (mp map[string]interface{}) {
+	mp = make(map[string]interface{})
+	for _, param := range params {
+		keyVal := strings.SplitN(param, utils.InInFieldSep, 2)
+		if len(keyVal) == 2 {
+			mp[keyVal[0]] = keyVal[1]
+		} else {
+			mp[keyVal[0]] = utils.EmptyString
+		}
+	}
+	return
+}
+
+func APItoDispatcherProfile(tpDPP *utils.TPDispatcherProfile, timezone string) (dpp *DispatcherProfile, err error) {
+	dpp = &DispatcherProfile{
+		Tenant:         tpDPP.Tenant,
+		ID:             tpDPP.ID,
+		Weight:         tpDPP.Weight,
+		Strategy:       tpDPP.Strategy,
+		FilterIDs:      make([]string, len(tpDPP.FilterIDs)),
+		Subsystems:     make([]string, len(tpDPP.Subsystems)),
+		StrategyParams: dispatcherParamsAsMap(tpDPP.StrategyParams),
+		Hosts:          make(DispatcherHosts, len(tpDPP.Hosts)),
+	}
+	for i, fli := range tpDPP.FilterIDs {
+		dpp.FilterIDs[i] = fli
+	}
+	for i, sub := range tpDPP.Subsystems {
+		dpp.Subsystems[i] = sub
+	}
+	for i, host := range tpDPP.Hosts {
+		dpp.Hosts[i] = &DispatcherHost{
+			ID:        host.ID,
+			FilterIDs: host.FilterIDs,
+			Weight:    host.Weight,
+			Params:    dispatcherParamsAsMap(host.Params),
+			Blocker:   host.Blocker,
+		}
+	}
+	if tpDPP.ActivationInterval != nil {
+		if dpp.ActivationInterval, err = tpDPP.ActivationInterval.AsActivationInterval(timezone); err != nil {
+			return nil, err
+		}
+	}
+	return dpp, nil
+}
@@ -1034,6 +1034,7 @@ func TestTPThresholdsAsTPThreshold(t *testing.T) {
 			Blocker:            false,
 			Weight:             20.0,
 			ActionIDs:          "WARN3",
+			Notifications:      "*log;*release_resource:RU_1;*log",
 		},
 	}
 	eTPs := []*utils.TPThreshold{
@@ -1044,12 +1045,13 @@ func TestTPThresholdsAsTPThreshold(t *testing.T) {
 			ActivationInterval: &utils.TPActivationInterval{
 				ActivationTime: tps[0].ActivationInterval,
 			},
-			MinSleep:  tps[0].MinSleep,
-			MaxHits:   tps[0].MaxHits,
-			MinHits:   tps[0].MinHits,
-			Blocker:   tps[0].Blocker,
-			Weight:    tps[0].Weight,
-			ActionIDs: []string{"WARN3"},
+			MinSleep:      tps[0].MinSleep,
+			MaxHits:       tps[0].MaxHits,
+			MinHits:       tps[0].MinHits,
+			Blocker:       tps[0].Blocker,
+			Weight:        tps[0].Weight,
+			ActionIDs:     []string{"WARN3"},
+			Notifications: []string{"*log", "*release_resource:RU_1"},
 		},
 		&utils.TPThreshold{
 			TPid:      tps[0].Tpid,
@@ -1058,12 +1060,13 @@ func TestTPThresholdsAsTPThreshold(t *testing.T) {
 			ActivationInterval: &utils.TPActivationInterval{
 				ActivationTime: tps[0].ActivationInterval,
 			},
-			MinSleep:  tps[0].MinSleep,
-			MaxHits:   tps[0].MaxHits,
-			MinHits:   tps[0].MinHits,
-			Blocker:   tps[0].Blocker,
-			Weight:    tps[0].Weight,
-			ActionIDs: []string{"WARN3"},
+			MinSleep:      tps[0].MinSleep,
+			MaxHits:       tps[0].MaxHits,
+			MinHits:       tps[0].MinHits,
+			Blocker:       tps[0].Blocker,
+			Weight:        tps[0].Weight,
+			ActionIDs:     []string{"WARN3"},
+			Notifications: []string{"*log", "*release_resource:RU_1"},
 		},
 	}
 	rcvTPs := TpThresholdS(tps).AsTPThreshold()
@@ -1896,3 +1899,111 @@ func TestModelAsTPChargers(t *testing.T) {
 		t.Errorf("Expecting : %+v, received: %+v", utils.ToJSON(expected), utils.ToJSON(rcv[0]))
 	}
 }
+
+func TestAPItoDispatcherProfile(t *testing.T) {
+	tpDPP := &utils.TPDispatcherProfile{
+		TPid:       "TP1",
+		Tenant:     "cgrates.org",
+		ID:         "Dsp1",
+		Subsystems: []string{utils.META_ANY},
+		FilterIDs:  []string{"FLTR_ACNT_dan", "FLTR_DST_DE"},
+		ActivationInterval: &utils.TPActivationInterval{
+			ActivationTime: "2014-07-14T14:35:00Z",
+			ExpiryTime:     "",
+		},
+		Strategy:       utils.MetaFirst,
+		StrategyParams: []string{"Param1:Value1"},
+		Weight:         20,
+		Hosts: []*utils.TPDispatcherHost{
+			&utils.TPDispatcherHost{
+				ID:        "ENGINE1",
+				FilterIDs: []string{"FLTR_ACNT_dan"},
+				Weight:    10,
+				Params:    []string{"192.168.54.203"},
+				Blocker:   true,
+			},
+		},
+	}
+	expected := &DispatcherProfile{
+		Tenant:     "cgrates.org",
+		ID:         "Dsp1",
+		Subsystems: []string{utils.META_ANY},
+		FilterIDs:  []string{"FLTR_ACNT_dan", "FLTR_DST_DE"},
+		ActivationInterval: &utils.ActivationInterval{
+			ActivationTime: time.Date(2014, 7, 14, 14, 35, 0, 0, time.UTC),
+		},
+		Strategy:       utils.MetaFirst,
+		StrategyParams: map[string]interface{}{"Param1": "Value1"},
+		Weight:         20,
+		Hosts: DispatcherHosts{
+			&DispatcherHost{
+				ID:        "ENGINE1",
+				FilterIDs: []string{"FLTR_ACNT_dan"},
+				Weight:    10,
+				Params:    map[string]interface{}{"192.168.54.203": ""},
+				Blocker:   true,
+			},
+		},
+	}
+	if rcv, err := APItoDispatcherProfile(tpDPP, "UTC"); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(expected, rcv) {
+		t.Errorf("Expecting : %+v, received: %+v", utils.ToJSON(expected), utils.ToJSON(rcv))
+	}
+}
+
+func TestAPItoModelTPDispatcher(t *testing.T) {
+	tpDPP := &utils.TPDispatcherProfile{
+		TPid:       "TP1",
+		Tenant:     "cgrates.org",
+		ID:         "Dsp1",
+		Subsystems: []string{utils.META_ANY},
+		FilterIDs:  []string{"FLTR_ACNT_dan", "FLTR_DST_DE"},
+		ActivationInterval: &utils.TPActivationInterval{
+			ActivationTime: "2014-07-14T14:35:00Z",
+		},
+		Strategy: utils.MetaFirst,
+		Weight:   20,
+		Hosts: []*utils.TPDispatcherHost{
+			&utils.TPDispatcherHost{
+				ID:     "ENGINE1",
+				Weight: 10,
+			},
+			&utils.TPDispatcherHost{
+				ID:      "ENGINE2",
+				Weight:  20,
+				Blocker: true,
+			},
+		},
+	}
+	expected := TPDispatchers{
+		&TPDispatcher{
+			Tpid:               "TP1",
+			Tenant:             "cgrates.org",
+			ID:                 "Dsp1",
+			Subsystems:         utils.META_ANY,
+			FilterIDs:          "FLTR_ACNT_dan;FLTR_DST_DE",
+			ActivationInterval: "2014-07-14T14:35:00Z",
+			Strategy:           utils.MetaFirst,
+			HostID:             "ENGINE1",
+			HostWeight:         10,
+			Weight:             20,
+		},
+		&TPDispatcher{
+			Tpid:        "TP1",
+			Tenant:      "cgrates.org",
+			ID:          "Dsp1",
+			HostID:      "ENGINE2",
+			HostWeight:  20,
+			HostBlocker: true,
+		},
+	}
+	rcv := APItoModelTPDispatcher(tpDPP)
+	if !reflect.DeepEqual(expected, rcv) {
+		t.Errorf("Expecting : %+v, received: %+v", utils.ToJSON(expected), utils.ToJSON(rcv))
+	}
+	if tps := rcv.AsTPDispatchers(); len(tps) != 1 ||
+		len(tps[0].Hosts) != 2 || tps[0].Strategy != utils.MetaFirst {
+		t.Errorf("received: %s", utils.ToJSON(tps))
+	}
+}
@@ -412,6 +412,8 @@ type TpResource struct {
 	Stored             bool    `index:"8" re:""`
 	Weight             float64 `index:"9" re:"\d+\.?\d*"`
 	ThresholdIDs       string  `index:"10" re:""`
+	QuotaPeriod        string  `index:"11" re:""`
+	QuotaTimezone      string  `index:"12" re:""`
 	CreatedAt          time.Time
 }
 
@@ -431,6 +433,8 @@ type TpStats struct {
 	Weight             float64 `index:"10" re:"\d+\.?\d*"`
 	MinItems           int     `index:"11" re:""`
 	ThresholdIDs       string  `index:"12" re:""`
+	BucketInterval     string  `index:"13" re:""`
+	SnapshotInterval   string  `index:"14" re:""`
 	CreatedAt          time.Time
 }
 
@@ -448,6 +452,9 @@ type TpThreshold struct {
 	Weight             float64 `index:"8" re:"\d+\.?\d*"`
 	ActionIDs          string  `index:"9" re:""`
 	Async              bool    `index:"10" re:""`
+	Notifications      string  `index:"11" re:""`
+	HitsWindow         string  `index:"12" re:""`
+	ResetInterval      string  `index:"13" re:""`
 	CreatedAt          time.Time
 }
 
@@ -511,6 +518,19 @@ func (t SessionsCostsSQL) TableName() string {
 	return utils.SessionsCostsTBL
 }
 
+type StatQueueSnapshotSQL struct {
+	ID           int64
+	Tenant       string
+	QueueID      string
+	Metrics      string
+	SnapshotTime time.Time
+	CreatedAt    time.Time
+}
+
+func (t StatQueueSnapshotSQL) TableName() string {
+	return utils.StatQueueSnapshotsTBL
+}
+
 type TBLVersion struct {
 	ID      uint
 	Item    string
@@ -572,3 +592,22 @@ type TPCharger struct {
 	Weight             float64 `index:"6" re:"\d+\.?\d*"`
 	CreatedAt          time.Time
 }
+
+type TPDispatcher struct {
+	PK                 uint `gorm:"primary_key"`
+	Tpid               string
+	Tenant             string  `index:"0" re:""`
+	ID                 string  `index:"1" re:""`
+	Subsystems         string  `index:"2" re:""`
+	FilterIDs          string  `index:"3" re:""`
+	ActivationInterval string  `index:"4" re:""`
+	Strategy           string  `index:"5" re:""`
+	StrategyParameters string  `index:"6" re:""`
+	HostID             string  `index:"7" re:""`
+	HostFilterIDs      string  `index:"8" re:""`
+	HostWeight         float64 `index:"9" re:"\d+\.?\d*"`
+	HostParameters     string  `index:"10" re:""`
+	HostBlocker        bool    `index:"11" re:""`
+	Weight             float64 `index:"12" re:"\d+\.?\d*"`
+	CreatedAt          time.Time
+}
@@ -20,8 +20,11 @@ package engine
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -31,16 +34,20 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/guardian"
 	"github.com/cgrates/cgrates/utils"
+	kafka "github.com/segmentio/kafka-go"
 	"github.com/streadway/amqp"
 )
 
 func init() {
 	AMQPPostersCache = &AMQPCachedPosters{cache: make(map[string]*AMQPPoster)} // Initialize the cache for amqpPosters
+	KafkaPostersCache = &KafkaCachedPosters{cache: make(map[string]*KafkaPoster)}
 }
 
 var AMQPPostersCache *AMQPCachedPosters
+var KafkaPostersCache *KafkaCachedPosters
 
 // Post without automatic failover
 func HttpJsonPost(url string, skipTlsVerify bool, content []byte) ([]byte, error) {
@@ -274,3 +281,168 @@ func (pstr *AMQPPoster) writeToFile(fileName string, content []byte) (err error)
 	}, time.Duration(2*time.Second), utils.FileLockPrefix+fallbackFilePath)
 	return
 }
+
+// KafkaCachedPosters is used to cache mutliple KafkaPoster connections based on the address
+type KafkaCachedPosters struct {
+	sync.Mutex
+	cache map[string]*KafkaPoster
+}
+
+// GetKafkaPoster creates a new poster only if not already cached
+// uses fallbackFileDir together with dialURL as cache key
+func (pc *KafkaCachedPosters) GetKafkaPoster(dialURL string, attempts int, fallbackFileDir string) (kafkaPoster *KafkaPoster, err error) {
+	pc.Lock()
+	defer pc.Unlock()
+	pstrKey := utils.ConcatenatedKey(fallbackFileDir, dialURL)
+	if _, hasIt := pc.cache[pstrKey]; !hasIt {
+		if pstr, err := NewKafkaPoster(dialURL, attempts, fallbackFileDir); err != nil {
+			return nil, err
+		} else {
+			pc.cache[pstrKey] = pstr
+		}
+	}
+	return pc.cache[pstrKey], nil
+}
+
+// KafkaTopic returns the topic defined in the kafka dialURL, empty if not defined
+func KafkaTopic(dialURL string) (topic string, err error) {
+	dialURLSplt := strings.SplitN(dialURL, "?", 2)
+	if len(dialURLSplt) != 2 {
+		return
+	}
+	var qry url.Values
+	if qry, err = url.ParseQuery(dialURLSplt[1]); err != nil {
+		return
+	}
+	return qry.Get("topic"), nil
+}
+
+// "localhost:9092,localhost:9093?topic=cgrates_cdrs&key=~Tenant%3B:%3B~Account&partition_field=Account"
+// key is a template over the fields of the posted content, query escaped,
+// partition_field is the content field hashed to choose the partition, the key is used if missing
+func NewKafkaPoster(dialURL string, attempts int, fallbackFileDir string) (pstr *KafkaPoster, err error) {
+	dialURLSplt := strings.SplitN(dialURL, "?", 2)
+	if dialURLSplt[0] == "" {
+		return nil, fmt.Errorf("missing brokers in kafka address: %s", dialURL)
+	}
+	var qry url.Values
+	if len(dialURLSplt) == 2 {
+		if qry, err = url.ParseQuery(dialURLSplt[1]); err != nil {
+			return
+		}
+	}
+	topic := "cgrates_cdrs"
+	if vals, has := qry["topic"]; has && len(vals) != 0 {
+		topic = vals[0]
+	}
+	pstr = &KafkaPoster{topic: topic, attempts: attempts,
+		fallbackFileDir: fallbackFileDir,
+		balancer:        &kafkaBalancer{partitionField: qry.Get("partition_field")}}
+	if pstr.key, err = config.NewRSRParsers(qry.Get("key"), true); err != nil {
+		return nil, err
+	}
+	pstr.writer = kafka.NewWriter(kafka.WriterConfig{
+		Brokers:     strings.Split(dialURLSplt[0], utils.FIELDS_SEP),
+		Topic:       topic,
+		Balancer:    pstr.balancer,
+		MaxAttempts: 1, // retries are done by the poster
+		BatchSize:   1, // Post is synchronous, do not wait for the batch to fill
+	})
+	return
+}
+
+type KafkaPoster struct {
+	topic           string            // identifier of the topic where we publish
+	key             config.RSRParsers // template of the message key
+	balancer        *kafkaBalancer
+	attempts        int
+	fallbackFileDir string
+	writer          kafkaWriter
+}
+
+// kafkaWriter is the part of *kafka.Writer used by KafkaPoster
+type kafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// Post publishes the content into the topic, writing it into fallbackFileName on failure
+func (pstr *KafkaPoster) Post(content []byte, fallbackFileName string) (err error) {
+	msg := kafka.Message{Key: pstr.messageKey(content), Value: content}
+	fib := utils.Fib()
+	for i := 0; i < pstr.attempts; i++ {
+		if err = pstr.writer.WriteMessages(context.Background(), msg); err == nil {
+			return
+		}
+		utils.Logger.Warning(fmt.Sprintf("<KafkaPoster> Posting to topic: <%s>, error: <%s>", pstr.topic, err.Error()))
+		time.Sleep(time.Duration(fib()) * time.Second)
+	}
+	if err != nil && fallbackFileName != utils.META_NONE {
+		err = pstr.writeToFile(fallbackFileName, content)
+	}
+	return
+}
+
+// messageKey builds the key of the message out of the content fields, nil if not possible
+func (pstr *KafkaPoster) messageKey(content []byte) []byte {
+	if len(pstr.key) == 0 {
+		return nil
+	}
+	var ev map[string]interface{}
+	if err := json.Unmarshal(content, &ev); err != nil {
+		return nil
+	}
+	key, err := pstr.key.ParseEvent(ev)
+	if err != nil || key == "" {
+		return nil
+	}
+	return []byte(key)
+}
+
+func (pstr *KafkaPoster) Close() {
+	pstr.writer.Close()
+}
+
+// writeToFile writes the content in the file with fileName on kafka.fallbackFileDir
+func (pstr *KafkaPoster) writeToFile(fileName string, content []byte) (err error) {
+	fallbackFilePath := path.Join(pstr.fallbackFileDir, fileName)
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		fileOut, err := os.Create(fallbackFilePath)
+		if err != nil {
+			return nil, err
+		}
+		defer fileOut.Close()
+		if _, err := fileOut.Write(content); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}, time.Duration(2*time.Second), utils.FileLockPrefix+fallbackFilePath)
+	return
+}
+
+// kafkaBalancer chooses the partition out of the hash of partitionField or of the message key
+// messages without any of them are distributed round-robin
+type kafkaBalancer struct {
+	partitionField string
+	rr             kafka.RoundRobin
+}
+
+// Balance implements kafka.Balancer interface
+func (kb *kafkaBalancer) Balance(msg kafka.Message, partitions ...int) int {
+	partKey := msg.Key
+	if kb.partitionField != "" {
+		partKey = nil
+		var ev map[string]interface{}
+		if err := json.Unmarshal(msg.Value, &ev); err == nil {
+			if val, err := utils.IfaceAsString(ev[kb.partitionField]); err == nil && val != "" {
+				partKey = []byte(val)
+			}
+		}
+	}
+	if len(partKey) == 0 {
+		return kb.rr.Balance(msg, partitions...)
+	}
+	h := fnv.New32a()
+	h.Write(partKey)
+	return partitions[h.Sum32()%uint32(len(partitions))]
+}
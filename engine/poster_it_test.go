@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"os"
@@ -28,6 +29,7 @@ import (
 	"time"
 
 	"github.com/cgrates/cgrates/utils"
+	kafka "github.com/segmentio/kafka-go"
 )
 
 type TestContent struct {
@@ -71,3 +73,50 @@ func TestHttpBytesPoster(t *testing.T) {
 		t.Error("Failed removing file: ", filePath)
 	}
 }
+
+// TestKafkaPoster needs a broker listening on localhost:9092 with topics auto-created
+func TestKafkaPoster(t *testing.T) {
+	pstr, err := NewKafkaPoster("localhost:9092?topic=cgrates_cdrs_test&key=~Account", 3, "/tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pstr.Close()
+	content := []byte(`{"Tenant":"cgrates.org","Account":"1001"}`)
+	if err := pstr.Post(content, utils.META_NONE); err != nil {
+		t.Fatal(err)
+	}
+	rdr := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: []string{"localhost:9092"},
+		Topic:   "cgrates_cdrs_test",
+	})
+	defer rdr.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if msg, err := rdr.ReadMessage(ctx); err != nil {
+		t.Error(err)
+	} else if string(msg.Key) != "1001" || !reflect.DeepEqual(content, msg.Value) {
+		t.Errorf("unexpected message, key: %q, value: %q", string(msg.Key), string(msg.Value))
+	}
+}
+
+func TestKafkaPosterFallback(t *testing.T) {
+	pstr, err := NewKafkaPoster("localhost:9091?topic=cgrates_cdrs_test", 1, "/tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pstr.Close()
+	content := []byte(`{"Tenant":"cgrates.org","Account":"1001"}`)
+	fileName := "cgr_test_kafka_poster.json"
+	if err := pstr.Post(content, fileName); err != nil {
+		t.Error(err)
+	}
+	filePath := "/tmp/" + fileName
+	if readBytes, err := ioutil.ReadFile(filePath); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(content, readBytes) {
+		t.Errorf("Expecting: %q, received: %q", string(content), string(readBytes))
+	}
+	if err := os.Remove(filePath); err != nil {
+		t.Error("Failed removing file: ", filePath)
+	}
+}
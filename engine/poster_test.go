@@ -16,3 +16,147 @@ You should have received a copy of the GNU General Public License
 along with this program.  If not, see <http://www.gnu.org/licenses/>
 */
 package engine
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+func TestNewKafkaPoster(t *testing.T) {
+	pstr, err := NewKafkaPoster("localhost:9092", 2, "/tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pstr.topic != "cgrates_cdrs" || len(pstr.key) != 0 ||
+		pstr.balancer.partitionField != "" {
+		t.Errorf("unexpected poster: %+v", pstr)
+	}
+	if pstr, err = NewKafkaPoster(
+		"localhost:9092,localhost:9093?topic=cdrs&key=~Tenant%3B%3A%3B~Account&partition_field=Account",
+		2, "/tmp"); err != nil {
+		t.Fatal(err)
+	}
+	if pstr.topic != "cdrs" || len(pstr.key) != 3 ||
+		pstr.balancer.partitionField != "Account" {
+		t.Errorf("unexpected poster: %+v", pstr)
+	}
+	content := []byte(`{"Tenant":"cgrates.org","Account":"1001","Cost":1.5}`)
+	if key := string(pstr.messageKey(content)); key != "cgrates.org:1001" {
+		t.Errorf("expecting: cgrates.org:1001, received: %s", key)
+	}
+	if key := pstr.messageKey([]byte(`{"Tenant":"cgrates.org"}`)); key != nil {
+		t.Errorf("expecting nil key, received: %s", key)
+	}
+	if _, err := NewKafkaPoster("?topic=cdrs", 2, "/tmp"); err == nil {
+		t.Error("expecting error for missing brokers")
+	}
+}
+
+func TestKafkaBalancer(t *testing.T) {
+	kb := &kafkaBalancer{partitionField: "Account"}
+	partitions := []int{0, 1, 2, 3}
+	msg1 := kafka.Message{Key: []byte("key1"), Value: []byte(`{"Account":"1001","Cost":1}`)}
+	msg2 := kafka.Message{Key: []byte("key2"), Value: []byte(`{"Account":"1001","Cost":2}`)}
+	if p1, p2 := kb.Balance(msg1, partitions...), kb.Balance(msg2, partitions...); p1 != p2 {
+		t.Errorf("same partition field on different partitions: %d, %d", p1, p2)
+	}
+	kb = new(kafkaBalancer) // partition on key
+	msg2.Key = msg1.Key
+	if p1, p2 := kb.Balance(msg1, partitions...), kb.Balance(msg2, partitions...); p1 != p2 {
+		t.Errorf("same key on different partitions: %d, %d", p1, p2)
+	}
+	msg1.Key, msg2.Key = nil, nil // round-robin
+	if p1, p2 := kb.Balance(msg1, partitions...), kb.Balance(msg2, partitions...); p1 == p2 {
+		t.Errorf("messages without key on the same partition: %d", p1)
+	}
+}
+
+func TestKafkaCachedPosters(t *testing.T) {
+	pc := &KafkaCachedPosters{cache: make(map[string]*KafkaPoster)}
+	pstr1, err := pc.GetKafkaPoster("localhost:9092?topic=cdrs", 2, "/tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pstr, err := pc.GetKafkaPoster("localhost:9092?topic=cdrs", 2, "/tmp"); err != nil {
+		t.Error(err)
+	} else if pstr != pstr1 {
+		t.Error("poster not cached")
+	}
+	if pstr, err := pc.GetKafkaPoster("localhost:9092?topic=cdrs", 2, "/var/spool"); err != nil {
+		t.Error(err)
+	} else if pstr == pstr1 || pstr.fallbackFileDir != "/var/spool" {
+		t.Errorf("unexpected poster: %+v", pstr)
+	}
+}
+
+func TestKafkaTopic(t *testing.T) {
+	if topic, err := KafkaTopic("localhost:9092"); err != nil {
+		t.Error(err)
+	} else if topic != "" {
+		t.Errorf("unexpected topic: %s", topic)
+	}
+	if topic, err := KafkaTopic("localhost:9092?key=~Account&topic=accounts"); err != nil {
+		t.Error(err)
+	} else if topic != "accounts" {
+		t.Errorf("expecting: accounts, received: %s", topic)
+	}
+}
+
+type testKafkaWriter struct {
+	fails int // number of writes failing before the first success
+	msgs  []kafka.Message
+}
+
+func (w *testKafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if w.fails > 0 {
+		w.fails--
+		return errors.New("broker unavailable")
+	}
+	w.msgs = append(w.msgs, msgs...)
+	return nil
+}
+
+func (w *testKafkaWriter) Close() error { return nil }
+
+func TestKafkaPosterPost(t *testing.T) {
+	pstr, err := NewKafkaPoster("localhost:9092?key=~Account", 2, os.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrtr := &testKafkaWriter{fails: 1}
+	pstr.writer = wrtr
+	content := []byte(`{"Account":"1001","Cost":1.5}`)
+	if err := pstr.Post(content, utils.META_NONE); err != nil {
+		t.Fatal(err)
+	}
+	if len(wrtr.msgs) != 1 || string(wrtr.msgs[0].Key) != "1001" ||
+		string(wrtr.msgs[0].Value) != string(content) {
+		t.Errorf("unexpected messages: %+v", wrtr.msgs)
+	}
+	pstr.attempts = 1
+	wrtr.fails = 1
+	fallbackFileName := "kafka_poster_test.json"
+	defer os.Remove(path.Join(pstr.fallbackFileDir, fallbackFileName))
+	if err := pstr.Post(content, fallbackFileName); err != nil {
+		t.Fatal(err)
+	}
+	if len(wrtr.msgs) != 1 {
+		t.Errorf("unexpected messages: %+v", wrtr.msgs)
+	}
+	if rcv, err := ioutil.ReadFile(path.Join(pstr.fallbackFileDir, fallbackFileName)); err != nil {
+		t.Error(err)
+	} else if string(rcv) != string(content) {
+		t.Errorf("expecting: %s, received: %s", content, rcv)
+	}
+	wrtr.fails = 1
+	if err := pstr.Post(content, utils.META_NONE); err == nil {
+		t.Error("expecting error without fallback")
+	}
+}
@@ -20,9 +20,11 @@ package engine
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -49,6 +51,8 @@ type ResourceProfile struct {
 	Stored             bool
 	Weight             float64  // Weight to sort the resources
 	ThresholdIDs       []string // Thresholds to check after changing Limit
+	QuotaPeriod        string   // <""|*daily|*weekly|*monthly>, Limit becomes a quota reset at the period boundary, usages are not released and their IDs can be reused within the period
+	QuotaTimezone      string   // timezone of the period boundaries, defaults to the general one
 }
 
 // TenantID returns unique identifier of the ResourceProfile in a multi-tenant environment
@@ -56,6 +60,41 @@ func (rp *ResourceProfile) TenantID() string {
 	return utils.ConcatenatedKey(rp.Tenant, rp.ID)
 }
 
+// CheckQuotaPeriod validates the QuotaPeriod and the QuotaTimezone
+func (rp *ResourceProfile) CheckQuotaPeriod() (err error) {
+	if rp.QuotaPeriod == "" {
+		return
+	}
+	_, err = rp.quotaPeriodEnd(time.Now())
+	return
+}
+
+// quotaPeriodEnd returns the end of the quota period containing atTime
+// weeks start on Monday
+func (rp *ResourceProfile) quotaPeriodEnd(atTime time.Time) (pEnd time.Time, err error) {
+	tz := rp.QuotaTimezone
+	if tz == "" {
+		tz = config.CgrConfig().GeneralCfg().DefaultTimezone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return
+	}
+	atTime = atTime.In(loc)
+	y, m, d := atTime.Date()
+	switch rp.QuotaPeriod {
+	case utils.MetaDaily:
+		pEnd = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	case utils.MetaWeekly:
+		pEnd = time.Date(y, m, d+7-(int(atTime.Weekday())+6)%7, 0, 0, 0, 0, loc)
+	case utils.MetaMonthly:
+		pEnd = time.Date(y, m+1, 1, 0, 0, 0, 0, loc)
+	default:
+		err = fmt.Errorf("unsupported quota period: %s", rp.QuotaPeriod)
+	}
+	return
+}
+
 // ResourceUsage represents an usage counted
 type ResourceUsage struct {
 	Tenant     string
@@ -83,14 +122,15 @@ func (ru *ResourceUsage) Clone() (cln *ResourceUsage) {
 // Resource represents a resource in the system
 // not thread safe, needs locking at process level
 type Resource struct {
-	Tenant string
-	ID     string
-	Usages map[string]*ResourceUsage
-	TTLIdx []string         // holds ordered list of ResourceIDs based on their TTL, empty if feature is disabled
-	ttl    *time.Duration   // time to leave for this resource, picked up on each Resource initialization out of config
-	tUsage *float64         // sum of all usages
-	dirty  *bool            // the usages were modified, needs save, *bool so we only save if enabled in config
-	rPrf   *ResourceProfile // for ordering purposes
+	Tenant         string
+	ID             string
+	Usages         map[string]*ResourceUsage
+	TTLIdx         []string         // holds ordered list of ResourceIDs based on their TTL, empty if feature is disabled
+	QuotaRemaining *float64         // units left out of the quota, populated only on the copies returned by V1ResourcesForEvent
+	ttl            *time.Duration   // time to leave for this resource, picked up on each Resource initialization out of config
+	tUsage         *float64         // sum of all usages
+	dirty          *bool            // the usages were modified, needs save, *bool so we only save if enabled in config
+	rPrf           *ResourceProfile // for ordering purposes
 }
 
 // TenantID returns the unique ID in a multi-tenant environment
@@ -142,12 +182,31 @@ func (r *Resource) totalUsage() (tU float64) {
 	return
 }
 
+// quotaUsageID returns the ID of an usage counted out of a quota,
+// the allocation time is part of it so the UsageID can be reused within the quota period
+func quotaUsageID(ruID string, allocTime time.Time) string {
+	return utils.ConcatenatedKey(ruID, strconv.FormatInt(allocTime.UnixNano(), 10))
+}
+
 // recordUsage records a new usage
 func (r *Resource) recordUsage(ru *ResourceUsage) (err error) {
-	if _, hasID := r.Usages[ru.ID]; hasID {
+	isQuota := r.rPrf != nil && r.rPrf.QuotaPeriod != ""
+	if _, hasID := r.Usages[ru.ID]; hasID && !isQuota {
 		return fmt.Errorf("duplicate resource usage with id: %s", ru.TenantID())
 	}
-	if r.ttl != nil && *r.ttl != -1 {
+	if isQuota { // usages are counted until the end of the quota period
+		allocTime := time.Now()
+		var pEnd time.Time
+		if pEnd, err = r.rPrf.quotaPeriodEnd(allocTime); err != nil {
+			return
+		}
+		ru = ru.Clone()
+		ru.ID = quotaUsageID(ru.ID, allocTime)
+		ru.ExpiryTime = pEnd
+		if _, hasID := r.Usages[ru.ID]; hasID {
+			return fmt.Errorf("duplicate resource usage with id: %s", ru.TenantID())
+		}
+	} else if r.ttl != nil && *r.ttl != -1 {
 		if *r.ttl == 0 {
 			return // no recording for ttl of 0
 		}
@@ -205,15 +264,23 @@ func (rs Resources) recordUsage(ru *ResourceUsage) (err error) {
 	}
 	if err != nil {
 		for _, r := range rs[:nonReservedIdx] {
-			r.clearUsage(ru.ID) // best effort
+			ruID := ru.ID
+			if r.rPrf != nil && r.rPrf.QuotaPeriod != "" { // recorded last, with the allocation time in ID
+				ruID = r.TTLIdx[len(r.TTLIdx)-1]
+			}
+			r.clearUsage(ruID) // best effort
 		}
 	}
 	return
 }
 
 // clearUsage gives back the units to the pool
+// units consumed out of quotas are only given back at the end of the quota period
 func (rs Resources) clearUsage(ruTntID string) (err error) {
 	for _, r := range rs {
+		if r.rPrf != nil && r.rPrf.QuotaPeriod != "" {
+			continue
+		}
 		if errClear := r.clearUsage(ruTntID); errClear != nil &&
 			r.ttl != nil && *r.ttl != 0 { // we only consider not found error in case of ttl different than 0
 			utils.Logger.Warning(fmt.Sprintf("<ResourceLimits>, clear ruID: %s, err: %s", ruTntID, errClear.Error()))
@@ -232,6 +299,33 @@ func (rs Resources) tenantIDs() []*utils.TenantID {
 	return tntIDs
 }
 
+// withQuotaRemaining returns the resources with the units left computed for the ones with QuotaPeriod
+// copies are returned for these since the cached resources are shared between requests
+func (rs Resources) withQuotaRemaining() (qRs Resources) {
+	lockIDs := utils.PrefixSliceItems(rs.tenatIDsStr(), utils.ResourcesPrefix)
+	guardian.Guardian.GuardIDs(config.CgrConfig().GeneralCfg().LockingTimeout, lockIDs...)
+	defer guardian.Guardian.UnguardIDs(lockIDs...)
+	qRs = make(Resources, len(rs))
+	for i, r := range rs {
+		qRs[i] = r
+		if r.rPrf == nil || r.rPrf.QuotaPeriod == "" {
+			continue
+		}
+		r.removeExpiredUnits()
+		qR := &Resource{Tenant: r.Tenant, ID: r.ID,
+			Usages:         make(map[string]*ResourceUsage, len(r.Usages)),
+			TTLIdx:         make([]string, len(r.TTLIdx)),
+			QuotaRemaining: utils.Float64Pointer(math.Max(r.rPrf.Limit-r.totalUsage(), 0)), // Limit could be lowered within the period
+			rPrf:           r.rPrf}
+		for ruID, ru := range r.Usages {
+			qR.Usages[ruID] = ru.Clone()
+		}
+		copy(qR.TTLIdx, r.TTLIdx)
+		qRs[i] = qR
+	}
+	return
+}
+
 func (rs Resources) tenatIDsStr() []string {
 	ids := make([]string, len(rs))
 	for i, r := range rs {
@@ -443,7 +537,7 @@ func (rS *ResourceService) cachedResourcesForEvent(evUUID string) (rs Resources)
 // matchingResourcesForEvent returns ordered list of matching resources which are active by the time of the call
 func (rS *ResourceService) matchingResourcesForEvent(ev *utils.CGREvent, usageTTL *time.Duration) (rs Resources, err error) {
 	matchingResources := make(map[string]*Resource)
-	rIDs, err := matchingItemIDsForEvent(ev.Event, rS.stringIndexedFields, rS.prefixIndexedFields,
+	rIDs, err := MatchingItemIDsForEvent(ev.Event, rS.stringIndexedFields, rS.prefixIndexedFields,
 		rS.dm, utils.CacheResourceFilterIndexes, ev.Tenant, rS.filterS.cfg.FilterSCfg().IndexedSelects)
 	if err != nil {
 		return nil, err
@@ -549,7 +643,7 @@ func (rS *ResourceService) V1ResourcesForEvent(args utils.ArgRSv1ResourceUsage,
 	if len(mtcRLs) == 0 {
 		return utils.ErrNotFound
 	}
-	*reply = mtcRLs
+	*reply = mtcRLs.withQuotaRemaining()
 	return
 }
 
@@ -687,3 +781,51 @@ func (rS *ResourceService) V1ReleaseResource(args utils.ArgRSv1ResourceUsage, re
 	*reply = utils.OK
 	return nil
 }
+
+// ResourceWithConfig is the Resource together with its profile and the computed usage
+type ResourceWithConfig struct {
+	*Resource
+	TotalUsage float64
+	Config     *ResourceProfile
+}
+
+// V1GetResourceWithConfig returns the Resource with its configuration, used when exporting the usage vs limit
+func (rS *ResourceService) V1GetResourceWithConfig(arg utils.TenantID, reply *ResourceWithConfig) (err error) {
+	if missing := utils.MissingStructFields(&arg, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	rPrf, err := rS.dm.GetResourceProfile(arg.Tenant, arg.ID, true, true, utils.NonTransactional)
+	if err != nil {
+		return
+	}
+	lockID := utils.ResourcesPrefix + arg.TenantID()
+	guardian.Guardian.GuardIDs(config.CgrConfig().GeneralCfg().LockingTimeout, lockID)
+	defer guardian.Guardian.UnguardIDs(lockID)
+	r, err := rS.dm.GetResource(arg.Tenant, arg.ID, true, true, utils.NonTransactional)
+	if err != nil {
+		return
+	}
+	// copy the active usages so we do not share the cached resource outside the lock
+	rCln := &Resource{Tenant: r.Tenant, ID: r.ID,
+		Usages: make(map[string]*ResourceUsage), TTLIdx: make([]string, 0, len(r.TTLIdx))}
+	var tUsage float64
+	now := time.Now()
+	for ruID, ru := range r.Usages {
+		if !ru.isActive(now) {
+			continue
+		}
+		rCln.Usages[ruID] = ru.Clone()
+		tUsage += ru.Units
+	}
+	for _, ruID := range r.TTLIdx {
+		if _, has := rCln.Usages[ruID]; has {
+			rCln.TTLIdx = append(rCln.TTLIdx, ruID)
+		}
+	}
+	*reply = ResourceWithConfig{
+		Resource:   rCln,
+		TotalUsage: tUsage,
+		Config:     rPrf,
+	}
+	return
+}
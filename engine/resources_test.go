@@ -19,6 +19,7 @@ package engine
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -722,3 +723,99 @@ func TestResourceMatchWithIndexFalse(t *testing.T) {
 		t.Errorf("Expecting: %+v, received: %+v", resourceTest[2].rPrf, mres[0].rPrf)
 	}
 }
+
+func TestResourceQuotaPeriodEnd(t *testing.T) {
+	rPrf := &ResourceProfile{Tenant: "cgrates.org", ID: "RES_SMS",
+		QuotaTimezone: "Europe/Berlin"}
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	atTime := time.Date(2018, 8, 31, 22, 30, 0, 0, time.UTC) // Saturday, 00:30 in Berlin
+	for period, ePEnd := range map[string]time.Time{
+		utils.MetaDaily:   time.Date(2018, 9, 2, 0, 0, 0, 0, loc),
+		utils.MetaWeekly:  time.Date(2018, 9, 3, 0, 0, 0, 0, loc),
+		utils.MetaMonthly: time.Date(2018, 10, 1, 0, 0, 0, 0, loc),
+	} {
+		rPrf.QuotaPeriod = period
+		if pEnd, err := rPrf.quotaPeriodEnd(atTime); err != nil {
+			t.Error(err)
+		} else if !pEnd.Equal(ePEnd) {
+			t.Errorf("period: %s, expecting: %v, received: %v", period, ePEnd, pEnd)
+		}
+	}
+	rPrf.QuotaPeriod = "*hourly"
+	if _, err := rPrf.quotaPeriodEnd(atTime); err == nil {
+		t.Error("expecting error for unsupported period")
+	}
+}
+
+func TestResourceQuotaUsage(t *testing.T) {
+	rPrf := &ResourceProfile{Tenant: "cgrates.org", ID: "RES_SMS",
+		Limit: 2, QuotaPeriod: utils.MetaDaily, QuotaTimezone: "UTC"}
+	r := &Resource{Tenant: "cgrates.org", ID: "RES_SMS",
+		Usages: make(map[string]*ResourceUsage),
+		ttl:    utils.DurationPointer(0), // quotas do not depend on UsageTTL
+		rPrf:   rPrf}
+	rs := Resources{r}
+	for _, ruID := range []string{"SMS1", "SMS2"} {
+		if _, err := rs.allocateResource(&ResourceUsage{Tenant: "cgrates.org",
+			ID: ruID, Units: 1}, false); err != nil {
+			t.Error(err)
+		}
+	}
+	ePEnd, _ := rPrf.quotaPeriodEnd(time.Now())
+	var sms1 *ResourceUsage
+	for ruID, ru := range r.Usages { // the allocation time is part of the ID
+		if strings.HasPrefix(ruID, "SMS1"+utils.CONCATENATED_KEY_SEP) {
+			sms1 = ru
+		}
+	}
+	if sms1 == nil {
+		t.Error("usage not recorded")
+	} else if !sms1.ExpiryTime.Equal(ePEnd) {
+		t.Errorf("expecting: %v, received: %v", ePEnd, sms1.ExpiryTime)
+	}
+	if err := rs.clearUsage("SMS1"); err != nil {
+		t.Error(err)
+	}
+	if _, err := rs.allocateResource(&ResourceUsage{Tenant: "cgrates.org",
+		ID: "SMS3", Units: 1}, true); err != utils.ErrResourceUnavailable {
+		t.Errorf("expecting: %v, received: %v", utils.ErrResourceUnavailable, err)
+	}
+	if qRs := rs.withQuotaRemaining(); qRs[0] == r {
+		t.Error("expecting a copy of the cached resource")
+	} else if qRs[0].QuotaRemaining == nil || *qRs[0].QuotaRemaining != 0 {
+		t.Errorf("expecting 0 remaining, received: %v", qRs[0].QuotaRemaining)
+	} else if len(qRs[0].Usages) != 2 {
+		t.Errorf("expecting 2 usages, received: %s", utils.ToJSON(qRs[0].Usages))
+	}
+	if r.QuotaRemaining != nil {
+		t.Errorf("cached resource modified: %v", *r.QuotaRemaining)
+	}
+	for _, ru := range r.Usages { // quota period passed
+		ru.ExpiryTime = time.Now().Add(-time.Second)
+	}
+	if qRs := rs.withQuotaRemaining(); *qRs[0].QuotaRemaining != 2 {
+		t.Errorf("expecting 2 remaining, received: %v", *qRs[0].QuotaRemaining)
+	}
+	for i := 0; i < 2; i++ { // UsageID reused within the period
+		if _, err := rs.allocateResource(&ResourceUsage{Tenant: "cgrates.org",
+			ID: "SMS1", Units: 1}, false); err != nil {
+			t.Error(err)
+		}
+	}
+	rPrf.Limit = 1 // lowered within the period
+	if qRs := rs.withQuotaRemaining(); *qRs[0].QuotaRemaining != 0 {
+		t.Errorf("expecting 0 remaining, received: %v", *qRs[0].QuotaRemaining)
+	}
+	rPrf.QuotaPeriod = "*hourly"
+	if err := rPrf.CheckQuotaPeriod(); err == nil {
+		t.Error("expecting error for unsupported period")
+	}
+	rPrf.QuotaPeriod = utils.MetaWeekly
+	rPrf.QuotaTimezone = "Unknown/Zone"
+	if err := rPrf.CheckQuotaPeriod(); err == nil {
+		t.Error("expecting error for unknown timezone")
+	}
+}
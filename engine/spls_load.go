@@ -0,0 +1,88 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func NewLoadDistributionSorter(spS *SupplierService) *LoadDistributionSorter {
+	return &LoadDistributionSorter{spS: spS,
+		sorting: utils.MetaLoadDistribution}
+}
+
+// LoadDistributionSorter orders suppliers so the traffic is spread proportionally to their ratios
+// the load of a supplier is the usage of its ResourceIDs
+type LoadDistributionSorter struct {
+	sorting string
+	spS     *SupplierService
+}
+
+func (ld *LoadDistributionSorter) SortSuppliers(prflID string, suppls []*Supplier,
+	suplEv *utils.CGREvent, extraOpts *optsGetSuppliers) (sortedSuppls *SortedSuppliers, err error) {
+	if ld.spS.resourceS == nil {
+		return nil, utils.NewErrNotConnected(utils.ResourceS)
+	}
+	ratios, err := supplierRatios(extraOpts.sortingParameters)
+	if err != nil {
+		return nil, err
+	}
+	sortedSuppls = &SortedSuppliers{ProfileID: prflID,
+		Sorting:         ld.sorting,
+		SortedSuppliers: make([]*SortedSupplier, 0)}
+	for _, s := range suppls {
+		if srtSpl, pass, err := ld.spS.populateSortingData(suplEv, s, extraOpts); err != nil {
+			return nil, err
+		} else if pass && srtSpl != nil {
+			ratio, has := ratios[s.ID]
+			if !has {
+				ratio = ratios[utils.MetaDefault]
+			}
+			srtSpl.SortingData[utils.Ratio] = ratio
+			if _, has := srtSpl.SortingData[utils.ResourceUsage]; !has { // no ResourceIDs, nothing in use
+				srtSpl.SortingData[utils.ResourceUsage] = 0.0
+			}
+			sortedSuppls.SortedSuppliers = append(sortedSuppls.SortedSuppliers, srtSpl)
+		}
+	}
+	sortedSuppls.SortLoadDistribution()
+	return
+}
+
+// supplierRatios parses the sorting parameters in the format supplierID:ratio
+// *default:ratio applies to the suppliers not listed, defaulting to 1
+func supplierRatios(params []string) (ratios map[string]float64, err error) {
+	ratios = map[string]float64{utils.MetaDefault: 1}
+	for _, param := range params {
+		splRatio := strings.Split(param, utils.InInFieldSep)
+		if len(splRatio) != 2 {
+			return nil, fmt.Errorf("invalid %s sorting parameter: %s",
+				utils.MetaLoadDistribution, param)
+		}
+		if ratios[splRatio[0]], err = strconv.ParseFloat(splRatio[1], 64); err != nil {
+			return nil, fmt.Errorf("invalid %s sorting parameter: %s",
+				utils.MetaLoadDistribution, param)
+		}
+	}
+	return
+}
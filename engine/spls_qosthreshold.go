@@ -0,0 +1,147 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func NewQOSThresholdSorter(spS *SupplierService) *QOSThresholdSorter {
+	return &QOSThresholdSorter{spS: spS,
+		sorting: utils.MetaQOSThreshold}
+}
+
+// QOSThresholdSorter excludes the suppliers with stats breaching the limits
+// and sorts the remaining ones based on their cost
+type QOSThresholdSorter struct {
+	sorting string
+	spS     *SupplierService
+}
+
+func (qt *QOSThresholdSorter) SortSuppliers(prflID string, suppls []*Supplier,
+	ev *utils.CGREvent, extraOpts *optsGetSuppliers) (sortedSuppls *SortedSuppliers, err error) {
+	lmts, err := newQOSLimits(extraOpts.sortingParameters)
+	if err != nil {
+		return nil, err
+	}
+	sortedSuppls = &SortedSuppliers{ProfileID: prflID,
+		Sorting:         qt.sorting,
+		SortedSuppliers: make([]*SortedSupplier, 0)}
+	for _, s := range suppls {
+		srtSpl, pass, err := qt.spS.populateSortingData(ev, s, extraOpts)
+		if err != nil {
+			return nil, err
+		} else if !pass || srtSpl == nil {
+			continue
+		}
+		var reason string
+		if _, has := srtSpl.SortingData[utils.Cost]; !has {
+			reason = "missing cost information"
+		} else {
+			for _, lmt := range lmts {
+				if reason = lmt.breach(srtSpl.SortingData); reason != "" {
+					break
+				}
+			}
+		}
+		if reason != "" {
+			sortedSuppls.ExcludedSuppliers = append(sortedSuppls.ExcludedSuppliers,
+				&ExcludedSupplier{SupplierID: s.ID, Reason: reason})
+			continue
+		}
+		sortedSuppls.SortedSuppliers = append(sortedSuppls.SortedSuppliers, srtSpl)
+	}
+	if len(sortedSuppls.SortedSuppliers) == 0 && len(sortedSuppls.ExcludedSuppliers) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	sortedSuppls.SortLeastCost()
+	return
+}
+
+// newQOSLimits parses the sorting parameters in the format metricID:operator:value
+// operator is one of <*gt|*gte|*lt|*lte>, value is a number or a duration in case of time based metrics
+// ie: *asr:*gte:40 or *pdd:*lte:5s
+func newQOSLimits(params []string) (lmts []*qosLimit, err error) {
+	lmts = make([]*qosLimit, len(params))
+	for i, param := range params {
+		lmtSplt := strings.Split(param, utils.InInFieldSep)
+		if len(lmtSplt) != 3 {
+			return nil, fmt.Errorf("invalid %s sorting parameter: %s",
+				utils.MetaQOSThreshold, param)
+		}
+		switch lmtSplt[1] {
+		case MetaGreaterThan, MetaGreaterOrEqual, MetaLessThan, MetaLessOrEqual:
+		default:
+			return nil, fmt.Errorf("unsupported operator in %s sorting parameter: %s",
+				utils.MetaQOSThreshold, param)
+		}
+		lmts[i] = &qosLimit{metricID: lmtSplt[0], operator: lmtSplt[1]}
+		if lmts[i].value, err = strconv.ParseFloat(lmtSplt[2], 64); err != nil {
+			dur, errDur := utils.ParseDurationWithNanosecs(lmtSplt[2])
+			if errDur != nil {
+				return nil, fmt.Errorf("invalid value in %s sorting parameter: %s",
+					utils.MetaQOSThreshold, param)
+			}
+			lmts[i].value, err = dur.Seconds(), nil // time based metrics are exported in seconds
+		}
+	}
+	return
+}
+
+// qosLimit is the value a supplier metric needs to satisfy in order to be used
+type qosLimit struct {
+	metricID string
+	operator string
+	value    float64
+}
+
+// breach returns the reason for the limit being breached by one of the supplier metrics, empty if not breached
+// metrics without enough data in the queues are not considered
+func (lmt *qosLimit) breach(sortingData map[string]interface{}) (reason string) {
+	for keyWithID, val := range sortingData {
+		if strings.Split(keyWithID, utils.InInFieldSep)[0] != lmt.metricID {
+			continue
+		}
+		fltVal, canCast := val.(float64)
+		if !canCast || fltVal == -1 { // not available
+			continue
+		}
+		var pass bool
+		switch lmt.operator {
+		case MetaGreaterThan:
+			pass = fltVal > lmt.value
+		case MetaGreaterOrEqual:
+			pass = fltVal >= lmt.value
+		case MetaLessThan:
+			pass = fltVal < lmt.value
+		case MetaLessOrEqual:
+			pass = fltVal <= lmt.value
+		}
+		if !pass {
+			return fmt.Sprintf("%s: %s not %s %s", keyWithID,
+				strconv.FormatFloat(fltVal, 'f', -1, 64), lmt.operator,
+				strconv.FormatFloat(lmt.value, 'f', -1, 64))
+		}
+	}
+	return
+}
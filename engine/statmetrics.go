@@ -20,6 +20,8 @@ package engine
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -32,17 +34,23 @@ import (
 // cfg serves as general purpose container to pass config options to metric
 func NewStatMetric(metricID string, minItems int, extraParams string) (sm StatMetric, err error) {
 	metrics := map[string]func(int, string) (StatMetric, error){
-		utils.MetaASR:     NewASR,
-		utils.MetaACD:     NewACD,
-		utils.MetaTCD:     NewTCD,
-		utils.MetaACC:     NewACC,
-		utils.MetaTCC:     NewTCC,
-		utils.MetaPDD:     NewPDD,
-		utils.MetaDDC:     NewDCC,
-		utils.MetaSum:     NewStatSum,
-		utils.MetaAverage: NewStatAverage,
+		utils.MetaASR:      NewASR,
+		utils.MetaACD:      NewACD,
+		utils.MetaTCD:      NewTCD,
+		utils.MetaACC:      NewACC,
+		utils.MetaTCC:      NewTCC,
+		utils.MetaPDD:      NewPDD,
+		utils.MetaDDC:      NewDCC,
+		utils.MetaSum:      NewStatSum,
+		utils.MetaAverage:  NewStatAverage,
+		utils.MetaHighest:  NewStatHighest,
+		utils.MetaLowest:   NewStatLowest,
+		utils.MetaDistinct: NewStatDistinct,
 	}
 	metricType := strings.Split(metricID, utils.InInFieldSep)[0]
+	if pctl, isPctl := percentileFromMetricType(metricType); isPctl {
+		return NewStatPercentile(minItems, extraParams, pctl)
+	}
 	if _, has := metrics[metricType]; !has {
 		return nil, fmt.Errorf("unsupported metric type <%s>", metricType)
 	}
@@ -60,6 +68,152 @@ type StatMetric interface {
 	LoadMarshaled(ms Marshaler, marshaled []byte) (err error)
 }
 
+// StatBucketMetric is implemented by the metrics able to aggregate the events into time buckets,
+// RemEvent with the bucket ID removing all the events aggregated into it
+type StatBucketMetric interface {
+	AddEventToBucket(bktID string, ev *utils.CGREvent) error
+}
+
+// StatBucket aggregates the values of the events received within one time bucket
+type StatBucket struct {
+	Count   int64 // number of events aggregated
+	Sum     float64
+	Highest float64
+	Lowest  float64
+	Values  utils.StringMap // distinct values, populated by the distinct count metrics
+}
+
+// StatBuckets indexes the StatBucket on bucket ID
+type StatBuckets map[string]*StatBucket
+
+// addValue aggregates the value into the bucket with bktID
+func (sbs *StatBuckets) addValue(bktID string, val float64) {
+	if *sbs == nil {
+		*sbs = make(StatBuckets)
+	}
+	bkt, has := (*sbs)[bktID]
+	if !has {
+		(*sbs)[bktID] = &StatBucket{Count: 1, Sum: val, Highest: val, Lowest: val}
+		return
+	}
+	bkt.Count += 1
+	bkt.Sum += val
+	if val > bkt.Highest {
+		bkt.Highest = val
+	}
+	if val < bkt.Lowest {
+		bkt.Lowest = val
+	}
+}
+
+// addString aggregates the value into the bucket with bktID,
+// returns true if the value was not yet part of the bucket
+func (sbs *StatBuckets) addString(bktID, val string) (isNew bool) {
+	if *sbs == nil {
+		*sbs = make(StatBuckets)
+	}
+	bkt, has := (*sbs)[bktID]
+	if !has {
+		bkt = &StatBucket{Values: make(utils.StringMap)}
+		(*sbs)[bktID] = bkt
+	}
+	bkt.Count += 1
+	if !bkt.Values[val] {
+		bkt.Values[val] = true
+		isNew = true
+	}
+	return
+}
+
+// count returns the number of events aggregated into all buckets
+func (sbs StatBuckets) count() (cnt int) {
+	for _, bkt := range sbs {
+		cnt += int(bkt.Count)
+	}
+	return
+}
+
+// StatValues indexes the value of the metric field on event ID,
+// events without the field are never counted
+type StatValues map[string]float64
+
+// addEvent indexes the value of the field out of the event
+func (svs StatValues) addEvent(ev *utils.CGREvent, fldName string) (added bool, err error) {
+	var val float64
+	if val, added, err = statFieldValue(ev, fldName); !added {
+		return
+	}
+	svs[ev.ID] = val
+	return
+}
+
+// remEvent removes the value of the event, returns false if the event was never counted
+func (svs StatValues) remEvent(evID string) (removed bool) {
+	if _, removed = svs[evID]; removed {
+		delete(svs, evID)
+	}
+	return
+}
+
+// statFieldValue returns the value of the field out of the event,
+// has is false for the events without the field
+func statFieldValue(ev *utils.CGREvent, fldName string) (val float64, has bool, err error) {
+	if val, err = statFieldAsFloat64(ev, fldName); err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	return val, true, nil
+}
+
+// statAnswered checks if the event was answered
+func statAnswered(ev *utils.CGREvent) (answered bool, err error) {
+	var at time.Time
+	if at, err = ev.FieldAsTime(utils.AnswerTime,
+		config.CgrConfig().GeneralCfg().DefaultTimezone); err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	return !at.IsZero(), nil
+}
+
+// statAnsweredUsage returns the Usage of the event, 0 if not answered
+func statAnsweredUsage(ev *utils.CGREvent) (usage time.Duration, err error) {
+	var at time.Time
+	if at, err = ev.FieldAsTime(utils.AnswerTime,
+		config.CgrConfig().GeneralCfg().DefaultTimezone); err != nil ||
+		at.IsZero() {
+		return
+	}
+	if usage, err = ev.FieldAsDuration(utils.Usage); err == utils.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
+// statAnsweredCost returns the Cost of the event, 0 if not answered or negative
+func statAnsweredCost(ev *utils.CGREvent) (cost float64, err error) {
+	var at time.Time
+	if at, err = ev.FieldAsTime(utils.AnswerTime,
+		config.CgrConfig().GeneralCfg().DefaultTimezone); err != nil ||
+		at.IsZero() {
+		return
+	}
+	if cost, err = ev.FieldAsFloat64(utils.COST); err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	if cost < 0 {
+		cost = 0
+	}
+	return
+}
+
 func NewASR(minItems int, extraParams string) (StatMetric, error) {
 	return &StatASR{Events: make(map[string]bool), MinItems: minItems}, nil
 }
@@ -69,6 +223,7 @@ type StatASR struct {
 	Answered float64
 	Count    float64
 	Events   map[string]bool // map[EventTenantID]Answered
+	Buckets  StatBuckets     // answered events summed up, in bucketed queues
 	MinItems int
 	val      *float64 // cached ASR value
 }
@@ -76,7 +231,7 @@ type StatASR struct {
 // getValue returns asr.val
 func (asr *StatASR) getValue() float64 {
 	if asr.val == nil {
-		if (asr.MinItems > 0 && len(asr.Events) < asr.MinItems) || (asr.Count == 0) {
+		if (asr.MinItems > 0 && len(asr.Events)+asr.Buckets.count() < asr.MinItems) || (asr.Count == 0) {
 			asr.val = utils.Float64Pointer(STATS_NA)
 		} else {
 			asr.val = utils.Float64Pointer(utils.Round((asr.Answered / asr.Count * 100),
@@ -108,12 +263,8 @@ func (asr *StatASR) GetFloat64Value() (val float64) {
 // AddEvent is part of StatMetric interface
 func (asr *StatASR) AddEvent(ev *utils.CGREvent) (err error) {
 	var answered bool
-	if at, err := ev.FieldAsTime(utils.AnswerTime,
-		config.CgrConfig().GeneralCfg().DefaultTimezone); err != nil &&
-		err != utils.ErrNotFound {
-		return err
-	} else if !at.IsZero() {
-		answered = true
+	if answered, err = statAnswered(ev); err != nil {
+		return
 	}
 	asr.Events[ev.ID] = answered
 	asr.Count += 1
@@ -124,10 +275,27 @@ func (asr *StatASR) AddEvent(ev *utils.CGREvent) (err error) {
 	return
 }
 
+// AddEventToBucket is part of StatBucketMetric interface
+func (asr *StatASR) AddEventToBucket(bktID string, ev *utils.CGREvent) (err error) {
+	var answered bool
+	if answered, err = statAnswered(ev); err != nil {
+		return
+	}
+	if answered {
+		asr.Buckets.addValue(bktID, 1)
+		asr.Answered += 1
+	} else {
+		asr.Buckets.addValue(bktID, 0)
+	}
+	asr.Count += 1
+	asr.val = nil
+	return
+}
+
 func (asr *StatASR) RemEvent(evID string) (err error) {
 	answered, has := asr.Events[evID]
 	if !has {
-		return utils.ErrNotFound
+		return asr.remBucket(evID)
 	}
 	if answered {
 		asr.Answered -= 1
@@ -138,6 +306,19 @@ func (asr *StatASR) RemEvent(evID string) (err error) {
 	return
 }
 
+// remBucket removes the events aggregated into the bucket with bktID
+func (asr *StatASR) remBucket(bktID string) (err error) {
+	bkt, has := asr.Buckets[bktID]
+	if !has {
+		return utils.ErrNotFound
+	}
+	asr.Answered -= bkt.Sum
+	asr.Count -= float64(bkt.Count)
+	delete(asr.Buckets, bktID)
+	asr.val = nil
+	return
+}
+
 // Marshal is part of StatMetric interface
 func (asr *StatASR) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(asr)
@@ -157,6 +338,7 @@ type StatACD struct {
 	Sum      time.Duration
 	Count    int64
 	Events   map[string]time.Duration // map[EventTenantID]Duration
+	Buckets  StatBuckets              // durations summed up in nanoseconds, in bucketed queues
 	MinItems int
 	val      *time.Duration // cached ACD value
 }
@@ -164,7 +346,7 @@ type StatACD struct {
 // getValue returns acr.val
 func (acd *StatACD) getValue() time.Duration {
 	if acd.val == nil {
-		if (acd.MinItems > 0 && len(acd.Events) < acd.MinItems) || (acd.Count == 0) {
+		if (acd.MinItems > 0 && len(acd.Events)+acd.Buckets.count() < acd.MinItems) || (acd.Count == 0) {
 			acd.val = utils.DurationPointer(time.Duration((-1) * time.Nanosecond))
 		} else {
 			acd.val = utils.DurationPointer(time.Duration(acd.Sum.Nanoseconds() / acd.Count))
@@ -197,18 +379,10 @@ func (acd *StatACD) GetFloat64Value() (v float64) {
 
 func (acd *StatACD) AddEvent(ev *utils.CGREvent) (err error) {
 	var value time.Duration
-	if at, err := ev.FieldAsTime(utils.AnswerTime,
-		config.CgrConfig().GeneralCfg().DefaultTimezone); err != nil {
-		return err
-	} else if !at.IsZero() {
-		if duration, err := ev.FieldAsDuration(utils.Usage); err != nil &&
-			err != utils.ErrNotFound {
-			return err
-		} else {
-			value = duration
-			acd.Sum += duration
-		}
+	if value, err = statAnsweredUsage(ev); err != nil {
+		return
 	}
+	acd.Sum += value
 	acd.Events[ev.ID] = value
 	acd.Count += 1
 	acd.val = nil
@@ -218,7 +392,7 @@ func (acd *StatACD) AddEvent(ev *utils.CGREvent) (err error) {
 func (acd *StatACD) RemEvent(evID string) (err error) {
 	duration, has := acd.Events[evID]
 	if !has {
-		return utils.ErrNotFound
+		return acd.remBucket(evID)
 	}
 	if duration != 0 {
 		acd.Sum -= duration
@@ -229,6 +403,32 @@ func (acd *StatACD) RemEvent(evID string) (err error) {
 	return
 }
 
+// AddEventToBucket is part of StatBucketMetric interface
+func (acd *StatACD) AddEventToBucket(bktID string, ev *utils.CGREvent) (err error) {
+	var duration time.Duration
+	if duration, err = statAnsweredUsage(ev); err != nil {
+		return
+	}
+	acd.Buckets.addValue(bktID, float64(duration.Nanoseconds()))
+	acd.Sum += duration
+	acd.Count += 1
+	acd.val = nil
+	return
+}
+
+// remBucket removes the events aggregated into the bucket with bktID
+func (acd *StatACD) remBucket(bktID string) (err error) {
+	bkt, has := acd.Buckets[bktID]
+	if !has {
+		return utils.ErrNotFound
+	}
+	acd.Sum -= time.Duration(bkt.Sum)
+	acd.Count -= bkt.Count
+	delete(acd.Buckets, bktID)
+	acd.val = nil
+	return
+}
+
 func (acd *StatACD) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(acd)
 }
@@ -245,6 +445,7 @@ type StatTCD struct {
 	Sum      time.Duration
 	Count    int64
 	Events   map[string]time.Duration // map[EventTenantID]Duration
+	Buckets  StatBuckets              // durations summed up in nanoseconds, in bucketed queues
 	MinItems int
 	val      *time.Duration // cached TCD value
 }
@@ -252,7 +453,7 @@ type StatTCD struct {
 // getValue returns tcd.val
 func (tcd *StatTCD) getValue() time.Duration {
 	if tcd.val == nil {
-		if (tcd.MinItems > 0 && len(tcd.Events) < tcd.MinItems) || (tcd.Count == 0) {
+		if (tcd.MinItems > 0 && len(tcd.Events)+tcd.Buckets.count() < tcd.MinItems) || (tcd.Count == 0) {
 			tcd.val = utils.DurationPointer(time.Duration((-1) * time.Nanosecond))
 		} else {
 			tcd.val = utils.DurationPointer(time.Duration(tcd.Sum.Nanoseconds()))
@@ -285,19 +486,10 @@ func (tcd *StatTCD) GetFloat64Value() (v float64) {
 
 func (tcd *StatTCD) AddEvent(ev *utils.CGREvent) (err error) {
 	var value time.Duration
-	if at, err := ev.FieldAsTime(utils.AnswerTime,
-		config.CgrConfig().GeneralCfg().DefaultTimezone); err != nil {
-		return err
-	} else if !at.IsZero() {
-		if duration, err := ev.FieldAsDuration(utils.Usage); err != nil &&
-			err != utils.ErrNotFound {
-			return err
-		} else {
-			value = duration
-			tcd.Sum += duration
-		}
-
+	if value, err = statAnsweredUsage(ev); err != nil {
+		return
 	}
+	tcd.Sum += value
 	tcd.Events[ev.ID] = value
 	tcd.Count += 1
 	tcd.val = nil
@@ -307,7 +499,7 @@ func (tcd *StatTCD) AddEvent(ev *utils.CGREvent) (err error) {
 func (tcd *StatTCD) RemEvent(evID string) (err error) {
 	duration, has := tcd.Events[evID]
 	if !has {
-		return utils.ErrNotFound
+		return tcd.remBucket(evID)
 	}
 	if duration != 0 {
 		tcd.Sum -= duration
@@ -318,6 +510,32 @@ func (tcd *StatTCD) RemEvent(evID string) (err error) {
 	return
 }
 
+// AddEventToBucket is part of StatBucketMetric interface
+func (tcd *StatTCD) AddEventToBucket(bktID string, ev *utils.CGREvent) (err error) {
+	var duration time.Duration
+	if duration, err = statAnsweredUsage(ev); err != nil {
+		return
+	}
+	tcd.Buckets.addValue(bktID, float64(duration.Nanoseconds()))
+	tcd.Sum += duration
+	tcd.Count += 1
+	tcd.val = nil
+	return
+}
+
+// remBucket removes the events aggregated into the bucket with bktID
+func (tcd *StatTCD) remBucket(bktID string) (err error) {
+	bkt, has := tcd.Buckets[bktID]
+	if !has {
+		return utils.ErrNotFound
+	}
+	tcd.Sum -= time.Duration(bkt.Sum)
+	tcd.Count -= bkt.Count
+	delete(tcd.Buckets, bktID)
+	tcd.val = nil
+	return
+}
+
 func (tcd *StatTCD) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(tcd)
 }
@@ -335,6 +553,7 @@ type StatACC struct {
 	Sum      float64
 	Count    float64
 	Events   map[string]float64 // map[EventTenantID]Cost
+	Buckets  StatBuckets        // costs summed up, in bucketed queues
 	MinItems int
 	val      *float64 // cached ACC value
 }
@@ -342,7 +561,7 @@ type StatACC struct {
 // getValue returns tcd.val
 func (acc *StatACC) getValue() float64 {
 	if acc.val == nil {
-		if (acc.MinItems > 0 && len(acc.Events) < acc.MinItems) || (acc.Count == 0) {
+		if (acc.MinItems > 0 && len(acc.Events)+acc.Buckets.count() < acc.MinItems) || (acc.Count == 0) {
 			acc.val = utils.Float64Pointer(STATS_NA)
 		} else {
 			acc.val = utils.Float64Pointer(utils.Round((acc.Sum / acc.Count),
@@ -372,18 +591,10 @@ func (acc *StatACC) GetFloat64Value() (v float64) {
 
 func (acc *StatACC) AddEvent(ev *utils.CGREvent) (err error) {
 	var value float64
-	if at, err := ev.FieldAsTime(utils.AnswerTime,
-		config.CgrConfig().GeneralCfg().DefaultTimezone); err != nil {
-		return err
-	} else if !at.IsZero() {
-		if cost, err := ev.FieldAsFloat64(utils.COST); err != nil &&
-			err != utils.ErrNotFound {
-			return err
-		} else if cost >= 0 {
-			value = cost
-			acc.Sum += cost
-		}
+	if value, err = statAnsweredCost(ev); err != nil {
+		return
 	}
+	acc.Sum += value
 	acc.Events[ev.ID] = value
 	acc.Count += 1
 	acc.val = nil
@@ -393,7 +604,7 @@ func (acc *StatACC) AddEvent(ev *utils.CGREvent) (err error) {
 func (acc *StatACC) RemEvent(evID string) (err error) {
 	cost, has := acc.Events[evID]
 	if !has {
-		return utils.ErrNotFound
+		return acc.remBucket(evID)
 	}
 	if cost >= 0 {
 		acc.Sum -= cost
@@ -404,6 +615,32 @@ func (acc *StatACC) RemEvent(evID string) (err error) {
 	return
 }
 
+// AddEventToBucket is part of StatBucketMetric interface
+func (acc *StatACC) AddEventToBucket(bktID string, ev *utils.CGREvent) (err error) {
+	var cost float64
+	if cost, err = statAnsweredCost(ev); err != nil {
+		return
+	}
+	acc.Buckets.addValue(bktID, cost)
+	acc.Sum += cost
+	acc.Count += 1
+	acc.val = nil
+	return
+}
+
+// remBucket removes the events aggregated into the bucket with bktID
+func (acc *StatACC) remBucket(bktID string) (err error) {
+	bkt, has := acc.Buckets[bktID]
+	if !has {
+		return utils.ErrNotFound
+	}
+	acc.Sum -= bkt.Sum
+	acc.Count -= float64(bkt.Count)
+	delete(acc.Buckets, bktID)
+	acc.val = nil
+	return
+}
+
 func (acc *StatACC) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(acc)
 }
@@ -421,6 +658,7 @@ type StatTCC struct {
 	Sum      float64
 	Count    float64
 	Events   map[string]float64 // map[EventTenantID]Cost
+	Buckets  StatBuckets        // costs summed up, in bucketed queues
 	MinItems int
 	val      *float64 // cached TCC value
 }
@@ -428,7 +666,7 @@ type StatTCC struct {
 // getValue returns tcd.val
 func (tcc *StatTCC) getValue() float64 {
 	if tcc.val == nil {
-		if (tcc.MinItems > 0 && len(tcc.Events) < tcc.MinItems) || (tcc.Count == 0) {
+		if (tcc.MinItems > 0 && len(tcc.Events)+tcc.Buckets.count() < tcc.MinItems) || (tcc.Count == 0) {
 			tcc.val = utils.Float64Pointer(STATS_NA)
 		} else {
 			tcc.val = utils.Float64Pointer(utils.Round(tcc.Sum,
@@ -458,18 +696,10 @@ func (tcc *StatTCC) GetFloat64Value() (v float64) {
 
 func (tcc *StatTCC) AddEvent(ev *utils.CGREvent) (err error) {
 	var value float64
-	if at, err := ev.FieldAsTime(utils.AnswerTime,
-		config.CgrConfig().GeneralCfg().DefaultTimezone); err != nil {
-		return err
-	} else if !at.IsZero() {
-		if cost, err := ev.FieldAsFloat64(utils.COST); err != nil &&
-			err != utils.ErrNotFound {
-			return err
-		} else if cost >= 0 {
-			value = cost
-			tcc.Sum += cost
-		}
+	if value, err = statAnsweredCost(ev); err != nil {
+		return
 	}
+	tcc.Sum += value
 	tcc.Events[ev.ID] = value
 	tcc.Count += 1
 	tcc.val = nil
@@ -479,7 +709,7 @@ func (tcc *StatTCC) AddEvent(ev *utils.CGREvent) (err error) {
 func (tcc *StatTCC) RemEvent(evID string) (err error) {
 	cost, has := tcc.Events[evID]
 	if !has {
-		return utils.ErrNotFound
+		return tcc.remBucket(evID)
 	}
 	if cost != 0 {
 		tcc.Sum -= cost
@@ -490,6 +720,32 @@ func (tcc *StatTCC) RemEvent(evID string) (err error) {
 	return
 }
 
+// AddEventToBucket is part of StatBucketMetric interface
+func (tcc *StatTCC) AddEventToBucket(bktID string, ev *utils.CGREvent) (err error) {
+	var cost float64
+	if cost, err = statAnsweredCost(ev); err != nil {
+		return
+	}
+	tcc.Buckets.addValue(bktID, cost)
+	tcc.Sum += cost
+	tcc.Count += 1
+	tcc.val = nil
+	return
+}
+
+// remBucket removes the events aggregated into the bucket with bktID
+func (tcc *StatTCC) remBucket(bktID string) (err error) {
+	bkt, has := tcc.Buckets[bktID]
+	if !has {
+		return utils.ErrNotFound
+	}
+	tcc.Sum -= bkt.Sum
+	tcc.Count -= float64(bkt.Count)
+	delete(tcc.Buckets, bktID)
+	tcc.val = nil
+	return
+}
+
 func (tcc *StatTCC) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(tcc)
 }
@@ -507,6 +763,7 @@ type StatPDD struct {
 	Sum      time.Duration
 	Count    int64
 	Events   map[string]time.Duration // map[EventTenantID]Duration
+	Buckets  StatBuckets              // durations summed up in nanoseconds, in bucketed queues
 	MinItems int
 	val      *time.Duration // cached PDD value
 }
@@ -514,7 +771,7 @@ type StatPDD struct {
 // getValue returns pdd.val
 func (pdd *StatPDD) getValue() time.Duration {
 	if pdd.val == nil {
-		if (pdd.MinItems > 0 && len(pdd.Events) < pdd.MinItems) || (pdd.Count == 0) {
+		if (pdd.MinItems > 0 && len(pdd.Events)+pdd.Buckets.count() < pdd.MinItems) || (pdd.Count == 0) {
 			pdd.val = utils.DurationPointer(time.Duration((-1) * time.Nanosecond))
 		} else {
 			pdd.val = utils.DurationPointer(time.Duration(pdd.Sum.Nanoseconds() / pdd.Count))
@@ -545,21 +802,24 @@ func (pdd *StatPDD) GetFloat64Value() (v float64) {
 	return
 }
 
+// pddFromEvent returns the PDD of the event, 0 if not answered
+func pddFromEvent(ev *utils.CGREvent) (pdd time.Duration, err error) {
+	var answered bool
+	if answered, err = statAnswered(ev); err != nil || !answered {
+		return
+	}
+	if pdd, err = ev.FieldAsDuration(utils.PDD); err == utils.ErrNotFound {
+		err = nil
+	}
+	return
+}
+
 func (pdd *StatPDD) AddEvent(ev *utils.CGREvent) (err error) {
 	var value time.Duration
-	if at, err := ev.FieldAsTime(utils.AnswerTime,
-		config.CgrConfig().GeneralCfg().DefaultTimezone); err != nil &&
-		err != utils.ErrNotFound {
-		return err
-	} else if !at.IsZero() {
-		if duration, err := ev.FieldAsDuration(utils.PDD); err != nil &&
-			err != utils.ErrNotFound {
-			return err
-		} else {
-			value = duration
-			pdd.Sum += duration
-		}
+	if value, err = pddFromEvent(ev); err != nil {
+		return
 	}
+	pdd.Sum += value
 	pdd.Events[ev.ID] = value
 	pdd.Count += 1
 	pdd.val = nil
@@ -569,7 +829,7 @@ func (pdd *StatPDD) AddEvent(ev *utils.CGREvent) (err error) {
 func (pdd *StatPDD) RemEvent(evID string) (err error) {
 	duration, has := pdd.Events[evID]
 	if !has {
-		return utils.ErrNotFound
+		return pdd.remBucket(evID)
 	}
 	if duration != 0 {
 		pdd.Sum -= duration
@@ -580,6 +840,32 @@ func (pdd *StatPDD) RemEvent(evID string) (err error) {
 	return
 }
 
+// AddEventToBucket is part of StatBucketMetric interface
+func (pdd *StatPDD) AddEventToBucket(bktID string, ev *utils.CGREvent) (err error) {
+	var duration time.Duration
+	if duration, err = pddFromEvent(ev); err != nil {
+		return
+	}
+	pdd.Buckets.addValue(bktID, float64(duration.Nanoseconds()))
+	pdd.Sum += duration
+	pdd.Count += 1
+	pdd.val = nil
+	return
+}
+
+// remBucket removes the events aggregated into the bucket with bktID
+func (pdd *StatPDD) remBucket(bktID string) (err error) {
+	bkt, has := pdd.Buckets[bktID]
+	if !has {
+		return utils.ErrNotFound
+	}
+	pdd.Sum -= time.Duration(bkt.Sum)
+	pdd.Count -= bkt.Count
+	delete(pdd.Buckets, bktID)
+	pdd.val = nil
+	return
+}
+
 func (pdd *StatPDD) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(pdd)
 }
@@ -595,11 +881,12 @@ func NewDCC(minItems int, extraParams string) (StatMetric, error) {
 type StatDDC struct {
 	Destinations map[string]utils.StringMap
 	Events       map[string]string // map[EventTenantID]Destination
+	Buckets      StatBuckets       // destinations seen, in bucketed queues
 	MinItems     int
 }
 
 func (ddc *StatDDC) GetStringValue(fmtOpts string) (valStr string) {
-	if val := len(ddc.Destinations); (val == 0) || (ddc.MinItems > 0 && len(ddc.Events) < ddc.MinItems) {
+	if val := len(ddc.Destinations); (val == 0) || (ddc.MinItems > 0 && len(ddc.Events)+ddc.Buckets.count() < ddc.MinItems) {
 		valStr = utils.NOT_AVAILABLE
 	} else {
 		valStr = fmt.Sprintf("%+v", len(ddc.Destinations))
@@ -612,7 +899,7 @@ func (ddc *StatDDC) GetValue() (v interface{}) {
 }
 
 func (ddc *StatDDC) GetFloat64Value() (v float64) {
-	if val := len(ddc.Destinations); (val == 0) || (ddc.MinItems > 0 && len(ddc.Events) < ddc.MinItems) {
+	if val := len(ddc.Destinations); (val == 0) || (ddc.MinItems > 0 && len(ddc.Events)+ddc.Buckets.count() < ddc.MinItems) {
 		v = -1.0
 	} else {
 		v = float64(len(ddc.Destinations))
@@ -636,7 +923,7 @@ func (ddc *StatDDC) AddEvent(ev *utils.CGREvent) (err error) {
 func (ddc *StatDDC) RemEvent(evID string) (err error) {
 	destination, has := ddc.Events[evID]
 	if !has {
-		return utils.ErrNotFound
+		return ddc.remBucket(evID)
 	}
 	delete(ddc.Events, evID)
 	if len(ddc.Destinations[destination]) == 1 {
@@ -647,6 +934,37 @@ func (ddc *StatDDC) RemEvent(evID string) (err error) {
 	return
 }
 
+// AddEventToBucket is part of StatBucketMetric interface
+func (ddc *StatDDC) AddEventToBucket(bktID string, ev *utils.CGREvent) (err error) {
+	var dest string
+	if dest, err = ev.FieldAsString(utils.Destination); err != nil {
+		return err
+	}
+	if ddc.Buckets.addString(bktID, dest) {
+		if _, has := ddc.Destinations[dest]; !has {
+			ddc.Destinations[dest] = make(map[string]bool)
+		}
+		ddc.Destinations[dest][bktID] = true
+	}
+	return
+}
+
+// remBucket removes the events aggregated into the bucket with bktID
+func (ddc *StatDDC) remBucket(bktID string) (err error) {
+	bkt, has := ddc.Buckets[bktID]
+	if !has {
+		return utils.ErrNotFound
+	}
+	for dest := range bkt.Values {
+		delete(ddc.Destinations[dest], bktID)
+		if len(ddc.Destinations[dest]) == 0 {
+			delete(ddc.Destinations, dest)
+		}
+	}
+	delete(ddc.Buckets, bktID)
+	return
+}
+
 func (ddc *StatDDC) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(DDC)
 }
@@ -660,6 +978,7 @@ func NewStatSum(minItems int, extraParams string) (StatMetric, error) {
 type StatSum struct {
 	Sum       float64
 	Events    map[string]float64 // map[EventTenantID]Cost
+	Buckets   StatBuckets        // values summed up, in bucketed queues
 	MinItems  int
 	FieldName string
 	val       *float64 // cached sum value
@@ -668,7 +987,7 @@ type StatSum struct {
 // getValue returns tcd.val
 func (sum *StatSum) getValue() float64 {
 	if sum.val == nil {
-		if len(sum.Events) == 0 || len(sum.Events) < sum.MinItems {
+		if items := len(sum.Events) + sum.Buckets.count(); items == 0 || items < sum.MinItems {
 			sum.val = utils.Float64Pointer(STATS_NA)
 		} else {
 			sum.val = utils.Float64Pointer(utils.Round(sum.Sum,
@@ -713,7 +1032,7 @@ func (sum *StatSum) AddEvent(ev *utils.CGREvent) (err error) {
 func (sum *StatSum) RemEvent(evID string) (err error) {
 	val, has := sum.Events[evID]
 	if !has {
-		return utils.ErrNotFound
+		return sum.remBucket(evID)
 	}
 	if val != 0 {
 		sum.Sum -= val
@@ -723,6 +1042,33 @@ func (sum *StatSum) RemEvent(evID string) (err error) {
 	return
 }
 
+// AddEventToBucket is part of StatBucketMetric interface
+func (sum *StatSum) AddEventToBucket(bktID string, ev *utils.CGREvent) (err error) {
+	var value float64
+	if val, err := ev.FieldAsFloat64(sum.FieldName); err != nil &&
+		err != utils.ErrNotFound {
+		return err
+	} else if val >= 0 {
+		value = val
+	}
+	sum.Buckets.addValue(bktID, value)
+	sum.Sum += value
+	sum.val = nil
+	return
+}
+
+// remBucket removes the events aggregated into the bucket with bktID
+func (sum *StatSum) remBucket(bktID string) (err error) {
+	bkt, has := sum.Buckets[bktID]
+	if !has {
+		return utils.ErrNotFound
+	}
+	sum.Sum -= bkt.Sum
+	delete(sum.Buckets, bktID)
+	sum.val = nil
+	return
+}
+
 func (sum *StatSum) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(sum)
 }
@@ -740,6 +1086,7 @@ type StatAverage struct {
 	Sum       float64
 	Count     float64
 	Events    map[string]float64 // map[EventTenantID]Cost
+	Buckets   StatBuckets        // values summed up, in bucketed queues
 	MinItems  int
 	FieldName string
 	val       *float64 // cached avg value
@@ -748,7 +1095,7 @@ type StatAverage struct {
 // getValue returns tcd.val
 func (avg *StatAverage) getValue() float64 {
 	if avg.val == nil {
-		if (avg.MinItems > 0 && len(avg.Events) < avg.MinItems) || (avg.Count == 0) {
+		if (avg.MinItems > 0 && len(avg.Events)+avg.Buckets.count() < avg.MinItems) || (avg.Count == 0) {
 			avg.val = utils.Float64Pointer(STATS_NA)
 		} else {
 			avg.val = utils.Float64Pointer(utils.Round((avg.Sum / avg.Count),
@@ -794,7 +1141,7 @@ func (avg *StatAverage) AddEvent(ev *utils.CGREvent) (err error) {
 func (avg *StatAverage) RemEvent(evID string) (err error) {
 	val, has := avg.Events[evID]
 	if !has {
-		return utils.ErrNotFound
+		return avg.remBucket(evID)
 	}
 	if avg.Events[avg.FieldName] >= 0 {
 		avg.Sum -= val
@@ -805,6 +1152,33 @@ func (avg *StatAverage) RemEvent(evID string) (err error) {
 	return
 }
 
+// AddEventToBucket is part of StatBucketMetric interface
+func (avg *StatAverage) AddEventToBucket(bktID string, ev *utils.CGREvent) (err error) {
+	if val, err := ev.FieldAsFloat64(avg.FieldName); err != nil &&
+		err != utils.ErrNotFound {
+		return err
+	} else if val > 0 {
+		avg.Buckets.addValue(bktID, val)
+		avg.Sum += val
+		avg.Count += 1
+		avg.val = nil
+	}
+	return
+}
+
+// remBucket removes the events aggregated into the bucket with bktID
+func (avg *StatAverage) remBucket(bktID string) (err error) {
+	bkt, has := avg.Buckets[bktID]
+	if !has {
+		return utils.ErrNotFound
+	}
+	avg.Sum -= bkt.Sum
+	avg.Count -= float64(bkt.Count)
+	delete(avg.Buckets, bktID)
+	avg.val = nil
+	return
+}
+
 func (avg *StatAverage) Marshal(ms Marshaler) (marshaled []byte, err error) {
 	return ms.Marshal(avg)
 }
@@ -812,3 +1186,438 @@ func (avg *StatAverage) Marshal(ms Marshaler) (marshaled []byte, err error) {
 func (avg *StatAverage) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
 	return ms.Unmarshal(marshaled, avg)
 }
+
+// percentileFromMetricType returns the percentile out of metric types like *p95 or *p99.9
+func percentileFromMetricType(metricType string) (pctl float64, isPctl bool) {
+	if !strings.HasPrefix(metricType, utils.MetaPercentile) {
+		return
+	}
+	var err error
+	if pctl, err = strconv.ParseFloat(metricType[len(utils.MetaPercentile):], 64); err != nil ||
+		pctl <= 0 || pctl > 100 {
+		return 0, false
+	}
+	return pctl, true
+}
+
+// statFieldAsFloat64 returns the value of the field as float64, durations being considered in seconds
+func statFieldAsFloat64(ev *utils.CGREvent, fldName string) (val float64, err error) {
+	if val, err = ev.FieldAsFloat64(fldName); err == nil || err == utils.ErrNotFound {
+		return
+	}
+	var dur time.Duration
+	if dur, err = ev.FieldAsDuration(fldName); err != nil {
+		return
+	}
+	return dur.Seconds(), nil
+}
+
+func NewStatPercentile(minItems int, extraParams string, percentile float64) (StatMetric, error) {
+	return &StatPercentile{Events: make(StatValues), MinItems: minItems,
+		FieldName: extraParams, Percentile: percentile}, nil
+}
+
+// StatPercentile implements the percentile metric (nearest-rank method) over the values of one field
+// needing all the values, it cannot be aggregated into time buckets
+type StatPercentile struct {
+	Events     StatValues // map[EventTenantID]Value
+	Percentile float64
+	MinItems   int
+	FieldName  string
+	val        *float64 // cached percentile value
+}
+
+// getValue returns pctl.val
+func (pctl *StatPercentile) getValue() float64 {
+	if pctl.val == nil {
+		if len(pctl.Events) == 0 || len(pctl.Events) < pctl.MinItems {
+			pctl.val = utils.Float64Pointer(STATS_NA)
+		} else {
+			vals := make([]float64, 0, len(pctl.Events))
+			for _, val := range pctl.Events {
+				vals = append(vals, val)
+			}
+			sort.Float64s(vals)
+			rank := int(math.Ceil(pctl.Percentile / 100 * float64(len(vals))))
+			if rank < 1 {
+				rank = 1
+			}
+			pctl.val = utils.Float64Pointer(utils.Round(vals[rank-1],
+				config.CgrConfig().GeneralCfg().RoundingDecimals,
+				utils.ROUNDING_MIDDLE))
+		}
+	}
+	return *pctl.val
+}
+
+func (pctl *StatPercentile) GetStringValue(fmtOpts string) (valStr string) {
+	if val := pctl.getValue(); val == STATS_NA {
+		valStr = utils.NOT_AVAILABLE
+	} else {
+		valStr = strconv.FormatFloat(val, 'f', -1, 64)
+	}
+	return
+}
+
+func (pctl *StatPercentile) GetValue() (v interface{}) {
+	return pctl.getValue()
+}
+
+func (pctl *StatPercentile) GetFloat64Value() (v float64) {
+	return pctl.getValue()
+}
+
+// AddEvent is part of StatMetric interface
+// events without the field are not considered
+func (pctl *StatPercentile) AddEvent(ev *utils.CGREvent) (err error) {
+	var added bool
+	if added, err = pctl.Events.addEvent(ev, pctl.FieldName); added {
+		pctl.val = nil
+	}
+	return
+}
+
+// RemEvent is part of StatMetric interface
+// returns utils.ErrNotFound for the events never counted
+func (pctl *StatPercentile) RemEvent(evID string) (err error) {
+	if !pctl.Events.remEvent(evID) {
+		return utils.ErrNotFound
+	}
+	pctl.val = nil
+	return
+}
+
+func (pctl *StatPercentile) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(pctl)
+}
+
+func (pctl *StatPercentile) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, pctl)
+}
+
+func NewStatHighest(minItems int, extraParams string) (StatMetric, error) {
+	return &StatHighest{Events: make(StatValues), MinItems: minItems, FieldName: extraParams}, nil
+}
+
+// StatHighest implements the highest value of one field
+type StatHighest struct {
+	Events    StatValues  // map[EventTenantID]Value
+	Buckets   StatBuckets // highest value of each bucket, in bucketed queues
+	MinItems  int
+	FieldName string
+	val       *float64 // cached highest value
+}
+
+// getValue returns hst.val
+func (hst *StatHighest) getValue() float64 {
+	if hst.val == nil {
+		if items := len(hst.Events) + hst.Buckets.count(); items == 0 || items < hst.MinItems {
+			hst.val = utils.Float64Pointer(STATS_NA)
+		} else {
+			highest := math.Inf(-1)
+			for _, val := range hst.Events {
+				if val > highest {
+					highest = val
+				}
+			}
+			for _, bkt := range hst.Buckets {
+				if bkt.Highest > highest {
+					highest = bkt.Highest
+				}
+			}
+			hst.val = utils.Float64Pointer(utils.Round(highest,
+				config.CgrConfig().GeneralCfg().RoundingDecimals,
+				utils.ROUNDING_MIDDLE))
+		}
+	}
+	return *hst.val
+}
+
+func (hst *StatHighest) GetStringValue(fmtOpts string) (valStr string) {
+	if val := hst.getValue(); val == STATS_NA {
+		valStr = utils.NOT_AVAILABLE
+	} else {
+		valStr = strconv.FormatFloat(val, 'f', -1, 64)
+	}
+	return
+}
+
+func (hst *StatHighest) GetValue() (v interface{}) {
+	return hst.getValue()
+}
+
+func (hst *StatHighest) GetFloat64Value() (v float64) {
+	return hst.getValue()
+}
+
+// AddEvent is part of StatMetric interface
+// events without the field are not considered
+func (hst *StatHighest) AddEvent(ev *utils.CGREvent) (err error) {
+	var added bool
+	if added, err = hst.Events.addEvent(ev, hst.FieldName); added {
+		hst.val = nil
+	}
+	return
+}
+
+// RemEvent is part of StatMetric interface
+// returns utils.ErrNotFound for the events never counted
+func (hst *StatHighest) RemEvent(evID string) (err error) {
+	if !hst.Events.remEvent(evID) {
+		return hst.remBucket(evID)
+	}
+	hst.val = nil
+	return
+}
+
+// AddEventToBucket is part of StatBucketMetric interface
+// events without the field are not considered
+func (hst *StatHighest) AddEventToBucket(bktID string, ev *utils.CGREvent) (err error) {
+	var val float64
+	var has bool
+	if val, has, err = statFieldValue(ev, hst.FieldName); !has {
+		return
+	}
+	hst.Buckets.addValue(bktID, val)
+	hst.val = nil
+	return
+}
+
+// remBucket removes the events aggregated into the bucket with bktID
+func (hst *StatHighest) remBucket(bktID string) (err error) {
+	if _, has := hst.Buckets[bktID]; !has {
+		return utils.ErrNotFound
+	}
+	delete(hst.Buckets, bktID)
+	hst.val = nil
+	return
+}
+
+func (hst *StatHighest) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(hst)
+}
+
+func (hst *StatHighest) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, hst)
+}
+
+func NewStatLowest(minItems int, extraParams string) (StatMetric, error) {
+	return &StatLowest{Events: make(StatValues), MinItems: minItems, FieldName: extraParams}, nil
+}
+
+// StatLowest implements the lowest value of one field
+type StatLowest struct {
+	Events    StatValues  // map[EventTenantID]Value
+	Buckets   StatBuckets // lowest value of each bucket, in bucketed queues
+	MinItems  int
+	FieldName string
+	val       *float64 // cached lowest value
+}
+
+// getValue returns lst.val
+func (lst *StatLowest) getValue() float64 {
+	if lst.val == nil {
+		if items := len(lst.Events) + lst.Buckets.count(); items == 0 || items < lst.MinItems {
+			lst.val = utils.Float64Pointer(STATS_NA)
+		} else {
+			lowest := math.Inf(1)
+			for _, val := range lst.Events {
+				if val < lowest {
+					lowest = val
+				}
+			}
+			for _, bkt := range lst.Buckets {
+				if bkt.Lowest < lowest {
+					lowest = bkt.Lowest
+				}
+			}
+			lst.val = utils.Float64Pointer(utils.Round(lowest,
+				config.CgrConfig().GeneralCfg().RoundingDecimals,
+				utils.ROUNDING_MIDDLE))
+		}
+	}
+	return *lst.val
+}
+
+func (lst *StatLowest) GetStringValue(fmtOpts string) (valStr string) {
+	if val := lst.getValue(); val == STATS_NA {
+		valStr = utils.NOT_AVAILABLE
+	} else {
+		valStr = strconv.FormatFloat(val, 'f', -1, 64)
+	}
+	return
+}
+
+func (lst *StatLowest) GetValue() (v interface{}) {
+	return lst.getValue()
+}
+
+func (lst *StatLowest) GetFloat64Value() (v float64) {
+	return lst.getValue()
+}
+
+// AddEvent is part of StatMetric interface
+// events without the field are not considered
+func (lst *StatLowest) AddEvent(ev *utils.CGREvent) (err error) {
+	var added bool
+	if added, err = lst.Events.addEvent(ev, lst.FieldName); added {
+		lst.val = nil
+	}
+	return
+}
+
+// RemEvent is part of StatMetric interface
+// returns utils.ErrNotFound for the events never counted
+func (lst *StatLowest) RemEvent(evID string) (err error) {
+	if !lst.Events.remEvent(evID) {
+		return lst.remBucket(evID)
+	}
+	lst.val = nil
+	return
+}
+
+// AddEventToBucket is part of StatBucketMetric interface
+// events without the field are not considered
+func (lst *StatLowest) AddEventToBucket(bktID string, ev *utils.CGREvent) (err error) {
+	var val float64
+	var has bool
+	if val, has, err = statFieldValue(ev, lst.FieldName); !has {
+		return
+	}
+	lst.Buckets.addValue(bktID, val)
+	lst.val = nil
+	return
+}
+
+// remBucket removes the events aggregated into the bucket with bktID
+func (lst *StatLowest) remBucket(bktID string) (err error) {
+	if _, has := lst.Buckets[bktID]; !has {
+		return utils.ErrNotFound
+	}
+	delete(lst.Buckets, bktID)
+	lst.val = nil
+	return
+}
+
+func (lst *StatLowest) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(lst)
+}
+
+func (lst *StatLowest) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, lst)
+}
+
+func NewStatDistinct(minItems int, extraParams string) (StatMetric, error) {
+	return &StatDistinct{FieldValues: make(map[string]utils.StringMap),
+		Events: make(map[string]string), MinItems: minItems, FieldName: extraParams}, nil
+}
+
+// StatDistinct implements the distinct count of the values of one field
+type StatDistinct struct {
+	FieldValues map[string]utils.StringMap // map[FieldValue]map[EventTenantID]bool
+	Events      map[string]string          // map[EventTenantID]FieldValue
+	Buckets     StatBuckets                // values seen, in bucketed queues
+	MinItems    int
+	FieldName   string
+}
+
+// getValue returns the number of distinct values
+func (dst *StatDistinct) getValue() float64 {
+	if len(dst.FieldValues) == 0 || len(dst.Events)+dst.Buckets.count() < dst.MinItems {
+		return STATS_NA
+	}
+	return float64(len(dst.FieldValues))
+}
+
+func (dst *StatDistinct) GetStringValue(fmtOpts string) (valStr string) {
+	if val := dst.getValue(); val == STATS_NA {
+		valStr = utils.NOT_AVAILABLE
+	} else {
+		valStr = strconv.Itoa(len(dst.FieldValues))
+	}
+	return
+}
+
+func (dst *StatDistinct) GetValue() (v interface{}) {
+	return dst.getValue()
+}
+
+func (dst *StatDistinct) GetFloat64Value() (v float64) {
+	return dst.getValue()
+}
+
+// AddEvent is part of StatMetric interface
+// events without the field are not considered
+func (dst *StatDistinct) AddEvent(ev *utils.CGREvent) (err error) {
+	var fldVal string
+	if fldVal, err = ev.FieldAsString(dst.FieldName); err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	if _, has := dst.FieldValues[fldVal]; !has {
+		dst.FieldValues[fldVal] = make(utils.StringMap)
+	}
+	dst.FieldValues[fldVal][ev.ID] = true
+	dst.Events[ev.ID] = fldVal
+	return
+}
+
+// RemEvent is part of StatMetric interface
+// returns utils.ErrNotFound for the events never counted
+func (dst *StatDistinct) RemEvent(evID string) (err error) {
+	fldVal, has := dst.Events[evID]
+	if !has {
+		return dst.remBucket(evID)
+	}
+	delete(dst.Events, evID)
+	delete(dst.FieldValues[fldVal], evID)
+	if len(dst.FieldValues[fldVal]) == 0 {
+		delete(dst.FieldValues, fldVal)
+	}
+	return
+}
+
+// AddEventToBucket is part of StatBucketMetric interface
+// events without the field are not considered
+func (dst *StatDistinct) AddEventToBucket(bktID string, ev *utils.CGREvent) (err error) {
+	var fldVal string
+	if fldVal, err = ev.FieldAsString(dst.FieldName); err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	if dst.Buckets.addString(bktID, fldVal) {
+		if _, has := dst.FieldValues[fldVal]; !has {
+			dst.FieldValues[fldVal] = make(utils.StringMap)
+		}
+		dst.FieldValues[fldVal][bktID] = true
+	}
+	return
+}
+
+// remBucket removes the events aggregated into the bucket with bktID
+func (dst *StatDistinct) remBucket(bktID string) (err error) {
+	bkt, has := dst.Buckets[bktID]
+	if !has {
+		return utils.ErrNotFound
+	}
+	for fldVal := range bkt.Values {
+		delete(dst.FieldValues[fldVal], bktID)
+		if len(dst.FieldValues[fldVal]) == 0 {
+			delete(dst.FieldValues, fldVal)
+		}
+	}
+	delete(dst.Buckets, bktID)
+	return
+}
+
+func (dst *StatDistinct) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(dst)
+}
+
+func (dst *StatDistinct) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, dst)
+}
@@ -18,6 +18,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -1150,3 +1152,242 @@ func TestStatAverageGetStringValue(t *testing.T) {
 		t.Errorf("wrong statAvg value: %s", strVal)
 	}
 }
+
+func TestNewStatMetricPercentile(t *testing.T) {
+	if metric, err := NewStatMetric("*p95:PDD", 0, "PDD"); err != nil {
+		t.Error(err)
+	} else if pctl, canCast := metric.(*StatPercentile); !canCast {
+		t.Errorf("unexpected metric: %T", metric)
+	} else if pctl.Percentile != 95 || pctl.FieldName != "PDD" {
+		t.Errorf("unexpected metric: %+v", pctl)
+	}
+	if metric, err := NewStatMetric("*p99.9", 0, "PDD"); err != nil {
+		t.Error(err)
+	} else if metric.(*StatPercentile).Percentile != 99.9 {
+		t.Errorf("unexpected metric: %+v", metric)
+	}
+	if _, err := NewStatMetric("*pdd", 0, ""); err != nil { // not to be confused with percentiles
+		t.Error(err)
+	}
+	for _, metricID := range []string{"*p0", "*p101", "*px"} {
+		if _, err := NewStatMetric(metricID, 0, "PDD"); err == nil {
+			t.Errorf("expecting error for metric: %s", metricID)
+		}
+	}
+}
+
+func TestStatPercentile(t *testing.T) {
+	pctl, _ := NewStatPercentile(2, "PDD", 95)
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1",
+		Event: map[string]interface{}{"PDD": "1s"}}
+	pctl.AddEvent(ev)
+	if strVal := pctl.GetStringValue(""); strVal != utils.NOT_AVAILABLE {
+		t.Errorf("wrong percentile value: %s", strVal)
+	}
+	for i, pdd := range []interface{}{2 * time.Second, 3.5, "10"} {
+		pctl.AddEvent(&utils.CGREvent{Tenant: "cgrates.org",
+			ID:    fmt.Sprintf("EVENT_%d", i+2),
+			Event: map[string]interface{}{"PDD": pdd}})
+	}
+	pctl.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_NO_PDD"})
+	if strVal := pctl.GetStringValue(""); strVal != "10" {
+		t.Errorf("wrong percentile value: %s", strVal)
+	}
+	if err := pctl.RemEvent("EVENT_4"); err != nil {
+		t.Error(err)
+	}
+	if val := pctl.GetFloat64Value(); val != 3.5 {
+		t.Errorf("wrong percentile value: %v", val)
+	}
+	pctl50, _ := NewStatPercentile(0, "PDD", 50)
+	for _, evID := range []string{"EVENT_1", "EVENT_2", "EVENT_3"} {
+		pctl50.(*StatPercentile).Events[evID] = pctl.(*StatPercentile).Events[evID]
+	}
+	if val := pctl50.GetFloat64Value(); val != 2 {
+		t.Errorf("wrong percentile value: %v", val)
+	}
+	if err := pctl.RemEvent("EVENT_NO_PDD"); err != utils.ErrNotFound { // never counted
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	if val := pctl.GetFloat64Value(); val != 3.5 {
+		t.Errorf("wrong percentile value: %v", val)
+	}
+}
+
+func TestStatHighestLowest(t *testing.T) {
+	hst, _ := NewStatHighest(0, "Cost")
+	lst, _ := NewStatLowest(0, "Cost")
+	if val := hst.GetFloat64Value(); val != STATS_NA {
+		t.Errorf("wrong highest value: %v", val)
+	}
+	for i, cost := range []float64{1.5, 0.2, 7.1} {
+		ev := &utils.CGREvent{Tenant: "cgrates.org",
+			ID:    fmt.Sprintf("EVENT_%d", i+1),
+			Event: map[string]interface{}{"Cost": cost}}
+		hst.AddEvent(ev)
+		lst.AddEvent(ev)
+	}
+	if strVal := hst.GetStringValue(""); strVal != "7.1" {
+		t.Errorf("wrong highest value: %s", strVal)
+	}
+	if strVal := lst.GetStringValue(""); strVal != "0.2" {
+		t.Errorf("wrong lowest value: %s", strVal)
+	}
+	noCost := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_NO_COST"}
+	hst.AddEvent(noCost)
+	lst.AddEvent(noCost)
+	for _, metric := range []StatMetric{hst, lst} {
+		if err := metric.RemEvent(noCost.ID); err != utils.ErrNotFound { // never counted
+			t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+		}
+	}
+	hst.RemEvent("EVENT_3")
+	lst.RemEvent("EVENT_2")
+	if val := hst.GetFloat64Value(); val != 1.5 {
+		t.Errorf("wrong highest value: %v", val)
+	}
+	if val := lst.GetFloat64Value(); val != 1.5 {
+		t.Errorf("wrong lowest value: %v", val)
+	}
+}
+
+func TestStatDistinct(t *testing.T) {
+	dst, _ := NewStatDistinct(3, utils.Account)
+	for i, acnt := range []string{"1001", "1002", "1001"} {
+		dst.AddEvent(&utils.CGREvent{Tenant: "cgrates.org",
+			ID:    fmt.Sprintf("EVENT_%d", i+1),
+			Event: map[string]interface{}{utils.Account: acnt}})
+	}
+	if strVal := dst.GetStringValue(""); strVal != "2" {
+		t.Errorf("wrong distinct value: %s", strVal)
+	}
+	dst.RemEvent("EVENT_1")
+	if val := dst.GetFloat64Value(); val != STATS_NA { // below MinItems
+		t.Errorf("wrong distinct value: %v", val)
+	}
+	dst.(*StatDistinct).MinItems = 0
+	if val := dst.GetFloat64Value(); val != 2 {
+		t.Errorf("wrong distinct value: %v", val)
+	}
+	dst.RemEvent("EVENT_3")
+	if val := dst.GetFloat64Value(); val != 1 {
+		t.Errorf("wrong distinct value: %v", val)
+	}
+	dst.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_NO_ACCOUNT"})
+	if err := dst.RemEvent("EVENT_NO_ACCOUNT"); err != utils.ErrNotFound { // never counted
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestStatMetricsMarshal(t *testing.T) {
+	ms := NewCodecMsgpackMarshaler()
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1",
+		Event: map[string]interface{}{"PDD": "3s", utils.Account: "1001"}}
+	for _, metricID := range []string{"*p95:PDD", "*highest:PDD",
+		"*lowest:PDD", "*distinct:Account"} {
+		param := strings.Split(metricID, utils.InInFieldSep)[1]
+		metric, err := NewStatMetric(metricID, 0, param)
+		if err != nil {
+			t.Fatal(err)
+		}
+		metric.AddEvent(ev)
+		marshaled, err := metric.Marshal(ms)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loaded, _ := NewStatMetric(metricID, 0, "") // same as stored queues load it
+		if err := loaded.LoadMarshaled(ms, marshaled); err != nil {
+			t.Error(err)
+		} else if loaded.GetStringValue("") != metric.GetStringValue("") {
+			t.Errorf("metric: %s, expecting: %s, received: %s", metricID,
+				metric.GetStringValue(""), loaded.GetStringValue(""))
+		} else if err := loaded.RemEvent(ev.ID); err != nil {
+			t.Errorf("metric: %s, error: %v", metricID, err)
+		}
+	}
+}
+
+func TestStatMetricsBuckets(t *testing.T) {
+	ms := NewCodecMsgpackMarshaler()
+	evs := []*utils.CGREvent{
+		{Tenant: "cgrates.org", ID: "EVENT_1",
+			Event: map[string]interface{}{
+				utils.AnswerTime:  time.Now(),
+				utils.Usage:       time.Duration(30 * time.Second),
+				utils.COST:        1.5,
+				utils.PDD:         time.Duration(2 * time.Second),
+				utils.Destination: "1002",
+				utils.Account:     "1001"}},
+		{Tenant: "cgrates.org", ID: "EVENT_2",
+			Event: map[string]interface{}{
+				utils.AnswerTime:  time.Now(),
+				utils.Usage:       time.Duration(90 * time.Second),
+				utils.COST:        2.5,
+				utils.PDD:         time.Duration(4 * time.Second),
+				utils.Destination: "1003",
+				utils.Account:     "1001"}},
+	}
+	for metricID, eVal := range map[string]string{
+		utils.MetaASR:                "100%",
+		utils.MetaACD:                "1m0s",
+		utils.MetaTCD:                "2m0s",
+		utils.MetaACC:                "2",
+		utils.MetaTCC:                "4",
+		utils.MetaPDD:                "3s",
+		utils.MetaDDC:                "2",
+		"*sum:" + utils.COST:         "4",
+		"*average:" + utils.COST:     "2",
+		"*highest:" + utils.COST:     "2.5",
+		"*lowest:" + utils.COST:      "1.5",
+		"*distinct:" + utils.Account: "1",
+	} {
+		var param string
+		if splt := strings.Split(metricID, utils.InInFieldSep); len(splt) == 2 {
+			param = splt[1]
+		}
+		metric, err := NewStatMetric(metricID, 0, param)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bktMetric, canBucket := metric.(StatBucketMetric)
+		if !canBucket {
+			t.Fatalf("metric: %s, not able to aggregate into buckets", metricID)
+		}
+		if err := bktMetric.AddEventToBucket("*bucket:1", evs[0]); err != nil {
+			t.Error(err)
+		}
+		if err := bktMetric.AddEventToBucket("*bucket:2", evs[1]); err != nil {
+			t.Error(err)
+		}
+		if val := metric.GetStringValue(""); val != eVal {
+			t.Errorf("metric: %s, expecting: %s, received: %s", metricID, eVal, val)
+		}
+		marshaled, err := metric.Marshal(ms)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if metricID == utils.MetaDDC { // marshals the metric type only
+			continue
+		}
+		loaded, _ := NewStatMetric(metricID, 0, "")
+		if err := loaded.LoadMarshaled(ms, marshaled); err != nil {
+			t.Error(err)
+		} else if val := loaded.GetStringValue(""); val != eVal {
+			t.Errorf("metric: %s, expecting: %s, received: %s", metricID, eVal, val)
+		}
+		for _, bktID := range []string{"*bucket:1", "*bucket:2"} {
+			if err := loaded.RemEvent(bktID); err != nil {
+				t.Errorf("metric: %s, error: %v", metricID, err)
+			}
+		}
+		if val := loaded.GetStringValue(""); val != utils.NOT_AVAILABLE {
+			t.Errorf("metric: %s, expecting: %s, received: %s", metricID, utils.NOT_AVAILABLE, val)
+		}
+		if err := loaded.RemEvent("*bucket:1"); err != utils.ErrNotFound {
+			t.Errorf("metric: %s, expecting: %v, received: %v", metricID, utils.ErrNotFound, err)
+		}
+	}
+	if _, canBucket := interface{}(new(StatPercentile)).(StatBucketMetric); canBucket {
+		t.Error("percentile should not aggregate into buckets")
+	}
+}
@@ -33,18 +33,24 @@ import (
 
 // NewStatService initializes a StatService
 func NewStatService(dm *DataManager, storeInterval time.Duration,
-	thdS rpcclient.RpcClientConnection, filterS *FilterS, stringIndexedFields, prefixIndexedFields *[]string) (ss *StatService, err error) {
+	thdS rpcclient.RpcClientConnection, storDB CdrStorage, filterS *FilterS,
+	stringIndexedFields, prefixIndexedFields *[]string) (ss *StatService, err error) {
 	if thdS != nil && reflect.ValueOf(thdS).IsNil() { // fix nil value in interface
 		thdS = nil
 	}
+	if storDB != nil && reflect.ValueOf(storDB).IsNil() {
+		storDB = nil
+	}
 	return &StatService{
 		dm:                  dm,
 		storeInterval:       storeInterval,
 		thdS:                thdS,
+		storDB:              storDB,
 		filterS:             filterS,
 		stringIndexedFields: stringIndexedFields,
 		prefixIndexedFields: prefixIndexedFields,
 		storedStatQueues:    make(utils.StringMap),
+		snapStatQueues:      make(utils.StringMap),
 		stopBackup:          make(chan struct{})}, nil
 }
 
@@ -53,17 +59,44 @@ type StatService struct {
 	dm                  *DataManager
 	storeInterval       time.Duration
 	thdS                rpcclient.RpcClientConnection // rpc connection towards ThresholdS
+	storDB              CdrStorage                    // stores the metric snapshots
 	filterS             *FilterS
 	stringIndexedFields *[]string
 	prefixIndexedFields *[]string
 	stopBackup          chan struct{}
 	storedStatQueues    utils.StringMap // keep a record of stats which need saving, map[statsTenantID]bool
 	ssqMux              sync.RWMutex    // protects storedStatQueues
+	snapStatQueues      utils.StringMap // queues with snapshots enabled, map[statsTenantID]bool
+	snapMux             sync.RWMutex    // protects snapStatQueues
+}
+
+// statSnapshotsTick is the resolution of the StatQueue snapshots
+const statSnapshotsTick = time.Second
+
+// HasStatQueueSnapshots checks if any of the StatQueueProfiles is configured with SnapshotInterval
+func HasStatQueueSnapshots(dm *DataManager) (has bool, err error) {
+	keys, err := dm.DataDB().GetKeysForPrefix(utils.StatQueueProfilePrefix)
+	if err != nil {
+		return
+	}
+	for _, key := range keys {
+		tntID := utils.NewTenantID(key[len(utils.StatQueueProfilePrefix):])
+		sqp, err := dm.GetStatQueueProfile(tntID.Tenant, tntID.ID,
+			true, false, utils.NonTransactional)
+		if err != nil {
+			return false, err
+		}
+		if sqp.SnapshotInterval > 0 {
+			return true, nil
+		}
+	}
+	return
 }
 
 // ListenAndServe loops keeps the service alive
 func (sS *StatService) ListenAndServe(exitChan chan bool) error {
 	go sS.runBackup() // start backup loop
+	go sS.runSnapshots()
 	e := <-exitChan
 	exitChan <- e // put back for the others listening for shutdown request
 	return nil
@@ -94,6 +127,51 @@ func (sS *StatService) runBackup() {
 	}
 }
 
+// runSnapshots will regularly store the metric snapshots of the queues into StorDB
+func (sS *StatService) runSnapshots() {
+	if sS.storDB == nil {
+		return
+	}
+	tkr := time.NewTicker(statSnapshotsTick)
+	defer tkr.Stop()
+	for {
+		select {
+		case <-sS.stopBackup:
+			return
+		case t := <-tkr.C:
+			sS.storeSnapshots(t)
+		}
+	}
+}
+
+// storeSnapshots stores the snapshots of the queues whose SnapshotInterval passed
+func (sS *StatService) storeSnapshots(t time.Time) {
+	sS.snapMux.RLock()
+	sqIDs := sS.snapStatQueues.Slice()
+	sS.snapMux.RUnlock()
+	for _, sqID := range sqIDs {
+		lkID := utils.StatQueuePrefix + sqID
+		guardian.Guardian.GuardIDs(config.CgrConfig().GeneralCfg().LockingTimeout, lkID)
+		var sqSnap *StatQueueSnapshot
+		if sqIf, ok := Cache.Get(utils.CacheStatQueues, sqID); !ok || sqIf == nil {
+			sS.snapMux.Lock()
+			delete(sS.snapStatQueues, sqID) // not active anymore
+			sS.snapMux.Unlock()
+		} else {
+			sqSnap = sqIf.(*StatQueue).newSnapshot(t)
+		}
+		guardian.Guardian.UnguardIDs(lkID)
+		if sqSnap == nil {
+			continue
+		}
+		if err := sS.storDB.SetStatQueueSnapshot(sqSnap); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> Queue: %s, storing snapshot, error: %s",
+					utils.StatService, sqID, err.Error()))
+		}
+	}
+}
+
 // storeResources represents one task of complete backup
 func (sS *StatService) storeStats() {
 	var failedSqIDs []string
@@ -151,7 +229,7 @@ func (sS *StatService) matchingStatQueuesForEvent(args *StatsArgsProcessEvent) (
 	if len(args.StatIDs) != 0 {
 		sqIDs = args.StatIDs
 	} else {
-		mapIDs, err := matchingItemIDsForEvent(args.Event, sS.stringIndexedFields, sS.prefixIndexedFields,
+		mapIDs, err := MatchingItemIDsForEvent(args.Event, sS.stringIndexedFields, sS.prefixIndexedFields,
 			sS.dm, utils.CacheStatFilterIndexes, args.Tenant, sS.filterS.cfg.FilterSCfg().IndexedSelects)
 		if err != nil {
 			return nil, err
@@ -244,6 +322,11 @@ func (sS *StatService) processEvent(args *StatsArgsProcessEvent) (statQueueIDs [
 					sq.TenantID(), args.TenantID(), err.Error()))
 			withErrors = true
 		}
+		if sS.storDB != nil && sq.sqPrfl.SnapshotInterval > 0 { // snapshots taken by runSnapshots
+			sS.snapMux.Lock()
+			sS.snapStatQueues[sq.TenantID()] = true
+			sS.snapMux.Unlock()
+		}
 		if sS.storeInterval != 0 && sq.dirty != nil { // don't save
 			if sS.storeInterval == -1 {
 				sS.StoreStatQueue(sq)
@@ -374,6 +457,25 @@ func (sS *StatService) V1GetQueueFloatMetrics(args *utils.TenantID, reply *map[s
 	return
 }
 
+// V1GetQueueHistory returns the metric snapshots of a Queue, ordered by time
+func (sS *StatService) V1GetQueueHistory(args *ArgsGetQueueHistory, reply *[]*StatQueueSnapshot) (err error) {
+	if missing := utils.MissingStructFields(args, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if sS.storDB == nil {
+		return utils.NewErrNotConnected(utils.StorDB)
+	}
+	sqSnaps, err := sS.storDB.GetStatQueueSnapshots(args.Tenant, args.ID, args.From, args.Until)
+	if err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	*reply = sqSnaps
+	return
+}
+
 // V1GetQueueIDs returns list of queueIDs registered for a tenant
 func (sS *StatService) V1GetQueueIDs(tenant string, qIDs *[]string) (err error) {
 	prfx := utils.StatQueuePrefix + tenant + ":"
@@ -153,7 +153,7 @@ func TestStatQueuesPopulateService(t *testing.T) {
 	}
 
 	statService, err = NewStatService(dmSTS, time.Duration(1),
-		nil, &FilterS{dm: dmSTS, cfg: defaultCfg}, nil, nil)
+		nil, nil, &FilterS{dm: dmSTS, cfg: defaultCfg}, nil, nil)
 	if err != nil {
 		t.Errorf("Error: %+v", err)
 	}
@@ -405,3 +405,66 @@ func TestStatQueuesV1ProcessEvent(t *testing.T) {
 		t.Errorf("Expecting: %+v, received: %+v", expected, reply)
 	}
 }
+
+func TestStatQueuesStoreSnapshots(t *testing.T) {
+	data, _ := NewMapStorage()
+	dm := NewDataManager(data)
+	sqPrf := &StatQueueProfile{
+		Tenant:           "cgrates.org",
+		ID:               "SQ_SNAPSHOTS",
+		Metrics:          []*utils.MetricWithParams{{MetricID: utils.MetaSum, Parameters: utils.Usage}},
+		ThresholdIDs:     []string{utils.META_NONE},
+		SnapshotInterval: time.Minute,
+	}
+	if has, err := HasStatQueueSnapshots(dm); err != nil {
+		t.Error(err)
+	} else if has {
+		t.Error("expecting no snapshots without profiles")
+	}
+	if err := dm.SetStatQueueProfile(sqPrf, false); err != nil {
+		t.Error(err)
+	}
+	if has, err := HasStatQueueSnapshots(dm); err != nil {
+		t.Error(err)
+	} else if !has {
+		t.Error("expecting snapshots")
+	}
+	sum, _ := NewStatSum(0, utils.Usage)
+	if err := dm.SetStatQueue(&StatQueue{Tenant: "cgrates.org", ID: "SQ_SNAPSHOTS",
+		SQMetrics: map[string]StatMetric{utils.MetaSum: sum}}); err != nil {
+		t.Error(err)
+	}
+	sq, err := dm.GetStatQueue("cgrates.org", "SQ_SNAPSHOTS", true, true, utils.NonTransactional)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sq.sqPrfl = sqPrf
+	defaultCfg, _ := config.NewDefaultCGRConfig()
+	sS, _ := NewStatService(dm, 0, nil, data, &FilterS{dm: dm, cfg: defaultCfg}, nil, nil)
+	sS.snapStatQueues[sq.TenantID()] = true
+	now := time.Now()
+	sS.storeSnapshots(now)
+	sS.storeSnapshots(now.Add(time.Second)) // before SnapshotInterval passed
+	if sqSnaps, err := data.GetStatQueueSnapshots("cgrates.org", "SQ_SNAPSHOTS", nil, nil); err != nil {
+		t.Error(err)
+	} else if len(sqSnaps) != 1 || !sqSnaps[0].Time.Equal(now) ||
+		sqSnaps[0].Metrics[utils.MetaSum] != STATS_NA {
+		t.Errorf("unexpected snapshots: %s", utils.ToJSON(sqSnaps))
+	}
+	sS.storeSnapshots(now.Add(time.Minute))
+	if sqSnaps, err := data.GetStatQueueSnapshots("cgrates.org", "SQ_SNAPSHOTS",
+		utils.TimePointer(now.Add(time.Second)), nil); err != nil {
+		t.Error(err)
+	} else if len(sqSnaps) != 1 || !sqSnaps[0].Time.Equal(now.Add(time.Minute)) {
+		t.Errorf("unexpected snapshots: %s", utils.ToJSON(sqSnaps))
+	}
+	if _, err := data.GetStatQueueSnapshots("cgrates.org", "SQ_SNAPSHOTS",
+		nil, utils.TimePointer(now)); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	Cache.Remove(utils.CacheStatQueues, sq.TenantID(), true, utils.NonTransactional)
+	sS.storeSnapshots(now.Add(2 * time.Minute))
+	if _, has := sS.snapStatQueues[sq.TenantID()]; has {
+		t.Error("inactive queue not removed from snapshots")
+	}
+}
@@ -1,3 +1,4 @@
+//go:build integration
 // +build integration
 
 /*
@@ -23,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
@@ -45,6 +47,9 @@ func TestITCDRsMySQL(t *testing.T) {
 	if err := testSMCosts(cfg); err != nil {
 		t.Error(err)
 	}
+	if err := testGetCDRsAggregate(cfg); err != nil {
+		t.Error(err)
+	}
 }
 
 func TestITCDRsPSQL(t *testing.T) {
@@ -61,6 +66,9 @@ func TestITCDRsPSQL(t *testing.T) {
 	if err := testSMCosts(cfg); err != nil {
 		t.Error(err)
 	}
+	if err := testGetCDRsAggregate(cfg); err != nil {
+		t.Error(err)
+	}
 }
 
 func TestITCDRsMongo(t *testing.T) {
@@ -77,6 +85,9 @@ func TestITCDRsMongo(t *testing.T) {
 	if err := testSMCosts(cfg); err != nil {
 		t.Error(err)
 	}
+	if err := testGetCDRsAggregate(cfg); err != nil {
+		t.Error(err)
+	}
 }
 
 // helper function to populate CDRs and check if they were stored in storDb
@@ -819,3 +830,77 @@ func testGetCDRs(cfg *config.CGRConfig) error {
 	}
 	return nil
 }
+
+func testGetCDRsAggregate(cfg *config.CGRConfig) error {
+	if err := InitStorDb(cfg); err != nil {
+		return fmt.Errorf("testGetCDRsAggregate #1: %v", err)
+	}
+	cdrStorage, err := ConfigureCdrStorage(cfg.StorDbCfg().StorDBType,
+		cfg.StorDbCfg().StorDBHost, cfg.StorDbCfg().StorDBPort,
+		cfg.StorDbCfg().StorDBName, cfg.StorDbCfg().StorDBUser,
+		cfg.StorDbCfg().StorDBPass, cfg.StorDbCfg().StorDBMaxOpenConns,
+		cfg.StorDbCfg().StorDBMaxIdleConns, cfg.StorDbCfg().StorDBConnMaxLifetime,
+		cfg.StorDbCfg().StorDBCDRSIndexes)
+	if err != nil {
+		return fmt.Errorf("testGetCDRsAggregate #2: %v", err)
+	}
+	groupBy := []*utils.CDRsGroupBy{
+		{Rule: utils.Account, Field: utils.Account},
+		{Rule: "Destination:4", Field: utils.Destination, PrefixLength: 4},
+		{Rule: "AnswerTime:*daily", Field: utils.AnswerTime, Interval: utils.MetaDaily},
+	}
+	if _, err := cdrStorage.GetCDRsAggregate(new(utils.CDRsFilter), groupBy); err == nil ||
+		err.Error() != utils.NotFoundCaps {
+		return fmt.Errorf("testGetCDRsAggregate #3: %v", err)
+	}
+	for i, cdr := range []*CDR{
+		{Account: "1002", Destination: "4986517174963", Usage: time.Minute, Cost: 0.5,
+			AnswerTime: time.Date(2018, 10, 4, 15, 10, 0, 0, time.UTC)},
+		{Account: "1001", Destination: "4986517174964", Usage: 2 * time.Minute, Cost: 1,
+			AnswerTime: time.Date(2018, 10, 4, 16, 10, 0, 0, time.UTC)},
+		{Account: "1001", Destination: "4986517174965", Usage: 30 * time.Second, Cost: -1,
+			AnswerTime: time.Date(2018, 10, 4, 23, 59, 0, 0, time.UTC)},
+		{Account: "1001", Destination: "4915117174963", Usage: 10 * time.Second, Cost: 0.25,
+			AnswerTime: time.Date(2018, 10, 5, 0, 0, 0, 0, time.UTC)},
+	} {
+		cdr.CGRID = utils.Sha1("testGetCDRsAggregate", strconv.Itoa(i))
+		cdr.RunID = utils.MetaRaw
+		cdr.OrderID = int64(i + 1)
+		cdr.OriginHost = "127.0.0.1"
+		cdr.Source = "testGetCDRsAggregate"
+		cdr.OriginID = "testGetCDRsAggregate" + strconv.Itoa(i)
+		cdr.ToR = utils.VOICE
+		cdr.RequestType = utils.META_PREPAID
+		cdr.Tenant = "cgrates.org"
+		cdr.Category = "call"
+		cdr.Subject = cdr.Account
+		cdr.SetupTime = cdr.AnswerTime
+		if err := cdrStorage.SetCDR(cdr, false); err != nil {
+			return fmt.Errorf("testGetCDRsAggregate #4 CDR: %+v, err: %v", cdr, err)
+		}
+	}
+	eAggrs := []*CDRsAggregate{
+		{Group: map[string]string{utils.Account: "1001", "Destination:4": "4915", "AnswerTime:*daily": "2018-10-05"},
+			Count: 1, TotalUsage: 10 * time.Second, TotalCost: 0.25},
+		{Group: map[string]string{utils.Account: "1001", "Destination:4": "4986", "AnswerTime:*daily": "2018-10-04"},
+			Count: 2, TotalUsage: 2*time.Minute + 30*time.Second, TotalCost: 1},
+		{Group: map[string]string{utils.Account: "1002", "Destination:4": "4986", "AnswerTime:*daily": "2018-10-04"},
+			Count: 1, TotalUsage: time.Minute, TotalCost: 0.5},
+	}
+	if aggrs, err := cdrStorage.GetCDRsAggregate(new(utils.CDRsFilter), groupBy); err != nil {
+		return fmt.Errorf("testGetCDRsAggregate #5: %v", err)
+	} else if !reflect.DeepEqual(eAggrs, aggrs) {
+		return fmt.Errorf("testGetCDRsAggregate #6, expecting: %s, received: %s",
+			utils.ToJSON(eAggrs), utils.ToJSON(aggrs))
+	}
+	// Filtered and paginated
+	if aggrs, err := cdrStorage.GetCDRsAggregate(&utils.CDRsFilter{Accounts: []string{"1001"},
+		Paginator: utils.Paginator{Limit: utils.IntPointer(1), Offset: utils.IntPointer(1)}},
+		groupBy); err != nil {
+		return fmt.Errorf("testGetCDRsAggregate #7: %v", err)
+	} else if !reflect.DeepEqual(eAggrs[1:2], aggrs) {
+		return fmt.Errorf("testGetCDRsAggregate #8, expecting: %s, received: %s",
+			utils.ToJSON(eAggrs[1:2]), utils.ToJSON(aggrs))
+	}
+	return nil
+}
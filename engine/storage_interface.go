@@ -24,6 +24,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 	"github.com/cgrates/mgo/bson"
@@ -149,6 +150,9 @@ type DataDB interface {
 	GetChargerProfileDrv(string, string) (*ChargerProfile, error)
 	SetChargerProfileDrv(*ChargerProfile) error
 	RemoveChargerProfileDrv(string, string) error
+	GetDispatcherProfileDrv(string, string) (*DispatcherProfile, error)
+	SetDispatcherProfileDrv(*DispatcherProfile) error
+	RemoveDispatcherProfileDrv(string, string) error
 }
 
 type StorDB interface {
@@ -164,6 +168,9 @@ type CdrStorage interface {
 	GetSMCosts(cgrid, runid, originHost, originIDPrfx string) ([]*SMCost, error)
 	RemoveSMCost(*SMCost) error
 	GetCDRs(*utils.CDRsFilter, bool) ([]*CDR, int64, error)
+	GetCDRsAggregate(*utils.CDRsFilter, []*utils.CDRsGroupBy) ([]*CDRsAggregate, error)
+	SetStatQueueSnapshot(sqSnap *StatQueueSnapshot) error
+	GetStatQueueSnapshots(tenant, id string, from, until *time.Time) ([]*StatQueueSnapshot, error)
 }
 
 type LoadStorage interface {
@@ -200,6 +207,7 @@ type LoadReader interface {
 	GetTPSuppliers(string, string) ([]*utils.TPSupplierProfile, error)
 	GetTPAttributes(string, string) ([]*utils.TPAttributeProfile, error)
 	GetTPChargers(string, string) ([]*utils.TPChargerProfile, error)
+	GetTPDispatchers(string, string) ([]*utils.TPDispatcherProfile, error)
 }
 
 type LoadWriter interface {
@@ -227,6 +235,7 @@ type LoadWriter interface {
 	SetTPSuppliers([]*utils.TPSupplierProfile) error
 	SetTPAttributes([]*utils.TPAttributeProfile) error
 	SetTPChargers([]*utils.TPChargerProfile) error
+	SetTPDispatchers([]*utils.TPDispatcherProfile) error
 }
 
 // NewMarshaler returns the marshaler type selected by mrshlerStr
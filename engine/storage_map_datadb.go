@@ -25,6 +25,7 @@ import (
 	"io/ioutil"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
@@ -36,6 +37,7 @@ type MapStorage struct {
 	ms       Marshaler
 	mu       sync.RWMutex
 	cacheCfg config.CacheCfg
+	cnter    *utils.Counter
 }
 
 type storage map[string][]byte
@@ -71,7 +73,8 @@ func (s storage) smembers(key string, ms Marshaler) (idMap utils.StringMap, ok b
 
 func NewMapStorage() (*MapStorage, error) {
 	return &MapStorage{dict: make(map[string][]byte), ms: NewCodecMsgpackMarshaler(),
-		cacheCfg: config.CgrConfig().CacheCfg()}, nil
+		cacheCfg: config.CgrConfig().CacheCfg(),
+		cnter:    utils.NewCounter(time.Now().UnixNano(), 0)}, nil
 }
 
 func NewMapStorageJson() (mpStorage *MapStorage, err error) {
@@ -222,7 +225,8 @@ func (ms *MapStorage) HasDataDrv(category, subject, tenant string) (bool, error)
 		return exists, nil
 	case utils.ResourcesPrefix, utils.ResourceProfilesPrefix, utils.StatQueuePrefix,
 		utils.StatQueueProfilePrefix, utils.ThresholdPrefix, utils.ThresholdProfilePrefix,
-		utils.FilterPrefix, utils.SupplierProfilePrefix, utils.AttributeProfilePrefix, utils.ChargerProfilePrefix:
+		utils.FilterPrefix, utils.SupplierProfilePrefix, utils.AttributeProfilePrefix, utils.ChargerProfilePrefix,
+		utils.DispatcherProfilePrefix:
 		_, exists := ms.dict[category+utils.ConcatenatedKey(tenant, subject)]
 		return exists, nil
 	}
@@ -1632,6 +1636,39 @@ func (ms *MapStorage) RemoveChargerProfileDrv(tenant, id string) (err error) {
 	return
 }
 
+func (ms *MapStorage) GetDispatcherProfileDrv(tenant, id string) (r *DispatcherProfile, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.DispatcherProfilePrefix+utils.ConcatenatedKey(tenant, id)]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &r)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (ms *MapStorage) SetDispatcherProfileDrv(r *DispatcherProfile) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	result, err := ms.ms.Marshal(r)
+	if err != nil {
+		return err
+	}
+	ms.dict[utils.DispatcherProfilePrefix+utils.ConcatenatedKey(r.Tenant, r.ID)] = result
+	return
+}
+
+func (ms *MapStorage) RemoveDispatcherProfileDrv(tenant, id string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	key := utils.DispatcherProfilePrefix + utils.ConcatenatedKey(tenant, id)
+	delete(ms.dict, key)
+	return
+}
+
 func (ms *MapStorage) GetVersions(itm string) (vrs Versions, err error) {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
@@ -19,6 +19,12 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -100,6 +106,9 @@ func (ms *MapStorage) GetTPAttributes(tpid, id string) (attrs []*utils.TPAttribu
 func (ms *MapStorage) GetTPChargers(tpid, id string) (attrs []*utils.TPChargerProfile, err error) {
 	return nil, utils.ErrNotImplemented
 }
+func (ms *MapStorage) GetTPDispatchers(tpid, id string) (attrs []*utils.TPDispatcherProfile, err error) {
+	return nil, utils.ErrNotImplemented
+}
 
 //implement LoadWriter interface
 func (ms *MapStorage) RemTpData(table, tpid string, args map[string]string) (err error) {
@@ -176,18 +185,300 @@ func (ms *MapStorage) SetTPAttributes(attributes []*utils.TPAttributeProfile) (e
 func (ms *MapStorage) SetTPChargers(attributes []*utils.TPChargerProfile) (err error) {
 	return utils.ErrNotImplemented
 }
+func (ms *MapStorage) SetTPDispatchers(dpps []*utils.TPDispatcherProfile) (err error) {
+	return utils.ErrNotImplemented
+}
 
 //implement CdrStorage interface
 func (ms *MapStorage) SetCDR(cdr *CDR, allowUpdate bool) (err error) {
-	return utils.ErrNotImplemented
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	key := utils.ConcatenatedKey(utils.CDRsTBL, cdr.CGRID, cdr.RunID, cdr.OriginID)
+	if _, has := ms.dict[key]; has && !allowUpdate {
+		return utils.ErrExists
+	}
+	if cdr.OrderID == 0 {
+		cdr.OrderID = ms.cnter.Next()
+	}
+	result, err := ms.ms.Marshal(cdr)
+	if err != nil {
+		return
+	}
+	ms.dict[key] = result
+	return
 }
 func (ms *MapStorage) RemoveSMCost(smc *SMCost) (err error) {
 	return utils.ErrNotImplemented
 }
 func (ms *MapStorage) GetCDRs(filter *utils.CDRsFilter, remove bool) (cdrs []*CDR, count int64, err error) {
-	return nil, 0, utils.ErrNotImplemented
+	var minUsage, maxUsage *time.Duration
+	if len(filter.MinUsage) != 0 {
+		var parsed time.Duration
+		if parsed, err = utils.ParseDurationWithNanosecs(filter.MinUsage); err != nil {
+			return nil, 0, err
+		}
+		minUsage = &parsed
+	}
+	if len(filter.MaxUsage) != 0 {
+		var parsed time.Duration
+		if parsed, err = utils.ParseDurationWithNanosecs(filter.MaxUsage); err != nil {
+			return nil, 0, err
+		}
+		maxUsage = &parsed
+	}
+	cdrsLess, err := cdrsOrderBy(filter.OrderBy)
+	if err != nil {
+		return nil, 0, err
+	}
+	prfx := utils.CDRsTBL + utils.CONCATENATED_KEY_SEP
+	if remove {
+		ms.mu.Lock()
+		defer ms.mu.Unlock()
+	} else {
+		ms.mu.RLock()
+		defer ms.mu.RUnlock()
+	}
+	for key, values := range ms.dict {
+		if !strings.HasPrefix(key, prfx) {
+			continue
+		}
+		var cdr *CDR
+		if err = ms.ms.Unmarshal(values, &cdr); err != nil {
+			return nil, 0, err
+		}
+		if !cdrMatchesFilter(cdr, filter, minUsage, maxUsage) {
+			continue
+		}
+		if remove {
+			delete(ms.dict, key)
+			count++
+			continue
+		}
+		cdrs = append(cdrs, cdr)
+	}
+	if remove {
+		return nil, count, nil
+	}
+	sort.SliceStable(cdrs, func(i, j int) bool {
+		return cdrsLess(cdrs[i], cdrs[j])
+	})
+	if filter.Paginator.Offset != nil && *filter.Paginator.Offset > 0 {
+		if *filter.Paginator.Offset >= len(cdrs) {
+			cdrs = nil
+		} else {
+			cdrs = cdrs[*filter.Paginator.Offset:]
+		}
+	}
+	if filter.Paginator.Limit != nil && *filter.Paginator.Limit > 0 &&
+		*filter.Paginator.Limit < len(cdrs) {
+		cdrs = cdrs[:*filter.Paginator.Limit]
+	}
+	if filter.Count {
+		return nil, int64(len(cdrs)), nil
+	}
+	if len(cdrs) == 0 {
+		return nil, 0, utils.ErrNotFound
+	}
+	return
+}
+
+// cdrsOrderBy returns the comparison used to sort the CDRs on orderBy, defaulting to OrderID
+func cdrsOrderBy(orderBy string) (less func(cdrI, cdrJ *CDR) bool, err error) {
+	fld := utils.OrderID
+	var desc bool
+	if orderBy != "" {
+		separateVals := strings.Split(orderBy, utils.INFIELD_SEP)
+		fld = separateVals[0]
+		desc = len(separateVals) == 2 && separateVals[1] == "desc"
+	}
+	switch fld {
+	case utils.OrderID:
+		less = func(cdrI, cdrJ *CDR) bool { return cdrI.OrderID < cdrJ.OrderID }
+	case utils.AnswerTime:
+		less = func(cdrI, cdrJ *CDR) bool { return cdrI.AnswerTime.Before(cdrJ.AnswerTime) }
+	case utils.SetupTime:
+		less = func(cdrI, cdrJ *CDR) bool { return cdrI.SetupTime.Before(cdrJ.SetupTime) }
+	case utils.Usage:
+		less = func(cdrI, cdrJ *CDR) bool { return cdrI.Usage < cdrJ.Usage }
+	case utils.Cost:
+		less = func(cdrI, cdrJ *CDR) bool { return cdrI.Cost < cdrJ.Cost }
+	default:
+		return nil, fmt.Errorf("Invalid value : %s", fld)
+	}
+	if desc {
+		asc := less
+		less = func(cdrI, cdrJ *CDR) bool { return asc(cdrJ, cdrI) }
+	}
+	return
+}
+
+// matchesStrings checks val against the included and excluded values of a CDRsFilter
+func matchesStrings(val string, in, notIn []string) bool {
+	return (len(in) == 0 || utils.IsSliceMember(in, val)) &&
+		!utils.IsSliceMember(notIn, val)
+}
+
+// matchesTimeInterval checks if t is within [start, end)
+func matchesTimeInterval(t time.Time, start, end *time.Time) bool {
+	return (start == nil || !t.Before(*start)) &&
+		(end == nil || t.Before(*end))
+}
+
+// cdrMatchesFilter applies in memory the rules of the CDRsFilter
+// CreatedAt and UpdatedAt are not kept on the CDR so they are not checked
+func cdrMatchesFilter(cdr *CDR, filter *utils.CDRsFilter,
+	minUsage, maxUsage *time.Duration) bool {
+	if !matchesStrings(cdr.CGRID, filter.CGRIDs, filter.NotCGRIDs) ||
+		!matchesStrings(cdr.RunID, filter.RunIDs, filter.NotRunIDs) ||
+		!matchesStrings(cdr.OriginID, filter.OriginIDs, filter.NotOriginIDs) ||
+		!matchesStrings(cdr.OriginHost, filter.OriginHosts, filter.NotOriginHosts) ||
+		!matchesStrings(cdr.Source, filter.Sources, filter.NotSources) ||
+		!matchesStrings(cdr.ToR, filter.ToRs, filter.NotToRs) ||
+		!matchesStrings(cdr.RequestType, filter.RequestTypes, filter.NotRequestTypes) ||
+		!matchesStrings(cdr.Tenant, filter.Tenants, filter.NotTenants) ||
+		!matchesStrings(cdr.Category, filter.Categories, filter.NotCategories) ||
+		!matchesStrings(cdr.Account, filter.Accounts, filter.NotAccounts) ||
+		!matchesStrings(cdr.Subject, filter.Subjects, filter.NotSubjects) {
+		return false
+	}
+	if (filter.OrderIDStart != nil && cdr.OrderID < *filter.OrderIDStart) ||
+		(filter.OrderIDEnd != nil && cdr.OrderID >= *filter.OrderIDEnd) {
+		return false
+	}
+	if !matchesTimeInterval(cdr.SetupTime, filter.SetupTimeStart, filter.SetupTimeEnd) ||
+		!matchesTimeInterval(cdr.AnswerTime, filter.AnswerTimeStart, filter.AnswerTimeEnd) {
+		return false
+	}
+	if (minUsage != nil && cdr.Usage < *minUsage) ||
+		(maxUsage != nil && cdr.Usage >= *maxUsage) {
+		return false
+	}
+	if len(filter.DestinationPrefixes) != 0 {
+		var hasPrfx bool
+		for _, prfx := range filter.DestinationPrefixes {
+			if prfx != "" && strings.HasPrefix(cdr.Destination, prfx) {
+				hasPrfx = true
+				break
+			}
+		}
+		if !hasPrfx {
+			return false
+		}
+	}
+	for _, prfx := range filter.NotDestinationPrefixes {
+		if prfx != "" && strings.HasPrefix(cdr.Destination, prfx) {
+			return false
+		}
+	}
+	for fld, val := range filter.ExtraFields {
+		cdrVal, has := cdr.ExtraFields[fld]
+		if !has || (val != utils.MetaExists && cdrVal != val) {
+			return false
+		}
+	}
+	for fld, val := range filter.NotExtraFields {
+		cdrVal, has := cdr.ExtraFields[fld]
+		if has && (val == utils.MetaExists || cdrVal == val) {
+			return false
+		}
+	}
+	if filter.MinCost != nil {
+		if filter.MaxCost == nil {
+			return cdr.Cost >= *filter.MinCost
+		}
+		if *filter.MinCost == 0.0 && *filter.MaxCost == -1.0 { // Special case when we want to skip errors
+			return cdr.Cost >= 0.0
+		}
+		return cdr.Cost >= *filter.MinCost && cdr.Cost < *filter.MaxCost
+	}
+	if filter.MaxCost != nil {
+		if *filter.MaxCost == -1.0 { // Non-rated CDRs
+			return cdr.Cost == 0.0
+		}
+		return cdr.Cost < *filter.MaxCost
+	}
+	return true
+}
+
+// GetCDRsAggregate summarizes in memory the CDRs matching filter, paginating the groups
+func (ms *MapStorage) GetCDRsAggregate(filter *utils.CDRsFilter,
+	groupBy []*utils.CDRsGroupBy) (aggrs []*CDRsAggregate, err error) {
+	cdrsFltr := *filter
+	cdrsFltr.Count = false
+	cdrsFltr.Paginator = utils.Paginator{}
+	cdrs, _, err := ms.GetCDRs(&cdrsFltr, false)
+	if err != nil {
+		return nil, err
+	}
+	if aggrs = paginateCDRsAggregates(
+		aggregateCDRs(cdrs, groupBy), filter.Paginator); len(aggrs) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	return
 }
 
 func (ms *MapStorage) GetSMCosts(cgrid, runid, originHost, originIDPrfx string) (smCosts []*SMCost, err error) {
-	return nil, utils.ErrNotImplemented
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	for key, values := range ms.dict {
+		if !strings.HasPrefix(key, utils.LOG_CALL_COST_PREFIX) {
+			continue
+		}
+		var smCost *SMCost
+		if err = ms.ms.Unmarshal(values, &smCost); err != nil {
+			return nil, err
+		}
+		if (cgrid != "" && smCost.CGRID != cgrid) ||
+			(runid != "" && smCost.RunID != runid) ||
+			(originHost != "" && smCost.OriginHost != originHost) ||
+			!strings.HasPrefix(smCost.OriginID, originIDPrfx) {
+			continue
+		}
+		smCosts = append(smCosts, smCost)
+	}
+	if len(smCosts) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
+func (ms *MapStorage) SetStatQueueSnapshot(sqSnap *StatQueueSnapshot) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	result, err := ms.ms.Marshal(sqSnap)
+	if err != nil {
+		return
+	}
+	ms.dict[utils.ConcatenatedKey(utils.StatQueueSnapshotsTBL, sqSnap.Tenant, sqSnap.ID,
+		strconv.FormatInt(sqSnap.Time.UnixNano(), 10))] = result
+	return
+}
+
+// GetStatQueueSnapshots returns the snapshots of one StatQueue within [from, until), ordered by time
+func (ms *MapStorage) GetStatQueueSnapshots(tenant, id string,
+	from, until *time.Time) (sqSnaps []*StatQueueSnapshot, err error) {
+	prfx := utils.ConcatenatedKey(utils.StatQueueSnapshotsTBL, tenant, id) + utils.CONCATENATED_KEY_SEP
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	for key, values := range ms.dict {
+		if !strings.HasPrefix(key, prfx) {
+			continue
+		}
+		var sqSnap *StatQueueSnapshot
+		if err = ms.ms.Unmarshal(values, &sqSnap); err != nil {
+			return nil, err
+		}
+		if (from != nil && sqSnap.Time.Before(*from)) ||
+			(until != nil && !sqSnap.Time.Before(*until)) {
+			continue
+		}
+		sqSnaps = append(sqSnaps, sqSnap)
+	}
+	if len(sqSnaps) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	sort.Slice(sqSnaps, func(i, j int) bool {
+		return sqSnaps[i].Time.Before(sqSnaps[j].Time)
+	})
+	return
 }
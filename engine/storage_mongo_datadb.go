@@ -69,6 +69,7 @@ const (
 	colAttr  = "attribute_profiles"
 	ColCDRs  = "cdrs"
 	colCpp   = "charger_profiles"
+	colDpp   = "dispatcher_profiles"
 )
 
 var (
@@ -174,7 +175,7 @@ func (ms *MongoStorage) EnsureIndexes() (err error) {
 			Sparse:     false,
 		}
 		for _, col := range []string{colRsP, colRes, colSqs, colSqp,
-			colTps, colThs, colSpp, colAttr, colFlt, colCpp} {
+			colTps, colThs, colSpp, colAttr, colFlt, colCpp, colDpp} {
 			if err = db.C(col).EnsureIndex(idx); err != nil {
 				return
 			}
@@ -322,6 +323,16 @@ func (ms *MongoStorage) EnsureIndexes() (err error) {
 		if err = db.C(utils.SessionsCostsTBL).EnsureIndex(idx); err != nil {
 			return
 		}
+		idx = mgo.Index{
+			Key:        []string{TenantLow, "id", "time"},
+			Unique:     false,
+			DropDups:   false,
+			Background: false,
+			Sparse:     false,
+		}
+		if err = db.C(utils.StatQueueSnapshotsTBL).EnsureIndex(idx); err != nil {
+			return
+		}
 	}
 	return
 }
@@ -730,6 +741,18 @@ func (ms *MongoStorage) GetKeysForPrefix(prefix string) (result []string, err er
 		for iter.Next(&idResult) {
 			result = append(result, utils.ChargerProfilePrefix+utils.ConcatenatedKey(idResult.Tenant, idResult.Id))
 		}
+	case utils.DispatcherProfilePrefix:
+		qry := bson.M{}
+		if tntID.Tenant != "" {
+			qry["tenant"] = tntID.Tenant
+		}
+		if tntID.ID != "" {
+			qry["id"] = bson.M{"$regex": bson.RegEx{Pattern: subject}}
+		}
+		iter := db.C(colDpp).Find(qry).Select(bson.M{"tenant": 1, "id": 1}).Iter()
+		for iter.Next(&idResult) {
+			result = append(result, utils.DispatcherProfilePrefix+utils.ConcatenatedKey(idResult.Tenant, idResult.Id))
+		}
 	default:
 		err = fmt.Errorf("unsupported prefix in GetKeysForPrefix: %s", prefix)
 	}
@@ -787,6 +810,9 @@ func (ms *MongoStorage) HasDataDrv(category, subject, tenant string) (has bool,
 	case utils.ChargerProfilePrefix:
 		count, err = db.C(colCpp).Find(bson.M{"tenant": tenant, "id": subject}).Count()
 		has = count > 0
+	case utils.DispatcherProfilePrefix:
+		count, err = db.C(colDpp).Find(bson.M{"tenant": tenant, "id": subject}).Count()
+		has = count > 0
 	default:
 		err = fmt.Errorf("unsupported category in HasData: %s", category)
 	}
@@ -2399,3 +2425,31 @@ func (ms *MongoStorage) RemoveChargerProfileDrv(tenant, id string) (err error) {
 	}
 	return nil
 }
+
+func (ms *MongoStorage) GetDispatcherProfileDrv(tenant, id string) (r *DispatcherProfile, err error) {
+	session, col := ms.conn(colDpp)
+	defer session.Close()
+	if err = col.Find(bson.M{"tenant": tenant, "id": id}).One(&r); err != nil {
+		if err == mgo.ErrNotFound {
+			err = utils.ErrNotFound
+		}
+		return nil, err
+	}
+	return
+}
+
+func (ms *MongoStorage) SetDispatcherProfileDrv(r *DispatcherProfile) (err error) {
+	session, col := ms.conn(colDpp)
+	defer session.Close()
+	_, err = col.Upsert(bson.M{"tenant": r.Tenant, "id": r.ID}, r)
+	return
+}
+
+func (ms *MongoStorage) RemoveDispatcherProfileDrv(tenant, id string) (err error) {
+	session, col := ms.conn(colDpp)
+	defer session.Close()
+	if err = col.Remove(bson.M{"tenant": tenant, "id": id}); err != nil {
+		return
+	}
+	return nil
+}
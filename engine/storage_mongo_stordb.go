@@ -943,6 +943,37 @@ func (ms *MongoStorage) GetSMCosts(cgrid, runid, originHost, originIDPrefix stri
 	return smcs, nil
 }
 
+func (ms *MongoStorage) SetStatQueueSnapshot(sqSnap *StatQueueSnapshot) error {
+	session, col := ms.conn(utils.StatQueueSnapshotsTBL)
+	defer session.Close()
+	return col.Insert(sqSnap)
+}
+
+// GetStatQueueSnapshots returns the snapshots of one StatQueue within [from, until), ordered by time
+func (ms *MongoStorage) GetStatQueueSnapshots(tenant, id string,
+	from, until *time.Time) (sqSnaps []*StatQueueSnapshot, err error) {
+	filter := bson.M{TenantLow: tenant, "id": id}
+	timeFltr := bson.M{}
+	if from != nil {
+		timeFltr["$gte"] = *from
+	}
+	if until != nil {
+		timeFltr["$lt"] = *until
+	}
+	if len(timeFltr) != 0 {
+		filter["time"] = timeFltr
+	}
+	session, col := ms.conn(utils.StatQueueSnapshotsTBL)
+	defer session.Close()
+	if err = col.Find(filter).Sort("time").All(&sqSnaps); err != nil {
+		return nil, err
+	}
+	if len(sqSnaps) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
 func (ms *MongoStorage) SetCDR(cdr *CDR, allowUpdate bool) (err error) {
 	if cdr.OrderID == 0 {
 		cdr.OrderID = ms.cnter.Next()
@@ -989,19 +1020,19 @@ func (ms *MongoStorage) cleanEmptyFilters(filters bson.M) {
 	}
 }
 
-//  _, err := col(ColCDRs).UpdateAll(bson.M{CGRIDLow: bson.M{"$in": cgrIds}}, bson.M{"$set": bson.M{"deleted_at": time.Now()}})
-func (ms *MongoStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR, int64, error) {
+// cdrsFilters builds the query selecting the CDRs matching qryFltr
+func (ms *MongoStorage) cdrsFilters(qryFltr *utils.CDRsFilter) (bson.M, error) {
 	var minUsage, maxUsage *time.Duration
 	if len(qryFltr.MinUsage) != 0 {
 		if parsed, err := utils.ParseDurationWithNanosecs(qryFltr.MinUsage); err != nil {
-			return nil, 0, err
+			return nil, err
 		} else {
 			minUsage = &parsed
 		}
 	}
 	if len(qryFltr.MaxUsage) != 0 {
 		if parsed, err := utils.ParseDurationWithNanosecs(qryFltr.MaxUsage); err != nil {
-			return nil, 0, err
+			return nil, err
 		} else {
 			maxUsage = &parsed
 		}
@@ -1103,6 +1134,15 @@ func (ms *MongoStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR,
 	}
 	//file.WriteString(fmt.Sprintf("AFTER: %v\n", utils.ToIJSON(filters)))
 	//file.Close()
+	return filters, nil
+}
+
+//  _, err := col(ColCDRs).UpdateAll(bson.M{CGRIDLow: bson.M{"$in": cgrIds}}, bson.M{"$set": bson.M{"deleted_at": time.Now()}})
+func (ms *MongoStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR, int64, error) {
+	filters, err := ms.cdrsFilters(qryFltr)
+	if err != nil {
+		return nil, 0, err
+	}
 	session, col := ms.conn(ColCDRs)
 	defer session.Close()
 	if remove {
@@ -1162,6 +1202,85 @@ func (ms *MongoStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR,
 	return cdrs, 0, nil
 }
 
+// GetCDRsAggregate summarizes the CDRs matching qryFltr for each combination of the groupBy values
+// qryFltr.Paginator applies to the groups
+func (ms *MongoStorage) GetCDRsAggregate(qryFltr *utils.CDRsFilter,
+	groupBy []*utils.CDRsGroupBy) (aggrs []*CDRsAggregate, err error) {
+	filters, err := ms.cdrsFilters(qryFltr)
+	if err != nil {
+		return nil, err
+	}
+	grpID := bson.M{}
+	var grpSort bson.D
+	for i, gb := range groupBy {
+		grpKey := fmt.Sprintf("grp%d", i)
+		grpID[grpKey] = groupByExpr(gb)
+		grpSort = append(grpSort, bson.DocElem{Name: "_id." + grpKey, Value: 1})
+	}
+	pipeline := []bson.M{
+		bson.M{"$match": filters},
+		bson.M{"$group": bson.M{
+			"_id":   grpID,
+			"count": bson.M{"$sum": 1},
+			"usage": bson.M{"$sum": "$" + UsageLow},
+			"cost": bson.M{"$sum": bson.M{"$cond": []interface{}{
+				bson.M{"$gt": []interface{}{"$" + CostLow, 0}}, "$" + CostLow, 0}}},
+		}},
+	}
+	if len(grpSort) != 0 {
+		pipeline = append(pipeline, bson.M{"$sort": grpSort})
+	}
+	if qryFltr.Paginator.Offset != nil {
+		pipeline = append(pipeline, bson.M{"$skip": *qryFltr.Paginator.Offset})
+	}
+	if qryFltr.Paginator.Limit != nil {
+		pipeline = append(pipeline, bson.M{"$limit": *qryFltr.Paginator.Limit})
+	}
+	var results []struct {
+		ID    map[string]interface{} `bson:"_id"`
+		Count int64                  `bson:"count"`
+		Usage int64                  `bson:"usage"`
+		Cost  float64                `bson:"cost"`
+	}
+	session, col := ms.conn(ColCDRs)
+	defer session.Close()
+	if err = col.Pipe(pipeline).All(&results); err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		aggr := &CDRsAggregate{Group: make(map[string]string), Count: result.Count,
+			TotalUsage: time.Duration(result.Usage), TotalCost: result.Cost}
+		for i, gb := range groupBy {
+			if aggr.Group[gb.Rule], err = utils.IfaceAsString(
+				result.ID[fmt.Sprintf("grp%d", i)]); err != nil {
+				return nil, err
+			}
+		}
+		aggrs = append(aggrs, aggr)
+	}
+	if len(aggrs) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
+// groupByExpr returns the expression used to group on gb
+func groupByExpr(gb *utils.CDRsGroupBy) interface{} {
+	switch gb.Field {
+	case utils.Destination:
+		if gb.PrefixLength != 0 {
+			return bson.M{"$substr": []interface{}{"$" + DestinationLow, 0, gb.PrefixLength}}
+		}
+	case utils.AnswerTime:
+		format := "%Y-%m-%d"
+		if gb.Interval == utils.MetaHourly {
+			format = "%Y-%m-%dT%H"
+		}
+		return bson.M{"$dateToString": bson.M{"format": format, "date": "$" + AnswerTimeLow}}
+	}
+	return "$" + strings.ToLower(gb.Field)
+}
+
 func (ms *MongoStorage) GetTPStat(tpid, id string) ([]*utils.TPStats, error) {
 	filter := bson.M{
 		"tpid": tpid,
@@ -1348,6 +1467,37 @@ func (ms *MongoStorage) SetTPChargers(tpCPP []*utils.TPChargerProfile) (err erro
 	return
 }
 
+func (ms *MongoStorage) GetTPDispatchers(tpid, id string) ([]*utils.TPDispatcherProfile, error) {
+	filter := bson.M{
+		"tpid": tpid,
+	}
+	if id != "" {
+		filter["id"] = id
+	}
+	var results []*utils.TPDispatcherProfile
+	session, col := ms.conn(utils.TBLTPDispatchers)
+	defer session.Close()
+	err := col.Find(filter).All(&results)
+	if len(results) == 0 {
+		return results, utils.ErrNotFound
+	}
+	return results, err
+}
+
+func (ms *MongoStorage) SetTPDispatchers(tpDPPs []*utils.TPDispatcherProfile) (err error) {
+	if len(tpDPPs) == 0 {
+		return
+	}
+	session, col := ms.conn(utils.TBLTPDispatchers)
+	defer session.Close()
+	tx := col.Bulk()
+	for _, tp := range tpDPPs {
+		tx.Upsert(bson.M{"tpid": tp.TPid, "id": tp.ID}, tp)
+	}
+	_, err = tx.Run()
+	return
+}
+
 func (ms *MongoStorage) GetVersions(itm string) (vrs Versions, err error) {
 	session, col := ms.conn(colVer)
 	defer session.Close()
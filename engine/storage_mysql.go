@@ -87,6 +87,15 @@ func (self *MySQLStorage) notExtraFieldsValueQry(field, value string) string {
 	return fmt.Sprintf(" extra_fields NOT LIKE '%%\"%s\":\"%s\"%%'", field, value)
 }
 
+// answerTimeGroupQry groups on the UTC answer_time, stored as datetime in the session time zone
+func (self *MySQLStorage) answerTimeGroupQry(interval string) string {
+	answerTimeUTC := "CONVERT_TZ(answer_time, @@session.time_zone, '+00:00')"
+	if interval == utils.MetaHourly {
+		return "DATE_FORMAT(" + answerTimeUTC + ", '%Y-%m-%dT%H')"
+	}
+	return "DATE_FORMAT(" + answerTimeUTC + ", '%Y-%m-%d')"
+}
+
 func (self *MySQLStorage) GetStorageType() string {
 	return utils.MYSQL
 }
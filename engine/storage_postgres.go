@@ -90,6 +90,14 @@ func (self *PostgresStorage) notExtraFieldsValueQry(field, value string) string
 	return fmt.Sprintf(" NOT (extra_fields ?'%s' AND (extra_fields ->> '%s') = '%s')", field, field, value)
 }
 
+// answerTimeGroupQry groups on the UTC answer_time, independent of the session TimeZone
+func (self *PostgresStorage) answerTimeGroupQry(interval string) string {
+	if interval == utils.MetaHourly {
+		return `to_char(answer_time AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24')`
+	}
+	return "to_char(answer_time AT TIME ZONE 'UTC', 'YYYY-MM-DD')"
+}
+
 func (self *PostgresStorage) GetStorageType() string {
 	return utils.POSTGRES
 }
@@ -37,6 +37,7 @@ type SQLImpl interface {
 	extraFieldsValueQry(string, string) string
 	notExtraFieldsExistsQry(string) string
 	notExtraFieldsValueQry(string, string) string
+	answerTimeGroupQry(string) string
 }
 
 type SQLStorage struct {
@@ -109,6 +110,7 @@ func (self *SQLStorage) IsDBEmpty() (resp bool, err error) {
 		utils.TBLTPAliases, utils.TBLTPResources, utils.TBLTPStats, utils.TBLTPThresholds,
 		utils.TBLTPFilters, utils.SessionsCostsTBL, utils.CDRsTBL, utils.TBLTPActionPlans,
 		utils.TBLVersions, utils.TBLTPSuppliers, utils.TBLTPAttributes, utils.TBLTPChargers,
+		utils.TBLTPDispatchers, utils.StatQueueSnapshotsTBL,
 	}
 	for _, tbl := range tbls {
 		if self.db.HasTable(tbl) {
@@ -127,7 +129,7 @@ func (self *SQLStorage) GetTpIds(colName string) ([]string, error) {
 	qryStr := fmt.Sprintf(" (SELECT tpid FROM %s)", colName)
 	if colName == "" {
 		qryStr = fmt.Sprintf(
-			"(SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s)",
+			"(SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s)",
 			utils.TBLTPTimings,
 			utils.TBLTPDestinations,
 			utils.TBLTPRates,
@@ -150,7 +152,8 @@ func (self *SQLStorage) GetTpIds(colName string) ([]string, error) {
 			utils.TBLTPActionPlans,
 			utils.TBLTPSuppliers,
 			utils.TBLTPAttributes,
-			utils.TBLTPChargers)
+			utils.TBLTPChargers,
+			utils.TBLTPDispatchers)
 	}
 	rows, err = self.Db.Query(qryStr)
 	if err != nil {
@@ -241,7 +244,8 @@ func (self *SQLStorage) RemTpData(table, tpid string, args map[string]string) er
 			utils.TBLTPSharedGroups, utils.TBLTPCdrStats, utils.TBLTPLcrs, utils.TBLTPActions,
 			utils.TBLTPActionPlans, utils.TBLTPActionTriggers, utils.TBLTPAccountActions,
 			utils.TBLTPDerivedChargers, utils.TBLTPAliases, utils.TBLTPUsers, utils.TBLTPResources,
-			utils.TBLTPStats, utils.TBLTPFilters, utils.TBLTPSuppliers, utils.TBLTPAttributes, utils.TBLTPChargers} {
+			utils.TBLTPStats, utils.TBLTPFilters, utils.TBLTPSuppliers, utils.TBLTPAttributes, utils.TBLTPChargers,
+			utils.TBLTPDispatchers} {
 			if err := tx.Table(tblName).Where("tpid = ?", tpid).Delete(nil).Error; err != nil {
 				tx.Rollback()
 				return err
@@ -762,6 +766,28 @@ func (self *SQLStorage) SetTPChargers(tpCPPs []*utils.TPChargerProfile) error {
 	return nil
 }
 
+func (self *SQLStorage) SetTPDispatchers(tpDPPs []*utils.TPDispatcherProfile) error {
+	if len(tpDPPs) == 0 {
+		return nil
+	}
+	tx := self.db.Begin()
+	for _, dpp := range tpDPPs {
+		// Remove previous
+		if err := tx.Where(&TPDispatcher{Tpid: dpp.TPid, ID: dpp.ID}).Delete(TPDispatcher{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		for _, mst := range APItoModelTPDispatcher(dpp) {
+			if err := tx.Save(&mst).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
 func (self *SQLStorage) SetSMCost(smc *SMCost) error {
 	if smc.CostDetails == nil {
 		return nil
@@ -845,6 +871,54 @@ func (self *SQLStorage) GetSMCosts(cgrid, runid, originHost, originIDPrefix stri
 	return smCosts, nil
 }
 
+func (self *SQLStorage) SetStatQueueSnapshot(sqSnap *StatQueueSnapshot) error {
+	tx := self.db.Begin()
+	sqs := &StatQueueSnapshotSQL{
+		Tenant:       sqSnap.Tenant,
+		QueueID:      sqSnap.ID,
+		Metrics:      utils.ToJSON(sqSnap.Metrics),
+		SnapshotTime: sqSnap.Time,
+		CreatedAt:    time.Now(),
+	}
+	if err := tx.Save(sqs).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+// GetStatQueueSnapshots returns the snapshots of one StatQueue within [from, until), ordered by time
+func (self *SQLStorage) GetStatQueueSnapshots(tenant, id string,
+	from, until *time.Time) (sqSnaps []*StatQueueSnapshot, err error) {
+	q := self.db.Where(&StatQueueSnapshotSQL{Tenant: tenant, QueueID: id})
+	if from != nil {
+		q = q.Where("snapshot_time >= ?", from)
+	}
+	if until != nil {
+		q = q.Where("snapshot_time < ?", until)
+	}
+	results := make([]*StatQueueSnapshotSQL, 0)
+	if err = q.Order("snapshot_time").Find(&results).Error; err != nil {
+		return
+	}
+	for _, result := range results {
+		sqSnap := &StatQueueSnapshot{
+			Tenant: result.Tenant,
+			ID:     result.QueueID,
+			Time:   result.SnapshotTime,
+		}
+		if err = json.Unmarshal([]byte(result.Metrics), &sqSnap.Metrics); err != nil {
+			return nil, err
+		}
+		sqSnaps = append(sqSnaps, sqSnap)
+	}
+	if len(sqSnaps) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
 func (self *SQLStorage) LogActionTrigger(ubId, source string, at *ActionTrigger, as Actions) (err error) {
 	return
 }
@@ -875,10 +949,8 @@ func (self *SQLStorage) SetCDR(cdr *CDR, allowUpdate bool) error {
 	return nil
 }
 
-// GetCDRs has ability to remove the selected CDRs, count them or simply return them
-// qryFltr.Unscoped will ignore soft deletes or delete records permanently
-func (self *SQLStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR, int64, error) {
-	var cdrs []*CDR
+// cdrsQuery builds the query selecting the CDRs matching qryFltr, without ordering and pagination
+func (self *SQLStorage) cdrsQuery(qryFltr *utils.CDRsFilter) (*gorm.DB, error) {
 	q := self.db.Table(utils.CDRsTBL).Select("*")
 	if qryFltr.Unscoped {
 		q = q.Unscoped()
@@ -1042,36 +1114,10 @@ func (self *SQLStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR,
 	if qryFltr.UpdatedAtEnd != nil && !qryFltr.UpdatedAtEnd.IsZero() {
 		q = q.Where("updated_at < ?", qryFltr.UpdatedAtEnd)
 	}
-	if qryFltr.OrderBy != "" {
-		var orderVal string
-		separateVals := strings.Split(qryFltr.OrderBy, utils.INFIELD_SEP)
-		switch separateVals[0] {
-		case utils.OrderID:
-			orderVal = "id"
-		case utils.AnswerTime:
-			orderVal = "answer_time"
-		case utils.SetupTime:
-			orderVal = "setup_time"
-		case utils.Usage:
-			if self.db.Dialect().GetName() == utils.MYSQL {
-				orderVal = "`usage`"
-			} else {
-				orderVal = "usage"
-			}
-		case utils.Cost:
-			orderVal = "cost"
-		default:
-			return nil, 0, fmt.Errorf("Invalid value : %s", separateVals[0])
-		}
-		if len(separateVals) == 2 && separateVals[1] == "desc" {
-			orderVal += " DESC"
-		}
-		q = q.Order(orderVal)
-	}
 	if len(qryFltr.MinUsage) != 0 {
 		minUsage, err := utils.ParseDurationWithNanosecs(qryFltr.MinUsage)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 		if self.db.Dialect().GetName() == utils.MYSQL { // MySQL needs escaping for usage
 			q = q.Where("`usage` >= ?", minUsage.Nanoseconds())
@@ -1082,7 +1128,7 @@ func (self *SQLStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR,
 	if len(qryFltr.MaxUsage) != 0 {
 		maxUsage, err := utils.ParseDurationWithNanosecs(qryFltr.MaxUsage)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 		if self.db.Dialect().GetName() == utils.MYSQL { // MySQL needs escaping for usage
 			q = q.Where("`usage` < ?", maxUsage.Nanoseconds())
@@ -1107,6 +1153,43 @@ func (self *SQLStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR,
 			q = q.Where(fmt.Sprintf("( cost IS NULL OR cost < %f )", *qryFltr.MaxCost))
 		}
 	}
+	return q, nil
+}
+
+// GetCDRs has ability to remove the selected CDRs, count them or simply return them
+// qryFltr.Unscoped will ignore soft deletes or delete records permanently
+func (self *SQLStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR, int64, error) {
+	var cdrs []*CDR
+	q, err := self.cdrsQuery(qryFltr)
+	if err != nil {
+		return nil, 0, err
+	}
+	if qryFltr.OrderBy != "" {
+		var orderVal string
+		separateVals := strings.Split(qryFltr.OrderBy, utils.INFIELD_SEP)
+		switch separateVals[0] {
+		case utils.OrderID:
+			orderVal = "id"
+		case utils.AnswerTime:
+			orderVal = "answer_time"
+		case utils.SetupTime:
+			orderVal = "setup_time"
+		case utils.Usage:
+			if self.db.Dialect().GetName() == utils.MYSQL {
+				orderVal = "`usage`"
+			} else {
+				orderVal = "usage"
+			}
+		case utils.Cost:
+			orderVal = "cost"
+		default:
+			return nil, 0, fmt.Errorf("Invalid value : %s", separateVals[0])
+		}
+		if len(separateVals) == 2 && separateVals[1] == "desc" {
+			orderVal += " DESC"
+		}
+		q = q.Order(orderVal)
+	}
 	if qryFltr.Paginator.Limit != nil {
 		q = q.Limit(*qryFltr.Paginator.Limit)
 	}
@@ -1145,6 +1228,94 @@ func (self *SQLStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR,
 	return cdrs, 0, nil
 }
 
+// GetCDRsAggregate summarizes the CDRs matching qryFltr for each combination of the groupBy values
+// qryFltr.Paginator applies to the groups
+func (self *SQLStorage) GetCDRsAggregate(qryFltr *utils.CDRsFilter,
+	groupBy []*utils.CDRsGroupBy) (aggrs []*CDRsAggregate, err error) {
+	q, err := self.cdrsQuery(qryFltr)
+	if err != nil {
+		return nil, err
+	}
+	usageCol := "usage"
+	if self.db.Dialect().GetName() == utils.MYSQL { // MySQL needs escaping for usage
+		usageCol = "`usage`"
+	}
+	grpCols := make([]string, len(groupBy))
+	slctCols := make([]string, 0, len(groupBy)+3)
+	for i, gb := range groupBy {
+		grpCols[i] = fmt.Sprintf("grp%d", i)
+		slctCols = append(slctCols, fmt.Sprintf("%s AS %s", self.groupByQry(gb), grpCols[i]))
+	}
+	slctCols = append(slctCols, "COUNT(*) AS cnt",
+		fmt.Sprintf("SUM(%s) AS total_usage", usageCol),
+		"SUM(CASE WHEN cost > 0 THEN cost ELSE 0 END) AS total_cost")
+	q = q.Select(strings.Join(slctCols, ", "))
+	if len(grpCols) != 0 {
+		q = q.Group(strings.Join(grpCols, ", ")).Order(strings.Join(grpCols, ", "))
+	}
+	if qryFltr.Paginator.Limit != nil {
+		q = q.Limit(*qryFltr.Paginator.Limit)
+	}
+	if qryFltr.Paginator.Offset != nil {
+		q = q.Offset(*qryFltr.Paginator.Offset)
+	}
+	rows, err := q.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		grpVals := make([]sql.NullString, len(groupBy))
+		var cnt int64
+		var usage sql.NullInt64
+		var cost sql.NullFloat64
+		dest := make([]interface{}, 0, len(groupBy)+3)
+		for i := range grpVals {
+			dest = append(dest, &grpVals[i])
+		}
+		dest = append(dest, &cnt, &usage, &cost)
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		if cnt == 0 { // no groups and no CDRs matching
+			continue
+		}
+		aggr := &CDRsAggregate{Group: make(map[string]string), Count: cnt,
+			TotalUsage: time.Duration(usage.Int64), TotalCost: cost.Float64}
+		for i, gb := range groupBy {
+			aggr.Group[gb.Rule] = grpVals[i].String
+		}
+		aggrs = append(aggrs, aggr)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(aggrs) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	return
+}
+
+// groupByQry returns the column expression used to group on gb
+func (self *SQLStorage) groupByQry(gb *utils.CDRsGroupBy) string {
+	switch gb.Field {
+	case utils.RunID:
+		return "run_id"
+	case utils.ToR:
+		return "tor"
+	case utils.RequestType:
+		return "request_type"
+	case utils.Destination:
+		if gb.PrefixLength != 0 {
+			return fmt.Sprintf("SUBSTR(destination, 1, %d)", gb.PrefixLength)
+		}
+		return "destination"
+	case utils.AnswerTime:
+		return self.SQLImpl.answerTimeGroupQry(gb.Interval)
+	}
+	return strings.ToLower(gb.Field) // tenant, category, account, subject
+}
+
 func (self *SQLStorage) GetTPDestinations(tpid, id string) (uTPDsts []*utils.TPDestination, err error) {
 	var tpDests TpDestinations
 	q := self.db.Where("tpid = ?", tpid)
@@ -1689,6 +1860,22 @@ func (self *SQLStorage) GetTPChargers(tpid, id string) ([]*utils.TPChargerProfil
 	return arls, nil
 }
 
+func (self *SQLStorage) GetTPDispatchers(tpid, id string) ([]*utils.TPDispatcherProfile, error) {
+	var dpps TPDispatchers
+	q := self.db.Where("tpid = ?", tpid)
+	if len(id) != 0 {
+		q = q.Where("id = ?", id)
+	}
+	if err := q.Find(&dpps).Error; err != nil {
+		return nil, err
+	}
+	arls := dpps.AsTPDispatchers()
+	if len(arls) == 0 {
+		return arls, utils.ErrNotFound
+	}
+	return arls, nil
+}
+
 // GetVersions returns slice of all versions or a specific version if tag is specified
 func (self *SQLStorage) GetVersions(itm string) (vrs Versions, err error) {
 	q := self.db.Model(&TBLVersion{})
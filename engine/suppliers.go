@@ -128,7 +128,7 @@ func (spS *SupplierService) Shutdown() error {
 // matchingSupplierProfilesForEvent returns ordered list of matching resources which are active by the time of the call
 func (spS *SupplierService) matchingSupplierProfilesForEvent(ev *utils.CGREvent) (sPrfls SupplierProfiles, err error) {
 	matchingLPs := make(map[string]*SupplierProfile)
-	sPrflIDs, err := matchingItemIDsForEvent(ev.Event, spS.stringIndexedFields, spS.prefixIndexedFields,
+	sPrflIDs, err := MatchingItemIDsForEvent(ev.Event, spS.stringIndexedFields, spS.prefixIndexedFields,
 		spS.dm, utils.CacheSupplierFilterIndexes, ev.Tenant, spS.filterS.cfg.FilterSCfg().IndexedSelects)
 	if err != nil {
 		return nil, err
@@ -281,7 +281,19 @@ func (spS *SupplierService) statMetrics(statIDs []string, tenant string) (stsMet
 }
 
 // resourceUsage returns sum of all resource usages out of list
-func (spS *SupplierService) resourceUsage(resIDs []string) (tUsage float64, err error) {
+func (spS *SupplierService) resourceUsage(resIDs []string, tenant string) (tUsage float64, err error) {
+	for _, resID := range resIDs {
+		var res ResourceWithConfig
+		if err = spS.resourceS.Call(utils.ResourceSv1GetResourceWithConfig,
+			utils.TenantID{Tenant: tenant, ID: resID}, &res); err != nil {
+			if err.Error() != utils.ErrNotFound.Error() {
+				return
+			}
+			err = nil // not yet allocated
+			continue
+		}
+		tUsage += res.TotalUsage
+	}
 	return
 }
 
@@ -309,10 +321,16 @@ func (spS *SupplierService) populateSortingData(ev *utils.CGREvent, spl *Supplie
 			} else {
 				return nil, false, err
 			}
+		} else if extraOpts.checkUsage && len(spl.AccountIDs) != 0 &&
+			costData[utils.Account] == nil { // none of the accounts covers the usage
+			return nil, false, nil
 		} else if len(costData) == 0 {
 			utils.Logger.Warning(
 				fmt.Sprintf("<%s> ignoring supplier with ID: %s, missing cost information",
 					utils.SupplierS, spl.ID))
+			if extraOpts.maxCost != 0 { // cannot guarantee the cost is within limits
+				return nil, false, nil
+			}
 		} else {
 			if extraOpts.maxCost != 0 &&
 				costData[utils.Cost].(float64) > extraOpts.maxCost {
@@ -366,6 +384,23 @@ func (spS *SupplierService) populateSortingData(ev *utils.CGREvent, spl *Supplie
 		}
 		sortedSpl.globalStats = globalStats
 	}
+	//calculate resource usage, only for the strategies sorting on it
+	if extraOpts.sortingStrategy == utils.MetaLoadDistribution &&
+		len(spl.ResourceIDs) != 0 && spS.resourceS != nil {
+		resTotUsage, err := spS.resourceUsage(spl.ResourceIDs, ev.Tenant)
+		if err != nil {
+			if extraOpts.ignoreErrors {
+				utils.Logger.Warning(
+					fmt.Sprintf("<%s> ignoring supplier with ID: %s, err: %s",
+						utils.SupplierS, spl.ID, err.Error()))
+				return nil, false, nil
+			} else {
+				return nil, false, err
+			}
+		}
+		sortedSpl.SortingData[utils.ResourceUsage] = resTotUsage
+		metricForFilter[utils.ResourceUsage] = resTotUsage
+	}
 	//filter the supplier
 	if len(spl.FilterIDs) != 0 {
 		nM := config.NewNavigableMap(nil)
@@ -400,6 +435,7 @@ func (spS *SupplierService) sortedSuppliersForEvent(args *ArgsGetSuppliers) (sor
 		return nil, err
 	}
 	extraOpts.sortingParameters = splPrfl.SortingParameters // populate sortingParameters in extraOpts
+	extraOpts.sortingStrategy = splPrfl.Sorting
 	sortedSuppliers, err := spS.sorter.SortSuppliers(splPrfl.ID, splPrfl.Sorting,
 		splPrfl.Suppliers, &args.CGREvent, extraOpts)
 	if err != nil {
@@ -421,12 +457,14 @@ func (spS *SupplierService) sortedSuppliersForEvent(args *ArgsGetSuppliers) (sor
 type ArgsGetSuppliers struct {
 	IgnoreErrors bool
 	MaxCost      string // toDo: try with interface{} here
+	CheckUsage   bool   // exclude the suppliers with AccountIDs not covering the Usage
 	utils.CGREvent
 	utils.Paginator
 }
 
 func (args *ArgsGetSuppliers) asOptsGetSuppliers() (opts *optsGetSuppliers, err error) {
-	opts = &optsGetSuppliers{ignoreErrors: args.IgnoreErrors}
+	opts = &optsGetSuppliers{ignoreErrors: args.IgnoreErrors,
+		checkUsage: args.CheckUsage}
 	if args.MaxCost == utils.MetaEventCost { // dynamic cost needs to be calculated from event
 		if err = args.CGREvent.CheckMandatoryFields([]string{utils.Account,
 			utils.Destination, utils.SetupTime, utils.Usage}); err != nil {
@@ -454,7 +492,9 @@ func (args *ArgsGetSuppliers) asOptsGetSuppliers() (opts *optsGetSuppliers, err
 type optsGetSuppliers struct {
 	ignoreErrors      bool
 	maxCost           float64
+	checkUsage        bool
 	sortingParameters []string //used for QOS strategy
+	sortingStrategy   string   // decides which of the sorting data needs to be populated
 }
 
 // V1GetSuppliersForEvent returns the list of valid supplier IDs
@@ -628,6 +628,24 @@ func TestSuppliersAsOptsGetSuppliersMaxCost(t *testing.T) {
 	}
 }
 
+func TestSuppliersAsOptsGetSuppliersCheckUsage(t *testing.T) {
+	s := &ArgsGetSuppliers{
+		MaxCost:    "0.5",
+		CheckUsage: true,
+	}
+	spl := &optsGetSuppliers{
+		maxCost:    0.5,
+		checkUsage: true,
+	}
+	sprf, err := s.asOptsGetSuppliers()
+	if err != nil {
+		t.Errorf("Error: %+v", err)
+	}
+	if !reflect.DeepEqual(spl, sprf) {
+		t.Errorf("Expecting: %+v,received: %+v", spl, sprf)
+	}
+}
+
 func TestSuppliersMatchWithIndexFalse(t *testing.T) {
 	splService.filterS.cfg.FilterSCfg().IndexedSelects = false
 	sprf, err := splService.matchingSupplierProfilesForEvent(&argsGetSuppliers[0].CGREvent)
@@ -654,3 +672,82 @@ func TestSuppliersMatchWithIndexFalse(t *testing.T) {
 		t.Errorf("Expecting: %+v, received: %+v", sppTest[2], sprf[0])
 	}
 }
+
+// splResourceConn counts the resource queries done by SupplierS
+type splResourceConn struct {
+	calls int
+}
+
+func (c *splResourceConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	c.calls++
+	*(reply.(*ResourceWithConfig)) = ResourceWithConfig{TotalUsage: 2}
+	return nil
+}
+
+func TestSuppliersPopulateSortingDataResourceUsage(t *testing.T) {
+	conn := new(splResourceConn)
+	spS := &SupplierService{resourceS: conn}
+	spl := &Supplier{ID: "SPL_1", ResourceIDs: []string{"RES_1", "RES_2"}, Weight: 10}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "ev1",
+		Event: map[string]interface{}{}}
+	for _, strategy := range []string{utils.MetaWeight, utils.MetaQOS} {
+		if srtSpl, pass, err := spS.populateSortingData(ev, spl,
+			&optsGetSuppliers{sortingStrategy: strategy}); err != nil {
+			t.Error(err)
+		} else if !pass {
+			t.Errorf("supplier not passing for strategy: %s", strategy)
+		} else if _, has := srtSpl.SortingData[utils.ResourceUsage]; has {
+			t.Errorf("unexpected resource usage for strategy: %s", strategy)
+		}
+	}
+	if conn.calls != 0 {
+		t.Errorf("expecting no resource queries, received: %d", conn.calls)
+	}
+	if srtSpl, _, err := spS.populateSortingData(ev, spl,
+		&optsGetSuppliers{sortingStrategy: utils.MetaLoadDistribution}); err != nil {
+		t.Error(err)
+	} else if usage := srtSpl.SortingData[utils.ResourceUsage]; usage != 4.0 {
+		t.Errorf("expecting resource usage: 4, received: %v", usage)
+	}
+	if conn.calls != 2 {
+		t.Errorf("expecting 2 resource queries, received: %d", conn.calls)
+	}
+}
+
+func TestSuppliersSortDropsOnCheckUsageAndMaxCost(t *testing.T) {
+	spS := &SupplierService{timezone: "UTC"}
+	suppls := []*Supplier{
+		&Supplier{ID: "SPL_NOCOST", Weight: 10},
+		&Supplier{ID: "SPL_ACNT", AccountIDs: []string{"splMissingAccount"}, Weight: 20},
+	}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "ev1",
+		Event: map[string]interface{}{
+			utils.Account:     "1001",
+			utils.Destination: "1002",
+			utils.SetupTime:   time.Date(2018, 1, 7, 16, 60, 0, 0, time.UTC),
+			utils.Usage:       time.Duration(30 * time.Second),
+		}}
+	eSplIDs := map[string][]string{
+		"none":       []string{"SPL_ACNT", "SPL_NOCOST"},
+		"checkUsage": []string{"SPL_NOCOST"},
+		"maxCost":    []string{"SPL_NOCOST"},
+	}
+	for name, opts := range map[string]*optsGetSuppliers{
+		"none":       &optsGetSuppliers{},
+		"checkUsage": &optsGetSuppliers{checkUsage: true},
+		"maxCost":    &optsGetSuppliers{maxCost: 1},
+	} {
+		sortedSpls, err := NewWeightSorter(spS).SortSuppliers("SPL_PRF", suppls, ev, opts)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		var splIDs []string
+		for _, srtSpl := range sortedSpls.SortedSuppliers {
+			splIDs = append(splIDs, srtSpl.SupplierID)
+		}
+		if !reflect.DeepEqual(eSplIDs[name], splIDs) {
+			t.Errorf("%s: expecting: %+v, received: %+v", name, eSplIDs[name], splIDs)
+		}
+	}
+}
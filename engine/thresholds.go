@@ -19,14 +19,20 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/rand"
+	"path"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/guardian"
 	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+	"github.com/streadway/amqp"
 )
 
 type ThresholdProfile struct {
@@ -41,6 +47,9 @@ type ThresholdProfile struct {
 	Weight             float64 // Weight to sort the thresholds
 	ActionIDs          []string
 	Async              bool
+	Notifications      []*ThresholdNotification // sent together with executing the ActionIDs
+	HitsWindow         time.Duration            // hits older than the window expire, 0 to count all of them
+	ResetInterval      time.Duration            // hits are reset periodically, 0 to never reset them
 }
 
 func (tp *ThresholdProfile) TenantID() string {
@@ -49,10 +58,12 @@ func (tp *ThresholdProfile) TenantID() string {
 
 // Threshold is the unit matched by filters
 type Threshold struct {
-	Tenant string
-	ID     string
-	Hits   int       // number of hits for this threshold
-	Snooze time.Time // prevent threshold to run too early
+	Tenant    string
+	ID        string
+	Hits      int         // number of hits for this threshold
+	Snooze    time.Time   // prevent threshold to run too early
+	HitTimes  []time.Time // time of each hit in the window, populated only for profiles with HitsWindow
+	LastReset time.Time   // start of the current reset interval
 
 	tPrfl *ThresholdProfile
 	dirty *bool // needs save
@@ -62,16 +73,61 @@ func (t *Threshold) TenantID() string {
 	return utils.ConcatenatedKey(t.Tenant, t.ID)
 }
 
-// ProcessEvent processes an ThresholdEvent
-// concurrentActions limits the number of simultaneous action sets executed
-func (t *Threshold) ProcessEvent(args *ArgsProcessEvent, dm *DataManager) (err error) {
+// shouldExecute checks the snooze and the hits before executing actions and notifications
+func (t *Threshold) shouldExecute() bool {
 	if t.Snooze.After(time.Now()) { // snoozed, not executing actions
-		return
+		return false
 	}
 	if t.Hits < t.tPrfl.MinHits { // number of hits was not met, will not execute actions
-		return
+		return false
 	}
 	if t.tPrfl.MaxHits != -1 && t.Hits > t.tPrfl.MaxHits {
+		return false
+	}
+	return true
+}
+
+// reset clears the hits and the snooze, starting a new reset interval
+func (t *Threshold) reset(now time.Time) {
+	t.Hits = 0
+	t.HitTimes = nil
+	t.Snooze = time.Time{}
+	t.LastReset = now
+}
+
+// decayHits resets the hits once the reset interval passed and expires the ones out of the window
+func (t *Threshold) decayHits(now time.Time) {
+	if t.tPrfl.ResetInterval > 0 {
+		if t.LastReset.IsZero() {
+			t.LastReset = now
+		} else if elapsed := now.Sub(t.LastReset); elapsed >= t.tPrfl.ResetInterval {
+			t.reset(t.LastReset.Add(elapsed - elapsed%t.tPrfl.ResetInterval)) // keep the intervals aligned
+		}
+	}
+	if t.tPrfl.HitsWindow > 0 {
+		var expired int
+		for expired < len(t.HitTimes) &&
+			!t.HitTimes[expired].After(now.Add(-t.tPrfl.HitsWindow)) {
+			expired++
+		}
+		t.HitTimes = t.HitTimes[expired:]
+		t.Hits = len(t.HitTimes)
+	}
+}
+
+// addHit records a new hit, after decaying the old ones
+func (t *Threshold) addHit(now time.Time) {
+	t.decayHits(now)
+	t.Hits += 1
+	if t.tPrfl.HitsWindow > 0 {
+		t.HitTimes = append(t.HitTimes, now)
+	}
+}
+
+// ProcessEvent processes an ThresholdEvent
+// concurrentActions limits the number of simultaneous action sets executed
+func (t *Threshold) ProcessEvent(args *ArgsProcessEvent, dm *DataManager) (err error) {
+	if !t.shouldExecute() {
 		return
 	}
 	acnt, _ := args.FieldAsString(utils.Account)
@@ -132,8 +188,20 @@ type ThresholdService struct {
 	storeInterval       time.Duration
 	filterS             *FilterS
 	stopBackup          chan struct{}
-	storedTdIDs         utils.StringMap // keep a record of stats which need saving, map[statsTenantID]bool
-	stMux               sync.RWMutex    // protects storedTdIDs
+	storedTdIDs         utils.StringMap               // keep a record of stats which need saving, map[statsTenantID]bool
+	stMux               sync.RWMutex                  // protects storedTdIDs
+	sessionS            rpcclient.RpcClientConnection // used by *disconnect_session notifications
+	resourceS           rpcclient.RpcClientConnection // used by *release_resource notifications
+	connMux             sync.RWMutex                  // protects sessionS and resourceS
+}
+
+// SetNotificationConns sets the connections used by the notifications
+// set after the service is started since SessionS and ResourceS can also connect to ThresholdS
+func (tS *ThresholdService) SetNotificationConns(sessionS, resourceS rpcclient.RpcClientConnection) {
+	tS.connMux.Lock()
+	tS.sessionS = sessionS
+	tS.resourceS = resourceS
+	tS.connMux.Unlock()
 }
 
 // Called to start the service
@@ -222,7 +290,7 @@ func (tS *ThresholdService) matchingThresholdsForEvent(args *ArgsProcessEvent) (
 	if len(args.ThresholdIDs) != 0 {
 		tIDs = args.ThresholdIDs
 	} else {
-		tIDsMap, err := matchingItemIDsForEvent(args.Event, tS.stringIndexedFields,
+		tIDsMap, err := MatchingItemIDsForEvent(args.Event, tS.stringIndexedFields,
 			tS.prefixIndexedFields, tS.dm, utils.CacheThresholdFilterIndexes,
 			args.Tenant, tS.filterS.cfg.FilterSCfg().IndexedSelects)
 		if err != nil {
@@ -290,34 +358,12 @@ func (tS *ThresholdService) processEvent(args *ArgsProcessEvent) (thresholdsIDs
 	var tIDs []string
 	for _, t := range matchTs {
 		tIDs = append(tIDs, t.ID)
-		t.Hits += 1
-		err = t.ProcessEvent(args, tS.dm)
-		if err != nil {
-			utils.Logger.Warning(
-				fmt.Sprintf("<ThresholdService> threshold: %s, ignoring event: %s, error: %s",
-					t.TenantID(), args.CGREvent.TenantID(), err.Error()))
+		lkID := utils.ThresholdPrefix + t.TenantID()
+		guardian.Guardian.GuardIDs(config.CgrConfig().GeneralCfg().LockingTimeout, lkID)
+		if tS.processThreshold(t, args) {
 			withErrors = true
-			continue
-		}
-		if t.dirty == nil || t.Hits == t.tPrfl.MaxHits { // one time threshold
-			if err = tS.dm.RemoveThreshold(t.Tenant, t.ID, utils.NonTransactional); err != nil {
-				utils.Logger.Warning(
-					fmt.Sprintf("<ThresholdService> failed removing non-recurrent threshold: %s, error: %s",
-						t.TenantID(), err.Error()))
-				withErrors = true
-			}
-			continue
-		}
-		t.Snooze = time.Now().Add(t.tPrfl.MinSleep)
-		// recurrent threshold
-		if tS.storeInterval == -1 {
-			tS.StoreThreshold(t)
-		} else {
-			*t.dirty = true // mark it to be saved
-			tS.stMux.Lock()
-			tS.storedTdIDs[t.TenantID()] = true
-			tS.stMux.Unlock()
 		}
+		guardian.Guardian.UnguardIDs(lkID)
 	}
 	if len(tIDs) == 0 {
 		return nil, utils.ErrNotFound
@@ -329,6 +375,43 @@ func (tS *ThresholdService) processEvent(args *ArgsProcessEvent) (thresholdsIDs
 	return
 }
 
+// processThreshold counts the hit on the threshold and executes it, returning true if there were errors
+// the threshold should be locked by the caller
+func (tS *ThresholdService) processThreshold(t *Threshold, args *ArgsProcessEvent) (withErrors bool) {
+	t.addHit(time.Now())
+	if len(t.tPrfl.Notifications) != 0 && t.shouldExecute() {
+		if errNtf := tS.sendNotifications(t, args); errNtf != nil {
+			withErrors = true // failed notifications do not stop processing the threshold
+		}
+	}
+	if err := t.ProcessEvent(args, tS.dm); err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<ThresholdService> threshold: %s, ignoring event: %s, error: %s",
+				t.TenantID(), args.CGREvent.TenantID(), err.Error()))
+		return true
+	}
+	if t.dirty == nil || t.Hits == t.tPrfl.MaxHits { // one time threshold
+		if err := tS.dm.RemoveThreshold(t.Tenant, t.ID, utils.NonTransactional); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<ThresholdService> failed removing non-recurrent threshold: %s, error: %s",
+					t.TenantID(), err.Error()))
+			withErrors = true
+		}
+		return
+	}
+	t.Snooze = time.Now().Add(t.tPrfl.MinSleep)
+	// recurrent threshold
+	if tS.storeInterval == -1 {
+		tS.StoreThreshold(t)
+	} else {
+		*t.dirty = true // mark it to be saved
+		tS.stMux.Lock()
+		tS.storedTdIDs[t.TenantID()] = true
+		tS.stMux.Unlock()
+	}
+	return
+}
+
 // V1ProcessEvent implements ThresholdService method for processing an Event
 func (tS *ThresholdService) V1ProcessEvent(args *ArgsProcessEvent, reply *[]string) (err error) {
 	if missing := utils.MissingStructFields(args, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
@@ -382,3 +465,177 @@ func (tS *ThresholdService) V1GetThreshold(tntID *utils.TenantID, t *Threshold)
 	}
 	return
 }
+
+// V1ResetThreshold clears the hits and the snooze of a Threshold
+// for profiles with ResetInterval, the next interval starts with the reset
+func (tS *ThresholdService) V1ResetThreshold(tntID *utils.TenantID, reply *string) (err error) {
+	if missing := utils.MissingStructFields(tntID, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if _, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		t, err := tS.dm.GetThreshold(tntID.Tenant, tntID.ID, true, true, "")
+		if err != nil {
+			return nil, err
+		}
+		t.reset(time.Now())
+		return nil, tS.dm.SetThreshold(t)
+	}, config.CgrConfig().GeneralCfg().LockingTimeout,
+		utils.ThresholdPrefix+tntID.TenantID()); err != nil {
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+// ThresholdNotification is sent out each time the Threshold executes
+type ThresholdNotification struct {
+	Type    string // <*http_json|*amqp_json_map|*log|*disconnect_session|*release_resource>
+	Address string // remote address for *http_json and *amqp_json_map
+	UsageID string // *release_resource UsageID used when the event does not carry one
+}
+
+// NewThresholdNotification parses the notification out of its <Type>[:<Address|UsageID>] string
+func NewThresholdNotification(ntfStr string) (ntf *ThresholdNotification, err error) {
+	ntfSplt := strings.SplitN(ntfStr, utils.CONCATENATED_KEY_SEP, 2)
+	ntf = &ThresholdNotification{Type: ntfSplt[0]}
+	switch ntf.Type {
+	case LOG, utils.MetaDisconnectSession:
+		if len(ntfSplt) != 1 {
+			return nil, fmt.Errorf("unsupported format for notification: <%s>", ntfStr)
+		}
+	case utils.MetaHTTPjson, utils.MetaAMQPjsonMap:
+		if len(ntfSplt) != 2 || ntfSplt[1] == "" {
+			return nil, fmt.Errorf("missing address for notification: <%s>", ntfStr)
+		}
+		ntf.Address = ntfSplt[1]
+	case utils.MetaReleaseResource:
+		if len(ntfSplt) == 2 {
+			ntf.UsageID = ntfSplt[1]
+		}
+	default:
+		return nil, fmt.Errorf("unsupported notification type: <%s>", ntf.Type)
+	}
+	return
+}
+
+// ThresholdNotificationEvent is the payload of the notifications
+type ThresholdNotificationEvent struct {
+	Threshold *Threshold // state of the Threshold when executed
+	Event     *utils.CGREvent
+}
+
+// sendNotifications sends the notifications of the threshold
+// with Async profiles the notifications are sent in the background and errors are only logged
+func (tS *ThresholdService) sendNotifications(t *Threshold, args *ArgsProcessEvent) (err error) {
+	ntfEv := &ThresholdNotificationEvent{
+		Threshold: &Threshold{Tenant: t.Tenant, ID: t.ID, Hits: t.Hits, Snooze: t.Snooze},
+		Event:     &args.CGREvent,
+	}
+	for _, ntf := range t.tPrfl.Notifications {
+		if t.tPrfl.Async {
+			go func(ntf *ThresholdNotification) {
+				if errNtf := tS.sendNotification(ntf, ntfEv); errNtf != nil {
+					utils.Logger.Warning(
+						fmt.Sprintf("<%s> threshold: %s, failed sending notification: %s, error: %s",
+							utils.ThresholdS, t.TenantID(), ntf.Type, errNtf.Error()))
+				}
+			}(ntf)
+			continue
+		}
+		if errNtf := tS.sendNotification(ntf, ntfEv); errNtf != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> threshold: %s, failed sending notification: %s, error: %s",
+					utils.ThresholdS, t.TenantID(), ntf.Type, errNtf.Error()))
+			err = utils.ErrPartiallyExecuted
+		}
+	}
+	return
+}
+
+// sendNotification sends out one notification
+func (tS *ThresholdService) sendNotification(ntf *ThresholdNotification,
+	ntfEv *ThresholdNotificationEvent) (err error) {
+	switch ntf.Type {
+	case LOG:
+		utils.Logger.Info(
+			fmt.Sprintf("<%s> threshold notification: %s", utils.ThresholdS, utils.ToJSON(ntfEv)))
+	case utils.MetaHTTPjson, utils.MetaAMQPjsonMap:
+		var body []byte
+		if body, err = json.Marshal(ntfEv); err != nil {
+			return
+		}
+		err = postThresholdNotification(ntf.Type, ntf.Address, body)
+	case utils.MetaDisconnectSession:
+		tS.connMux.RLock()
+		sessionS := tS.sessionS
+		tS.connMux.RUnlock()
+		if sessionS == nil {
+			return utils.NewErrNotConnected(utils.SessionS)
+		}
+		fltrs := make(map[string]string)
+		if cgrID, _ := ntfEv.Event.FieldAsString(utils.CGRID); cgrID != "" {
+			fltrs[utils.CGRID] = cgrID
+		} else if originID, _ := ntfEv.Event.FieldAsString(utils.OriginID); originID != "" {
+			fltrs[utils.OriginID] = originID
+		} else {
+			return utils.NewErrMandatoryIeMissing(utils.OriginID)
+		}
+		var reply string
+		err = sessionS.Call(utils.SessionSv1ForceDisconnect,
+			utils.AttrForceDisconnect{Filters: fltrs,
+				Reason: fmt.Sprintf("threshold %s", ntfEv.Threshold.TenantID())},
+			&reply)
+	case utils.MetaReleaseResource:
+		tS.connMux.RLock()
+		resourceS := tS.resourceS
+		tS.connMux.RUnlock()
+		if resourceS == nil {
+			return utils.NewErrNotConnected(utils.ResourceS)
+		}
+		usageID, _ := ntfEv.Event.FieldAsString(utils.UsageID)
+		if usageID == "" {
+			usageID = ntf.UsageID
+		}
+		if usageID == "" {
+			return utils.NewErrMandatoryIeMissing(utils.UsageID)
+		}
+		var reply string
+		err = resourceS.Call(utils.ResourceSv1ReleaseResources,
+			utils.ArgRSv1ResourceUsage{CGREvent: *ntfEv.Event, UsageID: usageID},
+			&reply)
+	default:
+		err = fmt.Errorf("unsupported notification type: <%s>", ntf.Type)
+	}
+	return
+}
+
+// postThresholdNotification posts the body over HTTP or AMQP, falling back to the failed posts directory
+func postThresholdNotification(transport, address string, body []byte) (err error) {
+	cfg := config.CgrConfig()
+	ffn := &utils.FallbackFileName{
+		Module:     utils.ThresholdSPoster,
+		Transport:  transport,
+		Address:    address,
+		RequestID:  utils.GenUUID(),
+		FileSuffix: utils.JSNSuffix,
+	}
+	switch transport {
+	case utils.MetaHTTPjson:
+		_, err = NewHTTPPoster(cfg.GeneralCfg().HttpSkipTlsVerify,
+			cfg.GeneralCfg().ReplyTimeout).Post(address,
+			utils.CONTENT_JSON, body, cfg.GeneralCfg().PosterAttempts,
+			path.Join(cfg.GeneralCfg().FailedPostsDir, ffn.AsString()))
+	case utils.MetaAMQPjsonMap:
+		var amqpPoster *AMQPPoster
+		if amqpPoster, err = AMQPPostersCache.GetAMQPPoster(address,
+			cfg.GeneralCfg().PosterAttempts, cfg.GeneralCfg().FailedPostsDir); err != nil {
+			return
+		}
+		var chn *amqp.Channel
+		chn, err = amqpPoster.Post(nil, utils.CONTENT_JSON, body, ffn.AsString())
+		if chn != nil {
+			chn.Close()
+		}
+	}
+	return
+}
@@ -387,3 +387,126 @@ func TestThresholdsProcessEvent2(t *testing.T) {
 		}
 	}
 }
+
+// thdNtfConn records the calls done by the notifications
+type thdNtfConn struct {
+	calls map[string]interface{}
+}
+
+func (c *thdNtfConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	c.calls[serviceMethod] = args
+	*(reply.(*string)) = utils.OK
+	return nil
+}
+
+func TestThresholdsSendNotifications(t *testing.T) {
+	tS := new(ThresholdService)
+	th := &Threshold{Tenant: "cgrates.org", ID: "TH_NTF", Hits: 3,
+		tPrfl: &ThresholdProfile{Tenant: "cgrates.org", ID: "TH_NTF",
+			Notifications: []*ThresholdNotification{
+				{Type: utils.MetaDisconnectSession},
+				{Type: utils.MetaReleaseResource},
+			}}}
+	args := &ArgsProcessEvent{CGREvent: utils.CGREvent{
+		Tenant: "cgrates.org",
+		ID:     "FraudEvent",
+		Event: map[string]interface{}{
+			utils.OriginID: "call1",
+			utils.UsageID:  "call1_usage",
+		}}}
+	if err := tS.sendNotifications(th, args); err != utils.ErrPartiallyExecuted {
+		t.Errorf("expecting: %v, received: %v", utils.ErrPartiallyExecuted, err)
+	}
+	conn := &thdNtfConn{calls: make(map[string]interface{})}
+	tS.SetNotificationConns(conn, conn)
+	if err := tS.sendNotifications(th, args); err != nil {
+		t.Error(err)
+	}
+	eDisc := utils.AttrForceDisconnect{
+		Filters: map[string]string{utils.OriginID: "call1"},
+		Reason:  "threshold cgrates.org:TH_NTF"}
+	if !reflect.DeepEqual(eDisc, conn.calls[utils.SessionSv1ForceDisconnect]) {
+		t.Errorf("expecting: %+v, received: %+v",
+			eDisc, conn.calls[utils.SessionSv1ForceDisconnect])
+	}
+	eRel := utils.ArgRSv1ResourceUsage{CGREvent: args.CGREvent, UsageID: "call1_usage"}
+	if !reflect.DeepEqual(eRel, conn.calls[utils.ResourceSv1ReleaseResources]) {
+		t.Errorf("expecting: %+v, received: %+v",
+			eRel, conn.calls[utils.ResourceSv1ReleaseResources])
+	}
+	delete(args.CGREvent.Event, utils.UsageID)
+	th.tPrfl.Notifications = []*ThresholdNotification{
+		{Type: utils.MetaReleaseResource}}
+	if err := tS.sendNotifications(th, args); err != utils.ErrPartiallyExecuted {
+		t.Errorf("expecting: %v, received: %v", utils.ErrPartiallyExecuted, err)
+	}
+	th.tPrfl.Notifications[0].UsageID = "RU_1"
+	if err := tS.sendNotifications(th, args); err != nil {
+		t.Error(err)
+	}
+	eRel = utils.ArgRSv1ResourceUsage{CGREvent: args.CGREvent, UsageID: "RU_1"}
+	if !reflect.DeepEqual(eRel, conn.calls[utils.ResourceSv1ReleaseResources]) {
+		t.Errorf("expecting: %+v, received: %+v",
+			eRel, conn.calls[utils.ResourceSv1ReleaseResources])
+	}
+	th.tPrfl.Notifications = []*ThresholdNotification{{Type: "*unsupported"}}
+	if err := tS.sendNotifications(th, args); err != utils.ErrPartiallyExecuted {
+		t.Errorf("expecting: %v, received: %v", utils.ErrPartiallyExecuted, err)
+	}
+}
+
+func TestThresholdDecayHits(t *testing.T) {
+	now := time.Date(2018, 8, 1, 10, 0, 0, 0, time.UTC)
+	th := &Threshold{Tenant: "cgrates.org", ID: "TH_RATE",
+		tPrfl: &ThresholdProfile{Tenant: "cgrates.org", ID: "TH_RATE",
+			HitsWindow: time.Minute}}
+	for i := 0; i < 3; i++ {
+		th.addHit(now.Add(time.Duration(i) * 20 * time.Second))
+	}
+	if th.Hits != 3 {
+		t.Errorf("expecting 3 hits, received: %d", th.Hits)
+	}
+	th.addHit(now.Add(70 * time.Second)) // first two hits expired
+	if th.Hits != 2 || len(th.HitTimes) != 2 {
+		t.Errorf("expecting 2 hits, received: %d, hitTimes: %+v", th.Hits, th.HitTimes)
+	}
+	th = &Threshold{Tenant: "cgrates.org", ID: "TH_HOURLY",
+		tPrfl: &ThresholdProfile{Tenant: "cgrates.org", ID: "TH_HOURLY",
+			ResetInterval: time.Hour}}
+	th.addHit(now)
+	th.addHit(now.Add(30 * time.Minute))
+	if th.Hits != 2 || !th.LastReset.Equal(now) {
+		t.Errorf("expecting 2 hits since: %v, received: %d since: %v", now, th.Hits, th.LastReset)
+	}
+	th.Snooze = now.Add(3 * time.Hour)
+	th.addHit(now.Add(150 * time.Minute))
+	if eReset := now.Add(2 * time.Hour); th.Hits != 1 || !th.LastReset.Equal(eReset) {
+		t.Errorf("expecting 1 hit since: %v, received: %d since: %v", eReset, th.Hits, th.LastReset)
+	}
+	if !th.Snooze.IsZero() {
+		t.Errorf("expecting snooze cleared, received: %v", th.Snooze)
+	}
+}
+
+func TestNewThresholdNotification(t *testing.T) {
+	eNtfs := map[string]*ThresholdNotification{
+		"*log": &ThresholdNotification{Type: LOG},
+		"*http_json:http://localhost:2080/thd": &ThresholdNotification{
+			Type: utils.MetaHTTPjson, Address: "http://localhost:2080/thd"},
+		"*release_resource": &ThresholdNotification{Type: utils.MetaReleaseResource},
+		"*release_resource:RU_1": &ThresholdNotification{
+			Type: utils.MetaReleaseResource, UsageID: "RU_1"},
+	}
+	for ntfStr, eNtf := range eNtfs {
+		if ntf, err := NewThresholdNotification(ntfStr); err != nil {
+			t.Error(err)
+		} else if !reflect.DeepEqual(eNtf, ntf) {
+			t.Errorf("expecting: %+v, received: %+v", eNtf, ntf)
+		}
+	}
+	for _, ntfStr := range []string{"*http_json", "*log:extra", "*unsupported"} {
+		if _, err := NewThresholdNotification(ntfStr); err == nil {
+			t.Errorf("expecting error for: <%s>", ntfStr)
+		}
+	}
+}
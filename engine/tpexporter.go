@@ -306,6 +306,17 @@ func (self *TPExporter) Run() error {
 		}
 	}
 
+	storDataDispatchers, err := self.storDb.GetTPDispatchers(self.tpID, "")
+	if err != nil && err.Error() != utils.ErrNotFound.Error() {
+		return err
+	}
+	for _, sd := range storDataDispatchers {
+		sdModels := APItoModelTPDispatcher(sd)
+		for _, sdModel := range sdModels {
+			toExportMap[utils.DispatchersCsv] = append(toExportMap[utils.DispatchersCsv], sdModel)
+		}
+	}
+
 	storDataUsers, err := self.storDb.GetTPUsers(&utils.TPUsers{TPid: self.tpID})
 	if err != nil && err.Error() != utils.ErrNotFound.Error() {
 		return err
@@ -64,6 +64,7 @@ var fileHandlers = map[string]func(*TPCSVImporter, string) error{
 	utils.SuppliersCsv:          (*TPCSVImporter).importSuppliers,
 	utils.AttributesCsv:         (*TPCSVImporter).importAttributeProfiles,
 	utils.ChargersCsv:           (*TPCSVImporter).importChargerProfiles,
+	utils.DispatchersCsv:        (*TPCSVImporter).importDispatcherProfiles,
 }
 
 func (self *TPCSVImporter) Run() error {
@@ -91,6 +92,7 @@ func (self *TPCSVImporter) Run() error {
 		path.Join(self.DirPath, utils.SuppliersCsv),
 		path.Join(self.DirPath, utils.AttributesCsv),
 		path.Join(self.DirPath, utils.ChargersCsv),
+		path.Join(self.DirPath, utils.DispatchersCsv),
 	)
 	files, _ := ioutil.ReadDir(self.DirPath)
 	for _, f := range files {
@@ -436,3 +438,14 @@ func (self *TPCSVImporter) importChargerProfiles(fn string) error {
 	}
 	return self.StorDb.SetTPChargers(rls)
 }
+
+func (self *TPCSVImporter) importDispatcherProfiles(fn string) error {
+	if self.Verbose {
+		log.Printf("Processing file: <%s> ", fn)
+	}
+	dpps, err := self.csvr.GetTPDispatchers(self.TPid, "")
+	if err != nil {
+		return err
+	}
+	return self.StorDb.SetTPDispatchers(dpps)
+}
@@ -59,10 +59,11 @@ ENABLE_ACNT,*enable_account,,,,,,,,,,,,,,false,false,10`
 	suppliers := ``
 	aliasProfiles := ``
 	chargerProfiles := ``
+	dispatcherProfiles := ``
 	csvr := engine.NewTpReader(dbAcntActs.DataDB(), engine.NewStringCSVStorage(',', destinations, timings,
 		rates, destinationRates, ratingPlans, ratingProfiles, sharedGroups, lcrs,
 		actions, actionPlans, actionTriggers, accountActions, derivedCharges, cdrStats,
-		users, aliases, resLimits, stats, thresholds, filters, suppliers, aliasProfiles, chargerProfiles), "", "")
+		users, aliases, resLimits, stats, thresholds, filters, suppliers, aliasProfiles, chargerProfiles, dispatcherProfiles), "", "")
 	if err := csvr.LoadAll(); err != nil {
 		t.Fatal(err)
 	}
@@ -71,7 +72,7 @@ ENABLE_ACNT,*enable_account,,,,,,,,,,,,,,false,false,10`
 	engine.Cache.Clear(nil)
 	dbAcntActs.LoadDataDBCache(nil, nil, nil, nil, nil,
 		nil, nil, nil, nil, nil, nil, nil, nil, nil,
-		nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	expectAcnt := &engine.Account{ID: "cgrates.org:1"}
 	if acnt, err := dbAcntActs.DataDB().GetAccount("cgrates.org:1"); err != nil {
@@ -66,12 +66,13 @@ TOPUP10_AT,TOPUP10_AC1,ASAP,10`
 	suppliers := ``
 	aliasProfiles := ``
 	chargerProfiles := ``
+	dispatcherProfiles := ``
 	csvr := engine.NewTpReader(dataDB.DataDB(),
 		engine.NewStringCSVStorage(',', destinations, timings, rates,
 			destinationRates, ratingPlans, ratingProfiles,
 			sharedGroups, lcrs, actions, actionPlans, actionTriggers, accountActions,
 			derivedCharges, cdrStats, users, aliases, resLimits, stats,
-			thresholds, filters, suppliers, aliasProfiles, chargerProfiles), "", "")
+			thresholds, filters, suppliers, aliasProfiles, chargerProfiles, dispatcherProfiles), "", "")
 	if err := csvr.LoadDestinations(); err != nil {
 		t.Fatal(err)
 	}
@@ -121,7 +122,7 @@ TOPUP10_AT,TOPUP10_AC1,ASAP,10`
 
 	dataDB.LoadDataDBCache(nil, nil, nil, nil, nil,
 		nil, nil, nil, nil, nil, nil, nil, nil,
-		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	if cachedDests := len(engine.Cache.GetItemIDs(utils.CacheDestinations, "")); cachedDests != 0 {
 		t.Error("Wrong number of cached destinations found", cachedDests)
@@ -64,10 +64,11 @@ RP_UK,DR_UK_Mobile_BIG5,ALWAYS,10`
 	suppliers := ``
 	aliasProfiles := ``
 	chargerProfiles := ``
+	dispatcherProfiles := ``
 	csvr := engine.NewTpReader(dataDB3.DataDB(), engine.NewStringCSVStorage(',', destinations, timings, rates,
 		destinationRates, ratingPlans, ratingProfiles, sharedGroups, lcrs, actions, actionPlans, actionTriggers,
 		accountActions, derivedCharges, cdrStats, users, aliases, resLimits, stats,
-		thresholds, filters, suppliers, aliasProfiles, chargerProfiles), "", "")
+		thresholds, filters, suppliers, aliasProfiles, chargerProfiles, dispatcherProfiles), "", "")
 	if err := csvr.LoadDestinations(); err != nil {
 		t.Fatal(err)
 	}
@@ -116,7 +117,7 @@ RP_UK,DR_UK_Mobile_BIG5,ALWAYS,10`
 	engine.Cache.Clear(nil)
 	dataDB3.LoadDataDBCache(nil, nil, nil, nil, nil,
 		nil, nil, nil, nil, nil, nil, nil, nil,
-		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	if cachedDests := len(engine.Cache.GetItemIDs(utils.CacheDestinations, "")); cachedDests != 0 {
 		t.Error("Wrong number of cached destinations found", cachedDests)
@@ -448,6 +448,32 @@ func (ldr *Loader) storeLoadedData(loaderType string,
 				}
 			}
 		}
+	case utils.MetaDispatchers:
+		for _, lDataSet := range lds {
+			dppModels := make(engine.TPDispatchers, len(lDataSet))
+			for i, ld := range lDataSet {
+				dppModels[i] = new(engine.TPDispatcher)
+				if err = utils.UpdateStructWithIfaceMap(dppModels[i], ld); err != nil {
+					return
+				}
+			}
+
+			for _, tpDPP := range dppModels.AsTPDispatchers() {
+				dpp, err := engine.APItoDispatcherProfile(tpDPP, ldr.timezone)
+				if err != nil {
+					return err
+				}
+				if ldr.dryRun {
+					utils.Logger.Info(
+						fmt.Sprintf("<%s-%s> DRY_RUN: DispatcherProfile: %s",
+							utils.LoaderS, ldr.ldrID, utils.ToJSON(dpp)))
+					continue
+				}
+				if err := ldr.dm.SetDispatcherProfile(dpp, true); err != nil {
+					return err
+				}
+			}
+		}
 	}
 	return
 }
@@ -1043,3 +1043,116 @@ cgrates.org,Charge2,*string:Account:1003,2014-07-29T15:00:00Z,*default,Attr3,10
 	}
 
 }
+
+func TestLoaderProcessDispatchers(t *testing.T) {
+	dispatcherCSV := `
+#Tenant[0],ID[1],Subsystems[2],FilterIDs[3],ActivationInterval[4],Strategy[5],StrategyParameters[6],HostID[7],HostFilterIDs[8],HostWeight[9],HostParameters[10],HostBlocker[11],Weight[12]
+cgrates.org,D1,*any,*string:Account:1001,2014-07-29T15:00:00Z,*first,Param1:Value1,ALL,*string:Account:1001,20,Param2:Value2,true,20
+`
+	data, _ := engine.NewMapStorage()
+	ldr := &Loader{
+		ldrID:         "TestLoaderProcessContent",
+		bufLoaderData: make(map[string][]LoaderData),
+		dm:            engine.NewDataManager(data),
+		timezone:      "UTC",
+	}
+	ldr.dataTpls = map[string][]*config.FCTemplate{
+		utils.MetaDispatchers: []*config.FCTemplate{
+			&config.FCTemplate{Tag: "Tenant",
+				FieldId:   "Tenant",
+				Type:      utils.META_COMPOSED,
+				Value:     config.NewRSRParsersMustCompile("~0", true),
+				Mandatory: true},
+			&config.FCTemplate{Tag: "ID",
+				FieldId:   "ID",
+				Type:      utils.META_COMPOSED,
+				Value:     config.NewRSRParsersMustCompile("~1", true),
+				Mandatory: true},
+			&config.FCTemplate{Tag: "Subsystems",
+				FieldId: "Subsystems",
+				Type:    utils.META_COMPOSED,
+				Value:   config.NewRSRParsersMustCompile("~2", true)},
+			&config.FCTemplate{Tag: "FilterIDs",
+				FieldId: "FilterIDs",
+				Type:    utils.META_COMPOSED,
+				Value:   config.NewRSRParsersMustCompile("~3", true)},
+			&config.FCTemplate{Tag: "ActivationInterval",
+				FieldId: "ActivationInterval",
+				Type:    utils.META_COMPOSED,
+				Value:   config.NewRSRParsersMustCompile("~4", true)},
+			&config.FCTemplate{Tag: "Strategy",
+				FieldId: "Strategy",
+				Type:    utils.META_COMPOSED,
+				Value:   config.NewRSRParsersMustCompile("~5", true)},
+			&config.FCTemplate{Tag: "StrategyParameters",
+				FieldId: "StrategyParameters",
+				Type:    utils.META_COMPOSED,
+				Value:   config.NewRSRParsersMustCompile("~6", true)},
+			&config.FCTemplate{Tag: "HostID",
+				FieldId: "HostID",
+				Type:    utils.META_COMPOSED,
+				Value:   config.NewRSRParsersMustCompile("~7", true)},
+			&config.FCTemplate{Tag: "HostFilterIDs",
+				FieldId: "HostFilterIDs",
+				Type:    utils.META_COMPOSED,
+				Value:   config.NewRSRParsersMustCompile("~8", true)},
+			&config.FCTemplate{Tag: "HostWeight",
+				FieldId: "HostWeight",
+				Type:    utils.META_COMPOSED,
+				Value:   config.NewRSRParsersMustCompile("~9", true)},
+			&config.FCTemplate{Tag: "HostParameters",
+				FieldId: "HostParameters",
+				Type:    utils.META_COMPOSED,
+				Value:   config.NewRSRParsersMustCompile("~10", true)},
+			&config.FCTemplate{Tag: "HostBlocker",
+				FieldId: "HostBlocker",
+				Type:    utils.META_COMPOSED,
+				Value:   config.NewRSRParsersMustCompile("~11", true)},
+			&config.FCTemplate{Tag: "Weight",
+				FieldId: "Weight",
+				Type:    utils.META_COMPOSED,
+				Value:   config.NewRSRParsersMustCompile("~12", true)},
+		},
+	}
+	rdr := ioutil.NopCloser(strings.NewReader(dispatcherCSV))
+	csvRdr := csv.NewReader(rdr)
+	csvRdr.Comment = '#'
+	ldr.rdrs = map[string]map[string]*openedCSVFile{
+		utils.MetaDispatchers: map[string]*openedCSVFile{
+			utils.DispatchersCsv: &openedCSVFile{fileName: utils.DispatchersCsv,
+				rdr: rdr, csvRdr: csvRdr}},
+	}
+	if err := ldr.processContent(utils.MetaDispatchers); err != nil {
+		t.Error(err)
+	}
+	if len(ldr.bufLoaderData) != 0 {
+		t.Errorf("wrong buffer content: %+v", ldr.bufLoaderData)
+	}
+	eDispatcher := &engine.DispatcherProfile{
+		Tenant:     "cgrates.org",
+		ID:         "D1",
+		Subsystems: []string{"*any"},
+		FilterIDs:  []string{"*string:Account:1001"},
+		ActivationInterval: &utils.ActivationInterval{
+			ActivationTime: time.Date(2014, 7, 29, 15, 00, 0, 0, time.UTC),
+		},
+		Strategy:       "*first",
+		StrategyParams: map[string]interface{}{"Param1": "Value1"},
+		Weight:         20,
+		Hosts: engine.DispatcherHosts{
+			&engine.DispatcherHost{
+				ID:        "ALL",
+				FilterIDs: []string{"*string:Account:1001"},
+				Weight:    20,
+				Params:    map[string]interface{}{"Param2": "Value2"},
+				Blocker:   true,
+			},
+		},
+	}
+	if rcv, err := ldr.dm.GetDispatcherProfile("cgrates.org", "D1",
+		true, false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eDispatcher, rcv) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eDispatcher), utils.ToJSON(rcv))
+	}
+}
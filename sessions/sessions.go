@@ -821,6 +821,18 @@ func (smg *SMGeneric) passiveToActive(cgrID string) (pSessions map[string][]*SMG
 // asActiveSessions returns sessions from either active or passive table as []*ActiveSession
 func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSessions bool) (aSessions []*ActiveSession, counter int, err error) {
 	aSessions = make([]*ActiveSession, 0) // Make sure we return at least empty list and not nil
+	remainingSessions := smg.filterSessions(fltrs, passiveSessions)
+	if count {
+		return nil, len(remainingSessions), nil
+	}
+	for _, s := range remainingSessions {
+		aSessions = append(aSessions, s.AsActiveSession(smg.Timezone)) // Expensive for large number of sessions
+	}
+	return
+}
+
+// filterSessions returns the sessions matching all of the filters
+func (smg *SMGeneric) filterSessions(fltrs map[string]string, passiveSessions bool) (remainingSessions []*SMGSession) {
 	// Check first based on indexes so we can downsize the list of matching sessions
 	matchingSessionIDs, checkedFilters := smg.getSessionIDsMatchingIndexes(fltrs, passiveSessions)
 	if len(matchingSessionIDs) == 0 && len(checkedFilters) != 0 {
@@ -831,7 +843,6 @@ func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSe
 			delete(fltrs, fltrFldName)
 		}
 	}
-	var remainingSessions []*SMGSession // Survived index matching
 	var ss map[string][]*SMGSession
 	if passiveSessions {
 		ss = smg.getSessions(fltrs[utils.CGRID], true)
@@ -865,12 +876,6 @@ func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSe
 			i++
 		}
 	}
-	if count {
-		return nil, len(remainingSessions), nil
-	}
-	for _, s := range remainingSessions {
-		aSessions = append(aSessions, s.AsActiveSession(smg.Timezone)) // Expensive for large number of sessions
-	}
 	return
 }
 
@@ -1481,6 +1486,40 @@ func (smg *SMGeneric) BiRPCV1GetPassiveSessionsCount(clnt rpcclient.RpcClientCon
 	return nil
 }
 
+// BiRPCV1ForceDisconnect sends the disconnect request for the active sessions matching the filters
+func (smg *SMGeneric) BiRPCV1ForceDisconnect(clnt rpcclient.RpcClientConnection,
+	args utils.AttrForceDisconnect, reply *string) (err error) {
+	if len(args.Filters) == 0 { // we do not want to disconnect all sessions by mistake
+		return utils.NewErrMandatoryIeMissing("Filters")
+	}
+	for fldName, fldVal := range args.Filters {
+		if fldVal == "" {
+			args.Filters[fldName] = utils.META_NONE
+		}
+	}
+	if _, has := args.Filters[utils.RunID]; !has { // one disconnect per session
+		args.Filters[utils.RunID] = utils.META_DEFAULT
+	}
+	ss := smg.filterSessions(args.Filters, false)
+	if len(ss) == 0 {
+		return utils.ErrNotFound
+	}
+	var withErrors bool
+	for _, s := range ss {
+		if errDisc := s.disconnectSession(args.Reason); errDisc != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> error: %s disconnecting session with CGRID: %s",
+					utils.SessionS, errDisc.Error(), s.CGRID))
+			withErrors = true
+		}
+	}
+	if withErrors {
+		return utils.ErrPartiallyExecuted
+	}
+	*reply = utils.OK
+	return
+}
+
 type ArgsSetPassiveSessions struct {
 	CGRID    string
 	Sessions []*SMGSession
@@ -1574,6 +1613,7 @@ type V1AuthorizeArgs struct {
 	GetSuppliers          bool
 	SuppliersMaxCost      string
 	SuppliersIgnoreErrors bool
+	SuppliersCheckUsage   bool
 	utils.CGREvent
 	utils.Paginator
 }
@@ -1695,6 +1735,7 @@ func (smg *SMGeneric) BiRPCv1AuthorizeEvent(clnt rpcclient.RpcClientConnection,
 		sArgs := &engine.ArgsGetSuppliers{
 			IgnoreErrors: args.SuppliersIgnoreErrors,
 			MaxCost:      args.SuppliersMaxCost,
+			CheckUsage:   args.SuppliersCheckUsage,
 			CGREvent:     *cgrEv,
 			Paginator:    args.Paginator,
 		}
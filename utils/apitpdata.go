@@ -21,6 +21,7 @@ package utils
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -698,6 +699,7 @@ type ArgsCache struct {
 	SupplierProfileIDs    *[]string
 	AttributeProfileIDs   *[]string
 	ChargerProfileIDs     *[]string
+	DispatcherProfileIDs  *[]string
 }
 
 // Data used to do remote cache reloads via api
@@ -742,6 +744,7 @@ type CacheStats struct {
 	SupplierProfiles    int
 	AttributeProfiles   int
 	ChargerProfiles     int
+	DispatcherProfiles  int
 }
 
 type AttrExpFileCdrs struct {
@@ -1220,6 +1223,57 @@ func (self *RPCCDRsFilter) AsCDRsFilter(timezone string) (*CDRsFilter, error) {
 	return cdrFltr, nil
 }
 
+// RPCCDRsAggregateFilter selects the CDRs summarized for each combination of the GroupBy values
+type RPCCDRsAggregateFilter struct {
+	RPCCDRsFilter
+	GroupBy []string // <Tenant|Category|Account|Subject|RunID|ToR|RequestType|Destination[:prefix_length]|AnswerTime:<*hourly|*daily>>
+}
+
+// AsCDRsGroupBy parses the GroupBy rules
+func (self *RPCCDRsAggregateFilter) AsCDRsGroupBy() (groupBy []*CDRsGroupBy, err error) {
+	groupBy = make([]*CDRsGroupBy, len(self.GroupBy))
+	for i, rule := range self.GroupBy {
+		if groupBy[i], err = NewCDRsGroupBy(rule); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// NewCDRsGroupBy parses one GroupBy rule, ie: Destination:4 or AnswerTime:*daily
+func NewCDRsGroupBy(rule string) (gb *CDRsGroupBy, err error) {
+	splt := strings.SplitN(rule, InInFieldSep, 2)
+	gb = &CDRsGroupBy{Rule: rule, Field: splt[0]}
+	switch gb.Field {
+	case Tenant, Category, Account, Subject, RunID, ToR, RequestType:
+		if len(splt) != 1 {
+			return nil, fmt.Errorf("unsupported parameter in group by rule: <%s>", rule)
+		}
+	case Destination:
+		if len(splt) == 2 {
+			if gb.PrefixLength, err = strconv.Atoi(splt[1]); err != nil || gb.PrefixLength < 1 {
+				return nil, fmt.Errorf("invalid prefix length in group by rule: <%s>", rule)
+			}
+		}
+	case AnswerTime:
+		if len(splt) != 2 || !IsSliceMember([]string{MetaHourly, MetaDaily}, splt[1]) {
+			return nil, fmt.Errorf("invalid interval in group by rule: <%s>", rule)
+		}
+		gb.Interval = splt[1]
+	default:
+		return nil, fmt.Errorf("unsupported field in group by rule: <%s>", rule)
+	}
+	return
+}
+
+// CDRsGroupBy is one of the fields the CDRs are aggregated on
+type CDRsGroupBy struct {
+	Rule         string // as received, the group values are indexed on it
+	Field        string
+	PrefixLength int    // Destination prefix, 0 for the full Destination
+	Interval     string // AnswerTime truncation <*hourly|*daily>
+}
+
 type AttrSetActions struct {
 	ActionsId string      // Actions id
 	Overwrite bool        // If previously defined, will be overwritten
@@ -1264,6 +1318,13 @@ type AttrRateCDRs struct {
 	ReplicateCDRs *bool // Replicate results
 }
 
+// ArgsRerateCDRs selects the rated CDRs to be rerated with cost diff report
+type ArgsRerateCDRs struct {
+	RPCCDRsFilter
+	DryRun         bool // only report the cost difference, without storing the new costs
+	AdjustBalances bool // credit/debit the cost difference on the monetary balances charged by *prepaid, *pseudoprepaid and *postpaid CDRs
+}
+
 type AttrSetBalance struct {
 	Tenant         string
 	Account        string
@@ -1296,6 +1357,8 @@ type TPResource struct {
 	Stored             bool
 	Weight             float64  // Weight to sort the ResourceLimits
 	ThresholdIDs       []string // Thresholds to check after changing Limit
+	QuotaPeriod        string
+	QuotaTimezone      string
 }
 
 // TPActivationInterval represents an activation interval for an item
@@ -1358,6 +1421,12 @@ type AttrDisconnectSession struct {
 	Reason     string
 }
 
+// AttrForceDisconnect selects the active sessions to be disconnected by SessionS
+type AttrForceDisconnect struct {
+	Filters map[string]string // session fields to match, eg: OriginID
+	Reason  string
+}
+
 // TPStats is used in APIs to manage remotely offline Stats config
 type TPStats struct {
 	TPid               string
@@ -1373,6 +1442,8 @@ type TPStats struct {
 	Weight             float64
 	MinItems           int
 	ThresholdIDs       []string
+	BucketInterval     string
+	SnapshotInterval   string
 }
 
 type MetricWithParams struct {
@@ -1393,6 +1464,9 @@ type TPThreshold struct {
 	Weight             float64 // Weight to sort the thresholds
 	ActionIDs          []string
 	Async              bool
+	Notifications      []string // <*http_json|*amqp_json_map>:address, *release_resource[:UsageID], *log, *disconnect_session
+	HitsWindow         string
+	ResetInterval      string
 }
 
 type TPFilterProfile struct {
@@ -1462,3 +1536,24 @@ type TPChargerProfile struct {
 	AttributeIDs       []string
 	Weight             float64
 }
+
+type TPDispatcherHost struct {
+	ID        string
+	FilterIDs []string
+	Weight    float64
+	Params    []string // key:value pairs passed to the strategy
+	Blocker   bool     // no connection after this one
+}
+
+type TPDispatcherProfile struct {
+	TPid               string
+	Tenant             string
+	ID                 string
+	Subsystems         []string
+	FilterIDs          []string
+	ActivationInterval *TPActivationInterval // Time when this limit becomes active and expires
+	Strategy           string
+	StrategyParams     []string // key:value pairs passed to the strategy
+	Weight             float64
+	Hosts              []*TPDispatcherHost
+}
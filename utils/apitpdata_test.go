@@ -59,3 +59,24 @@ func TestPaginatorPaginateStringSlice(t *testing.T) {
 		t.Errorf("Expecting: %+v, received: %+v", eOut, rcv)
 	}
 }
+
+func TestNewCDRsGroupBy(t *testing.T) {
+	args := &RPCCDRsAggregateFilter{
+		GroupBy: []string{Account, "Destination:3", "AnswerTime:*hourly"}}
+	eGroupBy := []*CDRsGroupBy{
+		{Rule: Account, Field: Account},
+		{Rule: "Destination:3", Field: Destination, PrefixLength: 3},
+		{Rule: "AnswerTime:*hourly", Field: AnswerTime, Interval: MetaHourly},
+	}
+	if groupBy, err := args.AsCDRsGroupBy(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eGroupBy, groupBy) {
+		t.Errorf("Expecting: %s, received: %s", ToJSON(eGroupBy), ToJSON(groupBy))
+	}
+	for _, rule := range []string{"OriginID", "Account:1", "Destination:0",
+		"Destination:a", "AnswerTime", "AnswerTime:*monthly"} {
+		if _, err := NewCDRsGroupBy(rule); err == nil {
+			t.Errorf("expecting error for rule: %s", rule)
+		}
+	}
+}
@@ -20,6 +20,7 @@ package utils
 
 import (
 	"net"
+	"sync"
 
 	"github.com/cenkalti/rpc2"
 	rpc2_jsonrpc "github.com/cenkalti/rpc2/jsonrpc"
@@ -63,5 +64,47 @@ func (clnt *BiRPCInternalClient) SetClientConn(clntConn rpcclient.RpcClientConne
 
 // Part of rpcclient.RpcClientConnection interface
 func (clnt *BiRPCInternalClient) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	return CallInternal(clnt.callBiRPC, serviceMethod, args, reply)
+}
+
+func (clnt *BiRPCInternalClient) callBiRPC(serviceMethod string, args interface{}, reply interface{}) error {
 	return clnt.serverConn.CallBiRPC(clnt.clntConn, serviceMethod, args, reply)
 }
+
+var (
+	internalAnz   RPCAnalyzer // captures the API calls done over *internal connections
+	internalAnzLk sync.RWMutex
+)
+
+// SetInternalAnalyzer will pass the API calls done from now on over *internal connections through the analyzer
+func SetInternalAnalyzer(anz RPCAnalyzer) {
+	internalAnzLk.Lock()
+	internalAnz = anz
+	internalAnzLk.Unlock()
+}
+
+// CallInternal does the API call over an *internal connection, passing it to the analyzer if set
+func CallInternal(call func(string, interface{}, interface{}) error,
+	serviceMethod string, args, reply interface{}) error {
+	internalAnzLk.RLock()
+	anz := internalAnz
+	internalAnzLk.RUnlock()
+	if anz == nil {
+		return call(serviceMethod, args, reply)
+	}
+	return anz.CallInternal(call, serviceMethod, args, reply)
+}
+
+// NewInternalConn wraps the *internal connection so its API calls pass through CallInternal
+func NewInternalConn(conn rpcclient.RpcClientConnection) rpcclient.RpcClientConnection {
+	return &internalConn{conn: conn}
+}
+
+type internalConn struct {
+	conn rpcclient.RpcClientConnection
+}
+
+// Part of rpcclient.RpcClientConnection interface
+func (ic *internalConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	return CallInternal(ic.conn.Call, serviceMethod, args, reply)
+}
@@ -19,69 +19,76 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package utils
 
 var (
-	CDRExportFormats = []string{DRYRUN, MetaFileCSV, MetaFileFWV, MetaHTTPjsonCDR, MetaHTTPjsonMap, MetaHTTPjson, META_HTTP_POST, MetaAMQPjsonCDR, MetaAMQPjsonMap}
+	CDRExportFormats = []string{DRYRUN, MetaFileCSV, MetaFileFWV, MetaHTTPjsonCDR, MetaHTTPjsonMap, MetaHTTPjson, META_HTTP_POST, MetaAMQPjsonCDR, MetaAMQPjsonMap, MetaKafkajsonCDR, MetaKafkajsonMap}
 	PrimaryCdrFields = []string{CGRID, Source, OriginHost, OriginID, ToR, RequestType, Tenant, Category, Account, Subject, Destination, SetupTime, AnswerTime, Usage,
 		COST, RATED, Partial, RunID}
 	GitLastLog                  string // If set, it will be processed as part of versioning
 	PosterTransportContentTypes = map[string]string{
-		MetaHTTPjsonCDR: CONTENT_JSON,
-		MetaHTTPjsonMap: CONTENT_JSON,
-		MetaHTTPjson:    CONTENT_JSON,
-		META_HTTP_POST:  CONTENT_FORM,
-		MetaAMQPjsonCDR: CONTENT_JSON,
-		MetaAMQPjsonMap: CONTENT_JSON,
+		MetaHTTPjsonCDR:  CONTENT_JSON,
+		MetaHTTPjsonMap:  CONTENT_JSON,
+		MetaHTTPjson:     CONTENT_JSON,
+		META_HTTP_POST:   CONTENT_FORM,
+		MetaAMQPjsonCDR:  CONTENT_JSON,
+		MetaAMQPjsonMap:  CONTENT_JSON,
+		MetaKafkajsonCDR: CONTENT_JSON,
+		MetaKafkajsonMap: CONTENT_JSON,
 	}
 	CDREFileSuffixes = map[string]string{
-		MetaHTTPjsonCDR: JSNSuffix,
-		MetaHTTPjsonMap: JSNSuffix,
-		MetaAMQPjsonCDR: JSNSuffix,
-		MetaAMQPjsonMap: JSNSuffix,
-		META_HTTP_POST:  FormSuffix,
-		MetaFileCSV:     CSVSuffix,
-		MetaFileFWV:     FWVSuffix,
+		MetaHTTPjsonCDR:  JSNSuffix,
+		MetaHTTPjsonMap:  JSNSuffix,
+		MetaAMQPjsonCDR:  JSNSuffix,
+		MetaAMQPjsonMap:  JSNSuffix,
+		MetaKafkajsonCDR: JSNSuffix,
+		MetaKafkajsonMap: JSNSuffix,
+		META_HTTP_POST:   FormSuffix,
+		MetaFileCSV:      CSVSuffix,
+		MetaFileFWV:      FWVSuffix,
 	}
 	CacheInstanceToPrefix = map[string]string{
-		CacheDestinations:           DESTINATION_PREFIX,
-		CacheReverseDestinations:    REVERSE_DESTINATION_PREFIX,
-		CacheRatingPlans:            RATING_PLAN_PREFIX,
-		CacheRatingProfiles:         RATING_PROFILE_PREFIX,
-		CacheLCRRules:               LCR_PREFIX,
-		CacheCDRStatS:               CDR_STATS_PREFIX,
-		CacheActions:                ACTION_PREFIX,
-		CacheActionPlans:            ACTION_PLAN_PREFIX,
-		CacheAccountActionPlans:     AccountActionPlansPrefix,
-		CacheActionTriggers:         ACTION_TRIGGER_PREFIX,
-		CacheSharedGroups:           SHARED_GROUP_PREFIX,
-		CacheAliases:                ALIASES_PREFIX,
-		CacheReverseAliases:         REVERSE_ALIASES_PREFIX,
-		CacheDerivedChargers:        DERIVEDCHARGERS_PREFIX,
-		CacheResourceProfiles:       ResourceProfilesPrefix,
-		CacheResources:              ResourcesPrefix,
-		CacheEventResources:         EventResourcesPrefix,
-		CacheTimings:                TimingsPrefix,
-		CacheStatQueueProfiles:      StatQueueProfilePrefix,
-		CacheStatQueues:             StatQueuePrefix,
-		CacheThresholdProfiles:      ThresholdProfilePrefix,
-		CacheThresholds:             ThresholdPrefix,
-		CacheFilters:                FilterPrefix,
-		CacheSupplierProfiles:       SupplierProfilePrefix,
-		CacheAttributeProfiles:      AttributeProfilePrefix,
-		CacheChargerProfiles:        ChargerProfilePrefix,
-		CacheResourceFilterIndexes:  ResourceFilterIndexes,
-		CacheStatFilterIndexes:      StatFilterIndexes,
-		CacheThresholdFilterIndexes: ThresholdFilterIndexes,
-		CacheSupplierFilterIndexes:  SupplierFilterIndexes,
-		CacheAttributeFilterIndexes: AttributeFilterIndexes,
-		CacheChargerFilterIndexes:   ChargerFilterIndexes,
+		CacheDestinations:            DESTINATION_PREFIX,
+		CacheReverseDestinations:     REVERSE_DESTINATION_PREFIX,
+		CacheRatingPlans:             RATING_PLAN_PREFIX,
+		CacheRatingProfiles:          RATING_PROFILE_PREFIX,
+		CacheLCRRules:                LCR_PREFIX,
+		CacheCDRStatS:                CDR_STATS_PREFIX,
+		CacheActions:                 ACTION_PREFIX,
+		CacheActionPlans:             ACTION_PLAN_PREFIX,
+		CacheAccountActionPlans:      AccountActionPlansPrefix,
+		CacheActionTriggers:          ACTION_TRIGGER_PREFIX,
+		CacheSharedGroups:            SHARED_GROUP_PREFIX,
+		CacheAliases:                 ALIASES_PREFIX,
+		CacheReverseAliases:          REVERSE_ALIASES_PREFIX,
+		CacheDerivedChargers:         DERIVEDCHARGERS_PREFIX,
+		CacheResourceProfiles:        ResourceProfilesPrefix,
+		CacheResources:               ResourcesPrefix,
+		CacheEventResources:          EventResourcesPrefix,
+		CacheTimings:                 TimingsPrefix,
+		CacheStatQueueProfiles:       StatQueueProfilePrefix,
+		CacheStatQueues:              StatQueuePrefix,
+		CacheThresholdProfiles:       ThresholdProfilePrefix,
+		CacheThresholds:              ThresholdPrefix,
+		CacheFilters:                 FilterPrefix,
+		CacheSupplierProfiles:        SupplierProfilePrefix,
+		CacheAttributeProfiles:       AttributeProfilePrefix,
+		CacheChargerProfiles:         ChargerProfilePrefix,
+		CacheDispatcherProfiles:      DispatcherProfilePrefix,
+		CacheResourceFilterIndexes:   ResourceFilterIndexes,
+		CacheStatFilterIndexes:       StatFilterIndexes,
+		CacheThresholdFilterIndexes:  ThresholdFilterIndexes,
+		CacheSupplierFilterIndexes:   SupplierFilterIndexes,
+		CacheAttributeFilterIndexes:  AttributeFilterIndexes,
+		CacheChargerFilterIndexes:    ChargerFilterIndexes,
+		CacheDispatcherFilterIndexes: DispatcherFilterIndexes,
 	}
 	CachePrefixToInstance map[string]string // will be built on init
 	PrefixToIndexCache    = map[string]string{
-		ThresholdProfilePrefix: CacheThresholdFilterIndexes,
-		ResourceProfilesPrefix: CacheResourceFilterIndexes,
-		StatQueueProfilePrefix: CacheStatFilterIndexes,
-		SupplierProfilePrefix:  CacheSupplierFilterIndexes,
-		AttributeProfilePrefix: CacheAttributeFilterIndexes,
-		ChargerProfilePrefix:   CacheChargerFilterIndexes,
+		ThresholdProfilePrefix:  CacheThresholdFilterIndexes,
+		ResourceProfilesPrefix:  CacheResourceFilterIndexes,
+		StatQueueProfilePrefix:  CacheStatFilterIndexes,
+		SupplierProfilePrefix:   CacheSupplierFilterIndexes,
+		AttributeProfilePrefix:  CacheAttributeFilterIndexes,
+		ChargerProfilePrefix:    CacheChargerFilterIndexes,
+		DispatcherProfilePrefix: CacheDispatcherFilterIndexes,
 	}
 	CacheIndexesToPrefix map[string]string // will be built on init
 )
@@ -195,6 +202,8 @@ const (
 	VOICE                         = "*voice"
 	MAX_COST_FREE                 = "*free"
 	MAX_COST_DISCONNECT           = "*disconnect"
+	MetaDisconnectSession         = "*disconnect_session"
+	MetaReleaseResource           = "*release_resource"
 	HOURS                         = "hours"
 	MINUTES                       = "minutes"
 	NANOSECONDS                   = "nanoseconds"
@@ -247,6 +256,7 @@ const (
 	SupplierProfilePrefix         = "spp_"
 	AttributeProfilePrefix        = "alp_"
 	ChargerProfilePrefix          = "cpp_"
+	DispatcherProfilePrefix       = "dpp_"
 	ThresholdProfilePrefix        = "thp_"
 	StatQueuePrefix               = "stq_"
 	LOADINST_KEY                  = "load_history"
@@ -269,6 +279,8 @@ const (
 	MetaHTTPjsonMap               = "*http_json_map"
 	MetaAMQPjsonCDR               = "*amqp_json_cdr"
 	MetaAMQPjsonMap               = "*amqp_json_map"
+	MetaKafkajsonCDR              = "*kafka_json_cdr"
+	MetaKafkajsonMap              = "*kafka_json_map"
 	NANO_MULTIPLIER               = 1000000000
 	CGR_AUTHORIZE                 = "CGR_AUTHORIZE"
 	CONFIG_DIR                    = "/etc/cgrates/"
@@ -312,6 +324,8 @@ const (
 	META_COMPOSED                = "*composed"
 	META_USAGE_DIFFERENCE        = "*usage_difference"
 	MetaCCUsage                  = "*cc_usage"
+	MetaList                     = "*list"
+	MetaBucket                   = "*bucket"
 	MetaString                   = "*string"
 	NegativePrefix               = "!"
 	MatchStartPrefix             = "^"
@@ -374,9 +388,12 @@ const (
 	MetaSuppliers                = "*suppliers"
 	MetaAttributes               = "*attributes"
 	MetaChargers                 = "*chargers"
+	MetaDispatchers              = "*dispatchers"
 	MetaResources                = "*resources"
 	MetaFilters                  = "*filters"
 	MetaCDRs                     = "*cdrs"
+	MetaRerate                   = "*rerate"
+	MetaAdjusted                 = "*adjusted"
 	Migrator                     = "migrator"
 	UnsupportedMigrationTask     = "unsupported migration task"
 	NoStorDBConnection           = "not connected to StorDB"
@@ -394,6 +411,7 @@ const (
 	FileLockPrefix               = "file_"
 	ActionsPoster                = "act"
 	CDRPoster                    = "cdr"
+	ThresholdSPoster             = "thd"
 	MetaFileCSV                  = "*file_csv"
 	MetaFileFWV                  = "*file_fwv"
 	Accounts                     = "Accounts"
@@ -414,6 +432,10 @@ const (
 	CostSource                   = "CostSource"
 	ExtraInfo                    = "ExtraInfo"
 	Meta                         = "*"
+	RequestMethod                = "RequestMethod"
+	RequestSource                = "RequestSource"
+	RequestDuration              = "RequestDuration"
+	ReplyError                   = "ReplyError"
 	EventResourcesPrefix         = "ers_"
 	MetaSysLog                   = "*syslog"
 	MetaStdLog                   = "*stdout"
@@ -474,6 +496,14 @@ const (
 	MetaLeastCost                = "*least_cost"
 	MetaHighestCost              = "*highest_cost"
 	MetaQOS                      = "*qos"
+	MetaLoadDistribution         = "*load_distribution"
+	MetaQOSThreshold             = "*qos_threshold"
+	MetaDaily                    = "*daily"
+	MetaWeekly                   = "*weekly"
+	MetaMonthly                  = "*monthly"
+	ResourceUsage                = "ResourceUsage"
+	UsageID                      = "UsageID"
+	Ratio                        = "Ratio"
 	Weight                       = "Weight"
 	Cost                         = "Cost"
 	RatingPlanID                 = "RatingPlanID"
@@ -504,6 +534,7 @@ const (
 	MetaDivide                   = "*divide"
 	MetaUrl                      = "*url"
 	MetaXml                      = "*xml"
+	MetaJSON                     = "*json"
 	ApiKey                       = "apikey"
 	MetaReq                      = "*req"
 	MetaVars                     = "*vars"
@@ -527,6 +558,12 @@ const (
 	MetaEnv                      = "*env:" // use in config for describing enviormant variables
 	MetaTemplate                 = "*template"
 	MetaCCA                      = "*cca"
+	MetaDiamASR                  = "*asr"
+	MetaDMR                      = "*dmr"
+	MetaCoA                      = "*coa"
+	MetaRAR                      = "*rar"
+	MetaGx                       = "*gx"
+	UDP                          = "udp"
 	OriginRealm                  = "OriginRealm"
 	ProductName                  = "ProductName"
 )
@@ -539,15 +576,19 @@ const (
 
 // MetaMetrics
 const (
-	MetaASR     = "*asr"
-	MetaACD     = "*acd"
-	MetaTCD     = "*tcd"
-	MetaACC     = "*acc"
-	MetaTCC     = "*tcc"
-	MetaPDD     = "*pdd"
-	MetaDDC     = "*ddc"
-	MetaSum     = "*sum"
-	MetaAverage = "*average"
+	MetaASR        = "*asr"
+	MetaACD        = "*acd"
+	MetaTCD        = "*tcd"
+	MetaACC        = "*acc"
+	MetaTCC        = "*tcc"
+	MetaPDD        = "*pdd"
+	MetaDDC        = "*ddc"
+	MetaSum        = "*sum"
+	MetaAverage    = "*average"
+	MetaHighest    = "*highest"
+	MetaLowest     = "*lowest"
+	MetaDistinct   = "*distinct"
+	MetaPercentile = "*p" // prefix for percentile metrics, ie: *p95
 )
 
 // Services
@@ -564,6 +605,8 @@ const (
 	ChargerS    = "ChargerS"
 	CacheS      = "CacheS"
 	AnalyzerS   = "AnalyzerS"
+
+	FailedPostsReplayer = "FailedPostsReplayer"
 )
 
 // Lower service names
@@ -653,6 +696,9 @@ const (
 	MetaRandom    = "*random"
 	MetaBroadcast = "*broadcast"
 	MetaNext      = "*next"
+	MetaSticky    = "*sticky"
+	MetaLoad      = "*load"
+	MetaKeyField  = "*key_field"
 	ThresholdSv1  = "ThresholdSv1"
 	StatSv1       = "StatSv1"
 	ResourceSv1   = "ResourceSv1"
@@ -710,6 +756,7 @@ const (
 	ThresholdSv1GetThresholdIDs       = "ThresholdSv1.GetThresholdIDs"
 	ThresholdSv1Ping                  = "ThresholdSv1.Ping"
 	ThresholdSv1GetThresholdsForEvent = "ThresholdSv1.GetThresholdsForEvent"
+	ThresholdSv1ResetThreshold        = "ThresholdSv1.ResetThreshold"
 )
 
 // StatS APIs
@@ -720,15 +767,17 @@ const (
 	StatSv1GetQueueFloatMetrics  = "StatSv1.GetQueueFloatMetrics"
 	StatSv1Ping                  = "StatSv1.Ping"
 	StatSv1GetStatQueuesForEvent = "StatSv1.GetStatQueuesForEvent"
+	StatSv1GetQueueHistory       = "StatSv1.GetQueueHistory"
 )
 
 // ResourceS APIs
 const (
-	ResourceSv1AuthorizeResources   = "ResourceSv1.AuthorizeResources"
-	ResourceSv1GetResourcesForEvent = "ResourceSv1.GetResourcesForEvent"
-	ResourceSv1AllocateResources    = "ResourceSv1.AllocateResources"
-	ResourceSv1ReleaseResources     = "ResourceSv1.ReleaseResources"
-	ResourceSv1Ping                 = "ResourceSv1.Ping"
+	ResourceSv1AuthorizeResources    = "ResourceSv1.AuthorizeResources"
+	ResourceSv1GetResourcesForEvent  = "ResourceSv1.GetResourcesForEvent"
+	ResourceSv1AllocateResources     = "ResourceSv1.AllocateResources"
+	ResourceSv1ReleaseResources      = "ResourceSv1.ReleaseResources"
+	ResourceSv1GetResourceWithConfig = "ResourceSv1.GetResourceWithConfig"
+	ResourceSv1Ping                  = "ResourceSv1.Ping"
 )
 
 // SessionS APIs
@@ -742,8 +791,11 @@ const (
 	SessionSv1ProcessCDR                 = "SessionSv1.ProcessCDR"
 	SessionSv1ProcessEvent               = "SessionSv1.ProcessEvent"
 	SessionSv1DisconnectSession          = "SessionSv1.DisconnectSession"
+	SessionSv1ForceDisconnect            = "SessionSv1.ForceDisconnect"
 	SessionSv1GetActiveSessions          = "SessionSv1.GetActiveSessions"
+	SessionSv1GetActiveSessionsCount     = "SessionSv1.GetActiveSessionsCount"
 	SessionSv1GetPassiveSessions         = "SessionSv1.GetPassiveSessions"
+	SessionSv1GetPassiveSessionsCount    = "SessionSv1.GetPassiveSessionsCount"
 	SMGenericV1InitiateSession           = "SMGenericV1.InitiateSession"
 	SMGenericV2InitiateSession           = "SMGenericV2.InitiateSession"
 	SMGenericV2UpdateSession             = "SMGenericV2.UpdateSession"
@@ -759,7 +811,15 @@ const (
 
 // AnalyzerS APIs
 const (
-	AnalyzerSv1Ping = "AnalyzerSv1.Ping"
+	AnalyzerSv1Ping        = "AnalyzerSv1.Ping"
+	AnalyzerSv1GetCalls    = "AnalyzerSv1.GetCalls"
+	AnalyzerSv1StringQuery = "AnalyzerSv1.StringQuery"
+)
+
+// DiameterAgent APIs
+const (
+	DiameterAgentV1ReAuthorizeGx = "DiameterAgentV1.ReAuthorizeGx"
+	DiameterAgentV1Ping          = "DiameterAgentV1.Ping"
 )
 
 // LoaderS APIs
@@ -771,6 +831,7 @@ const (
 // CacheS APIs
 const (
 	CacheSv1GetCacheStats     = "CacheSv1.GetCacheStats"
+	CacheSv1GetCacheHitStats  = "CacheSv1.GetCacheHitStats"
 	CacheSv1GetItemIDs        = "CacheSv1.GetItemIDs"
 	CacheSv1HasItem           = "CacheSv1.HasItem"
 	CacheSv1GetItemExpiryTime = "CacheSv1.GetItemExpiryTime"
@@ -782,12 +843,19 @@ const (
 	CacheSv1Clear             = "CacheSv1.Clear"
 )
 
+// FailedPosts APIs
+const (
+	FailedPostsV1GetStats = "FailedPostsV1.GetStats"
+)
+
 // Cdrs APIs
 const (
-	CdrsV1CountCDRs  = "CdrsV1.CountCDRs"
-	CdrsV1GetCDRs    = "CdrsV1.GetCDRs"
-	CdrsV2ProcessCDR = "CdrsV2.ProcessCDR"
-	CdrsV2RateCDRs   = "CdrsV2.RateCDRs"
+	CdrsV1CountCDRs        = "CdrsV1.CountCDRs"
+	CdrsV1GetCDRs          = "CdrsV1.GetCDRs"
+	CdrsV1GetCDRsAggregate = "CdrsV1.GetCDRsAggregate"
+	CdrsV1RerateCDRs       = "CdrsV1.RerateCDRs"
+	CdrsV2ProcessCDR       = "CdrsV2.ProcessCDR"
+	CdrsV2RateCDRs         = "CdrsV2.RateCDRs"
 )
 
 // Scheduler
@@ -800,7 +868,7 @@ const (
 	CdrcPing = "Cdrc.Ping"
 )
 
-//cgr_ variables
+// cgr_ variables
 const (
 	CGR_ACCOUNT          = "cgr_account"
 	CGR_SUPPLIER         = "cgr_supplier"
@@ -819,7 +887,7 @@ const (
 	CGRFlags             = "cgr_flags"
 )
 
-//CSV file name
+// CSV file name
 const (
 	TIMINGS_CSV           = "Timings.csv"
 	DESTINATIONS_CSV      = "Destinations.csv"
@@ -844,6 +912,7 @@ const (
 	SuppliersCsv          = "Suppliers.csv"
 	AttributesCsv         = "Attributes.csv"
 	ChargersCsv           = "Chargers.csv"
+	DispatchersCsv        = "Dispatchers.csv"
 )
 
 // Table Name
@@ -870,59 +939,66 @@ const (
 	TBLTPFilters          = "tp_filters"
 	SessionsCostsTBL      = "sessions_costs"
 	CDRsTBL               = "cdrs"
+	StatQueueSnapshotsTBL = "stat_queue_snapshots"
 	TBLTPSuppliers        = "tp_suppliers"
 	TBLTPAttributes       = "tp_attributes"
 	TBLTPChargers         = "tp_chargers"
+	TBLTPDispatchers      = "tp_dispatchers"
 	TBLVersions           = "versions"
 	OldSMCosts            = "sm_costs"
 )
 
 // Cache Name
 const (
-	CacheDestinations           = "destinations"
-	CacheReverseDestinations    = "reverse_destinations"
-	CacheRatingPlans            = "rating_plans"
-	CacheRatingProfiles         = "rating_profiles"
-	CacheLCRRules               = "lcr_rules"
-	CacheCDRStatS               = "cdr_stats"
-	CacheActions                = "actions"
-	CacheActionPlans            = "action_plans"
-	CacheAccountActionPlans     = "account_action_plans"
-	CacheActionTriggers         = "action_triggers"
-	CacheSharedGroups           = "shared_groups"
-	CacheAliases                = "aliases"
-	CacheReverseAliases         = "reverse_aliases"
-	CacheDerivedChargers        = "derived_chargers"
-	CacheResources              = "resources"
-	CacheResourceProfiles       = "resource_profiles"
-	CacheTimings                = "timings"
-	CacheEventResources         = "event_resources"
-	CacheStatQueueProfiles      = "statqueue_profiles"
-	CacheStatQueues             = "statqueues"
-	CacheThresholdProfiles      = "threshold_profiles"
-	CacheThresholds             = "thresholds"
-	CacheFilters                = "filters"
-	CacheSupplierProfiles       = "supplier_profiles"
-	CacheAttributeProfiles      = "attribute_profiles"
-	CacheChargerProfiles        = "charger_profiles"
-	CacheResourceFilterIndexes  = "resource_filter_indexes"
-	CacheStatFilterIndexes      = "stat_filter_indexes"
-	CacheThresholdFilterIndexes = "threshold_filter_indexes"
-	CacheSupplierFilterIndexes  = "supplier_filter_indexes"
-	CacheAttributeFilterIndexes = "attribute_filter_indexes"
-	CacheChargerFilterIndexes   = "charger_filter_indexes"
-	MetaPrecaching              = "*precaching"
-	MetaReady                   = "*ready"
+	CacheDestinations            = "destinations"
+	CacheReverseDestinations     = "reverse_destinations"
+	CacheRatingPlans             = "rating_plans"
+	CacheRatingProfiles          = "rating_profiles"
+	CacheLCRRules                = "lcr_rules"
+	CacheCDRStatS                = "cdr_stats"
+	CacheActions                 = "actions"
+	CacheActionPlans             = "action_plans"
+	CacheAccountActionPlans      = "account_action_plans"
+	CacheActionTriggers          = "action_triggers"
+	CacheSharedGroups            = "shared_groups"
+	CacheAliases                 = "aliases"
+	CacheReverseAliases          = "reverse_aliases"
+	CacheDerivedChargers         = "derived_chargers"
+	CacheResources               = "resources"
+	CacheResourceProfiles        = "resource_profiles"
+	CacheTimings                 = "timings"
+	CacheEventResources          = "event_resources"
+	CacheStatQueueProfiles       = "statqueue_profiles"
+	CacheStatQueues              = "statqueues"
+	CacheThresholdProfiles       = "threshold_profiles"
+	CacheThresholds              = "thresholds"
+	CacheFilters                 = "filters"
+	CacheSupplierProfiles        = "supplier_profiles"
+	CacheAttributeProfiles       = "attribute_profiles"
+	CacheChargerProfiles         = "charger_profiles"
+	CacheDispatcherProfiles      = "dispatcher_profiles"
+	CacheResourceFilterIndexes   = "resource_filter_indexes"
+	CacheStatFilterIndexes       = "stat_filter_indexes"
+	CacheThresholdFilterIndexes  = "threshold_filter_indexes"
+	CacheSupplierFilterIndexes   = "supplier_filter_indexes"
+	CacheAttributeFilterIndexes  = "attribute_filter_indexes"
+	CacheChargerFilterIndexes    = "charger_filter_indexes"
+	CacheDispatcherFilterIndexes = "dispatcher_filter_indexes"
+	CacheDispatcherRoutes        = "dispatcher_routes"
+	CacheDispatchers             = "dispatchers"
+	MetaPrecaching               = "*precaching"
+	MetaReady                    = "*ready"
 )
 
 // Prefix for indexing
 const (
-	ResourceFilterIndexes  = "rfi_"
-	StatFilterIndexes      = "sfi_"
-	ThresholdFilterIndexes = "tfi_"
-	SupplierFilterIndexes  = "spi_"
-	AttributeFilterIndexes = "afi_"
-	ChargerFilterIndexes   = "cfi_"
+	ResourceFilterIndexes   = "rfi_"
+	StatFilterIndexes       = "sfi_"
+	ThresholdFilterIndexes  = "tfi_"
+	SupplierFilterIndexes   = "spi_"
+	AttributeFilterIndexes  = "afi_"
+	ChargerFilterIndexes    = "cfi_"
+	DispatcherFilterIndexes = "dfi_"
 )
 
 // Agents
@@ -933,6 +1009,7 @@ const (
 	FreeSWITCHAgent = "FreeSWITCHAgent"
 	AsteriskAgent   = "AsteriskAgent"
 	HTTPAgent       = "HTTPAgent"
+	PrometheusAgent = "PrometheusAgent"
 )
 
 func buildCacheInstRevPrefixes() {
@@ -858,9 +858,12 @@ type AuthStruct struct {
 func NewFallbackFileNameFronString(fileName string) (ffn *FallbackFileName, err error) {
 	ffn = new(FallbackFileName)
 	moduleIdx := strings.Index(fileName, HandlerArgSep)
+	if moduleIdx == -1 {
+		return nil, fmt.Errorf("cannot find module in fallback file path: %s", fileName)
+	}
 	ffn.Module = fileName[:moduleIdx]
 	var supportedModule bool
-	for _, prfx := range []string{ActionsPoster, CDRPoster} {
+	for _, prfx := range []string{ActionsPoster, CDRPoster, ThresholdSPoster} {
 		if strings.HasPrefix(ffn.Module, prfx) {
 			supportedModule = true
 			break
@@ -870,7 +873,7 @@ func NewFallbackFileNameFronString(fileName string) (ffn *FallbackFileName, err
 		return nil, fmt.Errorf("unsupported module: %s", ffn.Module)
 	}
 	fileNameWithoutModule := fileName[moduleIdx+1:]
-	for _, trspt := range []string{MetaHTTPjsonCDR, MetaHTTPjsonMap, MetaHTTPjson, META_HTTP_POST, MetaAMQPjsonCDR, MetaAMQPjsonMap} {
+	for _, trspt := range []string{MetaHTTPjsonCDR, MetaHTTPjsonMap, MetaHTTPjson, META_HTTP_POST, MetaAMQPjsonCDR, MetaAMQPjsonMap, MetaKafkajsonCDR, MetaKafkajsonMap} {
 		if strings.HasPrefix(fileNameWithoutModule, trspt) {
 			ffn.Transport = trspt
 			break
@@ -0,0 +1,125 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"net/rpc"
+	"sync"
+	"time"
+)
+
+// NewRPCMetrics constructs the RPCMetrics
+func NewRPCMetrics() *RPCMetrics {
+	return &RPCMetrics{methods: make(map[string]*RPCMethodMetrics)}
+}
+
+// RPCMethodMetrics are the counters for one API method
+type RPCMethodMetrics struct {
+	Calls    int64
+	Errors   int64
+	Duration time.Duration // sum of the durations of all calls
+}
+
+// RPCMetrics counts the API calls served over a rpc.ServerCodec, per method
+type RPCMetrics struct {
+	methods map[string]*RPCMethodMetrics
+	sync.RWMutex
+}
+
+// NewServerCodec wraps the rpc.ServerCodec so the calls through it are counted
+func (rm *RPCMetrics) NewServerCodec(sc rpc.ServerCodec, enc, from string) rpc.ServerCodec {
+	return &rpcMetricsServerCodec{
+		sc:   sc,
+		rm:   rm,
+		reqs: make(map[uint64]*rpcMetricsReq),
+	}
+}
+
+// recordCall adds one call to the method counters
+func (rm *RPCMetrics) recordCall(method string, dur time.Duration, isErr bool) {
+	rm.Lock()
+	mm, has := rm.methods[method]
+	if !has {
+		mm = new(RPCMethodMetrics)
+		rm.methods[method] = mm
+	}
+	mm.Calls += 1
+	if isErr {
+		mm.Errors += 1
+	}
+	mm.Duration += dur
+	rm.Unlock()
+}
+
+// MethodMetrics returns a copy of the counters, indexed on method
+func (rm *RPCMetrics) MethodMetrics() (mms map[string]*RPCMethodMetrics) {
+	rm.RLock()
+	mms = make(map[string]*RPCMethodMetrics, len(rm.methods))
+	for method, mm := range rm.methods {
+		mmCln := *mm
+		mms[method] = &mmCln
+	}
+	rm.RUnlock()
+	return
+}
+
+// rpcMetricsReq is a request waiting for its reply
+type rpcMetricsReq struct {
+	method string
+	start  time.Time
+}
+
+// rpcMetricsServerCodec sits between the rpc.Server and the real codec,
+// timing the requests until their reply is written
+type rpcMetricsServerCodec struct {
+	sc rpc.ServerCodec
+	rm *RPCMetrics
+
+	reqs   map[uint64]*rpcMetricsReq // indexed on sequence
+	reqsLk sync.Mutex
+}
+
+func (c *rpcMetricsServerCodec) ReadRequestHeader(r *rpc.Request) (err error) {
+	if err = c.sc.ReadRequestHeader(r); err != nil {
+		return
+	}
+	c.reqsLk.Lock()
+	c.reqs[r.Seq] = &rpcMetricsReq{method: r.ServiceMethod, start: time.Now()}
+	c.reqsLk.Unlock()
+	return
+}
+
+func (c *rpcMetricsServerCodec) ReadRequestBody(x interface{}) error {
+	return c.sc.ReadRequestBody(x)
+}
+
+func (c *rpcMetricsServerCodec) WriteResponse(r *rpc.Response, x interface{}) error {
+	c.reqsLk.Lock()
+	req, has := c.reqs[r.Seq]
+	delete(c.reqs, r.Seq)
+	c.reqsLk.Unlock()
+	if has {
+		c.rm.recordCall(req.method, time.Now().Sub(req.start), r.Error != "")
+	}
+	return c.sc.WriteResponse(r, x)
+}
+
+func (c *rpcMetricsServerCodec) Close() error {
+	return c.sc.Close()
+}
@@ -0,0 +1,88 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"net/rpc"
+	"testing"
+)
+
+// testServerCodec replays the requests, recording the responses
+type testServerCodec struct {
+	reqs  []*rpc.Request
+	rplys []*rpc.Response
+}
+
+func (c *testServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	*r = *c.reqs[0]
+	c.reqs = c.reqs[1:]
+	return nil
+}
+
+func (c *testServerCodec) ReadRequestBody(x interface{}) error {
+	return nil
+}
+
+func (c *testServerCodec) WriteResponse(r *rpc.Response, x interface{}) error {
+	c.rplys = append(c.rplys, r)
+	return nil
+}
+
+func (c *testServerCodec) Close() error {
+	return nil
+}
+
+func TestRPCMetricsServerCodec(t *testing.T) {
+	rm := NewRPCMetrics()
+	tc := &testServerCodec{reqs: []*rpc.Request{
+		{ServiceMethod: StatSv1Ping, Seq: 1},
+		{ServiceMethod: StatSv1Ping, Seq: 2},
+		{ServiceMethod: CacheSv1GetCacheStats, Seq: 3},
+	}}
+	sc := rm.NewServerCodec(tc, MetaJSONrpc, "127.0.0.1")
+	for i := 0; i < 3; i++ {
+		var r rpc.Request
+		if err := sc.ReadRequestHeader(&r); err != nil {
+			t.Fatal(err)
+		}
+		if err := sc.ReadRequestBody(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sc.WriteResponse(&rpc.Response{ServiceMethod: StatSv1Ping, Seq: 2}, Pong)
+	sc.WriteResponse(&rpc.Response{ServiceMethod: StatSv1Ping, Seq: 1, Error: ErrNotFound.Error()}, nil)
+	sc.WriteResponse(&rpc.Response{ServiceMethod: CacheSv1GetCacheStats, Seq: 3}, nil)
+	if len(tc.rplys) != 3 {
+		t.Errorf("expecting 3 replies, received: %d", len(tc.rplys))
+	}
+	mms := rm.MethodMetrics()
+	if len(mms) != 2 {
+		t.Fatalf("unexpected metrics: %s", ToJSON(mms))
+	}
+	if mm := mms[StatSv1Ping]; mm.Calls != 2 || mm.Errors != 1 {
+		t.Errorf("unexpected metrics: %s", ToJSON(mm))
+	}
+	if mm := mms[CacheSv1GetCacheStats]; mm.Calls != 1 || mm.Errors != 0 {
+		t.Errorf("unexpected metrics: %s", ToJSON(mm))
+	}
+	mms[StatSv1Ping].Calls = 10 // copy should not modify the counters
+	if mm := rm.MethodMetrics()[StatSv1Ping]; mm.Calls != 2 {
+		t.Errorf("counters modified out of the copy: %s", ToJSON(mm))
+	}
+}
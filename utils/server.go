@@ -19,9 +19,11 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package utils
 
 import (
+	"bufio"
 	"bytes"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/gob"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -40,12 +42,62 @@ import (
 	_ "net/http/pprof"
 )
 
+// RPCAnalyzer is able to capture the API calls served over a rpc.ServerCodec, a rpc2.Codec or an *internal connection
+type RPCAnalyzer interface {
+	NewServerCodec(sc rpc.ServerCodec, enc, from string) rpc.ServerCodec
+	NewBiRPCCodec(sc rpc2.Codec, enc, from string) rpc2.Codec
+	CallInternal(call func(string, interface{}, interface{}) error,
+		serviceMethod string, args, reply interface{}) error
+}
+
 type Server struct {
 	rpcEnabled  bool
 	httpEnabled bool
 	birpcSrv    *rpc2.Server
 	sync.RWMutex
 	httpsMux *http.ServeMux
+	anz      RPCAnalyzer
+	rpcMtrs  *RPCMetrics
+}
+
+// SetAnalyzer will pass the API calls served from now on through the analyzer
+func (s *Server) SetAnalyzer(anz RPCAnalyzer) {
+	s.Lock()
+	s.anz = anz
+	s.Unlock()
+}
+
+// SetRPCMetrics will count the API calls served from now on
+func (s *Server) SetRPCMetrics(rpcMtrs *RPCMetrics) {
+	s.Lock()
+	s.rpcMtrs = rpcMtrs
+	s.Unlock()
+}
+
+// serveCodec serves the requests out of the codec, passing them to the analyzer and metrics if set
+func (s *Server) serveCodec(sc rpc.ServerCodec, enc, from string) {
+	s.RLock()
+	anz := s.anz
+	rpcMtrs := s.rpcMtrs
+	s.RUnlock()
+	if anz != nil {
+		sc = anz.NewServerCodec(sc, enc, from)
+	}
+	if rpcMtrs != nil {
+		sc = rpcMtrs.NewServerCodec(sc, enc, from)
+	}
+	rpc.ServeCodec(sc)
+}
+
+// serveBiRPCCodec serves the requests out of the bidirectional codec, passing them to the analyzer if set
+func (s *Server) serveBiRPCCodec(sc rpc2.Codec, enc, from string) {
+	s.RLock()
+	anz := s.anz
+	s.RUnlock()
+	if anz != nil {
+		sc = anz.NewBiRPCCodec(sc, enc, from)
+	}
+	s.birpcSrv.ServeCodec(sc)
 }
 
 func (s *Server) RpcRegister(rcvr interface{}) {
@@ -144,7 +196,7 @@ func (s *Server) ServeJSON(addr string) {
 			continue
 		}
 		//utils.Logger.Info(fmt.Sprintf("<CGRServer> New incoming connection: %v", conn.RemoteAddr()))
-		go jsonrpc.ServeConn(conn)
+		go s.serveCodec(jsonrpc.NewServerCodec(conn), MetaJSONrpc, conn.RemoteAddr().String())
 	}
 
 }
@@ -180,14 +232,16 @@ func (s *Server) ServeGOB(addr string) {
 		}
 
 		//utils.Logger.Info(fmt.Sprintf("<CGRServer> New incoming connection: %v", conn.RemoteAddr()))
-		go rpc.ServeConn(conn)
+		go s.serveCodec(newGobServerCodec(conn), MetaGOBrpc, conn.RemoteAddr().String())
 	}
 }
 
-func handleRequest(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleRequest(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	w.Header().Set("Content-Type", "application/json")
-	res := NewRPCRequest(r.Body).Call()
+	res := NewRPCRequest(r.Body).serve(func(conn io.ReadWriteCloser) {
+		s.serveCodec(jsonrpc.NewServerCodec(conn), MetaJSONrpc, r.RemoteAddr)
+	})
 	io.Copy(w, res)
 }
 
@@ -206,9 +260,9 @@ func (s *Server) ServeHTTP(addr string, jsonRPCURL string, wsRPCURL string,
 
 		Logger.Info("<HTTP> enabling handler for JSON-RPC")
 		if useBasicAuth {
-			http.HandleFunc(jsonRPCURL, use(handleRequest, basicAuth(userList)))
+			http.HandleFunc(jsonRPCURL, use(s.handleRequest, basicAuth(userList)))
 		} else {
-			http.HandleFunc(jsonRPCURL, handleRequest)
+			http.HandleFunc(jsonRPCURL, s.handleRequest)
 		}
 	}
 	if enabled && wsRPCURL != "" {
@@ -217,7 +271,7 @@ func (s *Server) ServeHTTP(addr string, jsonRPCURL string, wsRPCURL string,
 		s.Unlock()
 		Logger.Info("<HTTP> enabling handler for WebSocket connections")
 		wsHandler := websocket.Handler(func(ws *websocket.Conn) {
-			jsonrpc.ServeConn(ws)
+			s.serveCodec(jsonrpc.NewServerCodec(ws), MetaJSONrpc, ws.Request().RemoteAddr)
 		})
 		if useBasicAuth {
 			http.HandleFunc(wsRPCURL, use(func(w http.ResponseWriter, r *http.Request) {
@@ -257,7 +311,7 @@ func (s *Server) ServeBiJSON(addr string, onConn func(*rpc2.Client), onDis func(
 		if err != nil {
 			log.Fatal(err)
 		}
-		go s.birpcSrv.ServeCodec(rpc2_jsonrpc.NewJSONCodec(conn))
+		go s.serveBiRPCCodec(rpc2_jsonrpc.NewJSONCodec(conn), MetaJSONrpc, conn.RemoteAddr().String())
 	}
 }
 
@@ -293,11 +347,67 @@ func (r *rpcRequest) Close() error {
 
 // Call invokes the RPC request, waits for it to complete, and returns the results.
 func (r *rpcRequest) Call() io.Reader {
-	go jsonrpc.ServeConn(r)
+	return r.serve(jsonrpc.ServeConn)
+}
+
+// serve passes the request to serveConn and waits for the results
+func (r *rpcRequest) serve(serveConn func(io.ReadWriteCloser)) io.Reader {
+	go serveConn(r)
 	<-r.done
 	return r.rw
 }
 
+// gobServerCodec mirrors the codec used by rpc.ServeConn so it can be wrapped
+type gobServerCodec struct {
+	rwc    io.ReadWriteCloser
+	dec    *gob.Decoder
+	enc    *gob.Encoder
+	encBuf *bufio.Writer
+	closed bool
+}
+
+func newGobServerCodec(conn io.ReadWriteCloser) rpc.ServerCodec {
+	buf := bufio.NewWriter(conn)
+	return &gobServerCodec{
+		rwc:    conn,
+		dec:    gob.NewDecoder(conn),
+		enc:    gob.NewEncoder(buf),
+		encBuf: buf,
+	}
+}
+
+func (c *gobServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	return c.dec.Decode(r)
+}
+
+func (c *gobServerCodec) ReadRequestBody(body interface{}) error {
+	return c.dec.Decode(body)
+}
+
+func (c *gobServerCodec) WriteResponse(r *rpc.Response, body interface{}) (err error) {
+	if err = c.enc.Encode(r); err != nil {
+		if c.encBuf.Flush() == nil { // gob couldn't encode the header, shut down the connection
+			c.Close()
+		}
+		return
+	}
+	if err = c.enc.Encode(body); err != nil {
+		if c.encBuf.Flush() == nil { // was a gob problem encoding the body but the header has been written
+			c.Close()
+		}
+		return
+	}
+	return c.encBuf.Flush()
+}
+
+func (c *gobServerCodec) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.rwc.Close()
+}
+
 func loadTLSConfig(serverCrt, serverKey, caCert string, serverPolicy int,
 	serverName string) (config tls.Config, err error) {
 	cert, err := tls.LoadX509KeyPair(serverCrt, serverKey)
@@ -374,7 +484,7 @@ func (s *Server) ServeGOBTLS(addr, serverCrt, serverKey, caCert string,
 			continue
 		}
 		//utils.Logger.Info(fmt.Sprintf("<CGRServer> New incoming connection: %v", conn.RemoteAddr()))
-		go rpc.ServeConn(conn)
+		go s.serveCodec(newGobServerCodec(conn), MetaGOBrpc, conn.RemoteAddr().String())
 	}
 }
 
@@ -413,7 +523,7 @@ func (s *Server) ServeJSONTLS(addr, serverCrt, serverKey, caCert string,
 			}
 			continue
 		}
-		go jsonrpc.ServeConn(conn)
+		go s.serveCodec(jsonrpc.NewServerCodec(conn), MetaJSONrpc, conn.RemoteAddr().String())
 	}
 }
 
@@ -433,9 +543,9 @@ func (s *Server) ServeHTTPTLS(addr, serverCrt, serverKey, caCert string, serverP
 		s.Unlock()
 		Logger.Info("<HTTPTLS> enabling handler for JSON-RPC")
 		if useBasicAuth {
-			s.httpsMux.HandleFunc(jsonRPCURL, use(handleRequest, basicAuth(userList)))
+			s.httpsMux.HandleFunc(jsonRPCURL, use(s.handleRequest, basicAuth(userList)))
 		} else {
-			s.httpsMux.HandleFunc(jsonRPCURL, handleRequest)
+			s.httpsMux.HandleFunc(jsonRPCURL, s.handleRequest)
 		}
 	}
 	if enabled && wsRPCURL != "" {
@@ -444,7 +554,7 @@ func (s *Server) ServeHTTPTLS(addr, serverCrt, serverKey, caCert string, serverP
 		s.Unlock()
 		Logger.Info("<HTTPTLS> enabling handler for WebSocket connections")
 		wsHandler := websocket.Handler(func(ws *websocket.Conn) {
-			jsonrpc.ServeConn(ws)
+			s.serveCodec(jsonrpc.NewServerCodec(ws), MetaJSONrpc, ws.Request().RemoteAddr)
 		})
 		if useBasicAuth {
 			s.httpsMux.HandleFunc(wsRPCURL, use(func(w http.ResponseWriter, r *http.Request) {